@@ -0,0 +1,98 @@
+/*
+ * Copyright (c) 2023 Michaël COLL.
+ *
+ * Licensed under the Apache License, Version 2.0 (the "License");
+ * you may not use this file except in compliance with the License.
+ * You may obtain a copy of the License at
+ *
+ *      http://www.apache.org/licenses/LICENSE-2.0
+ *
+ * Unless required by applicable law or agreed to in writing, software
+ * distributed under the License is distributed on an "AS IS" BASIS,
+ * WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+ * See the License for the specific language governing permissions and
+ * limitations under the License.
+ */
+
+package cmd
+
+import (
+	"fmt"
+	"os"
+	"path/filepath"
+
+	"github.com/fatih/color"
+	"github.com/spf13/cobra"
+
+	"github.com/michaelcoll/quiz-app/internal/back/domain"
+)
+
+// importCmd represents the import command
+var importCmd = &cobra.Command{
+	Use:   "import <GIFT or Moodle XML file>",
+	Short: "Converts a Moodle GIFT or Moodle XML file to a quiz file",
+	Long: `
+Converts the questions of a Moodle GIFT or Moodle XML file to a quiz file written next to it or in the
+output directory. The questions that can not be converted are reported on the error output.`,
+	Args: cobra.ExactArgs(1),
+	Run:  importQuiz,
+}
+
+func importQuiz(cmd *cobra.Command, args []string) {
+	formatName, _ := cmd.Flags().GetString("format")
+	name, _ := cmd.Flags().GetString("name")
+	duration, _ := cmd.Flags().GetInt("duration")
+	output, _ := cmd.Flags().GetString("output")
+
+	format, err := domain.ParseImportFormat(formatName, args[0])
+	if err != nil {
+		fmt.Fprintf(os.Stderr, "%s %v\n", color.RedString("✗"), err)
+		os.Exit(2)
+	}
+
+	content, err := os.ReadFile(args[0])
+	if err != nil {
+		fmt.Fprintf(os.Stderr, "%s Can't read file (%v)\n", color.RedString("✗"), err)
+		os.Exit(2)
+	}
+
+	service := domain.NewQuizService(nil)
+	imported, err := service.ConvertQuiz(filepath.Base(args[0]), string(content), format, name, duration)
+	if err != nil {
+		fmt.Fprintf(os.Stderr, "%s Can't convert file (%v)\n", color.RedString("✗"), err)
+		os.Exit(2)
+	}
+
+	// the converted file is parsed to report the problems the quiz would have once synced
+	_, diagnostics := service.ParseWithDiagnostics(imported.Filename, imported.Content)
+	diagnostics = append(imported.Diagnostics, diagnostics...)
+	diagnostics.Print(os.Stderr)
+
+	if output == "" {
+		output = filepath.Dir(args[0])
+	}
+	filename := filepath.Join(output, imported.Filename)
+	if err := os.WriteFile(filename, []byte(imported.Content), 0644); err != nil {
+		fmt.Fprintf(os.Stderr, "%s Can't write quiz file (%v)\n", color.RedString("✗"), err)
+		os.Exit(2)
+	}
+
+	fmt.Printf("%s %s written (%s question(s) converted, %s question(s) or answer(s) left out)\n",
+		color.GreenString("✓"),
+		filename,
+		color.BlueString("%d", imported.Questions),
+		color.HiYellowString("%d", len(imported.Diagnostics)))
+
+	if diagnostics.HasErrors() {
+		os.Exit(1)
+	}
+}
+
+func init() {
+	importCmd.Flags().StringP("format", "f", "", "The format of the file (gift or moodle-xml), guessed from its extension by default.")
+	importCmd.Flags().StringP("name", "n", "", "The name of the quiz, the first category of the file by default.")
+	importCmd.Flags().IntP("duration", "d", 0, "The duration of the quiz in minutes, one minute per question by default.")
+	importCmd.Flags().StringP("output", "o", "", "The directory where the quiz file is written, the one of the imported file by default.")
+
+	rootCmd.AddCommand(importCmd)
+}
@@ -0,0 +1,105 @@
+/*
+ * Copyright (c) 2023 Michaël COLL.
+ *
+ * Licensed under the Apache License, Version 2.0 (the "License");
+ * you may not use this file except in compliance with the License.
+ * You may obtain a copy of the License at
+ *
+ *      http://www.apache.org/licenses/LICENSE-2.0
+ *
+ * Unless required by applicable law or agreed to in writing, software
+ * distributed under the License is distributed on an "AS IS" BASIS,
+ * WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+ * See the License for the specific language governing permissions and
+ * limitations under the License.
+ */
+
+package cmd
+
+import (
+	"encoding/json"
+	"fmt"
+	"os"
+
+	"github.com/fatih/color"
+	"github.com/spf13/cobra"
+
+	"github.com/michaelcoll/quiz-app/internal/back/domain"
+	"github.com/michaelcoll/quiz-app/internal/back/presentation"
+)
+
+// lintCmd represents the lint command
+var lintCmd = &cobra.Command{
+	Use:   "lint [directory or git url]",
+	Short: "Checks the quiz files of a directory or of a git repository",
+	Long: `
+Checks the quiz files of a local directory (the current one by default) or of a git repository.
+The problems found are written to stderr, the json report to stdout.
+Exits with 1 when a quiz file has errors and with 2 when the quiz files can not be read.`,
+	Args: cobra.MaximumNArgs(1),
+	Run:  lint,
+}
+
+func lint(cmd *cobra.Command, args []string) {
+	source := "."
+	if len(args) == 1 {
+		source = args[0]
+	}
+
+	format, _ := cmd.Flags().GetString("format")
+	if format != "text" && format != "json" {
+		fmt.Fprintf(os.Stderr, "%s Unknown format %s, it must be text or json\n", color.RedString("✗"), format)
+		os.Exit(2)
+	}
+	token, _ := cmd.Flags().GetString("token")
+
+	service := domain.NewQuizService(nil)
+	report, err := service.Lint(source, token)
+	if err != nil {
+		fmt.Fprintf(os.Stderr, "%s Can't lint quizzes (%v)\n", color.RedString("✗"), err)
+		os.Exit(2)
+	}
+
+	if format == "json" {
+		encoder := json.NewEncoder(os.Stdout)
+		encoder.SetIndent("", "  ")
+		encoder.SetEscapeHTML(false)
+		if err := encoder.Encode(presentation.ToLintReportDto(report)); err != nil {
+			fmt.Fprintf(os.Stderr, "%s Can't write the report (%v)\n", color.RedString("✗"), err)
+			os.Exit(2)
+		}
+	} else {
+		printLintReport(report)
+	}
+
+	if report.Diagnostics.HasErrors() {
+		os.Exit(1)
+	}
+}
+
+func printLintReport(report *domain.LintReport) {
+	report.Diagnostics.Print(os.Stderr)
+
+	errors := report.Diagnostics.Count(domain.ErrorSeverity)
+	warnings := report.Diagnostics.Count(domain.WarningSeverity)
+
+	if errors > 0 {
+		fmt.Printf("%s %s quiz file(s) checked, %s error(s), %s warning(s)\n",
+			color.RedString("✗"),
+			color.BlueString("%d", report.Files),
+			color.RedString("%d", errors),
+			color.HiYellowString("%d", warnings))
+	} else {
+		fmt.Printf("%s %s quiz file(s) checked, %s warning(s)\n",
+			color.GreenString("✓"),
+			color.BlueString("%d", report.Files),
+			color.HiYellowString("%d", warnings))
+	}
+}
+
+func init() {
+	lintCmd.Flags().StringP("format", "f", "text", "The output format (text or json).")
+	lintCmd.Flags().StringP("token", "t", "", "The P.A.T. used to access the repository when linting a git url.")
+
+	rootCmd.AddCommand(lintCmd)
+}
@@ -0,0 +1,89 @@
+/*
+ * Copyright (c) 2023 Michaël COLL.
+ *
+ * Licensed under the Apache License, Version 2.0 (the "License");
+ * you may not use this file except in compliance with the License.
+ * You may obtain a copy of the License at
+ *
+ *      http://www.apache.org/licenses/LICENSE-2.0
+ *
+ * Unless required by applicable law or agreed to in writing, software
+ * distributed under the License is distributed on an "AS IS" BASIS,
+ * WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+ * See the License for the specific language governing permissions and
+ * limitations under the License.
+ */
+
+package cmd
+
+import (
+	"encoding/json"
+	"fmt"
+	"os"
+
+	"github.com/fatih/color"
+	"github.com/spf13/cobra"
+
+	"github.com/michaelcoll/quiz-app/internal/back/domain"
+	"github.com/michaelcoll/quiz-app/internal/back/presentation"
+)
+
+// renderCmd represents the render command
+var renderCmd = &cobra.Command{
+	Use:   "render <quiz file>",
+	Short: "Previews a quiz file as JSON or HTML",
+	Long: `
+Parses a quiz file and prints it as the JSON returned by the API or as an HTML preview.
+The problems found in the file are written on the error output.`,
+	Args: cobra.ExactArgs(1),
+	Run:  render,
+}
+
+func render(cmd *cobra.Command, args []string) {
+	format, _ := cmd.Flags().GetString("format")
+	if format != "json" && format != "html" {
+		fmt.Fprintf(os.Stderr, "%s Unknown format %s, it must be json or html\n", color.RedString("✗"), format)
+		os.Exit(2)
+	}
+
+	service := domain.NewQuizService(nil)
+	quiz, diagnostics, err := service.ParseFile(args[0])
+	if err != nil {
+		fmt.Fprintf(os.Stderr, "%s Can't read quiz file (%v)\n", color.RedString("✗"), err)
+		os.Exit(2)
+	}
+
+	diagnostics.Print(os.Stderr)
+	if quiz == nil {
+		os.Exit(1)
+	}
+
+	if format == "html" {
+		err = presentation.RenderQuizHtml(os.Stdout, quiz)
+	} else {
+		err = renderQuizJson(quiz)
+	}
+	if err != nil {
+		fmt.Fprintf(os.Stderr, "%s Can't render quiz (%v)\n", color.RedString("✗"), err)
+		os.Exit(2)
+	}
+}
+
+// renderQuizJson prints the quiz as the API returns it once synced : it is the active version and
+// the explanations are only revealed with the sessions
+func renderQuizJson(quiz *domain.Quiz) error {
+	quiz.Active = true
+	quiz.HideSolutions(false)
+
+	encoder := json.NewEncoder(os.Stdout)
+	encoder.SetIndent("", "  ")
+	encoder.SetEscapeHTML(false)
+
+	return encoder.Encode(presentation.ToQuizDto(quiz))
+}
+
+func init() {
+	renderCmd.Flags().StringP("format", "f", "json", "The output format (json or html).")
+
+	rootCmd.AddCommand(renderCmd)
+}
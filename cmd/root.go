@@ -17,9 +17,11 @@
 package cmd
 
 import (
+	"fmt"
 	"os"
 	"strings"
 
+	"github.com/fatih/color"
 	"github.com/spf13/cobra"
 	"github.com/spf13/viper"
 )
@@ -34,6 +36,8 @@ The quiz wep app.`,
 
 var version = "v0.0.0"
 
+var cfgFile string
+
 // Execute adds all child commands to the root command and sets flags appropriately.
 // This is called by main.main(). It only needs to happen once to the rootCmd.
 func Execute() {
@@ -48,11 +52,20 @@ func init() {
 	cobra.OnInitialize(initConfig)
 
 	rootCmd.PersistentFlags().Bool("verbose", false, "Verbose display")
+	rootCmd.PersistentFlags().StringVar(&cfgFile, "config", "", "The configuration file (YAML, JSON or TOML) declaring the quiz sources.")
 
 	_ = viper.BindPFlag("verbose", serveCmd.Flags().Lookup("verbose"))
 }
 
 func initConfig() {
+	if cfgFile != "" {
+		viper.SetConfigFile(cfgFile)
+		if err := viper.ReadInConfig(); err != nil {
+			fmt.Printf("%s Can't read the configuration file %s (%v)\n", color.RedString("✗"), cfgFile, err)
+			os.Exit(1)
+		}
+	}
+
 	viper.SetEnvPrefix("quiz")
 	viper.AutomaticEnv() // read in environment variables that match
 	replacer := strings.NewReplacer("-", "_")
@@ -48,13 +48,17 @@ func serve(_ *cobra.Command, _ []string) {
 			color.HiYellowString("!"))
 	}
 
+	if viper.GetString("webhook-secret") != "" && !viper.IsSet("public-sync") {
+		viper.Set("public-sync", false)
+	}
+
 	go sync(module)
 
 	module.GetApiController().Serve()
 }
 
 func sync(module back.Module) {
-	err := module.GetService().Sync(context.Background())
+	_, err := module.GetService().Sync(context.Background())
 	if err != nil {
 		fmt.Printf("%s Can't sync quizzes (%v)\n", color.RedString("✗"), err)
 		os.Exit(-1)
@@ -63,17 +67,34 @@ func sync(module back.Module) {
 }
 
 func init() {
-	serveCmd.Flags().StringP("db-location", "l", "", "The folder where the database will be stored.")
+	serveCmd.Flags().StringP("db-location", "l", "", "The folder where the database and the working copy of the repository will be stored.")
 	serveCmd.Flags().StringP("repository-url", "r", "", "The url of the repository containing the quizzes.")
+	serveCmd.Flags().String("repository-ref", "", "The branch, tag or commit hash of the repository to read the quizzes from. Its default branch if not set.")
+	serveCmd.Flags().String("repository-dir", "", "The folder of the repository containing the quizzes. Its root if not set.")
 	serveCmd.Flags().StringP("token", "t", "", "The P.A.T. used to access the repository.")
+	serveCmd.Flags().String("ssh-key", "", "The private key file used to access the repository over SSH.")
+	serveCmd.Flags().String("ssh-key-password", "", "The password of the private key file.")
+	serveCmd.Flags().String("known-hosts", "",
+		"The known_hosts file used to check the host key of the repository over SSH. ~/.ssh/known_hosts and /etc/ssh/ssh_known_hosts if not set.")
 	serveCmd.Flags().String("default-admin-username", "",
 		"The default admin username. If specified when the user with the given username registers, it will be created with admin role automatically.")
 	serveCmd.Flags().StringP("api-key", "k", "", "The API key used for the maintenance endpoints.")
+	serveCmd.Flags().String("webhook-secret", "",
+		"The secret signing the push events of the repository. The webhook endpoint is disabled if not set.")
+	serveCmd.Flags().Bool("public-sync", true,
+		"Enables the public unauthenticated sync endpoint. Disabled by default when a webhook secret is set.")
 
 	_ = viper.BindPFlag("db-location", serveCmd.Flags().Lookup("db-location"))
 	_ = viper.BindPFlag("repository-url", serveCmd.Flags().Lookup("repository-url"))
+	_ = viper.BindPFlag("repository-ref", serveCmd.Flags().Lookup("repository-ref"))
+	_ = viper.BindPFlag("repository-dir", serveCmd.Flags().Lookup("repository-dir"))
 	_ = viper.BindPFlag("token", serveCmd.Flags().Lookup("token"))
+	_ = viper.BindPFlag("ssh-key", serveCmd.Flags().Lookup("ssh-key"))
+	_ = viper.BindPFlag("ssh-key-password", serveCmd.Flags().Lookup("ssh-key-password"))
+	_ = viper.BindPFlag("known-hosts", serveCmd.Flags().Lookup("known-hosts"))
 	_ = viper.BindPFlag("api-key", serveCmd.Flags().Lookup("api-key"))
+	_ = viper.BindPFlag("webhook-secret", serveCmd.Flags().Lookup("webhook-secret"))
+	_ = viper.BindPFlag("public-sync", serveCmd.Flags().Lookup("public-sync"))
 
 	viper.SetDefault("db-location", "data")
 	viper.SetDefault("repository-url", "https://github.com/michaelcoll/quiz-app.git")
@@ -0,0 +1,7 @@
+CREATE TABLE asset
+(
+    sha1         TEXT PRIMARY KEY,
+    filename     TEXT NOT NULL,
+    content_type TEXT NOT NULL,
+    content      BLOB NOT NULL
+);
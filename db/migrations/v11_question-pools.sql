@@ -0,0 +1,134 @@
+ALTER TABLE quiz
+    ADD COLUMN pool_draw INTEGER NOT NULL DEFAULT 0;
+ALTER TABLE quiz
+    ADD COLUMN pool_tags TEXT NOT NULL DEFAULT '';
+
+ALTER TABLE quiz_question
+    ADD COLUMN tags TEXT NOT NULL DEFAULT '';
+
+ALTER TABLE session
+    ADD COLUMN seed INTEGER NOT NULL DEFAULT 0;
+
+CREATE TABLE session_question
+(
+    session_uuid  TEXT NOT NULL,
+    question_sha1 TEXT NOT NULL,
+
+    PRIMARY KEY (session_uuid, question_sha1),
+    FOREIGN KEY (session_uuid) REFERENCES session (uuid),
+    FOREIGN KEY (question_sha1) REFERENCES quiz_question (sha1)
+);
+
+INSERT INTO session_question (session_uuid, question_sha1)
+SELECT s.uuid, qqq.question_sha1
+FROM session s
+         JOIN quiz_question_quiz qqq ON qqq.quiz_sha1 = s.quiz_sha1;
+
+CREATE TRIGGER verify_session_question_create
+    BEFORE INSERT
+    ON session_answer
+BEGIN
+    SELECT CASE
+               WHEN NOT EXISTS (SELECT 1
+                                FROM session_question sq
+                                WHERE sq.session_uuid = new.session_uuid
+                                  AND sq.question_sha1 = new.question_sha1) THEN
+                   RAISE(ABORT, 'question is not part of the session')
+               END;
+END;
+
+CREATE TRIGGER verify_session_question_update
+    BEFORE UPDATE
+    ON session_answer
+BEGIN
+    SELECT CASE
+               WHEN NOT EXISTS (SELECT 1
+                                FROM session_question sq
+                                WHERE sq.session_uuid = new.session_uuid
+                                  AND sq.question_sha1 = new.question_sha1) THEN
+                   RAISE(ABORT, 'question is not part of the session')
+               END;
+END;
+
+DROP VIEW quiz_class_view;
+
+CREATE VIEW quiz_class_view
+AS
+SELECT q.*,
+       CASE WHEN sc.uuid IS NULL THEN '' ELSE sc.uuid END AS class_uuid,
+       CASE WHEN sc.name IS NULL THEN '' ELSE sc.name END AS class_name
+FROM quiz q
+         LEFT JOIN quiz_class_visibility qcv ON q.sha1 = qcv.quiz_sha1
+         LEFT JOIN student_class sc ON sc.uuid = qcv.class_uuid;
+
+DROP VIEW quiz_session_detail_view;
+DROP VIEW session_response_view;
+
+CREATE VIEW session_response_view
+AS
+SELECT qqq.quiz_sha1,
+       qqq.question_sha1,
+       qqa.answer_sha1,
+       s.uuid AS session_uuid,
+       s.user_id,
+       sa.checked,
+       sa.content,
+       sa.position
+FROM quiz_question_quiz qqq
+         JOIN quiz_question_answer qqa ON qqq.question_sha1 = qqa.question_sha1
+         JOIN quiz_answer qa ON qa.sha1 = qqa.answer_sha1
+         LEFT JOIN session s ON qqq.quiz_sha1 = s.quiz_sha1
+         LEFT JOIN session_question sq
+                   ON sq.session_uuid = s.uuid
+                       AND sq.question_sha1 = qqq.question_sha1
+         LEFT JOIN session_answer sa
+                   ON sa.session_uuid = s.uuid
+                       AND qa.sha1 = sa.answer_sha1
+                       AND sa.question_sha1 = qqq.question_sha1
+                       AND sa.answer_sha1 = qqa.answer_sha1
+WHERE s.uuid IS NULL
+   OR sq.session_uuid IS NOT NULL;
+
+CREATE VIEW quiz_session_detail_view
+AS
+SELECT qsv.session_uuid                                          AS session_uuid,
+       qsv.user_id                                               AS user_id,
+       qsv.remaining_sec                                         AS remaining_sec,
+       s.seed                                                    AS session_seed,
+       qsv.quiz_sha1                                             AS quiz_sha1,
+       qsv.quiz_name                                             AS quiz_name,
+       qsv.quiz_duration                                         AS quiz_duration,
+       q.scoring                                                 AS quiz_scoring,
+       srv.question_sha1                                         AS question_sha1,
+       qq.kind                                                   AS question_kind,
+       qq.position                                               AS question_position,
+       qq.content                                                AS question_content,
+       qq.code                                                   AS question_code,
+       qq.code_language                                          AS question_code_language,
+       qq.partial_credit                                         AS question_partial_credit,
+       qq.points                                                 AS question_points,
+       qq.explanation                                            AS question_explanation,
+       srv.answer_sha1                                           AS answer_sha1,
+       qa.content                                                AS answer_content,
+       CASE WHEN srv.checked IS NULL THEN 0 ELSE srv.checked END AS answer_checked,
+       qa.valid                                                  AS answer_valid,
+       qa.match_mode                                             AS answer_match_mode,
+       srv.content                                               AS answer_text,
+       qa.position                                               AS answer_position,
+       srv.position                                              AS answer_answered_position,
+       qqp.right_sha1                                            AS answer_right_sha1,
+       qqp.right_content                                         AS answer_right_content,
+       qqa.explanation                                           AS answer_explanation
+FROM quiz_session_view qsv
+         JOIN quiz q ON qsv.quiz_sha1 = q.sha1
+         JOIN session s ON qsv.session_uuid = s.uuid
+         JOIN session_response_view srv ON qsv.session_uuid = srv.session_uuid
+         JOIN quiz_question qq ON srv.question_sha1 = qq.sha1
+         JOIN quiz_answer qa ON srv.answer_sha1 = qa.sha1
+         JOIN quiz_question_answer qqa
+              ON qqa.question_sha1 = srv.question_sha1
+                  AND qqa.answer_sha1 = srv.answer_sha1
+         LEFT JOIN quiz_question_pair qqp
+                   ON qqp.question_sha1 = srv.question_sha1
+                       AND qqp.answer_sha1 = srv.answer_sha1
+ORDER BY qq.position;
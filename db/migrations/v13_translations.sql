@@ -0,0 +1,123 @@
+ALTER TABLE quiz
+    ADD COLUMN lang TEXT NOT NULL DEFAULT '';
+
+ALTER TABLE user
+    ADD COLUMN lang TEXT NOT NULL DEFAULT '';
+
+CREATE TABLE quiz_translation
+(
+    quiz_sha1   TEXT NOT NULL,
+    lang        TEXT NOT NULL,
+    name        TEXT NOT NULL,
+    description TEXT NOT NULL DEFAULT '',
+
+    PRIMARY KEY (quiz_sha1, lang),
+    FOREIGN KEY (quiz_sha1) REFERENCES quiz (sha1)
+);
+
+CREATE TABLE quiz_question_translation
+(
+    quiz_sha1     TEXT NOT NULL,
+    lang          TEXT NOT NULL,
+    question_sha1 TEXT NOT NULL,
+    content       TEXT NOT NULL,
+    code          TEXT NOT NULL DEFAULT '',
+    explanation   TEXT NOT NULL DEFAULT '',
+
+    PRIMARY KEY (quiz_sha1, lang, question_sha1),
+    FOREIGN KEY (quiz_sha1) REFERENCES quiz (sha1),
+    FOREIGN KEY (question_sha1) REFERENCES quiz_question (sha1)
+);
+
+CREATE TABLE quiz_answer_translation
+(
+    quiz_sha1     TEXT NOT NULL,
+    lang          TEXT NOT NULL,
+    question_sha1 TEXT NOT NULL,
+    answer_sha1   TEXT NOT NULL,
+    content       TEXT NOT NULL,
+    right_content TEXT NOT NULL DEFAULT '',
+    explanation   TEXT NOT NULL DEFAULT '',
+
+    PRIMARY KEY (quiz_sha1, lang, question_sha1, answer_sha1),
+    FOREIGN KEY (quiz_sha1) REFERENCES quiz (sha1),
+    FOREIGN KEY (question_sha1) REFERENCES quiz_question (sha1),
+    FOREIGN KEY (answer_sha1) REFERENCES quiz_answer (sha1)
+);
+
+DROP VIEW user_class_view;
+
+CREATE VIEW user_class_view
+AS
+SELECT u.id,
+       u.login,
+       u.name,
+       u.picture,
+       u.active,
+       u.role_id,
+       CASE WHEN u.class_uuid IS NULL THEN '' ELSE u.class_uuid END AS class_uuid,
+       CASE WHEN sc.name IS NULL THEN '' ELSE sc.name END           AS class_name,
+       u.lang
+FROM user u
+         LEFT JOIN student_class sc ON u.class_uuid = sc.uuid;
+
+DROP VIEW quiz_class_view;
+
+CREATE VIEW quiz_class_view
+AS
+SELECT q.*,
+       CASE WHEN sc.uuid IS NULL THEN '' ELSE sc.uuid END AS class_uuid,
+       CASE WHEN sc.name IS NULL THEN '' ELSE sc.name END AS class_name
+FROM quiz q
+         LEFT JOIN quiz_class_visibility qcv ON q.sha1 = qcv.quiz_sha1
+         LEFT JOIN student_class sc ON sc.uuid = qcv.class_uuid;
+
+DROP VIEW quiz_session_detail_view;
+
+CREATE VIEW quiz_session_detail_view
+AS
+SELECT qsv.session_uuid                                          AS session_uuid,
+       qsv.user_id                                               AS user_id,
+       qsv.remaining_sec                                         AS remaining_sec,
+       s.seed                                                    AS session_seed,
+       qsv.quiz_sha1                                             AS quiz_sha1,
+       qsv.quiz_name                                             AS quiz_name,
+       qsv.quiz_duration                                         AS quiz_duration,
+       q.scoring                                                 AS quiz_scoring,
+       q.shuffle_questions                                       AS quiz_shuffle_questions,
+       q.shuffle_answers                                         AS quiz_shuffle_answers,
+       q.lang                                                    AS quiz_lang,
+       srv.question_sha1                                         AS question_sha1,
+       qq.kind                                                   AS question_kind,
+       qq.position                                               AS question_position,
+       qq.content                                                AS question_content,
+       qq.code                                                   AS question_code,
+       qq.code_language                                          AS question_code_language,
+       qq.partial_credit                                         AS question_partial_credit,
+       qq.points                                                 AS question_points,
+       qq.explanation                                            AS question_explanation,
+       srv.answer_sha1                                           AS answer_sha1,
+       qa.content                                                AS answer_content,
+       CASE WHEN srv.checked IS NULL THEN 0 ELSE srv.checked END AS answer_checked,
+       qa.valid                                                  AS answer_valid,
+       qa.match_mode                                             AS answer_match_mode,
+       srv.content                                               AS answer_text,
+       qa.position                                               AS answer_position,
+       srv.position                                              AS answer_answered_position,
+       qqp.right_sha1                                            AS answer_right_sha1,
+       qqp.right_content                                         AS answer_right_content,
+       qqa.explanation                                           AS answer_explanation,
+       qqa.ordinal                                               AS answer_ordinal
+FROM quiz_session_view qsv
+         JOIN quiz q ON qsv.quiz_sha1 = q.sha1
+         JOIN session s ON qsv.session_uuid = s.uuid
+         JOIN session_response_view srv ON qsv.session_uuid = srv.session_uuid
+         JOIN quiz_question qq ON srv.question_sha1 = qq.sha1
+         JOIN quiz_answer qa ON srv.answer_sha1 = qa.sha1
+         JOIN quiz_question_answer qqa
+              ON qqa.question_sha1 = srv.question_sha1
+                  AND qqa.answer_sha1 = srv.answer_sha1
+         LEFT JOIN quiz_question_pair qqp
+                   ON qqp.question_sha1 = srv.question_sha1
+                       AND qqp.answer_sha1 = srv.answer_sha1
+ORDER BY qq.position;
@@ -0,0 +1,13 @@
+ALTER TABLE quiz
+    ADD COLUMN category TEXT NOT NULL DEFAULT '';
+
+DROP VIEW quiz_class_view;
+
+CREATE VIEW quiz_class_view
+AS
+SELECT q.*,
+       CASE WHEN sc.uuid IS NULL THEN '' ELSE sc.uuid END AS class_uuid,
+       CASE WHEN sc.name IS NULL THEN '' ELSE sc.name END AS class_name
+FROM quiz q
+         LEFT JOIN quiz_class_visibility qcv ON q.sha1 = qcv.quiz_sha1
+         LEFT JOIN student_class sc ON sc.uuid = qcv.class_uuid;
@@ -0,0 +1,207 @@
+PRAGMA foreign_keys = OFF;
+
+DROP TRIGGER verify_remaining_time_create;
+DROP TRIGGER verify_remaining_time_update;
+
+DROP VIEW quiz_class_view;
+DROP VIEW quiz_session_detail_view;
+DROP VIEW quiz_session_view;
+DROP VIEW session_view;
+
+CREATE TABLE quiz_with_source
+(
+    sha1              TEXT PRIMARY KEY,
+    name              TEXT    NOT NULL,
+    filename          TEXT    NOT NULL,
+    version           INTEGER NOT NULL DEFAULT 1,
+    active            INTEGER NOT NULL DEFAULT 1,
+    created_at        TEXT    NOT NULL,
+    duration          INTEGER NOT NULL,
+    description       TEXT    NOT NULL DEFAULT '',
+    tags              TEXT    NOT NULL DEFAULT '',
+    author            TEXT    NOT NULL DEFAULT '',
+    pass_mark         INTEGER NOT NULL DEFAULT 0,
+    shuffle_questions INTEGER NOT NULL DEFAULT 0,
+    shuffle_answers   INTEGER NOT NULL DEFAULT 0,
+    max_attempts      INTEGER NOT NULL DEFAULT 0,
+    scoring           INTEGER NOT NULL DEFAULT 0,
+    pool_draw         INTEGER NOT NULL DEFAULT 0,
+    pool_tags         TEXT    NOT NULL DEFAULT '',
+    lang              TEXT    NOT NULL DEFAULT '',
+    category          TEXT    NOT NULL DEFAULT '',
+    source            TEXT    NOT NULL DEFAULT 'default',
+
+    UNIQUE (source, filename, version)
+);
+
+INSERT INTO quiz_with_source (sha1, name, filename, version, active, created_at, duration, description, tags, author,
+                              pass_mark, shuffle_questions, shuffle_answers, max_attempts, scoring, pool_draw,
+                              pool_tags, lang, category)
+SELECT sha1,
+       name,
+       filename,
+       version,
+       active,
+       created_at,
+       duration,
+       description,
+       tags,
+       author,
+       pass_mark,
+       shuffle_questions,
+       shuffle_answers,
+       max_attempts,
+       scoring,
+       pool_draw,
+       pool_tags,
+       lang,
+       category
+FROM quiz;
+
+DROP TABLE quiz;
+
+ALTER TABLE quiz_with_source
+    RENAME TO quiz;
+
+CREATE TABLE quiz_question_identity_with_source
+(
+    quiz_source   TEXT NOT NULL DEFAULT 'default',
+    quiz_filename TEXT NOT NULL,
+    question_sha1 TEXT NOT NULL,
+    question_id   TEXT NOT NULL,
+
+    PRIMARY KEY (quiz_source, quiz_filename, question_sha1),
+    FOREIGN KEY (question_sha1) REFERENCES quiz_question (sha1)
+);
+
+INSERT INTO quiz_question_identity_with_source (quiz_filename, question_sha1, question_id)
+SELECT quiz_filename, question_sha1, question_id
+FROM quiz_question_identity;
+
+DROP TABLE quiz_question_identity;
+
+ALTER TABLE quiz_question_identity_with_source
+    RENAME TO quiz_question_identity;
+
+CREATE VIEW session_view
+AS
+SELECT s.uuid                                                                                       AS uuid,
+       q.sha1                                                                                       AS quiz_sha1,
+       q.name                                                                                       AS quiz_name,
+       q.active                                                                                     AS quiz_active,
+       u.id                                                                                         AS user_id,
+       u.name                                                                                       AS user_name,
+       u.picture                                                                                    AS user_picture,
+       CAST(MAX(q.duration - (STRFTIME('%s', 'now') - STRFTIME('%s', s.created_at)), 0) AS INTEGER) AS remaining_sec
+FROM session s
+         JOIN quiz q ON q.sha1 = s.quiz_sha1
+         JOIN user u ON u.id = s.user_id;
+
+CREATE VIEW quiz_session_view
+AS
+SELECT q.sha1                                                              AS quiz_sha1,
+       q.name                                                              AS quiz_name,
+       q.filename                                                          AS quiz_filename,
+       q.version                                                           AS quiz_version,
+       q.duration                                                          AS quiz_duration,
+       q.created_at                                                        AS quiz_created_at,
+       CASE WHEN s.uuid IS NULL THEN '' ELSE s.uuid END                    AS session_uuid,
+       CASE WHEN s.user_id IS NULL THEN '' ELSE s.user_id END              AS user_id,
+       CASE WHEN u.name IS NULL THEN '' ELSE u.name END                    AS user_name,
+       CASE WHEN u.picture IS NULL THEN '' ELSE u.picture END              AS user_picture,
+       CASE WHEN sc.uuid IS NULL THEN '' ELSE sc.uuid END                  AS class_uuid,
+       CASE WHEN sc.name IS NULL THEN '' ELSE sc.name END                  AS class_name,
+       CASE WHEN sv.remaining_sec IS NULL THEN 0 ELSE sv.remaining_sec END AS remaining_sec
+FROM quiz q
+         LEFT JOIN session s ON q.sha1 = s.quiz_sha1
+         LEFT JOIN user u ON s.user_id = u.id
+         LEFT JOIN student_class sc ON u.class_uuid = sc.uuid
+         LEFT JOIN session_view sv ON q.sha1 = sv.quiz_sha1 AND s.uuid = sv.uuid
+WHERE q.active = TRUE;
+
+CREATE VIEW quiz_session_detail_view
+AS
+SELECT qsv.session_uuid                                          AS session_uuid,
+       qsv.user_id                                               AS user_id,
+       qsv.remaining_sec                                         AS remaining_sec,
+       s.seed                                                    AS session_seed,
+       qsv.quiz_sha1                                             AS quiz_sha1,
+       qsv.quiz_name                                             AS quiz_name,
+       qsv.quiz_duration                                         AS quiz_duration,
+       q.scoring                                                 AS quiz_scoring,
+       q.shuffle_questions                                       AS quiz_shuffle_questions,
+       q.shuffle_answers                                         AS quiz_shuffle_answers,
+       q.lang                                                    AS quiz_lang,
+       srv.question_sha1                                         AS question_sha1,
+       qqi.question_id                                           AS question_id,
+       qq.kind                                                   AS question_kind,
+       qqq.position                                              AS question_position,
+       qq.content                                                AS question_content,
+       qq.code                                                   AS question_code,
+       qq.code_language                                          AS question_code_language,
+       qq.partial_credit                                         AS question_partial_credit,
+       qq.points                                                 AS question_points,
+       qq.explanation                                            AS question_explanation,
+       srv.answer_sha1                                           AS answer_sha1,
+       qa.content                                                AS answer_content,
+       CASE WHEN srv.checked IS NULL THEN 0 ELSE srv.checked END AS answer_checked,
+       qa.valid                                                  AS answer_valid,
+       qa.match_mode                                             AS answer_match_mode,
+       srv.content                                               AS answer_text,
+       qa.position                                               AS answer_position,
+       srv.position                                              AS answer_answered_position,
+       qqp.right_sha1                                            AS answer_right_sha1,
+       qqp.right_content                                         AS answer_right_content,
+       qqa.explanation                                           AS answer_explanation,
+       qqa.ordinal                                               AS answer_ordinal
+FROM quiz_session_view qsv
+         JOIN quiz q ON qsv.quiz_sha1 = q.sha1
+         JOIN session s ON qsv.session_uuid = s.uuid
+         JOIN session_response_view srv ON qsv.session_uuid = srv.session_uuid
+         JOIN quiz_question qq ON srv.question_sha1 = qq.sha1
+         JOIN quiz_question_quiz qqq
+              ON qqq.quiz_sha1 = qsv.quiz_sha1
+                  AND qqq.question_sha1 = srv.question_sha1
+         JOIN quiz_question_identity qqi
+              ON qqi.quiz_source = q.source
+                  AND qqi.quiz_filename = q.filename
+                  AND qqi.question_sha1 = srv.question_sha1
+         JOIN quiz_answer qa ON srv.answer_sha1 = qa.sha1
+         JOIN quiz_question_answer qqa
+              ON qqa.question_sha1 = srv.question_sha1
+                  AND qqa.answer_sha1 = srv.answer_sha1
+         LEFT JOIN quiz_question_pair qqp
+                   ON qqp.question_sha1 = srv.question_sha1
+                       AND qqp.answer_sha1 = srv.answer_sha1
+ORDER BY qqq.position;
+
+CREATE VIEW quiz_class_view
+AS
+SELECT q.*,
+       CASE WHEN sc.uuid IS NULL THEN '' ELSE sc.uuid END AS class_uuid,
+       CASE WHEN sc.name IS NULL THEN '' ELSE sc.name END AS class_name
+FROM quiz q
+         LEFT JOIN quiz_class_visibility qcv ON q.sha1 = qcv.quiz_sha1
+         LEFT JOIN student_class sc ON sc.uuid = qcv.class_uuid;
+
+CREATE TRIGGER verify_remaining_time_create
+    BEFORE INSERT
+    ON session_answer
+BEGIN
+    SELECT CASE
+               WHEN (SELECT remaining_sec FROM session_view sv WHERE sv.uuid = new.session_uuid) = 0 THEN
+                   RAISE(ABORT, 'session is over')
+               END;
+END;
+
+CREATE TRIGGER verify_remaining_time_update
+    BEFORE UPDATE
+    ON session_answer
+BEGIN
+    SELECT CASE
+               WHEN (SELECT remaining_sec FROM session_view sv WHERE sv.uuid = new.session_uuid) = 0 THEN
+                   RAISE(ABORT, 'session is over')
+               END;
+END;
+
+PRAGMA foreign_keys = ON;
@@ -0,0 +1,118 @@
+PRAGMA foreign_keys = OFF;
+
+DROP VIEW quiz_session_detail_view;
+DROP VIEW session_response_view;
+
+CREATE TABLE quiz_question_answer_with_position
+(
+    question_sha1 TEXT    NOT NULL,
+    answer_sha1   TEXT    NOT NULL,
+    explanation   TEXT    NOT NULL DEFAULT '',
+    ordinal       INTEGER NOT NULL DEFAULT 0,
+    position      INTEGER NOT NULL DEFAULT 0,
+
+    PRIMARY KEY (question_sha1, answer_sha1),
+    FOREIGN KEY (question_sha1) REFERENCES quiz_question (sha1),
+    FOREIGN KEY (answer_sha1) REFERENCES quiz_answer (sha1)
+);
+
+INSERT OR REPLACE INTO quiz_question_answer_with_position (question_sha1, answer_sha1, explanation, ordinal, position)
+SELECT qqa.question_sha1,
+       qqa.answer_sha1,
+       qqa.explanation,
+       qqa.ordinal,
+       qa.position
+FROM quiz_question_answer qqa
+         JOIN quiz_answer qa ON qa.sha1 = qqa.answer_sha1
+ORDER BY qqa.rowid;
+
+DROP TABLE quiz_question_answer;
+
+ALTER TABLE quiz_question_answer_with_position
+    RENAME TO quiz_question_answer;
+
+ALTER TABLE quiz_answer
+    DROP COLUMN position;
+
+CREATE VIEW session_response_view
+AS
+SELECT qqq.quiz_sha1,
+       qqq.question_sha1,
+       qqa.answer_sha1,
+       s.uuid AS session_uuid,
+       s.user_id,
+       sa.checked,
+       sa.content,
+       sa.position
+FROM quiz_question_quiz qqq
+         JOIN quiz_question_answer qqa ON qqq.question_sha1 = qqa.question_sha1
+         JOIN quiz_answer qa ON qa.sha1 = qqa.answer_sha1
+         LEFT JOIN session s ON qqq.quiz_sha1 = s.quiz_sha1
+         LEFT JOIN session_question sq
+                   ON sq.session_uuid = s.uuid
+                       AND sq.question_sha1 = qqq.question_sha1
+         LEFT JOIN session_answer sa
+                   ON sa.session_uuid = s.uuid
+                       AND qa.sha1 = sa.answer_sha1
+                       AND sa.question_sha1 = qqq.question_sha1
+                       AND sa.answer_sha1 = qqa.answer_sha1
+WHERE s.uuid IS NULL
+   OR sq.session_uuid IS NOT NULL;
+
+CREATE VIEW quiz_session_detail_view
+AS
+SELECT qsv.session_uuid                                          AS session_uuid,
+       qsv.user_id                                               AS user_id,
+       qsv.remaining_sec                                         AS remaining_sec,
+       s.seed                                                    AS session_seed,
+       qsv.quiz_sha1                                             AS quiz_sha1,
+       qsv.quiz_name                                             AS quiz_name,
+       qsv.quiz_duration                                         AS quiz_duration,
+       q.scoring                                                 AS quiz_scoring,
+       q.shuffle_questions                                       AS quiz_shuffle_questions,
+       q.shuffle_answers                                         AS quiz_shuffle_answers,
+       q.lang                                                    AS quiz_lang,
+       srv.question_sha1                                         AS question_sha1,
+       qqi.question_id                                           AS question_id,
+       qq.kind                                                   AS question_kind,
+       qqq.position                                              AS question_position,
+       qq.content                                                AS question_content,
+       qq.code                                                   AS question_code,
+       qq.code_language                                          AS question_code_language,
+       qq.partial_credit                                         AS question_partial_credit,
+       qq.points                                                 AS question_points,
+       qq.explanation                                            AS question_explanation,
+       srv.answer_sha1                                           AS answer_sha1,
+       qa.content                                                AS answer_content,
+       CASE WHEN srv.checked IS NULL THEN 0 ELSE srv.checked END AS answer_checked,
+       qa.valid                                                  AS answer_valid,
+       qa.match_mode                                             AS answer_match_mode,
+       srv.content                                               AS answer_text,
+       qqa.position                                              AS answer_position,
+       srv.position                                              AS answer_answered_position,
+       qqp.right_sha1                                            AS answer_right_sha1,
+       qqp.right_content                                         AS answer_right_content,
+       qqa.explanation                                           AS answer_explanation,
+       qqa.ordinal                                               AS answer_ordinal
+FROM quiz_session_view qsv
+         JOIN quiz q ON qsv.quiz_sha1 = q.sha1
+         JOIN session s ON qsv.session_uuid = s.uuid
+         JOIN session_response_view srv ON qsv.session_uuid = srv.session_uuid
+         JOIN quiz_question qq ON srv.question_sha1 = qq.sha1
+         JOIN quiz_question_quiz qqq
+              ON qqq.quiz_sha1 = qsv.quiz_sha1
+                  AND qqq.question_sha1 = srv.question_sha1
+         JOIN quiz_question_identity qqi
+              ON qqi.quiz_source = q.source
+                  AND qqi.quiz_filename = q.filename
+                  AND qqi.question_sha1 = srv.question_sha1
+         JOIN quiz_answer qa ON srv.answer_sha1 = qa.sha1
+         JOIN quiz_question_answer qqa
+              ON qqa.question_sha1 = srv.question_sha1
+                  AND qqa.answer_sha1 = srv.answer_sha1
+         LEFT JOIN quiz_question_pair qqp
+                   ON qqp.question_sha1 = srv.question_sha1
+                       AND qqp.answer_sha1 = srv.answer_sha1
+ORDER BY qqq.position;
+
+PRAGMA foreign_keys = ON;
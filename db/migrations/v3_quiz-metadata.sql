@@ -0,0 +1,25 @@
+ALTER TABLE quiz
+    ADD COLUMN description TEXT NOT NULL DEFAULT '';
+ALTER TABLE quiz
+    ADD COLUMN tags TEXT NOT NULL DEFAULT '';
+ALTER TABLE quiz
+    ADD COLUMN author TEXT NOT NULL DEFAULT '';
+ALTER TABLE quiz
+    ADD COLUMN pass_mark INTEGER NOT NULL DEFAULT 0;
+ALTER TABLE quiz
+    ADD COLUMN shuffle_questions INTEGER NOT NULL DEFAULT 0;
+ALTER TABLE quiz
+    ADD COLUMN shuffle_answers INTEGER NOT NULL DEFAULT 0;
+ALTER TABLE quiz
+    ADD COLUMN max_attempts INTEGER NOT NULL DEFAULT 0;
+
+DROP VIEW quiz_class_view;
+
+CREATE VIEW quiz_class_view
+AS
+SELECT q.*,
+       CASE WHEN sc.uuid IS NULL THEN '' ELSE sc.uuid END AS class_uuid,
+       CASE WHEN sc.name IS NULL THEN '' ELSE sc.name END AS class_name
+FROM quiz q
+         LEFT JOIN quiz_class_visibility qcv ON q.sha1 = qcv.quiz_sha1
+         LEFT JOIN student_class sc ON sc.uuid = qcv.class_uuid;
@@ -0,0 +1,86 @@
+ALTER TABLE quiz_question
+    ADD COLUMN partial_credit INTEGER NOT NULL DEFAULT 0;
+
+ALTER TABLE quiz_answer
+    ADD COLUMN position INTEGER NOT NULL DEFAULT 0;
+
+ALTER TABLE session_answer
+    ADD COLUMN position INTEGER;
+
+DROP VIEW quiz_answer_count_view;
+
+CREATE VIEW quiz_answer_count_view
+AS
+SELECT q.sha1                                                                          AS quiz_sha1,
+       SUM(CASE WHEN qq.kind = 0 OR qq.partial_credit = 1 THEN 1 ELSE 0 END) +
+       COUNT(DISTINCT CASE WHEN qq.kind <> 0 AND qq.partial_credit = 0 THEN qq.sha1 END) AS checked_answers
+FROM quiz q
+         JOIN quiz_question_quiz qqq ON q.sha1 = qqq.quiz_sha1
+         JOIN quiz_question qq ON qq.sha1 = qqq.question_sha1
+         JOIN quiz_question_answer qqa ON qqq.question_sha1 = qqa.question_sha1
+         JOIN quiz_answer qa ON qa.sha1 = qqa.answer_sha1
+WHERE qa.valid = 1
+GROUP BY q.sha1;
+
+DROP VIEW session_response_view;
+
+CREATE VIEW session_response_view
+AS
+SELECT qqq.quiz_sha1,
+       qqq.question_sha1,
+       qqa.answer_sha1,
+       s.uuid  AS session_uuid,
+       s.user_id,
+       sa.checked,
+       sa.content,
+       sa.position,
+       CASE
+           WHEN checked IS NOT NULL
+               THEN CASE
+                        WHEN qa.valid == sa.checked
+                            THEN 1
+                        ELSE 0
+               END
+           END AS result
+FROM quiz_question_quiz qqq
+         JOIN quiz_question_answer qqa ON qqq.question_sha1 = qqa.question_sha1
+         JOIN quiz_answer qa ON qa.sha1 = qqa.answer_sha1
+         LEFT JOIN session s ON qqq.quiz_sha1 = s.quiz_sha1
+         LEFT JOIN session_answer sa
+                   ON sa.session_uuid = s.uuid
+                       AND qa.sha1 = sa.answer_sha1
+                       AND sa.question_sha1 = qqq.question_sha1
+                       AND sa.answer_sha1 = qqa.answer_sha1;
+
+DROP VIEW quiz_session_detail_view;
+
+CREATE VIEW quiz_session_detail_view
+AS
+SELECT qsv.session_uuid                                          AS session_uuid,
+       qsv.user_id                                               AS user_id,
+       qsv.remaining_sec                                         AS remaining_sec,
+       qsv.quiz_sha1                                             AS quiz_sha1,
+       qsv.quiz_name                                             AS quiz_name,
+       qsv.quiz_duration                                         AS quiz_duration,
+       qsv.checked_answers                                       AS checked_answers,
+       qsv.results                                               AS results,
+       srv.question_sha1                                         AS question_sha1,
+       qq.kind                                                   AS question_kind,
+       qq.position                                               AS question_position,
+       qq.content                                                AS question_content,
+       qq.code                                                   AS question_code,
+       qq.code_language                                          AS question_code_language,
+       qq.partial_credit                                         AS question_partial_credit,
+       srv.answer_sha1                                           AS answer_sha1,
+       qa.content                                                AS answer_content,
+       CASE WHEN srv.checked IS NULL THEN 0 ELSE srv.checked END AS answer_checked,
+       qa.valid                                                  AS answer_valid,
+       qa.match_mode                                             AS answer_match_mode,
+       srv.content                                               AS answer_text,
+       qa.position                                               AS answer_position,
+       srv.position                                              AS answer_answered_position
+FROM quiz_session_view qsv
+         JOIN session_response_view srv ON qsv.session_uuid = srv.session_uuid
+         JOIN quiz_question qq ON srv.question_sha1 = qq.sha1
+         JOIN quiz_answer qa ON srv.answer_sha1 = qa.sha1
+ORDER BY qq.position;
@@ -0,0 +1,133 @@
+ALTER TABLE quiz_question
+    ADD COLUMN points INTEGER NOT NULL DEFAULT 1;
+
+CREATE VIEW session_question_score_view
+AS
+SELECT s.uuid                                                        AS session_uuid,
+       qqq.quiz_sha1                                                 AS quiz_sha1,
+       qq.sha1                                                       AS question_sha1,
+       qq.points                                                     AS points,
+       CASE
+           WHEN qq.kind = 0
+               THEN qq.points * MIN(qa.valid = COALESCE(sa.checked, 0))
+           WHEN qq.partial_credit = 1
+               THEN qq.points * AVG(COALESCE(sa.checked, 0))
+           ELSE qq.points * MAX(COALESCE(sa.checked, 0))
+           END                                                       AS score
+FROM session s
+         JOIN quiz_question_quiz qqq ON s.quiz_sha1 = qqq.quiz_sha1
+         JOIN quiz_question qq ON qq.sha1 = qqq.question_sha1
+         JOIN quiz_question_answer qqa ON qq.sha1 = qqa.question_sha1
+         JOIN quiz_answer qa ON qa.sha1 = qqa.answer_sha1
+         LEFT JOIN session_answer sa
+                   ON sa.session_uuid = s.uuid
+                       AND sa.question_sha1 = qq.sha1
+                       AND sa.answer_sha1 = qa.sha1
+GROUP BY s.uuid, qqq.quiz_sha1, qq.sha1, qq.points, qq.kind, qq.partial_credit;
+
+CREATE VIEW quiz_points_view
+AS
+SELECT qqq.quiz_sha1   AS quiz_sha1,
+       SUM(qq.points) AS points_possible
+FROM quiz_question_quiz qqq
+         JOIN quiz_question qq ON qq.sha1 = qqq.question_sha1
+GROUP BY qqq.quiz_sha1;
+
+DROP VIEW session_view;
+
+CREATE VIEW session_view
+AS
+SELECT s.uuid                                                                                       AS uuid,
+       q.sha1                                                                                       AS quiz_sha1,
+       q.name                                                                                       AS quiz_name,
+       q.active                                                                                     AS quiz_active,
+       u.id                                                                                         AS user_id,
+       u.name                                                                                       AS user_name,
+       u.picture                                                                                    AS user_picture,
+       CAST(MAX(q.duration - (STRFTIME('%s', 'now') - STRFTIME('%s', s.created_at)), 0) AS INTEGER) AS remaining_sec,
+       checked_answers,
+       SUM(srv.result)                                                                              AS results,
+       (SELECT CAST(SUM(sqsv.score) AS REAL)
+        FROM session_question_score_view sqsv
+        WHERE sqsv.session_uuid = s.uuid)                                                           AS points_earned,
+       qpv.points_possible                                                                          AS points_possible
+FROM session s
+         JOIN quiz q ON q.sha1 = s.quiz_sha1
+         JOIN user u ON u.id = s.user_id
+         JOIN quiz_answer_count_view qacv ON s.quiz_sha1 = qacv.quiz_sha1
+         JOIN quiz_points_view qpv ON s.quiz_sha1 = qpv.quiz_sha1
+         JOIN session_response_view srv ON s.uuid = srv.session_uuid
+GROUP BY s.uuid, q.sha1, q.name, q.active, u.id, u.name, u.picture;
+
+DROP VIEW quiz_session_view;
+
+CREATE VIEW quiz_session_view
+AS
+SELECT q.sha1                                                                  AS quiz_sha1,
+       q.name                                                                  AS quiz_name,
+       q.filename                                                              AS quiz_filename,
+       q.version                                                               AS quiz_version,
+       q.duration                                                              AS quiz_duration,
+       q.created_at                                                            AS quiz_created_at,
+       CASE WHEN s.uuid IS NULL THEN '' ELSE s.uuid END                        AS session_uuid,
+       CASE WHEN s.user_id IS NULL THEN '' ELSE s.user_id END                  AS user_id,
+       CASE WHEN u.name IS NULL THEN '' ELSE u.name END                        AS user_name,
+       CASE WHEN u.picture IS NULL THEN '' ELSE u.picture END                  AS user_picture,
+       CASE WHEN sc.uuid IS NULL THEN '' ELSE sc.uuid END                      AS class_uuid,
+       CASE WHEN sc.name IS NULL THEN '' ELSE sc.name END                      AS class_name,
+       CASE WHEN sv.remaining_sec IS NULL THEN 0 ELSE sv.remaining_sec END     AS remaining_sec,
+       CASE WHEN sv.checked_answers IS NULL THEN 0 ELSE sv.checked_answers END AS checked_answers,
+       CASE WHEN sv.results IS NULL THEN 0 ELSE sv.results END                 AS results,
+       CASE WHEN sv.points_earned IS NULL THEN 0 ELSE sv.points_earned END     AS points_earned,
+       CASE WHEN sv.points_possible IS NULL THEN 0 ELSE sv.points_possible END AS points_possible
+FROM quiz q
+         LEFT JOIN session s ON q.sha1 = s.quiz_sha1
+         LEFT JOIN user u ON s.user_id = u.id
+         LEFT JOIN student_class sc ON u.class_uuid = sc.uuid
+         LEFT JOIN session_view sv ON q.sha1 = sv.quiz_sha1 AND s.uuid = sv.uuid
+WHERE q.active = TRUE;
+
+DROP VIEW quiz_session_detail_view;
+
+CREATE VIEW quiz_session_detail_view
+AS
+SELECT qsv.session_uuid                                          AS session_uuid,
+       qsv.user_id                                               AS user_id,
+       qsv.remaining_sec                                         AS remaining_sec,
+       qsv.quiz_sha1                                             AS quiz_sha1,
+       qsv.quiz_name                                             AS quiz_name,
+       qsv.quiz_duration                                         AS quiz_duration,
+       qsv.checked_answers                                       AS checked_answers,
+       qsv.results                                               AS results,
+       qsv.points_earned                                         AS points_earned,
+       qsv.points_possible                                       AS points_possible,
+       srv.question_sha1                                         AS question_sha1,
+       qq.kind                                                   AS question_kind,
+       qq.position                                               AS question_position,
+       qq.content                                                AS question_content,
+       qq.code                                                   AS question_code,
+       qq.code_language                                          AS question_code_language,
+       qq.partial_credit                                         AS question_partial_credit,
+       qq.points                                                 AS question_points,
+       sqsv.score                                                AS question_score,
+       srv.answer_sha1                                           AS answer_sha1,
+       qa.content                                                AS answer_content,
+       CASE WHEN srv.checked IS NULL THEN 0 ELSE srv.checked END AS answer_checked,
+       qa.valid                                                  AS answer_valid,
+       qa.match_mode                                             AS answer_match_mode,
+       srv.content                                               AS answer_text,
+       qa.position                                               AS answer_position,
+       srv.position                                              AS answer_answered_position,
+       qqp.right_sha1                                            AS answer_right_sha1,
+       qqp.right_content                                         AS answer_right_content
+FROM quiz_session_view qsv
+         JOIN session_response_view srv ON qsv.session_uuid = srv.session_uuid
+         JOIN quiz_question qq ON srv.question_sha1 = qq.sha1
+         JOIN quiz_answer qa ON srv.answer_sha1 = qa.sha1
+         JOIN session_question_score_view sqsv
+              ON sqsv.session_uuid = srv.session_uuid
+                  AND sqsv.question_sha1 = srv.question_sha1
+         LEFT JOIN quiz_question_pair qqp
+                   ON qqp.question_sha1 = srv.question_sha1
+                       AND qqp.answer_sha1 = srv.answer_sha1
+ORDER BY qq.position;
@@ -0,0 +1,114 @@
+ALTER TABLE quiz
+    ADD COLUMN scoring INTEGER NOT NULL DEFAULT 0;
+
+DROP VIEW quiz_class_view;
+
+CREATE VIEW quiz_class_view
+AS
+SELECT q.*,
+       CASE WHEN sc.uuid IS NULL THEN '' ELSE sc.uuid END AS class_uuid,
+       CASE WHEN sc.name IS NULL THEN '' ELSE sc.name END AS class_name
+FROM quiz q
+         LEFT JOIN quiz_class_visibility qcv ON q.sha1 = qcv.quiz_sha1
+         LEFT JOIN student_class sc ON sc.uuid = qcv.class_uuid;
+
+DROP VIEW quiz_session_detail_view;
+DROP VIEW quiz_session_view;
+DROP VIEW session_view;
+DROP VIEW session_question_score_view;
+DROP VIEW quiz_points_view;
+DROP VIEW quiz_answer_count_view;
+DROP VIEW session_response_view;
+
+CREATE VIEW session_response_view
+AS
+SELECT qqq.quiz_sha1,
+       qqq.question_sha1,
+       qqa.answer_sha1,
+       s.uuid AS session_uuid,
+       s.user_id,
+       sa.checked,
+       sa.content,
+       sa.position
+FROM quiz_question_quiz qqq
+         JOIN quiz_question_answer qqa ON qqq.question_sha1 = qqa.question_sha1
+         JOIN quiz_answer qa ON qa.sha1 = qqa.answer_sha1
+         LEFT JOIN session s ON qqq.quiz_sha1 = s.quiz_sha1
+         LEFT JOIN session_answer sa
+                   ON sa.session_uuid = s.uuid
+                       AND qa.sha1 = sa.answer_sha1
+                       AND sa.question_sha1 = qqq.question_sha1
+                       AND sa.answer_sha1 = qqa.answer_sha1;
+
+CREATE VIEW session_view
+AS
+SELECT s.uuid                                                                                       AS uuid,
+       q.sha1                                                                                       AS quiz_sha1,
+       q.name                                                                                       AS quiz_name,
+       q.active                                                                                     AS quiz_active,
+       u.id                                                                                         AS user_id,
+       u.name                                                                                       AS user_name,
+       u.picture                                                                                    AS user_picture,
+       CAST(MAX(q.duration - (STRFTIME('%s', 'now') - STRFTIME('%s', s.created_at)), 0) AS INTEGER) AS remaining_sec
+FROM session s
+         JOIN quiz q ON q.sha1 = s.quiz_sha1
+         JOIN user u ON u.id = s.user_id;
+
+CREATE VIEW quiz_session_view
+AS
+SELECT q.sha1                                                              AS quiz_sha1,
+       q.name                                                              AS quiz_name,
+       q.filename                                                          AS quiz_filename,
+       q.version                                                           AS quiz_version,
+       q.duration                                                          AS quiz_duration,
+       q.created_at                                                        AS quiz_created_at,
+       CASE WHEN s.uuid IS NULL THEN '' ELSE s.uuid END                    AS session_uuid,
+       CASE WHEN s.user_id IS NULL THEN '' ELSE s.user_id END              AS user_id,
+       CASE WHEN u.name IS NULL THEN '' ELSE u.name END                    AS user_name,
+       CASE WHEN u.picture IS NULL THEN '' ELSE u.picture END              AS user_picture,
+       CASE WHEN sc.uuid IS NULL THEN '' ELSE sc.uuid END                  AS class_uuid,
+       CASE WHEN sc.name IS NULL THEN '' ELSE sc.name END                  AS class_name,
+       CASE WHEN sv.remaining_sec IS NULL THEN 0 ELSE sv.remaining_sec END AS remaining_sec
+FROM quiz q
+         LEFT JOIN session s ON q.sha1 = s.quiz_sha1
+         LEFT JOIN user u ON s.user_id = u.id
+         LEFT JOIN student_class sc ON u.class_uuid = sc.uuid
+         LEFT JOIN session_view sv ON q.sha1 = sv.quiz_sha1 AND s.uuid = sv.uuid
+WHERE q.active = TRUE;
+
+CREATE VIEW quiz_session_detail_view
+AS
+SELECT qsv.session_uuid                                          AS session_uuid,
+       qsv.user_id                                               AS user_id,
+       qsv.remaining_sec                                         AS remaining_sec,
+       qsv.quiz_sha1                                             AS quiz_sha1,
+       qsv.quiz_name                                             AS quiz_name,
+       qsv.quiz_duration                                         AS quiz_duration,
+       q.scoring                                                 AS quiz_scoring,
+       srv.question_sha1                                         AS question_sha1,
+       qq.kind                                                   AS question_kind,
+       qq.position                                               AS question_position,
+       qq.content                                                AS question_content,
+       qq.code                                                   AS question_code,
+       qq.code_language                                          AS question_code_language,
+       qq.partial_credit                                         AS question_partial_credit,
+       qq.points                                                 AS question_points,
+       srv.answer_sha1                                           AS answer_sha1,
+       qa.content                                                AS answer_content,
+       CASE WHEN srv.checked IS NULL THEN 0 ELSE srv.checked END AS answer_checked,
+       qa.valid                                                  AS answer_valid,
+       qa.match_mode                                             AS answer_match_mode,
+       srv.content                                               AS answer_text,
+       qa.position                                               AS answer_position,
+       srv.position                                              AS answer_answered_position,
+       qqp.right_sha1                                            AS answer_right_sha1,
+       qqp.right_content                                         AS answer_right_content
+FROM quiz_session_view qsv
+         JOIN quiz q ON qsv.quiz_sha1 = q.sha1
+         JOIN session_response_view srv ON qsv.session_uuid = srv.session_uuid
+         JOIN quiz_question qq ON srv.question_sha1 = qq.sha1
+         JOIN quiz_answer qa ON srv.answer_sha1 = qa.sha1
+         LEFT JOIN quiz_question_pair qqp
+                   ON qqp.question_sha1 = srv.question_sha1
+                       AND qqp.answer_sha1 = srv.answer_sha1
+ORDER BY qq.position;
@@ -0,0 +1,8 @@
+-- name: CreateOrReplaceAsset :exec
+REPLACE INTO asset (sha1, filename, content_type, content)
+VALUES (?, ?, ?, ?);
+
+-- name: FindAssetBySha1 :one
+SELECT *
+FROM asset
+WHERE sha1 = ?;
@@ -1,61 +1,105 @@
 -- name: CreateOrReplaceQuiz :exec
-REPLACE INTO quiz (sha1, name, filename, version, duration, created_at)
-VALUES (?, ?, ?, ?, ?, ?);
+REPLACE INTO quiz (sha1, name, filename, version, duration, created_at, description, tags, author, pass_mark,
+                  shuffle_questions, shuffle_answers, max_attempts, scoring, pool_draw, pool_tags, lang, category, source,
+                  id)
+VALUES (?, ?, ?, ?, ?, ?, ?, ?, ?, ?, ?, ?, ?, ?, ?, ?, ?, ?, ?, ?);
 
--- name: CreateOrReplaceQuestion :exec
-REPLACE INTO quiz_question (sha1, position, content, code, code_language)
-VALUES (?, ?, ?, ?, ?);
+-- name: CreateQuestion :exec
+INSERT OR IGNORE INTO quiz_question (sha1)
+VALUES (?);
 
--- name: CreateOrReplaceAnswer :exec
-REPLACE INTO quiz_answer (sha1, content, valid)
-VALUES (?, ?, ?);
+-- name: CreateAnswer :exec
+INSERT OR IGNORE INTO quiz_answer (sha1)
+VALUES (?);
+
+-- name: CreateOrReplacePair :exec
+REPLACE INTO quiz_question_pair (quiz_sha1, question_sha1, answer_sha1, right_sha1, right_content)
+VALUES (?, ?, ?, ?, ?);
 
 -- name: LinkQuestion :exec
-REPLACE INTO quiz_question_quiz (quiz_sha1, question_sha1)
-VALUES (?, ?);
+REPLACE INTO quiz_question_quiz (quiz_sha1, question_sha1, position, kind, content, code, code_language, partial_credit,
+                                points, explanation, tags)
+VALUES (?, ?, ?, ?, ?, ?, ?, ?, ?, ?, ?);
+
+-- name: CreateOrReplaceQuestionIdentity :exec
+REPLACE INTO quiz_question_identity (quiz_source, quiz_filename, question_sha1, question_id)
+VALUES (?, ?, ?, ?);
 
 -- name: LinkAnswer :exec
-REPLACE INTO quiz_question_answer (question_sha1, answer_sha1)
-VALUES (?, ?);
+REPLACE INTO quiz_question_answer (quiz_sha1, question_sha1, answer_sha1, content, valid, match_mode, explanation, ordinal,
+                                  position)
+VALUES (?, ?, ?, ?, ?, ?, ?, ?, ?);
 
 -- name: ActivateOnlyVersion :exec
 UPDATE quiz
 SET active = 0
-WHERE filename = ?
+WHERE source = ?
+  AND filename = ?
   AND version <> ?;
 
 -- name: FindQuizFullBySha1 :many
-SELECT q.sha1           AS quiz_sha1,
-       q.filename       AS quiz_filename,
-       q.name           AS quiz_name,
-       q.version        AS quiz_version,
-       q.created_at     AS quiz_created_at,
-       q.duration       AS quiz_duration,
-       q.active         AS quiz_active,
-       qq.sha1          AS question_sha1,
-       qq.content       AS question_content,
-       qq.position      AS question_position,
-       qq.code          AS question_code,
-       qq.code_language AS question_code_language,
-       qa.sha1          AS answer_sha1,
-       qa.content       AS answer_content,
-       qa.valid         AS answer_valid
+SELECT q.sha1              AS quiz_sha1,
+       q.filename          AS quiz_filename,
+       q.name              AS quiz_name,
+       q.version           AS quiz_version,
+       q.created_at        AS quiz_created_at,
+       q.duration          AS quiz_duration,
+       q.active            AS quiz_active,
+       q.description       AS quiz_description,
+       q.tags              AS quiz_tags,
+       q.author            AS quiz_author,
+       q.pass_mark         AS quiz_pass_mark,
+       q.shuffle_questions AS quiz_shuffle_questions,
+       q.shuffle_answers   AS quiz_shuffle_answers,
+       q.max_attempts      AS quiz_max_attempts,
+       q.scoring           AS quiz_scoring,
+       q.pool_draw         AS quiz_pool_draw,
+       q.pool_tags         AS quiz_pool_tags,
+       q.lang              AS quiz_lang,
+       q.category          AS quiz_category,
+       q.source            AS quiz_source,
+       q.id                AS quiz_id,
+       qqq.question_sha1   AS question_sha1,
+       qqi.question_id     AS question_id,
+       qqq.kind            AS question_kind,
+       qqq.content         AS question_content,
+       qqq.position        AS question_position,
+       qqq.code            AS question_code,
+       qqq.code_language   AS question_code_language,
+       qqq.partial_credit  AS question_partial_credit,
+       qqq.points          AS question_points,
+       qqq.explanation     AS question_explanation,
+       qqq.tags            AS question_tags,
+       qqa.answer_sha1     AS answer_sha1,
+       qqa.content         AS answer_content,
+       qqa.valid           AS answer_valid,
+       qqa.match_mode      AS answer_match_mode,
+       qqa.position        AS answer_position,
+       qqa.ordinal         AS answer_ordinal,
+       qqa.explanation     AS answer_explanation,
+       qqp.right_sha1      AS answer_right_sha1,
+       qqp.right_content   AS answer_right_content
 FROM quiz q
          JOIN quiz_question_quiz qqq ON q.sha1 = qqq.quiz_sha1
-         JOIN quiz_question qq ON qq.sha1 = qqq.question_sha1
-         JOIN quiz_question_answer qqa ON qq.sha1 = qqa.question_sha1
-         JOIN quiz_answer qa ON qa.sha1 = qqa.answer_sha1
-         JOIN quiz_class_visibility qcv ON q.sha1 = qcv.quiz_sha1
-         JOIN student_class sc ON sc.uuid = qcv.class_uuid
-         JOIN user u ON sc.uuid = u.class_uuid
-WHERE q.sha1 = ?
-    AND u.id = ''
-   OR u.id = ?;
+         JOIN quiz_question_identity qqi
+              ON qqi.quiz_source = q.source AND qqi.quiz_filename = q.filename AND qqi.question_sha1 = qqq.question_sha1
+         JOIN quiz_question_answer qqa ON qqa.quiz_sha1 = q.sha1 AND qqa.question_sha1 = qqq.question_sha1
+         LEFT JOIN quiz_question_pair qqp
+                   ON qqp.quiz_sha1 = q.sha1 AND qqp.question_sha1 = qqq.question_sha1 AND
+                      qqp.answer_sha1 = qqa.answer_sha1
+WHERE q.sha1 = sqlc.arg(sha1)
+  AND (sqlc.arg(user_id) = ''
+    OR EXISTS (SELECT 1
+               FROM quiz_class_visibility qcv
+                        JOIN user u ON u.class_uuid = qcv.class_uuid
+               WHERE qcv.quiz_sha1 = q.sha1
+                 AND u.id = sqlc.arg(user_id)));
 
 -- name: FindQuizByFilenameAndLatestVersion :one
 SELECT *
 FROM quiz
-WHERE filename = ?
+WHERE source = ?
+  AND filename = ?
 ORDER BY version DESC
 LIMIT 1;
 
@@ -63,12 +107,18 @@ LIMIT 1;
 SELECT *
 FROM quiz_class_view qcv
 WHERE qcv.active = 1
-LIMIT ? OFFSET ?;
+  AND (sqlc.arg(category) = ''
+    OR qcv.category = sqlc.arg(category)
+    OR substr(qcv.category, 1, length(sqlc.arg(category)) + 1) = sqlc.arg(category) || '/')
+LIMIT sqlc.arg(limit) OFFSET sqlc.arg(offset);
 
 -- name: CountAllActiveQuiz :one
 SELECT COUNT(1)
 FROM quiz q
-WHERE q.active = 1;
+WHERE q.active = 1
+  AND (sqlc.arg(category) = ''
+    OR q.category = sqlc.arg(category)
+    OR substr(q.category, 1, length(sqlc.arg(category)) + 1) = sqlc.arg(category) || '/');
 
 -- name: CountAllActiveQuizForUser :one
 SELECT COUNT(1)
@@ -77,9 +127,91 @@ FROM quiz q
          JOIN student_class sc ON sc.uuid = qcv.class_uuid
          JOIN user u ON sc.uuid = u.class_uuid
 WHERE q.active = 1
-  AND u.id = ?;
+  AND u.id = sqlc.arg(id)
+  AND (sqlc.arg(category) = ''
+    OR q.category = sqlc.arg(category)
+    OR substr(q.category, 1, length(sqlc.arg(category)) + 1) = sqlc.arg(category) || '/');
+
+-- name: FindSessionQuestion :many
+SELECT qqq.question_sha1  AS question_sha1,
+       qqq.kind           AS question_kind,
+       qqq.content        AS question_content,
+       qqq.code           AS question_code,
+       qqq.code_language  AS question_code_language,
+       qqq.partial_credit AS question_partial_credit,
+       qqq.points         AS question_points,
+       qqa.answer_sha1    AS answer_sha1,
+       qqa.content        AS answer_content,
+       qqa.valid          AS answer_valid,
+       qqa.match_mode     AS answer_match_mode,
+       qqa.position       AS answer_position,
+       qqp.right_sha1     AS answer_right_sha1,
+       qqp.right_content  AS answer_right_content
+FROM session s
+         JOIN quiz_question_quiz qqq ON qqq.quiz_sha1 = s.quiz_sha1
+         JOIN quiz_question_answer qqa ON qqa.quiz_sha1 = qqq.quiz_sha1 AND qqa.question_sha1 = qqq.question_sha1
+         LEFT JOIN quiz_question_pair qqp
+                   ON qqp.quiz_sha1 = qqq.quiz_sha1 AND qqp.question_sha1 = qqq.question_sha1 AND
+                      qqp.answer_sha1 = qqa.answer_sha1
+WHERE s.uuid = ?
+  AND qqq.question_sha1 = ?;
+
+-- name: FindQuizPoolBySha1 :many
+SELECT q.pool_draw  AS quiz_pool_draw,
+       q.pool_tags  AS quiz_pool_tags,
+       qqq.question_sha1 AS question_sha1,
+       qqq.position      AS question_position,
+       qqq.tags          AS question_tags
+FROM quiz q
+         JOIN quiz_question_quiz qqq ON q.sha1 = qqq.quiz_sha1
+WHERE q.sha1 = ?;
 
 -- name: FindQuizSessionByUuid :many
 SELECT *
 FROM quiz_session_detail_view
 WHERE session_uuid = ?;
+
+-- name: FindQuizSessionsByUuids :many
+SELECT *
+FROM quiz_session_detail_view
+WHERE session_uuid IN (sqlc.slice('session_uuids'));
+
+-- name: CreateOrReplaceQuizTranslation :exec
+REPLACE INTO quiz_translation (quiz_sha1, lang, name, description)
+VALUES (?, ?, ?, ?);
+
+-- name: CreateOrReplaceQuestionTranslation :exec
+REPLACE INTO quiz_question_translation (quiz_sha1, lang, question_sha1, content, code, explanation)
+VALUES (?, ?, ?, ?, ?, ?);
+
+-- name: CreateOrReplaceAnswerTranslation :exec
+REPLACE INTO quiz_answer_translation (quiz_sha1, lang, question_sha1, answer_sha1, content, right_content, explanation)
+VALUES (?, ?, ?, ?, ?, ?, ?);
+
+-- name: FindQuizTranslations :many
+SELECT *
+FROM quiz_translation
+WHERE quiz_sha1 = ?;
+
+-- name: FindQuestionTranslations :many
+SELECT *
+FROM quiz_question_translation
+WHERE quiz_sha1 = ?;
+
+-- name: FindAnswerTranslations :many
+SELECT *
+FROM quiz_answer_translation
+WHERE quiz_sha1 = ?;
+
+-- name: FindActiveQuizTranslations :many
+SELECT qt.*
+FROM quiz_translation qt
+         JOIN quiz q ON q.sha1 = qt.quiz_sha1
+WHERE q.active = 1;
+
+-- name: FindSessionAnswerTranslations :many
+SELECT DISTINCT qat.answer_sha1, qat.content
+FROM session s
+         JOIN quiz_answer_translation qat ON qat.quiz_sha1 = s.quiz_sha1
+WHERE s.uuid = ?
+  AND qat.question_sha1 = ?;
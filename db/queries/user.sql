@@ -32,6 +32,11 @@ UPDATE user
 SET class_uuid = ?
 WHERE id = ?;
 
+-- name: UpdateUserLang :exec
+UPDATE user
+SET lang = ?
+WHERE id = ?;
+
 -- name: UpdateUserInfo :exec
 UPDATE user
 SET login   = ?,
@@ -0,0 +1,99 @@
+/*
+ * Copyright (c) 2023 Michaël COLL.
+ *
+ * Licensed under the Apache License, Version 2.0 (the "License");
+ * you may not use this file except in compliance with the License.
+ * You may obtain a copy of the License at
+ *
+ *      http://www.apache.org/licenses/LICENSE-2.0
+ *
+ * Unless required by applicable law or agreed to in writing, software
+ * distributed under the License is distributed on an "AS IS" BASIS,
+ * WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+ * See the License for the specific language governing permissions and
+ * limitations under the License.
+ */
+
+package domain
+
+import (
+	"fmt"
+	"io"
+	"strings"
+
+	"github.com/fatih/color"
+)
+
+type Severity int8
+
+const (
+	ErrorSeverity Severity = iota
+	WarningSeverity
+)
+
+func (s Severity) String() string {
+	if s == WarningSeverity {
+		return "warning"
+	}
+
+	return "error"
+}
+
+// Diagnostic is a problem found while parsing a quiz file. Line and column start at 1
+type Diagnostic struct {
+	// Source is the name of the quiz source of the file, only set when several sources are configured
+	Source   string
+	Filename string
+	Line     int
+	Column   int
+	Severity Severity
+	Message  string
+}
+
+func (d Diagnostic) String() string {
+	if d.Source != "" {
+		return fmt.Sprintf("[%s] %s:%d:%d: %s: %s", d.Source, d.Filename, d.Line, d.Column, d.Severity, d.Message)
+	}
+
+	return fmt.Sprintf("%s:%d:%d: %s: %s", d.Filename, d.Line, d.Column, d.Severity, d.Message)
+}
+
+// Diagnostics is the list of problems found in quiz files. It is returned as an error when one of
+// them is an error
+type Diagnostics []Diagnostic
+
+func (d Diagnostics) HasErrors() bool {
+	return d.Count(ErrorSeverity) > 0
+}
+
+// Count gives the number of diagnostics of the given severity
+func (d Diagnostics) Count(severity Severity) int {
+	count := 0
+	for _, diagnostic := range d {
+		if diagnostic.Severity == severity {
+			count++
+		}
+	}
+
+	return count
+}
+
+// Print writes the diagnostics one per line
+func (d Diagnostics) Print(w io.Writer) {
+	for _, diagnostic := range d {
+		if diagnostic.Severity == ErrorSeverity {
+			_, _ = fmt.Fprintf(w, "%s %s\n", color.RedString("✗"), diagnostic)
+		} else {
+			_, _ = fmt.Fprintf(w, "%s %s\n", color.HiYellowString("!"), diagnostic)
+		}
+	}
+}
+
+func (d Diagnostics) Error() string {
+	lines := make([]string, len(d))
+	for i, diagnostic := range d {
+		lines[i] = diagnostic.String()
+	}
+
+	return strings.Join(lines, "\n")
+}
@@ -38,13 +38,13 @@ func (_m *MockQuizRepository) EXPECT() *MockQuizRepository_Expecter {
 	return &MockQuizRepository_Expecter{mock: &_m.Mock}
 }
 
-// ActivateOnlyVersion provides a mock function with given fields: ctx, filename, version
-func (_m *MockQuizRepository) ActivateOnlyVersion(ctx context.Context, filename string, version int) error {
-	ret := _m.Called(ctx, filename, version)
+// ActivateOnlyVersion provides a mock function with given fields: ctx, source, filename, version
+func (_m *MockQuizRepository) ActivateOnlyVersion(ctx context.Context, source string, filename string, version int) error {
+	ret := _m.Called(ctx, source, filename, version)
 
 	var r0 error
-	if rf, ok := ret.Get(0).(func(context.Context, string, int) error); ok {
-		r0 = rf(ctx, filename, version)
+	if rf, ok := ret.Get(0).(func(context.Context, string, string, int) error); ok {
+		r0 = rf(ctx, source, filename, version)
 	} else {
 		r0 = ret.Error(0)
 	}
@@ -59,15 +59,16 @@ type MockQuizRepository_ActivateOnlyVersion_Call struct {
 
 // ActivateOnlyVersion is a helper method to define mock.On call
 //   - ctx context.Context
+//   - source string
 //   - filename string
 //   - version int
-func (_e *MockQuizRepository_Expecter) ActivateOnlyVersion(ctx interface{}, filename interface{}, version interface{}) *MockQuizRepository_ActivateOnlyVersion_Call {
-	return &MockQuizRepository_ActivateOnlyVersion_Call{Call: _e.mock.On("ActivateOnlyVersion", ctx, filename, version)}
+func (_e *MockQuizRepository_Expecter) ActivateOnlyVersion(ctx interface{}, source interface{}, filename interface{}, version interface{}) *MockQuizRepository_ActivateOnlyVersion_Call {
+	return &MockQuizRepository_ActivateOnlyVersion_Call{Call: _e.mock.On("ActivateOnlyVersion", ctx, source, filename, version)}
 }
 
-func (_c *MockQuizRepository_ActivateOnlyVersion_Call) Run(run func(ctx context.Context, filename string, version int)) *MockQuizRepository_ActivateOnlyVersion_Call {
+func (_c *MockQuizRepository_ActivateOnlyVersion_Call) Run(run func(ctx context.Context, source string, filename string, version int)) *MockQuizRepository_ActivateOnlyVersion_Call {
 	_c.Call.Run(func(args mock.Arguments) {
-		run(args[0].(context.Context), args[1].(string), args[2].(int))
+		run(args[0].(context.Context), args[1].(string), args[2].(string), args[3].(int))
 	})
 	return _c
 }
@@ -77,7 +78,7 @@ func (_c *MockQuizRepository_ActivateOnlyVersion_Call) Return(_a0 error) *MockQu
 	return _c
 }
 
-func (_c *MockQuizRepository_ActivateOnlyVersion_Call) RunAndReturn(run func(context.Context, string, int) error) *MockQuizRepository_ActivateOnlyVersion_Call {
+func (_c *MockQuizRepository_ActivateOnlyVersion_Call) RunAndReturn(run func(context.Context, string, string, int) error) *MockQuizRepository_ActivateOnlyVersion_Call {
 	_c.Call.Return(run)
 	return _c
 }
@@ -128,23 +129,159 @@ func (_c *MockQuizRepository_AddSessionAnswer_Call) RunAndReturn(run func(contex
 	return _c
 }
 
-// CountAllActive provides a mock function with given fields: ctx, userId
-func (_m *MockQuizRepository) CountAllActive(ctx context.Context, userId string) (uint32, error) {
-	ret := _m.Called(ctx, userId)
+// AddSessionOrderAnswer provides a mock function with given fields: ctx, sessionUuid, questionSha1, positions
+func (_m *MockQuizRepository) AddSessionOrderAnswer(ctx context.Context, sessionUuid uuid.UUID, questionSha1 string, positions map[string]int) error {
+	ret := _m.Called(ctx, sessionUuid, questionSha1, positions)
+
+	var r0 error
+	if rf, ok := ret.Get(0).(func(context.Context, uuid.UUID, string, map[string]int) error); ok {
+		r0 = rf(ctx, sessionUuid, questionSha1, positions)
+	} else {
+		r0 = ret.Error(0)
+	}
+
+	return r0
+}
+
+// MockQuizRepository_AddSessionOrderAnswer_Call is a *mock.Call that shadows Run/Return methods with type explicit version for method 'AddSessionOrderAnswer'
+type MockQuizRepository_AddSessionOrderAnswer_Call struct {
+	*mock.Call
+}
+
+// AddSessionOrderAnswer is a helper method to define mock.On call
+//   - ctx context.Context
+//   - sessionUuid uuid.UUID
+//   - questionSha1 string
+//   - positions map[string]int
+func (_e *MockQuizRepository_Expecter) AddSessionOrderAnswer(ctx interface{}, sessionUuid interface{}, questionSha1 interface{}, positions interface{}) *MockQuizRepository_AddSessionOrderAnswer_Call {
+	return &MockQuizRepository_AddSessionOrderAnswer_Call{Call: _e.mock.On("AddSessionOrderAnswer", ctx, sessionUuid, questionSha1, positions)}
+}
+
+func (_c *MockQuizRepository_AddSessionOrderAnswer_Call) Run(run func(ctx context.Context, sessionUuid uuid.UUID, questionSha1 string, positions map[string]int)) *MockQuizRepository_AddSessionOrderAnswer_Call {
+	_c.Call.Run(func(args mock.Arguments) {
+		run(args[0].(context.Context), args[1].(uuid.UUID), args[2].(string), args[3].(map[string]int))
+	})
+	return _c
+}
+
+func (_c *MockQuizRepository_AddSessionOrderAnswer_Call) Return(_a0 error) *MockQuizRepository_AddSessionOrderAnswer_Call {
+	_c.Call.Return(_a0)
+	return _c
+}
+
+func (_c *MockQuizRepository_AddSessionOrderAnswer_Call) RunAndReturn(run func(context.Context, uuid.UUID, string, map[string]int) error) *MockQuizRepository_AddSessionOrderAnswer_Call {
+	_c.Call.Return(run)
+	return _c
+}
+
+// AddSessionPairAnswer provides a mock function with given fields: ctx, sessionUuid, questionSha1, rights
+func (_m *MockQuizRepository) AddSessionPairAnswer(ctx context.Context, sessionUuid uuid.UUID, questionSha1 string, rights map[string]string) error {
+	ret := _m.Called(ctx, sessionUuid, questionSha1, rights)
+
+	var r0 error
+	if rf, ok := ret.Get(0).(func(context.Context, uuid.UUID, string, map[string]string) error); ok {
+		r0 = rf(ctx, sessionUuid, questionSha1, rights)
+	} else {
+		r0 = ret.Error(0)
+	}
+
+	return r0
+}
+
+// MockQuizRepository_AddSessionPairAnswer_Call is a *mock.Call that shadows Run/Return methods with type explicit version for method 'AddSessionPairAnswer'
+type MockQuizRepository_AddSessionPairAnswer_Call struct {
+	*mock.Call
+}
+
+// AddSessionPairAnswer is a helper method to define mock.On call
+//   - ctx context.Context
+//   - sessionUuid uuid.UUID
+//   - questionSha1 string
+//   - rights map[string]string
+func (_e *MockQuizRepository_Expecter) AddSessionPairAnswer(ctx interface{}, sessionUuid interface{}, questionSha1 interface{}, rights interface{}) *MockQuizRepository_AddSessionPairAnswer_Call {
+	return &MockQuizRepository_AddSessionPairAnswer_Call{Call: _e.mock.On("AddSessionPairAnswer", ctx, sessionUuid, questionSha1, rights)}
+}
+
+func (_c *MockQuizRepository_AddSessionPairAnswer_Call) Run(run func(ctx context.Context, sessionUuid uuid.UUID, questionSha1 string, rights map[string]string)) *MockQuizRepository_AddSessionPairAnswer_Call {
+	_c.Call.Run(func(args mock.Arguments) {
+		run(args[0].(context.Context), args[1].(uuid.UUID), args[2].(string), args[3].(map[string]string))
+	})
+	return _c
+}
+
+func (_c *MockQuizRepository_AddSessionPairAnswer_Call) Return(_a0 error) *MockQuizRepository_AddSessionPairAnswer_Call {
+	_c.Call.Return(_a0)
+	return _c
+}
+
+func (_c *MockQuizRepository_AddSessionPairAnswer_Call) RunAndReturn(run func(context.Context, uuid.UUID, string, map[string]string) error) *MockQuizRepository_AddSessionPairAnswer_Call {
+	_c.Call.Return(run)
+	return _c
+}
+
+// AddSessionTextAnswer provides a mock function with given fields: ctx, sessionUuid, questionSha1, answers, text
+func (_m *MockQuizRepository) AddSessionTextAnswer(ctx context.Context, sessionUuid uuid.UUID, questionSha1 string, answers map[string]bool, text string) error {
+	ret := _m.Called(ctx, sessionUuid, questionSha1, answers, text)
+
+	var r0 error
+	if rf, ok := ret.Get(0).(func(context.Context, uuid.UUID, string, map[string]bool, string) error); ok {
+		r0 = rf(ctx, sessionUuid, questionSha1, answers, text)
+	} else {
+		r0 = ret.Error(0)
+	}
+
+	return r0
+}
+
+// MockQuizRepository_AddSessionTextAnswer_Call is a *mock.Call that shadows Run/Return methods with type explicit version for method 'AddSessionTextAnswer'
+type MockQuizRepository_AddSessionTextAnswer_Call struct {
+	*mock.Call
+}
+
+// AddSessionTextAnswer is a helper method to define mock.On call
+//   - ctx context.Context
+//   - sessionUuid uuid.UUID
+//   - questionSha1 string
+//   - answers map[string]bool
+//   - text string
+func (_e *MockQuizRepository_Expecter) AddSessionTextAnswer(ctx interface{}, sessionUuid interface{}, questionSha1 interface{}, answers interface{}, text interface{}) *MockQuizRepository_AddSessionTextAnswer_Call {
+	return &MockQuizRepository_AddSessionTextAnswer_Call{Call: _e.mock.On("AddSessionTextAnswer", ctx, sessionUuid, questionSha1, answers, text)}
+}
+
+func (_c *MockQuizRepository_AddSessionTextAnswer_Call) Run(run func(ctx context.Context, sessionUuid uuid.UUID, questionSha1 string, answers map[string]bool, text string)) *MockQuizRepository_AddSessionTextAnswer_Call {
+	_c.Call.Run(func(args mock.Arguments) {
+		run(args[0].(context.Context), args[1].(uuid.UUID), args[2].(string), args[3].(map[string]bool), args[4].(string))
+	})
+	return _c
+}
+
+func (_c *MockQuizRepository_AddSessionTextAnswer_Call) Return(_a0 error) *MockQuizRepository_AddSessionTextAnswer_Call {
+	_c.Call.Return(_a0)
+	return _c
+}
+
+func (_c *MockQuizRepository_AddSessionTextAnswer_Call) RunAndReturn(run func(context.Context, uuid.UUID, string, map[string]bool, string) error) *MockQuizRepository_AddSessionTextAnswer_Call {
+	_c.Call.Return(run)
+	return _c
+}
+
+// CountAllActive provides a mock function with given fields: ctx, userId, category
+func (_m *MockQuizRepository) CountAllActive(ctx context.Context, userId string, category string) (uint32, error) {
+	ret := _m.Called(ctx, userId, category)
 
 	var r0 uint32
 	var r1 error
-	if rf, ok := ret.Get(0).(func(context.Context, string) (uint32, error)); ok {
-		return rf(ctx, userId)
+	if rf, ok := ret.Get(0).(func(context.Context, string, string) (uint32, error)); ok {
+		return rf(ctx, userId, category)
 	}
-	if rf, ok := ret.Get(0).(func(context.Context, string) uint32); ok {
-		r0 = rf(ctx, userId)
+	if rf, ok := ret.Get(0).(func(context.Context, string, string) uint32); ok {
+		r0 = rf(ctx, userId, category)
 	} else {
 		r0 = ret.Get(0).(uint32)
 	}
 
-	if rf, ok := ret.Get(1).(func(context.Context, string) error); ok {
-		r1 = rf(ctx, userId)
+	if rf, ok := ret.Get(1).(func(context.Context, string, string) error); ok {
+		r1 = rf(ctx, userId, category)
 	} else {
 		r1 = ret.Error(1)
 	}
@@ -160,13 +297,14 @@ type MockQuizRepository_CountAllActive_Call struct {
 // CountAllActive is a helper method to define mock.On call
 //   - ctx context.Context
 //   - userId string
-func (_e *MockQuizRepository_Expecter) CountAllActive(ctx interface{}, userId interface{}) *MockQuizRepository_CountAllActive_Call {
-	return &MockQuizRepository_CountAllActive_Call{Call: _e.mock.On("CountAllActive", ctx, userId)}
+//   - category string
+func (_e *MockQuizRepository_Expecter) CountAllActive(ctx interface{}, userId interface{}, category interface{}) *MockQuizRepository_CountAllActive_Call {
+	return &MockQuizRepository_CountAllActive_Call{Call: _e.mock.On("CountAllActive", ctx, userId, category)}
 }
 
-func (_c *MockQuizRepository_CountAllActive_Call) Run(run func(ctx context.Context, userId string)) *MockQuizRepository_CountAllActive_Call {
+func (_c *MockQuizRepository_CountAllActive_Call) Run(run func(ctx context.Context, userId string, category string)) *MockQuizRepository_CountAllActive_Call {
 	_c.Call.Run(func(args mock.Arguments) {
-		run(args[0].(context.Context), args[1].(string))
+		run(args[0].(context.Context), args[1].(string), args[2].(string))
 	})
 	return _c
 }
@@ -176,7 +314,7 @@ func (_c *MockQuizRepository_CountAllActive_Call) Return(_a0 uint32, _a1 error)
 	return _c
 }
 
-func (_c *MockQuizRepository_CountAllActive_Call) RunAndReturn(run func(context.Context, string) (uint32, error)) *MockQuizRepository_CountAllActive_Call {
+func (_c *MockQuizRepository_CountAllActive_Call) RunAndReturn(run func(context.Context, string, string) (uint32, error)) *MockQuizRepository_CountAllActive_Call {
 	_c.Call.Return(run)
 	return _c
 }
@@ -278,25 +416,79 @@ func (_c *MockQuizRepository_Create_Call) RunAndReturn(run func(context.Context,
 	return _c
 }
 
-// FindAllActive provides a mock function with given fields: ctx, userId, limit, offset
-func (_m *MockQuizRepository) FindAllActive(ctx context.Context, userId string, limit uint16, offset uint16) ([]*Quiz, error) {
-	ret := _m.Called(ctx, userId, limit, offset)
+// FindActiveQuizTranslations provides a mock function with given fields: ctx
+func (_m *MockQuizRepository) FindActiveQuizTranslations(ctx context.Context) (map[string]map[string]QuizTranslation, error) {
+	ret := _m.Called(ctx)
+
+	var r0 map[string]map[string]QuizTranslation
+	var r1 error
+	if rf, ok := ret.Get(0).(func(context.Context) (map[string]map[string]QuizTranslation, error)); ok {
+		return rf(ctx)
+	}
+	if rf, ok := ret.Get(0).(func(context.Context) map[string]map[string]QuizTranslation); ok {
+		r0 = rf(ctx)
+	} else {
+		if ret.Get(0) != nil {
+			r0 = ret.Get(0).(map[string]map[string]QuizTranslation)
+		}
+	}
+
+	if rf, ok := ret.Get(1).(func(context.Context) error); ok {
+		r1 = rf(ctx)
+	} else {
+		r1 = ret.Error(1)
+	}
+
+	return r0, r1
+}
+
+// MockQuizRepository_FindActiveQuizTranslations_Call is a *mock.Call that shadows Run/Return methods with type explicit version for method 'FindActiveQuizTranslations'
+type MockQuizRepository_FindActiveQuizTranslations_Call struct {
+	*mock.Call
+}
+
+// FindActiveQuizTranslations is a helper method to define mock.On call
+//   - ctx context.Context
+func (_e *MockQuizRepository_Expecter) FindActiveQuizTranslations(ctx interface{}) *MockQuizRepository_FindActiveQuizTranslations_Call {
+	return &MockQuizRepository_FindActiveQuizTranslations_Call{Call: _e.mock.On("FindActiveQuizTranslations", ctx)}
+}
+
+func (_c *MockQuizRepository_FindActiveQuizTranslations_Call) Run(run func(ctx context.Context)) *MockQuizRepository_FindActiveQuizTranslations_Call {
+	_c.Call.Run(func(args mock.Arguments) {
+		run(args[0].(context.Context))
+	})
+	return _c
+}
+
+func (_c *MockQuizRepository_FindActiveQuizTranslations_Call) Return(_a0 map[string]map[string]QuizTranslation, _a1 error) *MockQuizRepository_FindActiveQuizTranslations_Call {
+	_c.Call.Return(_a0, _a1)
+	return _c
+}
+
+func (_c *MockQuizRepository_FindActiveQuizTranslations_Call) RunAndReturn(run func(context.Context) (map[string]map[string]QuizTranslation, error)) *MockQuizRepository_FindActiveQuizTranslations_Call {
+	_c.Call.Return(run)
+	return _c
+}
+
+// FindAllActive provides a mock function with given fields: ctx, userId, category, limit, offset
+func (_m *MockQuizRepository) FindAllActive(ctx context.Context, userId string, category string, limit uint16, offset uint16) ([]*Quiz, error) {
+	ret := _m.Called(ctx, userId, category, limit, offset)
 
 	var r0 []*Quiz
 	var r1 error
-	if rf, ok := ret.Get(0).(func(context.Context, string, uint16, uint16) ([]*Quiz, error)); ok {
-		return rf(ctx, userId, limit, offset)
+	if rf, ok := ret.Get(0).(func(context.Context, string, string, uint16, uint16) ([]*Quiz, error)); ok {
+		return rf(ctx, userId, category, limit, offset)
 	}
-	if rf, ok := ret.Get(0).(func(context.Context, string, uint16, uint16) []*Quiz); ok {
-		r0 = rf(ctx, userId, limit, offset)
+	if rf, ok := ret.Get(0).(func(context.Context, string, string, uint16, uint16) []*Quiz); ok {
+		r0 = rf(ctx, userId, category, limit, offset)
 	} else {
 		if ret.Get(0) != nil {
 			r0 = ret.Get(0).([]*Quiz)
 		}
 	}
 
-	if rf, ok := ret.Get(1).(func(context.Context, string, uint16, uint16) error); ok {
-		r1 = rf(ctx, userId, limit, offset)
+	if rf, ok := ret.Get(1).(func(context.Context, string, string, uint16, uint16) error); ok {
+		r1 = rf(ctx, userId, category, limit, offset)
 	} else {
 		r1 = ret.Error(1)
 	}
@@ -312,15 +504,16 @@ type MockQuizRepository_FindAllActive_Call struct {
 // FindAllActive is a helper method to define mock.On call
 //   - ctx context.Context
 //   - userId string
+//   - category string
 //   - limit uint16
 //   - offset uint16
-func (_e *MockQuizRepository_Expecter) FindAllActive(ctx interface{}, userId interface{}, limit interface{}, offset interface{}) *MockQuizRepository_FindAllActive_Call {
-	return &MockQuizRepository_FindAllActive_Call{Call: _e.mock.On("FindAllActive", ctx, userId, limit, offset)}
+func (_e *MockQuizRepository_Expecter) FindAllActive(ctx interface{}, userId interface{}, category interface{}, limit interface{}, offset interface{}) *MockQuizRepository_FindAllActive_Call {
+	return &MockQuizRepository_FindAllActive_Call{Call: _e.mock.On("FindAllActive", ctx, userId, category, limit, offset)}
 }
 
-func (_c *MockQuizRepository_FindAllActive_Call) Run(run func(ctx context.Context, userId string, limit uint16, offset uint16)) *MockQuizRepository_FindAllActive_Call {
+func (_c *MockQuizRepository_FindAllActive_Call) Run(run func(ctx context.Context, userId string, category string, limit uint16, offset uint16)) *MockQuizRepository_FindAllActive_Call {
 	_c.Call.Run(func(args mock.Arguments) {
-		run(args[0].(context.Context), args[1].(string), args[2].(uint16), args[3].(uint16))
+		run(args[0].(context.Context), args[1].(string), args[2].(string), args[3].(uint16), args[4].(uint16))
 	})
 	return _c
 }
@@ -330,7 +523,7 @@ func (_c *MockQuizRepository_FindAllActive_Call) Return(_a0 []*Quiz, _a1 error)
 	return _c
 }
 
-func (_c *MockQuizRepository_FindAllActive_Call) RunAndReturn(run func(context.Context, string, uint16, uint16) ([]*Quiz, error)) *MockQuizRepository_FindAllActive_Call {
+func (_c *MockQuizRepository_FindAllActive_Call) RunAndReturn(run func(context.Context, string, string, uint16, uint16) ([]*Quiz, error)) *MockQuizRepository_FindAllActive_Call {
 	_c.Call.Return(run)
 	return _c
 }
@@ -451,6 +644,61 @@ func (_c *MockQuizRepository_FindAllSessions_Call) RunAndReturn(run func(context
 	return _c
 }
 
+// FindAssetBySha1 provides a mock function with given fields: ctx, sha1
+func (_m *MockQuizRepository) FindAssetBySha1(ctx context.Context, sha1 string) (*Asset, error) {
+	ret := _m.Called(ctx, sha1)
+
+	var r0 *Asset
+	var r1 error
+	if rf, ok := ret.Get(0).(func(context.Context, string) (*Asset, error)); ok {
+		return rf(ctx, sha1)
+	}
+	if rf, ok := ret.Get(0).(func(context.Context, string) *Asset); ok {
+		r0 = rf(ctx, sha1)
+	} else {
+		if ret.Get(0) != nil {
+			r0 = ret.Get(0).(*Asset)
+		}
+	}
+
+	if rf, ok := ret.Get(1).(func(context.Context, string) error); ok {
+		r1 = rf(ctx, sha1)
+	} else {
+		r1 = ret.Error(1)
+	}
+
+	return r0, r1
+}
+
+// MockQuizRepository_FindAssetBySha1_Call is a *mock.Call that shadows Run/Return methods with type explicit version for method 'FindAssetBySha1'
+type MockQuizRepository_FindAssetBySha1_Call struct {
+	*mock.Call
+}
+
+// FindAssetBySha1 is a helper method to define mock.On call
+//   - ctx context.Context
+//   - sha1 string
+func (_e *MockQuizRepository_Expecter) FindAssetBySha1(ctx interface{}, sha1 interface{}) *MockQuizRepository_FindAssetBySha1_Call {
+	return &MockQuizRepository_FindAssetBySha1_Call{Call: _e.mock.On("FindAssetBySha1", ctx, sha1)}
+}
+
+func (_c *MockQuizRepository_FindAssetBySha1_Call) Run(run func(ctx context.Context, sha1 string)) *MockQuizRepository_FindAssetBySha1_Call {
+	_c.Call.Run(func(args mock.Arguments) {
+		run(args[0].(context.Context), args[1].(string))
+	})
+	return _c
+}
+
+func (_c *MockQuizRepository_FindAssetBySha1_Call) Return(_a0 *Asset, _a1 error) *MockQuizRepository_FindAssetBySha1_Call {
+	_c.Call.Return(_a0, _a1)
+	return _c
+}
+
+func (_c *MockQuizRepository_FindAssetBySha1_Call) RunAndReturn(run func(context.Context, string) (*Asset, error)) *MockQuizRepository_FindAssetBySha1_Call {
+	_c.Call.Return(run)
+	return _c
+}
+
 // FindFullBySha1 provides a mock function with given fields: ctx, sha1, userId
 func (_m *MockQuizRepository) FindFullBySha1(ctx context.Context, sha1 string, userId string) (*Quiz, error) {
 	ret := _m.Called(ctx, sha1, userId)
@@ -507,25 +755,25 @@ func (_c *MockQuizRepository_FindFullBySha1_Call) RunAndReturn(run func(context.
 	return _c
 }
 
-// FindLatestVersionByFilename provides a mock function with given fields: ctx, filename
-func (_m *MockQuizRepository) FindLatestVersionByFilename(ctx context.Context, filename string) (*Quiz, error) {
-	ret := _m.Called(ctx, filename)
+// FindLatestVersionByFilename provides a mock function with given fields: ctx, source, filename
+func (_m *MockQuizRepository) FindLatestVersionByFilename(ctx context.Context, source string, filename string) (*Quiz, error) {
+	ret := _m.Called(ctx, source, filename)
 
 	var r0 *Quiz
 	var r1 error
-	if rf, ok := ret.Get(0).(func(context.Context, string) (*Quiz, error)); ok {
-		return rf(ctx, filename)
+	if rf, ok := ret.Get(0).(func(context.Context, string, string) (*Quiz, error)); ok {
+		return rf(ctx, source, filename)
 	}
-	if rf, ok := ret.Get(0).(func(context.Context, string) *Quiz); ok {
-		r0 = rf(ctx, filename)
+	if rf, ok := ret.Get(0).(func(context.Context, string, string) *Quiz); ok {
+		r0 = rf(ctx, source, filename)
 	} else {
 		if ret.Get(0) != nil {
 			r0 = ret.Get(0).(*Quiz)
 		}
 	}
 
-	if rf, ok := ret.Get(1).(func(context.Context, string) error); ok {
-		r1 = rf(ctx, filename)
+	if rf, ok := ret.Get(1).(func(context.Context, string, string) error); ok {
+		r1 = rf(ctx, source, filename)
 	} else {
 		r1 = ret.Error(1)
 	}
@@ -540,14 +788,15 @@ type MockQuizRepository_FindLatestVersionByFilename_Call struct {
 
 // FindLatestVersionByFilename is a helper method to define mock.On call
 //   - ctx context.Context
+//   - source string
 //   - filename string
-func (_e *MockQuizRepository_Expecter) FindLatestVersionByFilename(ctx interface{}, filename interface{}) *MockQuizRepository_FindLatestVersionByFilename_Call {
-	return &MockQuizRepository_FindLatestVersionByFilename_Call{Call: _e.mock.On("FindLatestVersionByFilename", ctx, filename)}
+func (_e *MockQuizRepository_Expecter) FindLatestVersionByFilename(ctx interface{}, source interface{}, filename interface{}) *MockQuizRepository_FindLatestVersionByFilename_Call {
+	return &MockQuizRepository_FindLatestVersionByFilename_Call{Call: _e.mock.On("FindLatestVersionByFilename", ctx, source, filename)}
 }
 
-func (_c *MockQuizRepository_FindLatestVersionByFilename_Call) Run(run func(ctx context.Context, filename string)) *MockQuizRepository_FindLatestVersionByFilename_Call {
+func (_c *MockQuizRepository_FindLatestVersionByFilename_Call) Run(run func(ctx context.Context, source string, filename string)) *MockQuizRepository_FindLatestVersionByFilename_Call {
 	_c.Call.Run(func(args mock.Arguments) {
-		run(args[0].(context.Context), args[1].(string))
+		run(args[0].(context.Context), args[1].(string), args[2].(string))
 	})
 	return _c
 }
@@ -557,7 +806,62 @@ func (_c *MockQuizRepository_FindLatestVersionByFilename_Call) Return(_a0 *Quiz,
 	return _c
 }
 
-func (_c *MockQuizRepository_FindLatestVersionByFilename_Call) RunAndReturn(run func(context.Context, string) (*Quiz, error)) *MockQuizRepository_FindLatestVersionByFilename_Call {
+func (_c *MockQuizRepository_FindLatestVersionByFilename_Call) RunAndReturn(run func(context.Context, string, string) (*Quiz, error)) *MockQuizRepository_FindLatestVersionByFilename_Call {
+	_c.Call.Return(run)
+	return _c
+}
+
+// FindPoolBySha1 provides a mock function with given fields: ctx, sha1
+func (_m *MockQuizRepository) FindPoolBySha1(ctx context.Context, sha1 string) (*Quiz, error) {
+	ret := _m.Called(ctx, sha1)
+
+	var r0 *Quiz
+	var r1 error
+	if rf, ok := ret.Get(0).(func(context.Context, string) (*Quiz, error)); ok {
+		return rf(ctx, sha1)
+	}
+	if rf, ok := ret.Get(0).(func(context.Context, string) *Quiz); ok {
+		r0 = rf(ctx, sha1)
+	} else {
+		if ret.Get(0) != nil {
+			r0 = ret.Get(0).(*Quiz)
+		}
+	}
+
+	if rf, ok := ret.Get(1).(func(context.Context, string) error); ok {
+		r1 = rf(ctx, sha1)
+	} else {
+		r1 = ret.Error(1)
+	}
+
+	return r0, r1
+}
+
+// MockQuizRepository_FindPoolBySha1_Call is a *mock.Call that shadows Run/Return methods with type explicit version for method 'FindPoolBySha1'
+type MockQuizRepository_FindPoolBySha1_Call struct {
+	*mock.Call
+}
+
+// FindPoolBySha1 is a helper method to define mock.On call
+//   - ctx context.Context
+//   - sha1 string
+func (_e *MockQuizRepository_Expecter) FindPoolBySha1(ctx interface{}, sha1 interface{}) *MockQuizRepository_FindPoolBySha1_Call {
+	return &MockQuizRepository_FindPoolBySha1_Call{Call: _e.mock.On("FindPoolBySha1", ctx, sha1)}
+}
+
+func (_c *MockQuizRepository_FindPoolBySha1_Call) Run(run func(ctx context.Context, sha1 string)) *MockQuizRepository_FindPoolBySha1_Call {
+	_c.Call.Run(func(args mock.Arguments) {
+		run(args[0].(context.Context), args[1].(string))
+	})
+	return _c
+}
+
+func (_c *MockQuizRepository_FindPoolBySha1_Call) Return(_a0 *Quiz, _a1 error) *MockQuizRepository_FindPoolBySha1_Call {
+	_c.Call.Return(_a0, _a1)
+	return _c
+}
+
+func (_c *MockQuizRepository_FindPoolBySha1_Call) RunAndReturn(run func(context.Context, string) (*Quiz, error)) *MockQuizRepository_FindPoolBySha1_Call {
 	_c.Call.Return(run)
 	return _c
 }
@@ -617,25 +921,247 @@ func (_c *MockQuizRepository_FindQuizSessionByUuid_Call) RunAndReturn(run func(c
 	return _c
 }
 
-// StartSession provides a mock function with given fields: ctx, userId, quizSha1
-func (_m *MockQuizRepository) StartSession(ctx context.Context, userId string, quizSha1 string) (uuid.UUID, error) {
-	ret := _m.Called(ctx, userId, quizSha1)
+// FindQuizSessionsByUuids provides a mock function with given fields: ctx, sessionUuids
+func (_m *MockQuizRepository) FindQuizSessionsByUuids(ctx context.Context, sessionUuids []uuid.UUID) (map[uuid.UUID]*QuizSessionDetail, error) {
+	ret := _m.Called(ctx, sessionUuids)
+
+	var r0 map[uuid.UUID]*QuizSessionDetail
+	var r1 error
+	if rf, ok := ret.Get(0).(func(context.Context, []uuid.UUID) (map[uuid.UUID]*QuizSessionDetail, error)); ok {
+		return rf(ctx, sessionUuids)
+	}
+	if rf, ok := ret.Get(0).(func(context.Context, []uuid.UUID) map[uuid.UUID]*QuizSessionDetail); ok {
+		r0 = rf(ctx, sessionUuids)
+	} else {
+		if ret.Get(0) != nil {
+			r0 = ret.Get(0).(map[uuid.UUID]*QuizSessionDetail)
+		}
+	}
+
+	if rf, ok := ret.Get(1).(func(context.Context, []uuid.UUID) error); ok {
+		r1 = rf(ctx, sessionUuids)
+	} else {
+		r1 = ret.Error(1)
+	}
+
+	return r0, r1
+}
+
+// MockQuizRepository_FindQuizSessionsByUuids_Call is a *mock.Call that shadows Run/Return methods with type explicit version for method 'FindQuizSessionsByUuids'
+type MockQuizRepository_FindQuizSessionsByUuids_Call struct {
+	*mock.Call
+}
+
+// FindQuizSessionsByUuids is a helper method to define mock.On call
+//   - ctx context.Context
+//   - sessionUuids []uuid.UUID
+func (_e *MockQuizRepository_Expecter) FindQuizSessionsByUuids(ctx interface{}, sessionUuids interface{}) *MockQuizRepository_FindQuizSessionsByUuids_Call {
+	return &MockQuizRepository_FindQuizSessionsByUuids_Call{Call: _e.mock.On("FindQuizSessionsByUuids", ctx, sessionUuids)}
+}
+
+func (_c *MockQuizRepository_FindQuizSessionsByUuids_Call) Run(run func(ctx context.Context, sessionUuids []uuid.UUID)) *MockQuizRepository_FindQuizSessionsByUuids_Call {
+	_c.Call.Run(func(args mock.Arguments) {
+		run(args[0].(context.Context), args[1].([]uuid.UUID))
+	})
+	return _c
+}
+
+func (_c *MockQuizRepository_FindQuizSessionsByUuids_Call) Return(_a0 map[uuid.UUID]*QuizSessionDetail, _a1 error) *MockQuizRepository_FindQuizSessionsByUuids_Call {
+	_c.Call.Return(_a0, _a1)
+	return _c
+}
+
+func (_c *MockQuizRepository_FindQuizSessionsByUuids_Call) RunAndReturn(run func(context.Context, []uuid.UUID) (map[uuid.UUID]*QuizSessionDetail, error)) *MockQuizRepository_FindQuizSessionsByUuids_Call {
+	_c.Call.Return(run)
+	return _c
+}
+
+// FindSessionAnswerTranslations provides a mock function with given fields: ctx, sessionUuid, questionSha1
+func (_m *MockQuizRepository) FindSessionAnswerTranslations(ctx context.Context, sessionUuid uuid.UUID, questionSha1 string) (map[string][]string, error) {
+	ret := _m.Called(ctx, sessionUuid, questionSha1)
+
+	var r0 map[string][]string
+	var r1 error
+	if rf, ok := ret.Get(0).(func(context.Context, uuid.UUID, string) (map[string][]string, error)); ok {
+		return rf(ctx, sessionUuid, questionSha1)
+	}
+	if rf, ok := ret.Get(0).(func(context.Context, uuid.UUID, string) map[string][]string); ok {
+		r0 = rf(ctx, sessionUuid, questionSha1)
+	} else {
+		if ret.Get(0) != nil {
+			r0 = ret.Get(0).(map[string][]string)
+		}
+	}
+
+	if rf, ok := ret.Get(1).(func(context.Context, uuid.UUID, string) error); ok {
+		r1 = rf(ctx, sessionUuid, questionSha1)
+	} else {
+		r1 = ret.Error(1)
+	}
+
+	return r0, r1
+}
+
+// MockQuizRepository_FindSessionAnswerTranslations_Call is a *mock.Call that shadows Run/Return methods with type explicit version for method 'FindSessionAnswerTranslations'
+type MockQuizRepository_FindSessionAnswerTranslations_Call struct {
+	*mock.Call
+}
+
+// FindSessionAnswerTranslations is a helper method to define mock.On call
+//   - ctx context.Context
+//   - sessionUuid uuid.UUID
+//   - questionSha1 string
+func (_e *MockQuizRepository_Expecter) FindSessionAnswerTranslations(ctx interface{}, sessionUuid interface{}, questionSha1 interface{}) *MockQuizRepository_FindSessionAnswerTranslations_Call {
+	return &MockQuizRepository_FindSessionAnswerTranslations_Call{Call: _e.mock.On("FindSessionAnswerTranslations", ctx, sessionUuid, questionSha1)}
+}
+
+func (_c *MockQuizRepository_FindSessionAnswerTranslations_Call) Run(run func(ctx context.Context, sessionUuid uuid.UUID, questionSha1 string)) *MockQuizRepository_FindSessionAnswerTranslations_Call {
+	_c.Call.Run(func(args mock.Arguments) {
+		run(args[0].(context.Context), args[1].(uuid.UUID), args[2].(string))
+	})
+	return _c
+}
+
+func (_c *MockQuizRepository_FindSessionAnswerTranslations_Call) Return(_a0 map[string][]string, _a1 error) *MockQuizRepository_FindSessionAnswerTranslations_Call {
+	_c.Call.Return(_a0, _a1)
+	return _c
+}
+
+func (_c *MockQuizRepository_FindSessionAnswerTranslations_Call) RunAndReturn(run func(context.Context, uuid.UUID, string) (map[string][]string, error)) *MockQuizRepository_FindSessionAnswerTranslations_Call {
+	_c.Call.Return(run)
+	return _c
+}
+
+// FindSessionQuestion provides a mock function with given fields: ctx, sessionUuid, questionSha1
+func (_m *MockQuizRepository) FindSessionQuestion(ctx context.Context, sessionUuid uuid.UUID, questionSha1 string) (*QuizQuestion, error) {
+	ret := _m.Called(ctx, sessionUuid, questionSha1)
+
+	var r0 *QuizQuestion
+	var r1 error
+	if rf, ok := ret.Get(0).(func(context.Context, uuid.UUID, string) (*QuizQuestion, error)); ok {
+		return rf(ctx, sessionUuid, questionSha1)
+	}
+	if rf, ok := ret.Get(0).(func(context.Context, uuid.UUID, string) *QuizQuestion); ok {
+		r0 = rf(ctx, sessionUuid, questionSha1)
+	} else {
+		if ret.Get(0) != nil {
+			r0 = ret.Get(0).(*QuizQuestion)
+		}
+	}
+
+	if rf, ok := ret.Get(1).(func(context.Context, uuid.UUID, string) error); ok {
+		r1 = rf(ctx, sessionUuid, questionSha1)
+	} else {
+		r1 = ret.Error(1)
+	}
+
+	return r0, r1
+}
+
+// MockQuizRepository_FindSessionQuestion_Call is a *mock.Call that shadows Run/Return methods with type explicit version for method 'FindSessionQuestion'
+type MockQuizRepository_FindSessionQuestion_Call struct {
+	*mock.Call
+}
+
+// FindSessionQuestion is a helper method to define mock.On call
+//   - ctx context.Context
+//   - sessionUuid uuid.UUID
+//   - questionSha1 string
+func (_e *MockQuizRepository_Expecter) FindSessionQuestion(ctx interface{}, sessionUuid interface{}, questionSha1 interface{}) *MockQuizRepository_FindSessionQuestion_Call {
+	return &MockQuizRepository_FindSessionQuestion_Call{Call: _e.mock.On("FindSessionQuestion", ctx, sessionUuid, questionSha1)}
+}
+
+func (_c *MockQuizRepository_FindSessionQuestion_Call) Run(run func(ctx context.Context, sessionUuid uuid.UUID, questionSha1 string)) *MockQuizRepository_FindSessionQuestion_Call {
+	_c.Call.Run(func(args mock.Arguments) {
+		run(args[0].(context.Context), args[1].(uuid.UUID), args[2].(string))
+	})
+	return _c
+}
+
+func (_c *MockQuizRepository_FindSessionQuestion_Call) Return(_a0 *QuizQuestion, _a1 error) *MockQuizRepository_FindSessionQuestion_Call {
+	_c.Call.Return(_a0, _a1)
+	return _c
+}
+
+func (_c *MockQuizRepository_FindSessionQuestion_Call) RunAndReturn(run func(context.Context, uuid.UUID, string) (*QuizQuestion, error)) *MockQuizRepository_FindSessionQuestion_Call {
+	_c.Call.Return(run)
+	return _c
+}
+
+// FindTranslations provides a mock function with given fields: ctx, quizSha1
+func (_m *MockQuizRepository) FindTranslations(ctx context.Context, quizSha1 string) (map[string]QuizTranslation, error) {
+	ret := _m.Called(ctx, quizSha1)
+
+	var r0 map[string]QuizTranslation
+	var r1 error
+	if rf, ok := ret.Get(0).(func(context.Context, string) (map[string]QuizTranslation, error)); ok {
+		return rf(ctx, quizSha1)
+	}
+	if rf, ok := ret.Get(0).(func(context.Context, string) map[string]QuizTranslation); ok {
+		r0 = rf(ctx, quizSha1)
+	} else {
+		if ret.Get(0) != nil {
+			r0 = ret.Get(0).(map[string]QuizTranslation)
+		}
+	}
+
+	if rf, ok := ret.Get(1).(func(context.Context, string) error); ok {
+		r1 = rf(ctx, quizSha1)
+	} else {
+		r1 = ret.Error(1)
+	}
+
+	return r0, r1
+}
+
+// MockQuizRepository_FindTranslations_Call is a *mock.Call that shadows Run/Return methods with type explicit version for method 'FindTranslations'
+type MockQuizRepository_FindTranslations_Call struct {
+	*mock.Call
+}
+
+// FindTranslations is a helper method to define mock.On call
+//   - ctx context.Context
+//   - quizSha1 string
+func (_e *MockQuizRepository_Expecter) FindTranslations(ctx interface{}, quizSha1 interface{}) *MockQuizRepository_FindTranslations_Call {
+	return &MockQuizRepository_FindTranslations_Call{Call: _e.mock.On("FindTranslations", ctx, quizSha1)}
+}
+
+func (_c *MockQuizRepository_FindTranslations_Call) Run(run func(ctx context.Context, quizSha1 string)) *MockQuizRepository_FindTranslations_Call {
+	_c.Call.Run(func(args mock.Arguments) {
+		run(args[0].(context.Context), args[1].(string))
+	})
+	return _c
+}
+
+func (_c *MockQuizRepository_FindTranslations_Call) Return(_a0 map[string]QuizTranslation, _a1 error) *MockQuizRepository_FindTranslations_Call {
+	_c.Call.Return(_a0, _a1)
+	return _c
+}
+
+func (_c *MockQuizRepository_FindTranslations_Call) RunAndReturn(run func(context.Context, string) (map[string]QuizTranslation, error)) *MockQuizRepository_FindTranslations_Call {
+	_c.Call.Return(run)
+	return _c
+}
+
+// StartSession provides a mock function with given fields: ctx, userId, quizSha1, seed, questionSha1s
+func (_m *MockQuizRepository) StartSession(ctx context.Context, userId string, quizSha1 string, seed int64, questionSha1s []string) (uuid.UUID, error) {
+	ret := _m.Called(ctx, userId, quizSha1, seed, questionSha1s)
 
 	var r0 uuid.UUID
 	var r1 error
-	if rf, ok := ret.Get(0).(func(context.Context, string, string) (uuid.UUID, error)); ok {
-		return rf(ctx, userId, quizSha1)
+	if rf, ok := ret.Get(0).(func(context.Context, string, string, int64, []string) (uuid.UUID, error)); ok {
+		return rf(ctx, userId, quizSha1, seed, questionSha1s)
 	}
-	if rf, ok := ret.Get(0).(func(context.Context, string, string) uuid.UUID); ok {
-		r0 = rf(ctx, userId, quizSha1)
+	if rf, ok := ret.Get(0).(func(context.Context, string, string, int64, []string) uuid.UUID); ok {
+		r0 = rf(ctx, userId, quizSha1, seed, questionSha1s)
 	} else {
 		if ret.Get(0) != nil {
 			r0 = ret.Get(0).(uuid.UUID)
 		}
 	}
 
-	if rf, ok := ret.Get(1).(func(context.Context, string, string) error); ok {
-		r1 = rf(ctx, userId, quizSha1)
+	if rf, ok := ret.Get(1).(func(context.Context, string, string, int64, []string) error); ok {
+		r1 = rf(ctx, userId, quizSha1, seed, questionSha1s)
 	} else {
 		r1 = ret.Error(1)
 	}
@@ -652,13 +1178,15 @@ type MockQuizRepository_StartSession_Call struct {
 //   - ctx context.Context
 //   - userId string
 //   - quizSha1 string
-func (_e *MockQuizRepository_Expecter) StartSession(ctx interface{}, userId interface{}, quizSha1 interface{}) *MockQuizRepository_StartSession_Call {
-	return &MockQuizRepository_StartSession_Call{Call: _e.mock.On("StartSession", ctx, userId, quizSha1)}
+//   - seed int64
+//   - questionSha1s []string
+func (_e *MockQuizRepository_Expecter) StartSession(ctx interface{}, userId interface{}, quizSha1 interface{}, seed interface{}, questionSha1s interface{}) *MockQuizRepository_StartSession_Call {
+	return &MockQuizRepository_StartSession_Call{Call: _e.mock.On("StartSession", ctx, userId, quizSha1, seed, questionSha1s)}
 }
 
-func (_c *MockQuizRepository_StartSession_Call) Run(run func(ctx context.Context, userId string, quizSha1 string)) *MockQuizRepository_StartSession_Call {
+func (_c *MockQuizRepository_StartSession_Call) Run(run func(ctx context.Context, userId string, quizSha1 string, seed int64, questionSha1s []string)) *MockQuizRepository_StartSession_Call {
 	_c.Call.Run(func(args mock.Arguments) {
-		run(args[0].(context.Context), args[1].(string), args[2].(string))
+		run(args[0].(context.Context), args[1].(string), args[2].(string), args[3].(int64), args[4].([]string))
 	})
 	return _c
 }
@@ -668,7 +1196,7 @@ func (_c *MockQuizRepository_StartSession_Call) Return(_a0 uuid.UUID, _a1 error)
 	return _c
 }
 
-func (_c *MockQuizRepository_StartSession_Call) RunAndReturn(run func(context.Context, string, string) (uuid.UUID, error)) *MockQuizRepository_StartSession_Call {
+func (_c *MockQuizRepository_StartSession_Call) RunAndReturn(run func(context.Context, string, string, int64, []string) (uuid.UUID, error)) *MockQuizRepository_StartSession_Call {
 	_c.Call.Return(run)
 	return _c
 }
@@ -376,6 +376,50 @@ func (_c *MockUserRepository_UpdateUserInfo_Call) RunAndReturn(run func(context.
 	return _c
 }
 
+// UpdateUserLang provides a mock function with given fields: ctx, userId, lang
+func (_m *MockUserRepository) UpdateUserLang(ctx context.Context, userId string, lang string) error {
+	ret := _m.Called(ctx, userId, lang)
+
+	var r0 error
+	if rf, ok := ret.Get(0).(func(context.Context, string, string) error); ok {
+		r0 = rf(ctx, userId, lang)
+	} else {
+		r0 = ret.Error(0)
+	}
+
+	return r0
+}
+
+// MockUserRepository_UpdateUserLang_Call is a *mock.Call that shadows Run/Return methods with type explicit version for method 'UpdateUserLang'
+type MockUserRepository_UpdateUserLang_Call struct {
+	*mock.Call
+}
+
+// UpdateUserLang is a helper method to define mock.On call
+//   - ctx context.Context
+//   - userId string
+//   - lang string
+func (_e *MockUserRepository_Expecter) UpdateUserLang(ctx interface{}, userId interface{}, lang interface{}) *MockUserRepository_UpdateUserLang_Call {
+	return &MockUserRepository_UpdateUserLang_Call{Call: _e.mock.On("UpdateUserLang", ctx, userId, lang)}
+}
+
+func (_c *MockUserRepository_UpdateUserLang_Call) Run(run func(ctx context.Context, userId string, lang string)) *MockUserRepository_UpdateUserLang_Call {
+	_c.Call.Run(func(args mock.Arguments) {
+		run(args[0].(context.Context), args[1].(string), args[2].(string))
+	})
+	return _c
+}
+
+func (_c *MockUserRepository_UpdateUserLang_Call) Return(_a0 error) *MockUserRepository_UpdateUserLang_Call {
+	_c.Call.Return(_a0)
+	return _c
+}
+
+func (_c *MockUserRepository_UpdateUserLang_Call) RunAndReturn(run func(context.Context, string, string) error) *MockUserRepository_UpdateUserLang_Call {
+	_c.Call.Return(run)
+	return _c
+}
+
 // UpdateUserRole provides a mock function with given fields: ctx, userId, role
 func (_m *MockUserRepository) UpdateUserRole(ctx context.Context, userId string, role Role) error {
 	ret := _m.Called(ctx, userId, role)
@@ -17,6 +17,8 @@
 package domain
 
 import (
+	"math"
+
 	"github.com/google/uuid"
 )
 
@@ -28,16 +30,95 @@ type QuizInfos interface {
 type Quiz struct {
 	Sha1 string
 
-	Filename  string
+	// Source is the name of the quiz source the file comes from, the filenames being unique by source
+	Source   string
+	Filename string
+	// Category is the folder of the quiz file in the repository, like networking/tcp
+	Category  string
 	Name      string
 	Version   int
 	CreatedAt string
 	Active    bool
 	Duration  int
+	Metadata  QuizMetadata
 	Questions map[string]QuizQuestion
 	Classes   map[uuid.UUID]string
+	Assets    map[string]*Asset
+	// Translations are the texts of the quiz in other languages than Metadata.Lang, by language
+	Translations map[string]QuizTranslation
+	// Langs are the languages the quiz is available in, set when it is translated
+	Langs []string
+}
+
+// QuizTranslation holds the texts of a quiz in another language. The questions and the answers are
+// the ones of the quiz, by sha1, so that the sessions and their statistics are shared by all the
+// languages
+type QuizTranslation struct {
+	Name        string
+	Description string
+	Questions   map[string]QuestionTranslation
+}
+
+// QuestionTranslation holds the texts of a question in another language
+type QuestionTranslation struct {
+	Content     string
+	Code        string
+	Explanation string
+	Answers     map[string]AnswerTranslation
+}
+
+// AnswerTranslation holds the texts of an answer in another language
+type AnswerTranslation struct {
+	Content     string
+	Right       string
+	Explanation string
+}
+
+// Asset is a file of the quiz repository referenced by a quiz, addressed by the sha1 of its content
+type Asset struct {
+	Sha1        string
+	Filename    string
+	ContentType string
+	Content     []byte
 }
 
+// QuizMetadata holds the optional settings declared in the front-matter of a quiz file
+type QuizMetadata struct {
+	Description      string
+	Tags             []string
+	Author           string
+	PassMark         int
+	ShuffleQuestions bool
+	ShuffleAnswers   bool
+	MaxAttempts      int
+	Scoring          ScoringStrategy
+	Pool             QuizPool
+	// Lang is the language of the texts of the quiz
+	Lang string
+	// Id links the files holding the translations of a same quiz
+	Id string
+}
+
+// QuizPool tells how many questions are drawn for each session among the questions of the quiz,
+// only the questions having one of the tags are drawn when some are given. Every question is asked
+// when Draw is 0
+type QuizPool struct {
+	Draw int
+	Tags []string
+}
+
+// ScoringStrategy tells how the answers of a question are turned into points
+type ScoringStrategy int8
+
+const (
+	// AllOrNothing gives the points of a question only when it is entirely right
+	AllOrNothing ScoringStrategy = 0
+	// Proportional gives the share of the points matching the share of good answers given
+	Proportional ScoringStrategy = 1
+	// NegativeMarking is Proportional where each wrong answer given also removes its share of points
+	NegativeMarking ScoringStrategy = 2
+)
+
 func (q *Quiz) GetSha1NameAndDuration() (string, string, int) {
 	return q.Sha1, q.Name, q.Duration
 }
@@ -46,27 +127,91 @@ func (q *Quiz) GetQuestions() map[string]QuizQuestion {
 	return q.Questions
 }
 
+type QuestionKind int8
+
+const (
+	Choice      QuestionKind = 0
+	ShortAnswer QuestionKind = 1
+	Numeric     QuestionKind = 2
+	Ordering    QuestionKind = 3
+	Matching    QuestionKind = 4
+)
+
 type QuizQuestion struct {
 	Sha1 string
+	// Id follows the question across the versions of the quiz. It is given by the '(id: <id>)' marker
+	// of the question and defaults to its sha1, so that a question without id is a new question each
+	// time its content changes
+	Id string
 
+	Kind         QuestionKind
 	Content      string
 	Code         string
 	CodeLanguage string
 	Position     int
 	Answers      map[string]QuizQuestionAnswer
+	// Text is the answer typed by the user on a ShortAnswer or a Numeric question
+	Text string
+	// PartialCredit gives a point for each item of an Ordering question put at the right position (or each
+	// right pair of a Matching question) instead of a single point when the whole question is right
+	PartialCredit bool
+	// Points is the weight of the question in the quiz score
+	Points int
+	// Score is the number of points earned on the question, only set once the session is over
+	Score float64
+	// Explanation tells why the answers are right, it is only revealed once the session is over
+	Explanation string
+	// RightItems are the right-hand items (by sha1) the user can pair with the answers of a Matching question
+	RightItems map[string]string
+	// Tags are used to restrict the questions drawn by the pool of the quiz
+	Tags []string
 }
 
+type AnswerMatch int8
+
+const (
+	NoMatch              AnswerMatch = 0
+	ExactMatch           AnswerMatch = 1
+	CaseInsensitiveMatch AnswerMatch = 2
+	RegexMatch           AnswerMatch = 3
+	ToleranceMatch       AnswerMatch = 4
+)
+
 type QuizQuestionAnswer struct {
 	Sha1 string
 
 	Content string
 	Checked bool
 	Valid   bool
+	Match   AnswerMatch
+	// Position is the expected position of an Ordering question item
+	Position int
+	// AnsweredPosition is the position given by the user to an Ordering question item
+	AnsweredPosition int
+	// Right is the right-hand item expected to be paired with this answer on a Matching question
+	Right     string
+	RightSha1 string
+	// AnsweredRight is the right-hand item paired with this answer by the user
+	AnsweredRight string
+	// Explanation tells why this answer is right or wrong, it is only revealed once the session is over
+	Explanation string
+	// Ordinal is the place of the answer in the list shown to the user, starting at 1
+	Ordinal int
+	// Translations are the contents of a ShortAnswer answer in the other languages of its quizzes,
+	// they are accepted as well
+	Translations []string
 }
 
 type SyncStats struct {
-	Created int
-	Updated int
+	Created     int
+	Updated     int
+	Diagnostics Diagnostics
+}
+
+// LintReport holds the diagnostics of the quiz files checked by Lint
+type LintReport struct {
+	Files       int
+	Diagnostics Diagnostics
 }
 
 type Role int8
@@ -88,6 +233,8 @@ type User struct {
 	Active  bool
 	Role    Role
 	Class   *Class
+	// Lang is the language the user prefers the quizzes in
+	Lang string
 }
 
 type TokenProvenance int8
@@ -108,8 +255,19 @@ type AccessToken struct {
 }
 
 type SessionResult struct {
-	GoodAnswer  int
-	TotalAnswer int
+	GoodAnswer     int
+	TotalAnswer    int
+	PointsEarned   float64
+	PointsPossible int
+}
+
+// Percentage returns the points earned in percent of the points possible, rounded to 2 decimals
+func (r *SessionResult) Percentage() float64 {
+	if r.PointsPossible == 0 {
+		return 0
+	}
+
+	return math.Round(r.PointsEarned*10000/float64(r.PointsPossible)) / 100
 }
 
 type Session struct {
@@ -158,10 +316,17 @@ type QuizSessionDetail struct {
 	UserId       string
 	RemainingSec int
 	Result       *SessionResult
-	QuizSha1     string
-	Name         string
-	QuizDuration int
-	Questions    map[string]QuizQuestion
+	// Seed is the seed used to draw the questions of the session and to shuffle them
+	Seed             int64
+	QuizSha1         string
+	Name             string
+	QuizDuration     int
+	Scoring          ScoringStrategy
+	ShuffleQuestions bool
+	ShuffleAnswers   bool
+	Questions        map[string]QuizQuestion
+	// Lang is the language of the texts of the session
+	Lang string
 }
 
 func (qd *QuizSessionDetail) GetSha1NameAndDuration() (string, string, int) {
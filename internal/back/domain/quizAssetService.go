@@ -0,0 +1,194 @@
+/*
+ * Copyright (c) 2023 Michaël COLL.
+ *
+ * Licensed under the Apache License, Version 2.0 (the "License");
+ * you may not use this file except in compliance with the License.
+ * You may obtain a copy of the License at
+ *
+ *      http://www.apache.org/licenses/LICENSE-2.0
+ *
+ * Unless required by applicable law or agreed to in writing, software
+ * distributed under the License is distributed on an "AS IS" BASIS,
+ * WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+ * See the License for the specific language governing permissions and
+ * limitations under the License.
+ */
+
+package domain
+
+import (
+	"context"
+	"fmt"
+	"mime"
+	"net/http"
+	"net/url"
+	"path"
+	"path/filepath"
+	"regexp"
+	"sort"
+	"strings"
+
+	"github.com/go-git/go-billy/v5"
+)
+
+// AssetPath is the path the assets are served from
+const AssetPath = "/api/v1/asset/"
+
+// assetLinkRegexp matches the markdown links and images : the text, the target and the
+// optional title with the closing parenthesis
+var assetLinkRegexp = regexp.MustCompile(`(!?\[[^\]]*]\()([^)\s]+)((?:\s+"[^"]*")?\))`)
+
+func (s *QuizService) FindAssetBySha1(ctx context.Context, sha1 string) (*Asset, error) {
+	asset, err := s.r.FindAssetBySha1(ctx, sha1)
+	if err != nil {
+		return nil, err
+	}
+	if asset == nil {
+		return nil, Errorf(NotFound, "asset with sha1: %s was not found.", sha1)
+	}
+
+	return asset, nil
+}
+
+// collectAssets reads the files referenced by relative links in the questions, answers and
+// explanations of the quiz and rewrites the links to point at the asset endpoint. The links that
+// can not be read are reported at their position in the content of the quiz file. The links of the
+// included files are relative to these files, the segments telling where each part of the expanded
+// content comes from.
+// As the assets are not part of the quiz file, their sha1 are added to the one of the quiz so that
+// changing an image creates a new version of the quiz
+func collectAssets(fs billy.Filesystem, quiz *Quiz, content string, segments []contentSegment) Diagnostics {
+	quiz.Assets = map[string]*Asset{}
+	p := &quizParser{filename: quiz.Filename, content: content, segments: segments}
+
+	rewrite := func(content string) string {
+		return p.rewriteAssetLinks(fs, quiz, content)
+	}
+
+	for sha1, question := range quiz.Questions {
+		question.Content = rewrite(question.Content)
+		question.Explanation = rewrite(question.Explanation)
+
+		for answerSha1, answer := range question.Answers {
+			answer.Content = rewrite(answer.Content)
+			answer.Explanation = rewrite(answer.Explanation)
+			question.Answers[answerSha1] = answer
+		}
+
+		quiz.Questions[sha1] = question
+	}
+
+	if len(quiz.Assets) > 0 {
+		sha1s := []string{quiz.Sha1}
+		for sha1 := range quiz.Assets {
+			sha1s = append(sha1s, sha1)
+		}
+		sort.Strings(sha1s[1:])
+
+		quiz.Sha1 = getSha1(strings.Join(sha1s, "\n"))
+	}
+
+	return p.diagnostics
+}
+
+// rewriteAssetLinks replaces the relative links of the given content by links to the asset endpoint
+// and adds the targeted files to the assets of the quiz
+func (p *quizParser) rewriteAssetLinks(fs billy.Filesystem, quiz *Quiz, content string) string {
+	return assetLinkRegexp.ReplaceAllStringFunc(content, func(link string) string {
+		groups := assetLinkRegexp.FindStringSubmatch(link)
+		target := groups[2]
+		if !isRelativeLink(target) {
+			return link
+		}
+
+		offset := offsetOf(p.content, 0, link)
+		filename, _, _ := p.locate(offset)
+
+		asset, err := readAsset(fs, path.Join(path.Dir(filename), target))
+		if err != nil {
+			p.errorf(offset, "%v", err)
+			return link
+		}
+		quiz.Assets[asset.Sha1] = asset
+
+		return groups[1] + AssetPath + asset.Sha1 + groups[3]
+	})
+}
+
+// relativeAssetLinks replaces the links to the asset endpoint of the given content by links to the
+// files of the repository the assets were read from, relative to the quiz file. The links to an
+// unknown asset are kept
+func (s *QuizService) relativeAssetLinks(ctx context.Context, filename string, content string) (string, error) {
+	var err error
+	content = assetLinkRegexp.ReplaceAllStringFunc(content, func(link string) string {
+		groups := assetLinkRegexp.FindStringSubmatch(link)
+		sha1, found := strings.CutPrefix(groups[2], AssetPath)
+		if !found || err != nil {
+			return link
+		}
+
+		asset, findErr := s.r.FindAssetBySha1(ctx, sha1)
+		if findErr != nil {
+			err = findErr
+			return link
+		}
+		if asset == nil {
+			return link
+		}
+
+		return groups[1] + relativeLink(path.Dir(filename), asset.Filename) + groups[3]
+	})
+
+	return content, err
+}
+
+// relativeLink gives the link to a file of the repository from the given folder
+func relativeLink(dir string, filename string) string {
+	rel, err := filepath.Rel(filepath.FromSlash(dir), filepath.FromSlash(filename))
+	if err != nil {
+		return filename
+	}
+
+	return (&url.URL{Path: filepath.ToSlash(rel)}).EscapedPath()
+}
+
+// isRelativeLink tells if a link targets a file of the quiz repository
+func isRelativeLink(target string) bool {
+	if strings.Contains(target, "://") {
+		return false
+	}
+
+	for _, prefix := range []string{"/", "#", "mailto:", "data:"} {
+		if strings.HasPrefix(target, prefix) {
+			return false
+		}
+	}
+
+	return true
+}
+
+// readAsset reads a file of the quiz repository
+func readAsset(fs billy.Filesystem, filename string) (*Asset, error) {
+	unescaped, err := url.PathUnescape(filename)
+	if err != nil {
+		return nil, fmt.Errorf("invalid asset link %s : %w", filename, err)
+	}
+	filename = unescaped
+
+	content, err := readFileContent(fs, filename)
+	if err != nil {
+		return nil, fmt.Errorf("can't read asset %s : %w", filename, err)
+	}
+
+	contentType := mime.TypeByExtension(path.Ext(filename))
+	if contentType == "" {
+		contentType = http.DetectContentType([]byte(content))
+	}
+
+	return &Asset{
+		Sha1:        getSha1(content),
+		Filename:    filename,
+		ContentType: contentType,
+		Content:     []byte(content),
+	}, nil
+}
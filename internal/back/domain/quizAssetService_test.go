@@ -0,0 +1,118 @@
+/*
+ * Copyright (c) 2023 Michaël COLL.
+ *
+ * Licensed under the Apache License, Version 2.0 (the "License");
+ * you may not use this file except in compliance with the License.
+ * You may obtain a copy of the License at
+ *
+ *      http://www.apache.org/licenses/LICENSE-2.0
+ *
+ * Unless required by applicable law or agreed to in writing, software
+ * distributed under the License is distributed on an "AS IS" BASIS,
+ * WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+ * See the License for the specific language governing permissions and
+ * limitations under the License.
+ */
+
+package domain
+
+import (
+	"context"
+	"testing"
+
+	"github.com/go-git/go-billy/v5"
+	"github.com/go-git/go-billy/v5/memfs"
+	"github.com/go-git/go-billy/v5/util"
+	"github.com/stretchr/testify/assert"
+)
+
+func assetFs(t *testing.T) billy.Filesystem {
+	fs := memfs.New()
+	if err := util.WriteFile(fs, "images/hulk.png", []byte("hulk"), 0644); err != nil {
+		assert.Fail(t, "Can't write asset", "%v", err)
+	}
+	if err := util.WriteFile(fs, "images/thor hammer.svg", []byte("<svg/>"), 0644); err != nil {
+		assert.Fail(t, "Can't write asset", "%v", err)
+	}
+
+	return fs
+}
+
+func Test_collectAssets(t *testing.T) {
+	hulk := getSha1("hulk")
+	hammer := getSha1("<svg/>")
+
+	quiz := &Quiz{
+		Sha1:     "quiz",
+		Filename: "marvel-universe.quiz.md",
+		Questions: map[string]QuizQuestion{
+			"q1": {
+				Sha1:        "q1",
+				Content:     "Who is this ?\n\n![The Hulk](images/hulk.png \"Hulk\")",
+				Explanation: "See [the comics](https://www.marvel.com) or [this page](#hulk)",
+				Answers: map[string]QuizQuestionAnswer{
+					"a1": {Sha1: "a1", Content: "![hammer](images/thor%20hammer.svg)"},
+					"a2": {Sha1: "a2", Content: "Bruce Banner", Explanation: "Yes ![again](./images/hulk.png)"},
+				},
+			},
+		},
+	}
+
+	diagnostics := collectAssets(assetFs(t), quiz, "", nil)
+	assert.Empty(t, diagnostics)
+
+	question := quiz.Questions["q1"]
+	assert.Equal(t, "Who is this ?\n\n![The Hulk](/api/v1/asset/"+hulk+" \"Hulk\")", question.Content)
+	assert.Equal(t, "See [the comics](https://www.marvel.com) or [this page](#hulk)", question.Explanation)
+	assert.Equal(t, "![hammer](/api/v1/asset/"+hammer+")", question.Answers["a1"].Content)
+	assert.Equal(t, "Yes ![again](/api/v1/asset/"+hulk+")", question.Answers["a2"].Explanation)
+
+	assert.Len(t, quiz.Assets, 2)
+	assert.Equal(t, &Asset{Sha1: hulk, Filename: "images/hulk.png", ContentType: "image/png", Content: []byte("hulk")}, quiz.Assets[hulk])
+	assert.Equal(t, "image/svg+xml", quiz.Assets[hammer].ContentType)
+	assert.NotEqual(t, "quiz", quiz.Sha1)
+}
+
+func Test_collectAssets_missing_file(t *testing.T) {
+	quiz := &Quiz{
+		Sha1:     "quiz",
+		Filename: "marvel-universe.quiz.md",
+		Questions: map[string]QuizQuestion{
+			"q1": {Sha1: "q1", Content: "![Iron Man](images/iron-man.png)"},
+		},
+	}
+
+	diagnostics := collectAssets(assetFs(t), quiz, "# Avengers (duration: 1min)\n\n![Iron Man](images/iron-man.png)\n", nil)
+	assert.True(t, diagnostics.HasErrors())
+	assert.Equal(t, 3, diagnostics[0].Line)
+	assert.Equal(t, 1, diagnostics[0].Column)
+	assert.Equal(t, "marvel-universe.quiz.md", diagnostics[0].Filename)
+}
+
+func Test_collectAssets_without_assets(t *testing.T) {
+	quiz := &Quiz{
+		Sha1:     "quiz",
+		Filename: "marvel-universe.quiz.md",
+		Questions: map[string]QuizQuestion{
+			"q1": {Sha1: "q1", Content: "Who is Iron Man ?"},
+		},
+	}
+
+	assert.Empty(t, collectAssets(assetFs(t), quiz, "", nil))
+	assert.Empty(t, quiz.Assets)
+	assert.Equal(t, "quiz", quiz.Sha1)
+}
+
+func TestQuizService_FindAssetBySha1_not_found(t *testing.T) {
+
+	mockQuizRepository := NewMockQuizRepository(t)
+
+	s := NewQuizService(mockQuizRepository)
+
+	mockQuizRepository.On("FindAssetBySha1", context.Background(), "unknown").Return(nil, nil)
+
+	_, err := s.FindAssetBySha1(context.Background(), "unknown")
+	assert.Error(t, err)
+
+	mockQuizRepository.AssertExpectations(t)
+}
@@ -0,0 +1,257 @@
+/*
+ * Copyright (c) 2023 Michaël COLL.
+ *
+ * Licensed under the Apache License, Version 2.0 (the "License");
+ * you may not use this file except in compliance with the License.
+ * You may obtain a copy of the License at
+ *
+ *      http://www.apache.org/licenses/LICENSE-2.0
+ *
+ * Unless required by applicable law or agreed to in writing, software
+ * distributed under the License is distributed on an "AS IS" BASIS,
+ * WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+ * See the License for the specific language governing permissions and
+ * limitations under the License.
+ */
+
+package domain
+
+import (
+	"context"
+	"fmt"
+	"sort"
+	"strings"
+
+	"go.yaml.in/yaml/v3"
+)
+
+// ExportFormat is a format a stored quiz can be exported to
+type ExportFormat int8
+
+const (
+	// MarkdownExport is the format of the quiz files
+	MarkdownExport ExportFormat = 0
+	// GiftExport is the Moodle GIFT text format
+	GiftExport ExportFormat = 1
+	// QtiExport is an IMS QTI 2.1 content package
+	QtiExport ExportFormat = 2
+)
+
+// QuizExport is a quiz written in an export format
+type QuizExport struct {
+	Filename    string
+	ContentType string
+	Content     []byte
+}
+
+// ParseExportFormat reads the name of an export format (markdown, gift or qti), markdown being the
+// default
+func ParseExportFormat(format string) (ExportFormat, error) {
+	switch format {
+	case "", "markdown":
+		return MarkdownExport, nil
+	case "gift":
+		return GiftExport, nil
+	case "qti":
+		return QtiExport, nil
+	default:
+		return 0, Errorf(InvalidArgument, "unknown export format %s, it must be markdown, gift or qti", format)
+	}
+}
+
+// ExportQuiz writes a stored version of a quiz, with its explanations, in the given format. Only the
+// markdown format holds the translations of the quiz, its assets being linked by their path in the
+// repository so that the file can be put back next to them
+func (s *QuizService) ExportQuiz(ctx context.Context, sha1 string, format ExportFormat) (*QuizExport, error) {
+	quiz, err := s.r.FindFullBySha1(ctx, sha1, "")
+	if err != nil {
+		return nil, err
+	}
+	if format == MarkdownExport {
+		if quiz.Translations, err = s.r.FindTranslations(ctx, sha1); err != nil {
+			return nil, err
+		}
+	}
+
+	stem := strings.TrimSuffix(quiz.Filename, ".quiz.md")
+	switch format {
+	case MarkdownExport:
+		content, err := s.relativeAssetLinks(ctx, quiz.Filename, writeQuizFile(quiz))
+		if err != nil {
+			return nil, err
+		}
+		return &QuizExport{
+			Filename:    stem + ".quiz.md",
+			ContentType: "text/markdown; charset=utf-8",
+			Content:     []byte(content),
+		}, nil
+	case GiftExport:
+		return &QuizExport{
+			Filename:    stem + ".gift",
+			ContentType: "text/plain; charset=utf-8",
+			Content:     []byte(writeGift(quiz)),
+		}, nil
+	case QtiExport:
+		content, err := writeQtiPackage(quiz)
+		if err != nil {
+			return nil, err
+		}
+		return &QuizExport{
+			Filename:    stem + "-qti.zip",
+			ContentType: "application/zip",
+			Content:     content,
+		}, nil
+	default:
+		return nil, Errorf(InvalidArgument, "unknown export format %d", format)
+	}
+}
+
+// writeQuizFile writes a quiz in the format of the quiz files, its translations being written as
+// translation blocks. Parsing the result gives back the same quiz, the question sha1s being kept
+// when the questions were written in the same layout
+func writeQuizFile(quiz *Quiz) string {
+	var sb strings.Builder
+	sb.WriteString(writeFrontMatter(quiz))
+	fmt.Fprintf(&sb, "# %s (duration: %dmin)\n", quiz.Name, quiz.Duration/60)
+
+	langs := make([]string, 0, len(quiz.Translations))
+	for lang := range quiz.Translations {
+		langs = append(langs, lang)
+	}
+	sort.Strings(langs)
+
+	for i, question := range sortedQuestions(quiz.Questions) {
+		if i > 0 {
+			sb.WriteString("\n---\n")
+		}
+
+		sb.WriteString("\n")
+		writeQuestion(&sb, question, true)
+
+		for _, lang := range langs {
+			if translation, found := quiz.Translations[lang].Questions[question.Sha1]; found {
+				sb.WriteString("\n:::" + lang + "\n")
+				writeQuestion(&sb, question.translate(translation), false)
+				sb.WriteString(":::\n")
+			}
+		}
+	}
+
+	return sb.String()
+}
+
+// writeQuestion writes a question with its answers, the markers giving its points, partial credit,
+// tags and id being left out of translations
+func writeQuestion(sb *strings.Builder, question QuizQuestion, markers bool) {
+	// a '---' line would split the question
+	sb.WriteString(strings.ReplaceAll(question.Content, "\n---\n", "\n- - -\n"))
+	if markers && question.Points > 1 {
+		fmt.Fprintf(sb, " (points: %d)", question.Points)
+	}
+	if markers && question.PartialCredit {
+		sb.WriteString(" (partial credit)")
+	}
+	if markers && len(question.Tags) > 0 {
+		fmt.Fprintf(sb, " (tags: %s)", strings.Join(question.Tags, ", "))
+	}
+	if markers && question.Id != "" && question.Id != question.Sha1 {
+		fmt.Fprintf(sb, " (id: %s)", question.Id)
+	}
+	sb.WriteString("\n")
+
+	if question.Code != "" {
+		fmt.Fprintf(sb, "```%s\n%s\n```\n", question.CodeLanguage, question.Code)
+	}
+
+	for _, sha1 := range sortedAnswerSha1s(question.Answers) {
+		answer := question.Answers[sha1]
+		sb.WriteString(writeAnswer(question.Kind, answer) + "\n")
+		for _, line := range strings.Split(answer.Explanation, "\n") {
+			if line != "" {
+				sb.WriteString("  > " + line + "\n")
+			}
+		}
+	}
+
+	if question.Explanation != "" {
+		sb.WriteString("> Explanation: " + strings.ReplaceAll(question.Explanation, "\n", "\n> ") + "\n")
+	}
+}
+
+// writeFrontMatter writes the metadata of a quiz and the translations of its name as the
+// front-matter of its file, nothing being written when the quiz has none
+func writeFrontMatter(quiz *Quiz) string {
+	metadata := quiz.Metadata
+	fm := frontMatter{
+		Description:      metadata.Description,
+		Tags:             metadata.Tags,
+		Author:           metadata.Author,
+		PassMark:         metadata.PassMark,
+		ShuffleQuestions: metadata.ShuffleQuestions,
+		ShuffleAnswers:   metadata.ShuffleAnswers,
+		MaxAttempts:      metadata.MaxAttempts,
+		Pool:             frontMatterPool{Draw: metadata.Pool.Draw, Tags: metadata.Pool.Tags},
+		Lang:             metadata.Lang,
+		Id:               metadata.Id,
+	}
+	for name, scoring := range scoringMapping {
+		if scoring == metadata.Scoring && name != "all-or-nothing" {
+			fm.Scoring = name
+		}
+	}
+	for lang, translation := range quiz.Translations {
+		if translation.Name != "" || translation.Description != "" {
+			if fm.Translations == nil {
+				fm.Translations = map[string]frontMatterTranslation{}
+			}
+			fm.Translations[lang] = frontMatterTranslation{Name: translation.Name, Description: translation.Description}
+		}
+	}
+
+	out, err := yaml.Marshal(fm)
+	if err != nil || string(out) == "{}\n" {
+		return ""
+	}
+
+	return frontMatterDelimiter + string(out) + frontMatterDelimiter
+}
+
+// writeAnswer writes an answer as a line of the quiz file
+func writeAnswer(kind QuestionKind, answer QuizQuestionAnswer) string {
+	content := strings.ReplaceAll(answer.Content, "\n", " ")
+
+	switch {
+	case kind == Numeric:
+		return "= " + content
+	case kind == Ordering:
+		return fmt.Sprintf("%d. %s", answer.Position, content)
+	case kind == Matching:
+		return fmt.Sprintf("- %s -> %s", content, answer.Right)
+	case kind == ShortAnswer && answer.Match == ExactMatch:
+		return "- [=] " + content
+	case kind == ShortAnswer && answer.Match == RegexMatch:
+		return "- [/] " + content
+	case kind == ShortAnswer:
+		return "- [~] " + content
+	case answer.Valid:
+		return "- [x] " + content
+	default:
+		return "- [ ] " + content
+	}
+}
+
+// sortedQuestions gives the questions in the order of the quiz file
+func sortedQuestions(questions map[string]QuizQuestion) []QuizQuestion {
+	sorted := make([]QuizQuestion, 0, len(questions))
+	for _, question := range questions {
+		sorted = append(sorted, question)
+	}
+	sort.Slice(sorted, func(i, j int) bool {
+		if sorted[i].Position != sorted[j].Position {
+			return sorted[i].Position < sorted[j].Position
+		}
+		return sorted[i].Sha1 < sorted[j].Sha1
+	})
+
+	return sorted
+}
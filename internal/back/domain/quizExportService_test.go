@@ -0,0 +1,283 @@
+/*
+ * Copyright (c) 2023 Michaël COLL.
+ *
+ * Licensed under the Apache License, Version 2.0 (the "License");
+ * you may not use this file except in compliance with the License.
+ * You may obtain a copy of the License at
+ *
+ *      http://www.apache.org/licenses/LICENSE-2.0
+ *
+ * Unless required by applicable law or agreed to in writing, software
+ * distributed under the License is distributed on an "AS IS" BASIS,
+ * WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+ * See the License for the specific language governing permissions and
+ * limitations under the License.
+ */
+
+package domain
+
+import (
+	"archive/zip"
+	"bytes"
+	"context"
+	"io"
+	"os"
+	"testing"
+
+	"github.com/go-git/go-billy/v5/util"
+	"github.com/stretchr/testify/assert"
+)
+
+const exportContent = `---
+description: The Avengers quiz
+tags: [marvel, avengers]
+author: Nick Fury
+pass-mark: 60
+scoring: partial
+shuffle-answers: true
+max-attempts: 2
+---
+# Avengers (duration: 5min)
+
+Who are **Avengers** ? (points: 2) (tags: team) (id: avengers)
+- [x] Thor
+  > The god of thunder
+- [x] Hulk
+- [ ] Thanos
+> Explanation: The first Avengers.
+> Thanos is a villain.
+
+---
+
+What does this print ?
+` + "```go\nfmt.Println(\"Avengers\")\n```" + `
+- [=] Avengers
+- [~] avengers
+- [/] ^Aveng.*
+
+---
+
+In which year was Captain America created ?
+= 1941 ± 1
+
+---
+
+Sort the movies by release date (partial credit)
+1. Iron Man
+2. The Avengers
+3. Endgame
+
+---
+
+Pair the heroes (points: 3) (partial credit)
+- Thor -> Asgard
+- Hulk -> Earth
+`
+
+// normalizedQuiz keeps what a quiz file defines, leaving out the sha1 and the filename
+func normalizedQuiz(quiz *Quiz) Quiz {
+	return Quiz{
+		Name:      quiz.Name,
+		Duration:  quiz.Duration,
+		Metadata:  quiz.Metadata,
+		Questions: quiz.Questions,
+	}
+}
+
+func TestWriteQuizFile_round_trip(t *testing.T) {
+	s := NewQuizService(nil)
+
+	sources := map[string]string{"avengers.quiz.md": exportContent}
+	for _, filename := range []string{"marvel-universe.quiz.md", "video-games.quiz.md"} {
+		content, err := os.ReadFile("../../../" + filename)
+		if err != nil {
+			assert.Fail(t, "Can't read quiz file", "%v", err)
+		}
+		sources[filename] = string(content)
+	}
+
+	for filename, content := range sources {
+		quiz, err := s.Parse(filename, content)
+		if err != nil {
+			assert.Fail(t, "Can't parse quiz", "%s : %v", filename, err)
+			continue
+		}
+
+		exported := writeQuizFile(quiz)
+		reparsed, diagnostics := s.ParseWithDiagnostics(filename, exported)
+		assert.Empty(t, diagnostics, filename)
+		if reparsed == nil {
+			continue
+		}
+
+		assert.Equal(t, normalizedQuiz(quiz), normalizedQuiz(reparsed), filename)
+		assert.Equal(t, exported, writeQuizFile(reparsed), filename)
+	}
+}
+
+func TestWriteQuizFile(t *testing.T) {
+	s := NewQuizService(nil)
+
+	quiz, err := s.Parse("avengers.quiz.md", exportContent)
+	if err != nil {
+		assert.Fail(t, "Can't parse quiz", "%v", err)
+	}
+
+	exported := writeQuizFile(quiz)
+
+	assert.Contains(t, exported, "# Avengers (duration: 5min)\n")
+	assert.Contains(t, exported, "scoring: partial\n")
+	assert.Contains(t, exported, "Who are **Avengers** ? (points: 2) (tags: team) (id: avengers)\n- [x] Thor\n  > The god of thunder\n")
+	assert.Contains(t, exported, "> Explanation: The first Avengers.\n> Thanos is a villain.\n")
+	assert.Contains(t, exported, "Pair the heroes (points: 3) (partial credit)\n- Thor -> Asgard\n")
+}
+
+func TestWriteGift(t *testing.T) {
+	s := NewQuizService(nil)
+
+	quiz, err := s.Parse("avengers.quiz.md", exportContent)
+	if err != nil {
+		assert.Fail(t, "Can't parse quiz", "%v", err)
+	}
+
+	gift := writeGift(quiz)
+
+	assert.Contains(t, gift, "::Question 1::[markdown]Who are **Avengers** ? {\n~%50%Thor#The god of thunder\n~%50%Hulk\n~Thanos\n")
+	assert.Contains(t, gift, "####The first Avengers.\\nThanos is a villain.\n}")
+	assert.Contains(t, gift, "=Avengers\n=avengers\n}")
+	assert.NotContains(t, gift, "Aveng.*")
+	assert.Contains(t, gift, "{\n#\n=1941:1\n}")
+	assert.Contains(t, gift, "=Thor -> Asgard\n")
+	assert.Contains(t, gift, "// question 4 can not be exported to GIFT")
+
+	converted, err := s.ConvertQuiz("avengers.gift", gift, GiftFormat, "Avengers", 5)
+	if err != nil {
+		assert.Fail(t, "Can't convert exported quiz", "%v", err)
+	}
+	assert.Equal(t, 3, converted.Questions)
+}
+
+func TestWriteQtiPackage(t *testing.T) {
+	s := NewQuizService(nil)
+
+	quiz, err := s.Parse("avengers.quiz.md", exportContent)
+	if err != nil {
+		assert.Fail(t, "Can't parse quiz", "%v", err)
+	}
+
+	content, err := writeQtiPackage(quiz)
+	if err != nil {
+		assert.Fail(t, "Can't write QTI package", "%v", err)
+	}
+
+	archive, err := zip.NewReader(bytes.NewReader(content), int64(len(content)))
+	if err != nil {
+		assert.Fail(t, "Can't read QTI package", "%v", err)
+	}
+
+	files := map[string]string{}
+	for _, file := range archive.File {
+		r, _ := file.Open()
+		b, _ := io.ReadAll(r)
+		files[file.Name] = string(b)
+	}
+
+	assert.Len(t, files, 7)
+	assert.Contains(t, files["imsmanifest.xml"], `<resource identifier="question-5" type="imsqti_item_xmlv2p1" href="question-5.xml">`)
+	assert.Contains(t, files["test.xml"], `<timeLimits maxTime="300"/>`)
+	assert.Contains(t, files["test.xml"], `<weight identifier="WEIGHT" value="3"/>`)
+	assert.Contains(t, files["question-1.xml"], `<choiceInteraction responseIdentifier="RESPONSE" shuffle="true" maxChoices="0">`)
+	assert.Contains(t, files["question-1.xml"], `<value>A1</value><value>A2</value>`)
+	assert.Contains(t, files["question-2.xml"], `<mapEntry mapKey="Avengers" mappedValue="1" caseSensitive="true"/><mapEntry mapKey="avengers" mappedValue="1" caseSensitive="false"/>`)
+	assert.Contains(t, files["question-3.xml"], `<equal toleranceMode="absolute" tolerance="1 1">`)
+	assert.Contains(t, files["question-4.xml"], `<orderInteraction`)
+	assert.Contains(t, files["question-5.xml"], `<value>A1 R1</value><value>A2 R2</value>`)
+}
+
+func TestQuizService_ExportQuiz(t *testing.T) {
+	mockQuizRepository := NewMockQuizRepository(t)
+
+	s := NewQuizService(mockQuizRepository)
+
+	quiz, err := s.Parse("avengers.quiz.md", exportContent)
+	if err != nil {
+		assert.Fail(t, "Can't parse quiz", "%v", err)
+	}
+
+	mockQuizRepository.On("FindFullBySha1", context.Background(), "sha1", "").Return(quiz, nil)
+
+	export, err := s.ExportQuiz(context.Background(), "sha1", GiftExport)
+	if err != nil {
+		assert.Fail(t, "Can't export quiz", "%v", err)
+	}
+
+	assert.Equal(t, "avengers.gift", export.Filename)
+	assert.Equal(t, "text/plain; charset=utf-8", export.ContentType)
+
+	_, err = ParseExportFormat("pdf")
+	assert.Error(t, err)
+
+	mockQuizRepository.AssertExpectations(t)
+}
+
+func TestQuizService_ExportQuiz_markdown_round_trip(t *testing.T) {
+	mockQuizRepository := NewMockQuizRepository(t)
+
+	s := NewQuizService(mockQuizRepository)
+
+	fs := assetFs(t)
+	content := `---
+id: avengers
+---
+# Avengers (duration: 5min)
+
+Who is this ? (id: hulk)
+
+![The Hulk](../images/hulk.png)
+- [x] Hulk
+- [ ] Thor
+  > He holds ![a hammer](../images/thor%20hammer.svg)
+`
+	if err := util.WriteFile(fs, "marvel/avengers.quiz.md", []byte(content), 0644); err != nil {
+		assert.Fail(t, "Can't write quiz file", "%v", err)
+	}
+
+	quiz, diagnostics, err := s.parseQuizFile(fs, "marvel/avengers.quiz.md")
+	if err != nil || quiz == nil {
+		assert.Fail(t, "Can't parse quiz", "%v %v", err, diagnostics)
+		return
+	}
+
+	mockQuizRepository.On("FindFullBySha1", context.Background(), "sha1", "").Return(quiz, nil)
+	mockQuizRepository.On("FindTranslations", context.Background(), "sha1").Return(nil, nil)
+	for sha1, asset := range quiz.Assets {
+		mockQuizRepository.On("FindAssetBySha1", context.Background(), sha1).Return(asset, nil)
+	}
+
+	export, err := s.ExportQuiz(context.Background(), "sha1", MarkdownExport)
+	if err != nil {
+		assert.Fail(t, "Can't export quiz", "%v", err)
+		return
+	}
+
+	exported := string(export.Content)
+	assert.Contains(t, exported, "id: avengers\n")
+	assert.Contains(t, exported, "Who is this ? (id: hulk)\n")
+	assert.Contains(t, exported, "![The Hulk](../images/hulk.png)")
+	assert.Contains(t, exported, "![a hammer](../images/thor%20hammer.svg)")
+
+	if err := util.WriteFile(fs, "marvel/avengers.quiz.md", export.Content, 0644); err != nil {
+		assert.Fail(t, "Can't write quiz file", "%v", err)
+	}
+
+	reimported, diagnostics, err := s.parseQuizFile(fs, "marvel/avengers.quiz.md")
+	if err != nil || reimported == nil {
+		assert.Fail(t, "Can't parse exported quiz", "%v %v", err, diagnostics)
+		return
+	}
+
+	assert.Equal(t, normalizedQuiz(quiz), normalizedQuiz(reimported))
+	assert.Equal(t, quiz.Assets, reimported.Assets)
+
+	mockQuizRepository.AssertExpectations(t)
+}
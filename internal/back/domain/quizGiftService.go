@@ -0,0 +1,509 @@
+/*
+ * Copyright (c) 2023 Michaël COLL.
+ *
+ * Licensed under the Apache License, Version 2.0 (the "License");
+ * you may not use this file except in compliance with the License.
+ * You may obtain a copy of the License at
+ *
+ *      http://www.apache.org/licenses/LICENSE-2.0
+ *
+ * Unless required by applicable law or agreed to in writing, software
+ * distributed under the License is distributed on an "AS IS" BASIS,
+ * WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+ * See the License for the specific language governing permissions and
+ * limitations under the License.
+ */
+
+package domain
+
+import (
+	"fmt"
+	"math"
+	"regexp"
+	"strconv"
+	"strings"
+	"unicode"
+)
+
+var giftCategoryRegexp = regexp.MustCompile(`^\$CATEGORY:\s*(.*)$`)
+var giftFormatRegexp = regexp.MustCompile(`^\[(html|moodle|plain|markdown)]`)
+var giftWeightRegexp = regexp.MustCompile(`^%(-?[0-9.]+)%`)
+var giftBooleanRegexp = regexp.MustCompile(`^(?i:T|TRUE|F|FALSE)$`)
+var giftEscaper = strings.NewReplacer(`\`, `\\`, "~", `\~`, "=", `\=`, "#", `\#`, "{", `\{`, "}", `\}`, ":", `\:`, "\n", `\n`)
+var giftEscapeReplacer = strings.NewReplacer(`\\`, `\`, `\:`, ":", `\~`, "~", `\=`, "=", `\#`, "#", `\{`, "{", `\}`, "}", `\n`, "\n")
+
+// giftChunk is the text of a question of a GIFT file, starting at the given offset of the file
+type giftChunk struct {
+	text     string
+	offset   int
+	category string
+}
+
+// giftAnswer is an answer of a GIFT question, marked '=' when it is right and '~' when it is wrong
+type giftAnswer struct {
+	marker   byte
+	weight   float64
+	text     string
+	feedback string
+}
+
+// readGift reads the questions of a GIFT file and gives the name of its first category. The
+// questions of a category are tagged with the last level of the category
+func readGift(filename string, content string) ([]QuizQuestion, string, Diagnostics) {
+	p := &quizParser{filename: filename, content: content}
+
+	var questions []QuizQuestion
+	firstCategory := ""
+	for _, chunk := range splitGiftQuestions(content) {
+		if firstCategory == "" {
+			firstCategory = categoryTag(chunk.category)
+		}
+
+		if question, ok := p.readGiftQuestion(chunk); ok {
+			questions = append(questions, question)
+		}
+	}
+
+	return questions, firstCategory, p.diagnostics
+}
+
+// splitGiftQuestions splits a GIFT file on the blank lines outside of the answer blocks, leaving
+// out the comments and the category lines
+func splitGiftQuestions(content string) []giftChunk {
+	var chunks []giftChunk
+	var current strings.Builder
+	start, depth, offset := -1, 0, 0
+	category := ""
+
+	flush := func() {
+		if start >= 0 && strings.TrimSpace(current.String()) != "" {
+			chunks = append(chunks, giftChunk{text: current.String(), offset: start, category: category})
+		}
+		current.Reset()
+		start = -1
+	}
+
+	for _, line := range strings.SplitAfter(content, "\n") {
+		lineOffset := offset
+		offset += len(line)
+		trimmed := strings.TrimSpace(line)
+
+		if strings.HasPrefix(trimmed, "//") {
+			continue
+		}
+		if depth == 0 && trimmed == "" {
+			flush()
+			continue
+		}
+		if subMatch := giftCategoryRegexp.FindStringSubmatch(trimmed); depth == 0 && subMatch != nil {
+			flush()
+			category = subMatch[1]
+			continue
+		}
+
+		if start == -1 {
+			start = lineOffset
+		}
+		current.WriteString(line)
+		depth += strings.Count(removeGiftEscapes(line), "{") - strings.Count(removeGiftEscapes(line), "}")
+	}
+	flush()
+
+	return chunks
+}
+
+// readGiftQuestion converts a GIFT question, the questions that can not be converted are reported
+func (p *quizParser) readGiftQuestion(chunk giftChunk) (QuizQuestion, bool) {
+	offset := contentStart(chunk.text, chunk.offset)
+	text := strings.TrimSpace(chunk.text)
+
+	if strings.HasPrefix(text, "::") {
+		if end := indexUnescaped(text[2:], "::"); end >= 0 {
+			text = strings.TrimSpace(text[end+4:])
+		}
+	}
+	isHtml := false
+	if subMatch := giftFormatRegexp.FindStringSubmatch(text); subMatch != nil {
+		isHtml = subMatch[1] == "html"
+		text = text[len(subMatch[0]):]
+	}
+
+	open := indexUnescaped(text, "{")
+	if open == -1 {
+		p.warnf(offset, "description can not be converted, it has no answer")
+		return QuizQuestion{}, false
+	}
+	end := indexUnescaped(text[open:], "}")
+	if end == -1 {
+		p.warnf(offset, "question can not be converted, its answers are not closed by '}'")
+		return QuizQuestion{}, false
+	}
+	end += open
+
+	// the text following the answers makes a missing word question
+	content := strings.TrimSpace(text[:open])
+	if after := strings.TrimSpace(text[end+1:]); after != "" {
+		content += " _____"
+		if !unicode.IsPunct(rune(after[0])) {
+			content += " "
+		}
+		content += after
+	}
+
+	question := QuizQuestion{Content: giftText(content, isHtml), Points: 1}
+	if tag := categoryTag(chunk.category); tag != "" {
+		question.Tags = []string{tag}
+	}
+
+	block := strings.TrimSpace(text[open+1 : end])
+	if i := indexUnescaped(block, "####"); i >= 0 {
+		question.Explanation = giftText(block[i+4:], isHtml)
+		block = strings.TrimSpace(block[:i])
+	}
+
+	var answers []QuizQuestionAnswer
+	switch {
+	case block == "":
+		p.warnf(offset, "essay question can not be converted")
+		return QuizQuestion{}, false
+	case strings.HasPrefix(block, "#"):
+		question.Kind = Numeric
+		answers = p.readGiftNumericAnswers(block[1:], offset)
+	case giftBooleanRegexp.MatchString(strings.TrimSpace(splitUnescaped(block, "#")[0])):
+		question.Kind = Choice
+		answers = readGiftBooleanAnswers(block, isHtml)
+	default:
+		question.Kind, answers = p.readGiftAnswers(block, isHtml, offset)
+	}
+
+	if len(answers) == 0 {
+		return QuizQuestion{}, false
+	}
+	question.Answers = importedAnswers(answers)
+
+	return question, true
+}
+
+// readGiftBooleanAnswers converts a true/false question to a choice between 'True' and 'False'. The
+// first feedback is shown on a wrong answer and the second one on a right answer
+func readGiftBooleanAnswers(block string, isHtml bool) []QuizQuestionAnswer {
+	parts := splitUnescaped(block, "#")
+	right := strings.HasPrefix(strings.ToUpper(strings.TrimSpace(parts[0])), "T")
+
+	var wrongFeedback, rightFeedback string
+	if len(parts) > 1 {
+		wrongFeedback = giftText(parts[1], isHtml)
+	}
+	if len(parts) > 2 {
+		rightFeedback = giftText(parts[2], isHtml)
+	}
+
+	feedback := func(valid bool) string {
+		if valid {
+			return rightFeedback
+		}
+		return wrongFeedback
+	}
+
+	return []QuizQuestionAnswer{
+		{Content: "True", Valid: right, Explanation: feedback(right)},
+		{Content: "False", Valid: !right, Explanation: feedback(!right)},
+	}
+}
+
+// readGiftAnswers converts the answers of a multiple choice question, or of a short answer question
+// when none of them is wrong
+func (p *quizParser) readGiftAnswers(block string, isHtml bool, offset int) (QuestionKind, []QuizQuestionAnswer) {
+	giftAnswers := parseGiftAnswers(block)
+
+	kind := ShortAnswer
+	for _, answer := range giftAnswers {
+		if strings.Contains(removeGiftEscapes(answer.text), "->") {
+			p.warnf(offset, "matching question can not be converted")
+			return kind, nil
+		}
+		if answer.marker == '~' {
+			kind = Choice
+		}
+	}
+
+	var answers []QuizQuestionAnswer
+	for _, answer := range giftAnswers {
+		content := giftText(answer.text, isHtml)
+		if kind == Choice {
+			answers = append(answers, QuizQuestionAnswer{
+				Content:     content,
+				Valid:       answer.weight > 0,
+				Explanation: giftText(answer.feedback, isHtml),
+			})
+			continue
+		}
+
+		if answer.weight < 100 {
+			p.warnf(offset, "answer '%s' gives partial credit, it can not be converted", content)
+			continue
+		}
+		imported := wildcardAnswer(content, false)
+		imported.Explanation = giftText(answer.feedback, isHtml)
+		answers = append(answers, imported)
+	}
+
+	if len(answers) == 0 {
+		p.warnf(offset, "question has no answer that can be converted")
+	}
+
+	return kind, answers
+}
+
+// readGiftNumericAnswers converts the answers of a numeric question written '<value>',
+// '<value>:<tolerance>' or '<min>..<max>'
+func (p *quizParser) readGiftNumericAnswers(block string, offset int) []QuizQuestionAnswer {
+	giftAnswers := []giftAnswer{{marker: '=', weight: 100, text: block}}
+	if indexUnescaped(block, "=") >= 0 {
+		giftAnswers = parseGiftAnswers(block)
+	} else if parts := splitUnescaped(block, "#"); len(parts) > 1 {
+		giftAnswers[0].text, giftAnswers[0].feedback = parts[0], strings.Join(parts[1:], "#")
+	}
+
+	var answers []QuizQuestionAnswer
+	for _, answer := range giftAnswers {
+		text := strings.TrimSpace(answer.text)
+		if answer.marker != '=' || answer.weight < 100 {
+			p.warnf(offset, "answer '%s' gives partial credit, it can not be converted", text)
+			continue
+		}
+
+		content, ok := numericRange(text)
+		if !ok {
+			p.warnf(offset, "answer '%s' is not a valid number, it can not be converted", text)
+			continue
+		}
+		answers = append(answers, QuizQuestionAnswer{
+			Content:     content,
+			Valid:       true,
+			Match:       ToleranceMatch,
+			Explanation: giftText(answer.feedback, false),
+		})
+	}
+
+	if len(answers) == 0 {
+		p.warnf(offset, "question has no answer that can be converted")
+	}
+
+	return answers
+}
+
+// numericRange writes a GIFT numeric answer the way the quiz files do
+func numericRange(text string) (string, bool) {
+	if low, high, found := strings.Cut(text, ".."); found {
+		min, errLow := strconv.ParseFloat(strings.TrimSpace(low), 64)
+		max, errHigh := strconv.ParseFloat(strings.TrimSpace(high), 64)
+		if errLow != nil || errHigh != nil || max < min {
+			return "", false
+		}
+
+		if max == min {
+			return formatFloat(min), true
+		}
+		return formatFloat((min+max)/2) + " ± " + formatFloat((max-min)/2), true
+	}
+
+	value, tolerance, found := strings.Cut(text, ":")
+	if _, err := strconv.ParseFloat(strings.TrimSpace(value), 64); err != nil {
+		return "", false
+	}
+	if !found {
+		return strings.TrimSpace(value), true
+	}
+	if t, err := strconv.ParseFloat(strings.TrimSpace(tolerance), 64); err != nil || t < 0 {
+		return "", false
+	} else if t == 0 {
+		return strings.TrimSpace(value), true
+	}
+
+	return strings.TrimSpace(value) + " ± " + strings.TrimSpace(tolerance), true
+}
+
+func formatFloat(value float64) string {
+	return strconv.FormatFloat(value, 'f', -1, 64)
+}
+
+// parseGiftAnswers splits an answer block on its '=' and '~' markers. An answer can start with a
+// '%<weight>%' and end with a '#<feedback>'
+func parseGiftAnswers(block string) []giftAnswer {
+	var answers []giftAnswer
+	for i := 0; i < len(block); i++ {
+		switch block[i] {
+		case '\\':
+			if len(answers) > 0 && i+1 < len(block) {
+				answers[len(answers)-1].text += block[i : i+2]
+			}
+			i++
+		case '=', '~':
+			weight := 0.0
+			if block[i] == '=' {
+				weight = 100
+			}
+			answers = append(answers, giftAnswer{marker: block[i], weight: weight})
+		default:
+			if len(answers) > 0 {
+				answers[len(answers)-1].text += block[i : i+1]
+			}
+		}
+	}
+
+	for i, answer := range answers {
+		text := strings.TrimSpace(answer.text)
+		if subMatch := giftWeightRegexp.FindStringSubmatch(text); subMatch != nil {
+			if weight, err := strconv.ParseFloat(subMatch[1], 64); err == nil {
+				answers[i].weight = weight
+			}
+			text = text[len(subMatch[0]):]
+		}
+
+		parts := splitUnescaped(text, "#")
+		answers[i].text = parts[0]
+		answers[i].feedback = strings.Join(parts[1:], "#")
+	}
+
+	return answers
+}
+
+// giftText unescapes the special characters of a GIFT text
+func giftText(text string, isHtml bool) string {
+	text = giftEscapeReplacer.Replace(strings.TrimSpace(text))
+	if isHtml {
+		return htmlToText(text)
+	}
+
+	return text
+}
+
+// indexUnescaped gives the index of the first occurrence of sep that is not escaped by a '\'
+func indexUnescaped(s string, sep string) int {
+	for i := 0; i < len(s); i++ {
+		if s[i] == '\\' {
+			i++
+			continue
+		}
+		if strings.HasPrefix(s[i:], sep) {
+			return i
+		}
+	}
+
+	return -1
+}
+
+// splitUnescaped splits s around the occurrences of sep that are not escaped by a '\'
+func splitUnescaped(s string, sep string) []string {
+	var parts []string
+	for {
+		i := indexUnescaped(s, sep)
+		if i == -1 {
+			return append(parts, s)
+		}
+		parts = append(parts, s[:i])
+		s = s[i+len(sep):]
+	}
+}
+
+// removeGiftEscapes removes the escaped characters so that they are not taken for markers
+func removeGiftEscapes(s string) string {
+	var sb strings.Builder
+	for i := 0; i < len(s); i++ {
+		if s[i] == '\\' {
+			i++
+			continue
+		}
+		sb.WriteByte(s[i])
+	}
+
+	return sb.String()
+}
+
+// writeGift writes a quiz in the GIFT format, the texts being written as markdown. The ordering
+// questions and the short answers matched by a regular expression have no GIFT equivalent, they are
+// left out with a comment
+func writeGift(quiz *Quiz) string {
+	var sb strings.Builder
+	fmt.Fprintf(&sb, "// %s\n", quiz.Name)
+
+	for _, question := range sortedQuestions(quiz.Questions) {
+		sb.WriteString("\n")
+
+		answers, ok := writeGiftAnswers(question)
+		if !ok {
+			fmt.Fprintf(&sb, "// question %d can not be exported to GIFT\n", question.Position)
+			continue
+		}
+
+		content := question.Content
+		if question.Code != "" {
+			content += fmt.Sprintf("\n```%s\n%s\n```", question.CodeLanguage, question.Code)
+		}
+		fmt.Fprintf(&sb, "::Question %d::[markdown]%s {\n%s", question.Position, giftEscaper.Replace(content), answers)
+		if question.Explanation != "" {
+			sb.WriteString("####" + giftEscaper.Replace(question.Explanation) + "\n")
+		}
+		sb.WriteString("}\n")
+	}
+
+	return sb.String()
+}
+
+// writeGiftAnswers writes the answer block of a question, one answer per line
+func writeGiftAnswers(question QuizQuestion) (string, bool) {
+	validCount := 0
+	for _, answer := range question.Answers {
+		if answer.Valid {
+			validCount++
+		}
+	}
+
+	var sb strings.Builder
+	if question.Kind == Numeric {
+		sb.WriteString("#\n")
+	}
+
+	for _, sha1 := range sortedAnswerSha1s(question.Answers) {
+		answer := question.Answers[sha1]
+		content := giftEscaper.Replace(answer.Content)
+
+		switch question.Kind {
+		case Choice:
+			if !answer.Valid {
+				content = "~" + content
+			} else if validCount == 1 {
+				content = "=" + content
+			} else {
+				content = fmt.Sprintf("~%%%s%%%s", formatFloat(math.Round(100000/float64(validCount))/1000), content)
+			}
+		case ShortAnswer:
+			if answer.Match == RegexMatch {
+				continue
+			}
+			content = "=" + content
+		case Numeric:
+			value, tolerance, err := parseNumericAnswer(answer.Content)
+			if err != nil {
+				continue
+			}
+			content = fmt.Sprintf("=%s:%s", formatFloat(value), formatFloat(tolerance))
+		case Matching:
+			content = "=" + content + " -> " + giftEscaper.Replace(answer.Right)
+		default:
+			return "", false
+		}
+
+		if answer.Explanation != "" {
+			content += "#" + giftEscaper.Replace(answer.Explanation)
+		}
+		sb.WriteString(content + "\n")
+	}
+
+	if sb.Len() == 0 || sb.String() == "#\n" {
+		return "", false
+	}
+
+	return sb.String(), true
+}
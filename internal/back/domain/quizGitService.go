@@ -17,74 +17,475 @@
 package domain
 
 import (
+	"errors"
+	"fmt"
 	"io"
+	"os"
+	"path"
+	"path/filepath"
 	"regexp"
+	"sort"
 	"strings"
 
+	"github.com/fatih/color"
 	"github.com/go-git/go-billy/v5"
 	"github.com/go-git/go-billy/v5/memfs"
 	"github.com/go-git/go-git/v5"
+	"github.com/go-git/go-git/v5/config"
+	"github.com/go-git/go-git/v5/plumbing"
+	"github.com/go-git/go-git/v5/plumbing/format/gitignore"
+	"github.com/go-git/go-git/v5/plumbing/transport"
 	"github.com/go-git/go-git/v5/plumbing/transport/http"
+	"github.com/go-git/go-git/v5/plumbing/transport/ssh"
 	"github.com/go-git/go-git/v5/storage/memory"
 	"github.com/spf13/viper"
 )
 
-func (s *QuizService) ScanGitRepo() ([]*Quiz, error) {
-	storage := memory.NewStorage()
-	fs := memfs.New()
+var quizFilenameRegexp = regexp.MustCompile(`.*\.quiz\.md`)
 
-	url := viper.GetString("repository-url")
-	token := viper.GetString("token")
+// gitRepoDir is the folder of db-location holding the working copy of the repository
+const gitRepoDir = "repository"
 
-	var option git.CloneOptions
-	if len(token) == 0 {
-		option = git.CloneOptions{
-			URL: url,
+// gitRepoLocation gives the folder of the working copy of the repository, the repository being
+// cloned in memory when no db-location is set
+func gitRepoLocation() string {
+	dbLocation := viper.GetString("db-location")
+	if dbLocation == "" {
+		return ""
+	}
+
+	return filepath.Join(dbLocation, gitRepoDir)
+}
+
+// gitSource is a git repository the quizzes are read from
+type gitSource struct {
+	name string
+	// location is the folder of the working copy of the repository, the repository being cloned in
+	// memory when empty
+	location string
+	url      string
+	// ref is the branch, the tag or the hash of the commit to read, the default branch when empty
+	ref string
+	// dir is the folder of the repository holding the quizzes, the root when empty
+	dir string
+	// token is the P.A.T. used over HTTPS
+	token string
+	// sshKey is the file of the private key used over SSH, with its password
+	sshKey         string
+	sshKeyPassword string
+	// knownHosts is the known_hosts file checking the host key of the server over SSH, the ones
+	// of the system being used when empty
+	knownHosts string
+}
+
+// defaultGitSource gives the repository set by the flags of the serve command, used when no source
+// is declared in the configuration file
+func defaultGitSource() gitSource {
+	return gitSource{
+		name:           defaultSourceName,
+		location:       gitRepoLocation(),
+		url:            viper.GetString("repository-url"),
+		ref:            viper.GetString("repository-ref"),
+		dir:            viper.GetString("repository-dir"),
+		token:          viper.GetString("token"),
+		sshKey:         viper.GetString("ssh-key"),
+		sshKeyPassword: viper.GetString("ssh-key-password"),
+		knownHosts:     viper.GetString("known-hosts"),
+	}
+}
+
+func (g gitSource) Name() string {
+	return g.name
+}
+
+// Open checks out the quizzes of the repository, its version being the hash of the commit
+func (g gitSource) Open() (billy.Filesystem, string, error) {
+	fs, hash, err := g.checkout(g.location)
+	if err != nil {
+		return nil, "", err
+	}
+
+	return fs, hash.String(), nil
+}
+
+// auth gives the credentials used to access the repository : the private key when one is set,
+// checking the host key of the server against the known hosts, or else the token
+func (g gitSource) auth() (transport.AuthMethod, error) {
+	if g.sshKey != "" {
+		endpoint, err := transport.NewEndpoint(g.url)
+		if err != nil {
+			return nil, err
+		}
+
+		user := endpoint.User
+		if user == "" {
+			user = ssh.DefaultUsername
+		}
+		keys, err := ssh.NewPublicKeysFromFile(user, g.sshKey, g.sshKeyPassword)
+		if err != nil {
+			return nil, fmt.Errorf("can't read the ssh key %s (%v)", g.sshKey, err)
+		}
+
+		var knownHosts []string
+		if g.knownHosts != "" {
+			knownHosts = append(knownHosts, g.knownHosts)
+		}
+		db, err := ssh.NewKnownHostsDb(knownHosts...)
+		if err != nil {
+			return nil, fmt.Errorf("can't read the known hosts (%v)", err)
 		}
+		port := endpoint.Port
+		if port == 0 {
+			port = 22
+		}
+		keys.HostKeyCallback = db.HostKeyCallback()
+		keys.HostKeyAlgorithms = db.HostKeyAlgorithms(fmt.Sprintf("%s:%d", endpoint.Host, port))
+
+		return keys, nil
+	}
+
+	if len(g.token) == 0 {
+		return nil, nil
+	}
+
+	return &http.BasicAuth{
+		Username: "42", // yes, this can be anything except an empty string
+		Password: g.token,
+	}, nil
+}
+
+// checkout returns the folder of the quizzes at the commit given by the ref, with the hash of the
+// commit. The working copy kept in dir is fetched and moved to the commit, the repository is
+// cloned again when the working copy can't be used
+func (g gitSource) checkout(dir string) (billy.Filesystem, plumbing.Hash, error) {
+	auth, err := g.auth()
+	if err != nil {
+		return nil, plumbing.ZeroHash, err
+	}
+
+	hash, err := g.resolveRef(auth)
+	if err != nil {
+		return nil, plumbing.ZeroHash, err
+	}
+
+	var repo *git.Repository
+	if dir == "" {
+		repo, err = git.Clone(memory.NewStorage(), memfs.New(), g.cloneOptions(auth))
 	} else {
-		option = git.CloneOptions{
-			Auth: &http.BasicAuth{
-				Username: "42", // yes, this can be anything except an empty string
-				Password: token,
-			},
-			URL: url,
+		repo, err = g.updateWorkingCopy(dir, auth, hash)
+		if err != nil {
+			if !errors.Is(err, git.ErrRepositoryNotExists) {
+				fmt.Printf("%s Working copy %s can't be used (%v), cloning the repository again\n",
+					color.HiYellowString("!"), dir, err)
+			}
+			repo, err = g.cloneTo(dir, auth)
 		}
 	}
+	if err != nil {
+		return nil, plumbing.ZeroHash, err
+	}
 
-	_, err := git.Clone(storage, fs, &option)
+	fs, err := g.checkoutCommit(repo, hash)
+	if err != nil {
+		return nil, plumbing.ZeroHash, err
+	}
+
+	return fs, hash, nil
+}
+
+// resolveRef finds on the remote the commit the ref points to. The ref is looked for as a branch,
+// then as a tag, then as a full reference name, and else taken as the hash of a commit
+func (g gitSource) resolveRef(auth transport.AuthMethod) (plumbing.Hash, error) {
+	remote := git.NewRemote(memory.NewStorage(), &config.RemoteConfig{
+		Name: git.DefaultRemoteName,
+		URLs: []string{g.url},
+	})
+	refs, err := remote.List(&git.ListOptions{Auth: auth, PeelingOption: git.AppendPeeled})
+	if err != nil {
+		return plumbing.ZeroHash, err
+	}
+
+	refsByName := make(map[plumbing.ReferenceName]*plumbing.Reference, len(refs))
+	for _, ref := range refs {
+		refsByName[ref.Name()] = ref
+	}
+
+	names := []plumbing.ReferenceName{plumbing.HEAD}
+	if g.ref != "" {
+		tag := plumbing.NewTagReferenceName(g.ref)
+		names = []plumbing.ReferenceName{
+			plumbing.NewBranchReferenceName(g.ref),
+			tag + "^{}", // the commit of an annotated tag
+			tag,
+			plumbing.ReferenceName(g.ref),
+		}
+	}
+
+	for _, name := range names {
+		ref, found := refsByName[name]
+		for found && ref.Type() == plumbing.SymbolicReference {
+			ref, found = refsByName[ref.Target()]
+		}
+		if found {
+			return ref.Hash(), nil
+		}
+	}
+
+	if commitHashRegexp.MatchString(g.ref) {
+		return plumbing.NewHash(g.ref), nil
+	}
+	if g.ref == "" {
+		return plumbing.ZeroHash, fmt.Errorf("the repository %s has no default branch", g.url)
+	}
+
+	return plumbing.ZeroHash, fmt.Errorf("ref %s is not a branch, a tag or the hash of a commit of the repository", g.ref)
+}
+
+var commitHashRegexp = regexp.MustCompile(`^[0-9a-f]{40}$`)
+
+func (g gitSource) cloneOptions(auth transport.AuthMethod) *git.CloneOptions {
+	return &git.CloneOptions{
+		URL:        g.url,
+		Auth:       auth,
+		Tags:       git.AllTags,
+		NoCheckout: true,
+	}
+}
+
+// updateWorkingCopy opens the working copy kept in dir and fetches the commit when it doesn't have
+// it yet
+func (g gitSource) updateWorkingCopy(dir string, auth transport.AuthMethod, hash plumbing.Hash) (*git.Repository, error) {
+	repo, err := git.PlainOpen(dir)
 	if err != nil {
 		return nil, err
 	}
 
-	dir, err := fs.ReadDir(".")
+	remote, err := repo.Remote(git.DefaultRemoteName)
 	if err != nil {
 		return nil, err
 	}
+	if urls := remote.Config().URLs; len(urls) == 0 || urls[0] != g.url {
+		return nil, fmt.Errorf("it is a clone of another repository")
+	}
 
-	var quizzes []*Quiz
+	if _, err := repo.Head(); err != nil {
+		return nil, err
+	}
+	if _, err := repo.CommitObject(hash); err == nil {
+		return repo, nil
+	}
 
-	r := regexp.MustCompile(`.*\.quiz\.md`)
-	for _, fileInfo := range dir {
+	err = repo.Fetch(&git.FetchOptions{
+		RemoteName: git.DefaultRemoteName,
+		Auth:       auth,
+		Tags:       git.AllTags,
+		Force:      true,
+	})
+	if err != nil && !errors.Is(err, git.NoErrAlreadyUpToDate) {
+		return nil, err
+	}
 
-		if r.MatchString(fileInfo.Name()) {
-			content, err := readFileContent(fs, fileInfo.Name())
-			if err != nil {
-				return nil, err
-			}
+	return repo, nil
+}
 
-			quiz, err := s.Parse(fileInfo.Name(), content)
-			if err != nil {
-				return nil, err
+// cloneTo clones the repository in dir. The clone is made beside dir and replaces it once done, so
+// that a failed clone keeps the previous working copy
+func (g gitSource) cloneTo(dir string, auth transport.AuthMethod) (*git.Repository, error) {
+	tmpDir := dir + ".tmp"
+	if err := os.RemoveAll(tmpDir); err != nil {
+		return nil, err
+	}
+
+	_, err := git.PlainClone(tmpDir, false, g.cloneOptions(auth))
+	if err != nil {
+		_ = os.RemoveAll(tmpDir)
+		return nil, err
+	}
+
+	if err := os.RemoveAll(dir); err != nil {
+		return nil, err
+	}
+	if err := os.Rename(tmpDir, dir); err != nil {
+		return nil, err
+	}
+
+	return git.PlainOpen(dir)
+}
+
+// checkoutCommit moves the working tree of the repository to the commit and returns the folder of
+// the quizzes in it
+func (g gitSource) checkoutCommit(repo *git.Repository, hash plumbing.Hash) (billy.Filesystem, error) {
+	worktree, err := repo.Worktree()
+	if err != nil {
+		return nil, err
+	}
+
+	err = worktree.Checkout(&git.CheckoutOptions{Hash: hash, Force: true})
+	if err != nil {
+		return nil, fmt.Errorf("can't check out commit %s (%v)", hash, err)
+	}
+
+	if g.dir == "" {
+		return worktree.Filesystem, nil
+	}
+
+	info, err := worktree.Filesystem.Stat(g.dir)
+	if err != nil || !info.IsDir() {
+		return nil, fmt.Errorf("folder %s does not exist in the repository at commit %s", g.dir, hash.String()[:7])
+	}
+
+	return worktree.Filesystem.Chroot(g.dir)
+}
+
+// quizIgnoreFile lists the files and folders left out of the scan, in the format of a .gitignore file
+const quizIgnoreFile = ".quizignore"
+
+// quizFilenames lists the quiz files of the given filesystem and of its folders. The hidden folders
+// and the paths matching a pattern of the .quizignore file are left out
+func quizFilenames(fs billy.Filesystem) ([]string, error) {
+	patterns, err := readIgnorePatterns(fs)
+	if err != nil {
+		return nil, err
+	}
+
+	var filenames []string
+	err = walkQuizFilenames(fs, "", gitignore.NewMatcher(patterns), &filenames)
+	if err != nil {
+		return nil, err
+	}
+	sort.Strings(filenames)
+
+	return filenames, nil
+}
+
+func walkQuizFilenames(fs billy.Filesystem, dir string, matcher gitignore.Matcher, filenames *[]string) error {
+	entries, err := fs.ReadDir(path.Join(".", dir))
+	if err != nil {
+		return err
+	}
+
+	for _, entry := range entries {
+		filename := path.Join(dir, entry.Name())
+		if matcher.Match(strings.Split(filename, "/"), entry.IsDir()) {
+			continue
+		}
+
+		if entry.IsDir() {
+			if strings.HasPrefix(entry.Name(), ".") {
+				continue
 			}
+			if err := walkQuizFilenames(fs, filename, matcher, filenames); err != nil {
+				return err
+			}
+		} else if quizFilenameRegexp.MatchString(entry.Name()) {
+			*filenames = append(*filenames, filename)
+		}
+	}
+
+	return nil
+}
+
+// readIgnorePatterns reads the patterns of the .quizignore file at the root of the filesystem, there
+// is none when the file does not exist
+func readIgnorePatterns(fs billy.Filesystem) ([]gitignore.Pattern, error) {
+	content, err := readTextFile(fs, quizIgnoreFile)
+	if errors.Is(err, os.ErrNotExist) {
+		return nil, nil
+	} else if err != nil {
+		return nil, err
+	}
+
+	var patterns []gitignore.Pattern
+	for _, line := range strings.Split(content, "\n") {
+		line = strings.TrimRight(line, " \r")
+		if line != "" && !strings.HasPrefix(line, "#") {
+			patterns = append(patterns, gitignore.ParsePattern(line, nil))
+		}
+	}
+
+	return patterns, nil
+}
 
+// quizCategory gives the category of a quiz, the path of the folder holding its file like
+// networking/tcp. The quizzes at the root of the repository have no category
+func quizCategory(filename string) string {
+	dir := path.Dir(filename)
+	if dir == "." {
+		return ""
+	}
+
+	return dir
+}
+
+// scanQuizzes parses the quiz files of the given filesystem and collects their assets. The files
+// sharing an id are merged into a single quiz holding their translations
+func (s *QuizService) scanQuizzes(fs billy.Filesystem) ([]*Quiz, Diagnostics, error) {
+	filenames, err := quizFilenames(fs)
+	if err != nil {
+		return nil, nil, err
+	}
+
+	var quizzes []*Quiz
+	var diagnostics Diagnostics
+	failedIds := map[string]bool{}
+
+	for _, filename := range filenames {
+		quiz, quizDiagnostics, err := s.parseQuizFile(fs, filename)
+		if err != nil {
+			return nil, nil, err
+		}
+
+		diagnostics = append(diagnostics, quizDiagnostics...)
+		if quiz != nil {
 			quizzes = append(quizzes, quiz)
+		} else if content, err := readTextFile(fs, filename); err == nil {
+			if id := frontMatterId(content); id != "" {
+				failedIds[translationGroup(filename, id)] = true
+			}
 		}
 	}
 
-	return quizzes, nil
+	quizzes, mergeDiagnostics := mergeTranslations(quizzes, failedIds)
+
+	return quizzes, append(diagnostics, mergeDiagnostics...), nil
+}
+
+// parseQuizFile parses a quiz file of the given filesystem once its include directives are
+// resolved, and collects its assets. The quiz sha1 is the one of the expanded content so that
+// changing an included file creates a new version of the quiz. The quiz is nil when the file has
+// errors
+func (s *QuizService) parseQuizFile(fs billy.Filesystem, filename string) (*Quiz, Diagnostics, error) {
+	content, err := readTextFile(fs, filename)
+	if err != nil {
+		return nil, nil, err
+	}
+
+	expanded, segments, diagnostics := expandIncludes(fs, filename, content)
+	if diagnostics.HasErrors() {
+		return nil, diagnostics, nil
+	}
+
+	quiz, quizDiagnostics := s.parse(&quizParser{filename: filename, content: expanded, segments: segments})
+	diagnostics = append(diagnostics, quizDiagnostics...)
+	if quiz == nil {
+		return nil, diagnostics, nil
+	}
+
+	diagnostics = append(diagnostics, collectAssets(fs, quiz, expanded, segments)...)
+	if diagnostics.HasErrors() {
+		return nil, diagnostics, nil
+	}
+
+	return quiz, diagnostics, nil
 }
 
+// readFileContent reads a file of a source. The symbolic links are not followed, a link of a working
+// copy or of a local directory being able to target any file of the server
 func readFileContent(fs billy.Filesystem, filename string) (string, error) {
+	if err := checkNoSymlink(fs, filename); err != nil {
+		return "", err
+	}
+
 	file, err := fs.Open(filename)
 	if err != nil {
 		return "", err
@@ -99,3 +500,30 @@ func readFileContent(fs billy.Filesystem, filename string) (string, error) {
 
 	return buf.String(), nil
 }
+
+// checkNoSymlink fails when the file or one of its folders is a symbolic link
+func checkNoSymlink(fs billy.Filesystem, filename string) error {
+	current := ""
+	for _, name := range strings.Split(strings.TrimPrefix(path.Clean(filename), "/"), "/") {
+		current = path.Join(current, name)
+		info, err := fs.Lstat(current)
+		if err != nil {
+			return err
+		}
+		if info.Mode()&os.ModeSymlink != 0 {
+			return fmt.Errorf("%s is a symbolic link", current)
+		}
+	}
+
+	return nil
+}
+
+// readTextFile reads a text file like a quiz file, its Windows line endings being turned into '\n'
+func readTextFile(fs billy.Filesystem, filename string) (string, error) {
+	content, err := readFileContent(fs, filename)
+	if err != nil {
+		return "", err
+	}
+
+	return normalizeLineEndings(content), nil
+}
@@ -17,14 +17,26 @@
 package domain
 
 import (
+	"context"
+	"crypto/ed25519"
+	"crypto/x509"
+	"encoding/pem"
 	"os"
+	"path/filepath"
 	"testing"
+	"time"
 
 	"github.com/go-git/go-billy/v5/memfs"
 	"github.com/go-git/go-git/v5"
+	"github.com/go-git/go-git/v5/config"
+	"github.com/go-git/go-git/v5/plumbing"
+	"github.com/go-git/go-git/v5/plumbing/object"
+	"github.com/go-git/go-git/v5/plumbing/transport/http"
+	"github.com/go-git/go-git/v5/plumbing/transport/ssh"
 	"github.com/go-git/go-git/v5/storage/memory"
 	"github.com/spf13/viper"
 	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/mock"
 )
 
 func Test_readFileContent(t *testing.T) {
@@ -52,21 +64,343 @@ func Test_readFileContent(t *testing.T) {
 	assert.Equal(t, string(expected), actual)
 }
 
-func TestScanGitRepo(t *testing.T) {
+func TestScanSource_git(t *testing.T) {
 
 	s := NewQuizService(nil)
 
-	viper.Set("repository-url", "../../../.")
-	viper.Set("token", "")
-
-	quizzes, err := s.ScanGitRepo()
+	quizzes, diagnostics, err := s.ScanSource(gitSource{name: defaultSourceName, url: "../../../."})
 	if err != nil {
 		assert.Fail(t, "Can't scan repo", "%v", err)
 	}
 
+	assert.False(t, diagnostics.HasErrors())
 	assert.Len(t, quizzes, 2)
 	assert.Equal(t, "Marvel Universe", quizzes[0].Name)
 	assert.Equal(t, "marvel-universe.quiz.md", quizzes[0].Filename)
 	assert.Equal(t, "c152b2d0a2509a82ea5e8a6ae22fea55c7221002", quizzes[0].Sha1)
 	assert.Len(t, quizzes[0].Questions, 7)
 }
+
+func Test_quizFilenames(t *testing.T) {
+	fs := includeFs(t, map[string]string{
+		".quizignore":                      "# drafts are not published\ndrafts/\n*.wip.quiz.md\n",
+		"git.quiz.md":                      gitQuizContent,
+		"networking/tcp/handshake.quiz.md": gitQuizContent,
+		"networking/dns.quiz.md":           gitQuizContent,
+		"networking/README.md":             "# Networking",
+		"networking/routing.wip.quiz.md":   gitQuizContent,
+		"drafts/http.quiz.md":              gitQuizContent,
+		".github/template.quiz.md":         gitQuizContent,
+	})
+
+	filenames, err := quizFilenames(fs)
+	if err != nil {
+		assert.FailNow(t, "Can't list quiz files", "%v", err)
+	}
+
+	assert.Equal(t, []string{"git.quiz.md", "networking/dns.quiz.md", "networking/tcp/handshake.quiz.md"}, filenames)
+}
+
+func Test_quizCategory(t *testing.T) {
+	assert.Equal(t, "", quizCategory("git.quiz.md"))
+	assert.Equal(t, "networking", quizCategory("networking/dns.quiz.md"))
+	assert.Equal(t, "networking/tcp", quizCategory("networking/tcp/handshake.quiz.md"))
+}
+
+const gitQuizContent = `# Git (duration: 5min)
+
+What is a commit ?
+- [x] A snapshot of the repository
+- [ ] A branch
+`
+
+// newBareRepo creates a bare repository holding the given files, it returns its path with a
+// function committing files to it
+func newBareRepo(t *testing.T, files map[string]string) (string, func(files map[string]string)) {
+	bareDir := t.TempDir()
+	_, err := git.PlainInit(bareDir, true)
+	if err != nil {
+		assert.FailNow(t, "Can't create bare repo", "%v", err)
+	}
+
+	workDir := t.TempDir()
+	repo, err := git.PlainInit(workDir, false)
+	if err != nil {
+		assert.FailNow(t, "Can't create repo", "%v", err)
+	}
+	_, err = repo.CreateRemote(&config.RemoteConfig{Name: git.DefaultRemoteName, URLs: []string{bareDir}})
+	if err != nil {
+		assert.FailNow(t, "Can't create remote", "%v", err)
+	}
+
+	commit := func(files map[string]string) {
+		worktree, err := repo.Worktree()
+		if err != nil {
+			assert.FailNow(t, "Can't open worktree", "%v", err)
+		}
+
+		for filename, content := range files {
+			if err := os.MkdirAll(filepath.Dir(filepath.Join(workDir, filename)), 0o755); err != nil {
+				assert.FailNow(t, "Can't create folder", "%v", err)
+			}
+			if err := os.WriteFile(filepath.Join(workDir, filename), []byte(content), 0o644); err != nil {
+				assert.FailNow(t, "Can't write file", "%v", err)
+			}
+			if _, err := worktree.Add(filename); err != nil {
+				assert.FailNow(t, "Can't add file", "%v", err)
+			}
+		}
+
+		_, err = worktree.Commit("Update quizzes", &git.CommitOptions{
+			Author: &object.Signature{Name: "Test", Email: "test@example.com", When: time.Now()},
+		})
+		if err != nil {
+			assert.FailNow(t, "Can't commit", "%v", err)
+		}
+
+		if err := repo.Push(&git.PushOptions{RemoteName: git.DefaultRemoteName}); err != nil {
+			assert.FailNow(t, "Can't push", "%v", err)
+		}
+	}
+	commit(files)
+
+	return bareDir, commit
+}
+
+func TestGitSource_checkout(t *testing.T) {
+	url, commit := newBareRepo(t, map[string]string{"git.quiz.md": gitQuizContent})
+	dir := filepath.Join(t.TempDir(), gitRepoDir)
+
+	fs, head, err := gitSource{url: url}.checkout(dir)
+	if err != nil {
+		assert.FailNow(t, "Can't checkout repo", "%v", err)
+	}
+	assert.DirExists(t, filepath.Join(dir, ".git"))
+	filenames, _ := quizFilenames(fs)
+	assert.Equal(t, []string{"git.quiz.md"}, filenames)
+
+	_, unchangedHead, err := gitSource{url: url}.checkout(dir)
+	if err != nil {
+		assert.FailNow(t, "Can't checkout repo", "%v", err)
+	}
+	assert.Equal(t, head, unchangedHead)
+
+	commit(map[string]string{"vcs.quiz.md": gitQuizContent})
+
+	fs, newHead, err := gitSource{url: url}.checkout(dir)
+	if err != nil {
+		assert.FailNow(t, "Can't checkout repo", "%v", err)
+	}
+	assert.NotEqual(t, head, newHead)
+	filenames, _ = quizFilenames(fs)
+	assert.Equal(t, []string{"git.quiz.md", "vcs.quiz.md"}, filenames)
+}
+
+func TestGitSource_checkout_symlinked_asset(t *testing.T) {
+	secret := filepath.Join(t.TempDir(), "secret.png")
+	if err := os.WriteFile(secret, []byte("secret"), 0o644); err != nil {
+		assert.FailNow(t, "Can't write file", "%v", err)
+	}
+
+	url, _ := newBareRepo(t, map[string]string{"git.quiz.md": gitQuizContent + "\n![logo](images/logo.png)\n"})
+
+	// the symbolic link is pushed from a clone of the repository
+	workDir := t.TempDir()
+	repo, err := git.PlainClone(workDir, false, &git.CloneOptions{URL: url})
+	if err != nil {
+		assert.FailNow(t, "Can't clone repo", "%v", err)
+	}
+	if err := os.Mkdir(filepath.Join(workDir, "images"), 0o755); err != nil {
+		assert.FailNow(t, "Can't create folder", "%v", err)
+	}
+	if err := os.Symlink(secret, filepath.Join(workDir, "images", "logo.png")); err != nil {
+		assert.FailNow(t, "Can't create symbolic link", "%v", err)
+	}
+	worktree, _ := repo.Worktree()
+	if _, err := worktree.Add("images/logo.png"); err != nil {
+		assert.FailNow(t, "Can't add file", "%v", err)
+	}
+	_, err = worktree.Commit("Add logo", &git.CommitOptions{
+		Author: &object.Signature{Name: "Test", Email: "test@example.com", When: time.Now()},
+	})
+	if err != nil {
+		assert.FailNow(t, "Can't commit", "%v", err)
+	}
+	if err := repo.Push(&git.PushOptions{}); err != nil {
+		assert.FailNow(t, "Can't push", "%v", err)
+	}
+
+	fs, _, err := gitSource{url: url}.checkout(filepath.Join(t.TempDir(), gitRepoDir))
+	if err != nil {
+		assert.FailNow(t, "Can't checkout repo", "%v", err)
+	}
+
+	_, err = readFileContent(fs, "images/logo.png")
+	assert.EqualError(t, err, "images/logo.png is a symbolic link")
+
+	s := NewQuizService(nil)
+	quiz, diagnostics, err := s.parseQuizFile(fs, "git.quiz.md")
+	assert.NoError(t, err)
+	assert.Nil(t, quiz)
+	assert.True(t, diagnostics.HasErrors())
+	assert.Contains(t, diagnostics[0].Message, "images/logo.png is a symbolic link")
+}
+
+func TestGitSource_checkout_corrupted_working_copy(t *testing.T) {
+	url, commit := newBareRepo(t, map[string]string{"git.quiz.md": gitQuizContent})
+	dir := filepath.Join(t.TempDir(), gitRepoDir)
+
+	_, head, err := gitSource{url: url}.checkout(dir)
+	if err != nil {
+		assert.FailNow(t, "Can't checkout repo", "%v", err)
+	}
+
+	if err := os.WriteFile(filepath.Join(dir, ".git", "HEAD"), []byte("garbage"), 0o644); err != nil {
+		assert.FailNow(t, "Can't corrupt working copy", "%v", err)
+	}
+	commit(map[string]string{"vcs.quiz.md": gitQuizContent})
+
+	fs, clonedHead, err := gitSource{url: url}.checkout(dir)
+	if err != nil {
+		assert.FailNow(t, "Can't checkout repo", "%v", err)
+	}
+	assert.NotEqual(t, head, clonedHead)
+	assert.NoDirExists(t, dir+".tmp")
+	content, err := readFileContent(fs, "vcs.quiz.md")
+	assert.NoError(t, err)
+	assert.Equal(t, gitQuizContent, content)
+}
+
+func TestGitSource_checkout_ref(t *testing.T) {
+	url, commit := newBareRepo(t, map[string]string{"git.quiz.md": gitQuizContent})
+	dir := filepath.Join(t.TempDir(), gitRepoDir)
+
+	bare, err := git.PlainOpen(url)
+	if err != nil {
+		assert.FailNow(t, "Can't open bare repo", "%v", err)
+	}
+	first, err := bare.Reference(plumbing.Master, false)
+	if err != nil {
+		assert.FailNow(t, "Can't read master", "%v", err)
+	}
+	if err := bare.Storer.SetReference(plumbing.NewHashReference(plumbing.NewBranchReferenceName("exams"), first.Hash())); err != nil {
+		assert.FailNow(t, "Can't create branch", "%v", err)
+	}
+	_, err = bare.CreateTag("v1", first.Hash(), &git.CreateTagOptions{
+		Tagger:  &object.Signature{Name: "Test", Email: "test@example.com", When: time.Now()},
+		Message: "First version",
+	})
+	if err != nil {
+		assert.FailNow(t, "Can't create tag", "%v", err)
+	}
+	commit(map[string]string{"networking/dns.quiz.md": gitQuizContent})
+
+	tests := []struct {
+		name      string
+		source    gitSource
+		filenames []string
+	}{
+		{"default branch", gitSource{url: url}, []string{"git.quiz.md", "networking/dns.quiz.md"}},
+		{"branch", gitSource{url: url, ref: "exams"}, []string{"git.quiz.md"}},
+		{"annotated tag", gitSource{url: url, ref: "v1"}, []string{"git.quiz.md"}},
+		{"commit", gitSource{url: url, ref: first.Hash().String()}, []string{"git.quiz.md"}},
+		{"subdirectory", gitSource{url: url, dir: "networking"}, []string{"dns.quiz.md"}},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			// the same working copy is moved from one ref to another
+			fs, _, err := tt.source.checkout(dir)
+			if err != nil {
+				assert.FailNow(t, "Can't checkout repo", "%v", err)
+			}
+			filenames, _ := quizFilenames(fs)
+			assert.Equal(t, tt.filenames, filenames)
+		})
+	}
+
+	_, _, err = gitSource{url: url, ref: "missing"}.checkout(dir)
+	assert.EqualError(t, err, "ref missing is not a branch, a tag or the hash of a commit of the repository")
+
+	_, _, err = gitSource{url: url, ref: "exams", dir: "networking"}.checkout("")
+	assert.ErrorContains(t, err, "folder networking does not exist in the repository")
+}
+
+func TestGitSource_auth(t *testing.T) {
+	auth, err := gitSource{url: "https://example.com/quizzes.git"}.auth()
+	assert.NoError(t, err)
+	assert.Nil(t, auth)
+
+	auth, err = gitSource{url: "https://example.com/quizzes.git", token: "secret"}.auth()
+	assert.NoError(t, err)
+	assert.Equal(t, "secret", auth.(*http.BasicAuth).Password)
+
+	_, key, err := ed25519.GenerateKey(nil)
+	if err != nil {
+		assert.FailNow(t, "Can't generate key", "%v", err)
+	}
+	der, err := x509.MarshalPKCS8PrivateKey(key)
+	if err != nil {
+		assert.FailNow(t, "Can't encode key", "%v", err)
+	}
+	keyFile := filepath.Join(t.TempDir(), "id_ed25519")
+	knownHostsFile := filepath.Join(t.TempDir(), "known_hosts")
+	if err := os.WriteFile(keyFile, pem.EncodeToMemory(&pem.Block{Type: "PRIVATE KEY", Bytes: der}), 0o600); err != nil {
+		assert.FailNow(t, "Can't write key", "%v", err)
+	}
+	if err := os.WriteFile(knownHostsFile, nil, 0o644); err != nil {
+		assert.FailNow(t, "Can't write known hosts", "%v", err)
+	}
+
+	auth, err = gitSource{url: "git@example.com:quizzes.git", sshKey: keyFile, knownHosts: knownHostsFile}.auth()
+	assert.NoError(t, err)
+	assert.Equal(t, "git", auth.(*ssh.PublicKeys).User)
+	assert.NotNil(t, auth.(*ssh.PublicKeys).HostKeyCallback)
+
+	auth, err = gitSource{url: "ssh://deploy@example.com:2222/quizzes.git", sshKey: keyFile, knownHosts: knownHostsFile}.auth()
+	assert.NoError(t, err)
+	assert.Equal(t, "deploy", auth.(*ssh.PublicKeys).User)
+
+	_, err = gitSource{url: "git@example.com:quizzes.git", sshKey: keyFile, knownHosts: filepath.Join(t.TempDir(), "missing")}.auth()
+	assert.ErrorContains(t, err, "can't read the known hosts")
+}
+
+func TestQuizService_Sync_unchanged_repo(t *testing.T) {
+	url, commit := newBareRepo(t, map[string]string{"git.quiz.md": gitQuizContent})
+	viper.Set("repository-url", url)
+	viper.Set("token", "")
+	viper.Set("db-location", t.TempDir())
+	t.Cleanup(func() {
+		viper.Set("db-location", "")
+	})
+
+	mockQuizRepository := NewMockQuizRepository(t)
+	s := NewQuizService(mockQuizRepository)
+	ctx := context.Background()
+
+	mockQuizRepository.On("FindLatestVersionByFilename", ctx, defaultSourceName, "git.quiz.md").Return(nil, nil).Once()
+	mockQuizRepository.On("Create", ctx, mock.Anything).Return(nil).Once()
+
+	stats, err := s.Sync(ctx)
+	if err != nil {
+		assert.FailNow(t, "Can't sync", "%v", err)
+	}
+	assert.Equal(t, 1, stats.Created)
+
+	// the quiz files are not parsed again, the repository being at the same commit
+	stats, err = s.Sync(ctx)
+	if err != nil {
+		assert.FailNow(t, "Can't sync", "%v", err)
+	}
+	assert.Equal(t, 0, stats.Created)
+
+	commit(map[string]string{"vcs.quiz.md": gitQuizContent})
+	mockQuizRepository.On("FindLatestVersionByFilename", ctx, defaultSourceName, "git.quiz.md").Return(&Quiz{Sha1: getSha1(gitQuizContent)}, nil).Once()
+	mockQuizRepository.On("FindLatestVersionByFilename", ctx, defaultSourceName, "vcs.quiz.md").Return(nil, nil).Once()
+	mockQuizRepository.On("Create", ctx, mock.Anything).Return(nil).Once()
+
+	stats, err = s.Sync(ctx)
+	if err != nil {
+		assert.FailNow(t, "Can't sync", "%v", err)
+	}
+	assert.Equal(t, 1, stats.Created)
+}
@@ -0,0 +1,210 @@
+/*
+ * Copyright (c) 2023 Michaël COLL.
+ *
+ * Licensed under the Apache License, Version 2.0 (the "License");
+ * you may not use this file except in compliance with the License.
+ * You may obtain a copy of the License at
+ *
+ *      http://www.apache.org/licenses/LICENSE-2.0
+ *
+ * Unless required by applicable law or agreed to in writing, software
+ * distributed under the License is distributed on an "AS IS" BASIS,
+ * WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+ * See the License for the specific language governing permissions and
+ * limitations under the License.
+ */
+
+package domain
+
+import (
+	"context"
+	"html"
+	"path"
+	"regexp"
+	"strconv"
+	"strings"
+)
+
+// ImportFormat is a question format of another quiz software that can be converted to a quiz file
+type ImportFormat int8
+
+const (
+	// GiftFormat is the Moodle GIFT text format
+	GiftFormat ImportFormat = 0
+	// MoodleXmlFormat is the Moodle XML export format
+	MoodleXmlFormat ImportFormat = 1
+)
+
+// ParseImportFormat reads the name of an import format (gift or moodle-xml), the format being guessed
+// from the extension of the imported file when it is empty
+func ParseImportFormat(format string, filename string) (ImportFormat, error) {
+	if format == "" {
+		if strings.EqualFold(path.Ext(filename), ".xml") {
+			return MoodleXmlFormat, nil
+		}
+		return GiftFormat, nil
+	}
+
+	switch format {
+	case "gift":
+		return GiftFormat, nil
+	case "moodle-xml":
+		return MoodleXmlFormat, nil
+	default:
+		return 0, Errorf(InvalidArgument, "unknown import format %s, it must be gift or moodle-xml", format)
+	}
+}
+
+// ImportedQuiz is a quiz file converted from another format. The questions that can not be
+// converted are left out and reported as warnings located in the imported file
+type ImportedQuiz struct {
+	Filename    string
+	Content     string
+	Questions   int
+	Diagnostics Diagnostics
+}
+
+var htmlBreakRegexp = regexp.MustCompile(`(?i)<br\s*/?>|</p>|</div>|</li>`)
+var htmlTagRegexp = regexp.MustCompile(`<[^>]*>`)
+var blankLinesRegexp = regexp.MustCompile(`\n{3,}`)
+var importedTagRegexp = regexp.MustCompile(`[,()\n]+`)
+
+// ConvertQuiz converts a GIFT or Moodle XML file to a quiz file named after it. The name of the quiz
+// is the one of the first category when none is given, and its duration is one minute per question
+// when it is 0
+func (s *QuizService) ConvertQuiz(filename string, content string, format ImportFormat, name string, duration int) (*ImportedQuiz, error) {
+	var questions []QuizQuestion
+	var category string
+	var diagnostics Diagnostics
+
+	switch format {
+	case GiftFormat:
+		questions, category, diagnostics = readGift(filename, content)
+	case MoodleXmlFormat:
+		var err error
+		questions, category, diagnostics, err = readMoodleXml(filename, content)
+		if err != nil {
+			return nil, Errorf(InvalidArgument, "%s is not a valid Moodle XML file (%v)", filename, err)
+		}
+	default:
+		return nil, Errorf(InvalidArgument, "unknown import format %d", format)
+	}
+
+	if len(questions) == 0 {
+		return nil, Errorf(InvalidArgument, "%s has no question that can be converted", filename)
+	}
+
+	stem := strings.TrimSuffix(path.Base(filename), path.Ext(filename))
+	if name == "" {
+		name = category
+	}
+	if name == "" {
+		name = stem
+	}
+	if duration <= 0 {
+		duration = len(questions)
+	}
+
+	quiz := &Quiz{
+		Name:      singleLine(name),
+		Duration:  duration * 60,
+		Questions: make(map[string]QuizQuestion, len(questions)),
+	}
+	for i, question := range questions {
+		question.Sha1 = strconv.Itoa(i + 1)
+		question.Position = i + 1
+		quiz.Questions[question.Sha1] = question
+	}
+
+	return &ImportedQuiz{
+		Filename:    stem + ".quiz.md",
+		Content:     writeQuizFile(quiz),
+		Questions:   len(questions),
+		Diagnostics: diagnostics,
+	}, nil
+}
+
+// ImportQuiz converts a GIFT or Moodle XML file and saves the resulting quiz the same way Sync
+// does. The quiz is not saved when the converted file has errors
+func (s *QuizService) ImportQuiz(ctx context.Context, filename string, content string, format ImportFormat, name string, duration int) (*SyncStats, error) {
+	imported, err := s.ConvertQuiz(filename, content, format, name, duration)
+	if err != nil {
+		return nil, err
+	}
+
+	quiz, diagnostics := s.ParseWithDiagnostics(imported.Filename, imported.Content)
+	diagnostics = append(imported.Diagnostics, diagnostics...)
+	if quiz == nil {
+		return &SyncStats{Diagnostics: diagnostics}, nil
+	}
+
+	quiz.Source = importSourceName
+	quiz.Sha1 = sourceSha1(quiz.Source, quiz.Sha1)
+	stats, err := s.SaveQuiz(ctx, quiz)
+	if err != nil {
+		return nil, err
+	}
+	stats.Diagnostics = diagnostics
+
+	return stats, nil
+}
+
+// importedAnswers keys the answers of an imported question, keeping their order
+func importedAnswers(answers []QuizQuestionAnswer) map[string]QuizQuestionAnswer {
+	keyed := make(map[string]QuizQuestionAnswer, len(answers))
+	for i, answer := range answers {
+		answer.Sha1 = strconv.Itoa(i + 1)
+		answer.Ordinal = i + 1
+		keyed[answer.Sha1] = answer
+	}
+
+	return keyed
+}
+
+// wildcardAnswer turns a short answer where '*' matches any text into the matching regular
+// expression, the answer being kept as is when it has no wildcard
+func wildcardAnswer(content string, caseSensitive bool) QuizQuestionAnswer {
+	match := CaseInsensitiveMatch
+	if caseSensitive {
+		match = ExactMatch
+	}
+	if !strings.Contains(content, "*") {
+		return QuizQuestionAnswer{Content: content, Valid: true, Match: match}
+	}
+
+	parts := strings.Split(content, "*")
+	for i, part := range parts {
+		parts[i] = regexp.QuoteMeta(part)
+	}
+	pattern := strings.Join(parts, ".*")
+	if !caseSensitive {
+		pattern = "(?i)" + pattern
+	}
+
+	return QuizQuestionAnswer{Content: pattern, Valid: true, Match: RegexMatch}
+}
+
+// htmlToText keeps the text of an HTML fragment, the paragraphs and line breaks becoming new lines
+func htmlToText(content string) string {
+	content = htmlBreakRegexp.ReplaceAllString(content, "$0\n")
+	content = html.UnescapeString(htmlTagRegexp.ReplaceAllString(content, ""))
+	content = strings.ReplaceAll(content, "\u00a0", " ")
+
+	return strings.TrimSpace(blankLinesRegexp.ReplaceAllString(content, "\n\n"))
+}
+
+// categoryTag gives the name of the last level of a Moodle category, usable as a question tag
+func categoryTag(category string) string {
+	levels := strings.Split(strings.Trim(category, "/ "), "/")
+	tag := strings.TrimSpace(importedTagRegexp.ReplaceAllString(levels[len(levels)-1], " "))
+	if strings.HasPrefix(tag, "$") && strings.HasSuffix(tag, "$") {
+		return ""
+	}
+
+	return tag
+}
+
+// singleLine joins the lines of a text that must hold on one line
+func singleLine(content string) string {
+	return strings.Join(strings.Fields(content), " ")
+}
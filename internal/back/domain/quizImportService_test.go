@@ -0,0 +1,225 @@
+/*
+ * Copyright (c) 2023 Michaël COLL.
+ *
+ * Licensed under the Apache License, Version 2.0 (the "License");
+ * you may not use this file except in compliance with the License.
+ * You may obtain a copy of the License at
+ *
+ *      http://www.apache.org/licenses/LICENSE-2.0
+ *
+ * Unless required by applicable law or agreed to in writing, software
+ * distributed under the License is distributed on an "AS IS" BASIS,
+ * WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+ * See the License for the specific language governing permissions and
+ * limitations under the License.
+ */
+
+package domain
+
+import (
+	"context"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/mock"
+)
+
+const giftContent = `// Marvel questions
+$CATEGORY: $course$/top/Marvel Universe
+
+::Q1:: Who is Iron Man ?{
+=Tony Stark#Right!
+~Steve Rogers#No, he is Captain America
+####Tony Stark built the armor.
+}
+
+Thor is a god.{T}
+
+Who is the green giant ?{=Hulk =Bruce*}
+
+How many infinity stones are there ?{#6:0}
+
+::Q5:: Pair the heroes {=Thor -> Asgard =Hulk -> Earth}
+
+Tell us about your favorite hero.{}
+
+Black Widow's name is {=Natasha =%50%Nat} Romanoff.
+`
+
+const moodleXmlContent = `<?xml version="1.0" encoding="UTF-8"?>
+<quiz>
+  <question type="category">
+    <category><text>$course$/top/Avengers</text></category>
+  </question>
+  <question type="multichoice">
+    <questiontext format="html"><text><![CDATA[<p>Who are <b>Avengers</b> ?</p>]]></text></questiontext>
+    <generalfeedback format="html"><text>The first Avengers.</text></generalfeedback>
+    <defaultgrade>2.0000000</defaultgrade>
+    <answer fraction="50" format="html"><text>Thor</text><feedback format="html"><text>Yes</text></feedback></answer>
+    <answer fraction="50" format="html"><text>Hulk</text></answer>
+    <answer fraction="-100" format="html"><text>Thanos</text></answer>
+  </question>
+  <question type="truefalse">
+    <questiontext format="html"><text>Thanos is an Avenger.</text></questiontext>
+    <answer fraction="0" format="moodle_auto_format"><text>true</text></answer>
+    <answer fraction="100" format="moodle_auto_format"><text>false</text></answer>
+  </question>
+  <question type="shortanswer">
+    <questiontext format="html"><text>Who is Captain America ?</text></questiontext>
+    <usecase>1</usecase>
+    <answer fraction="100" format="moodle_auto_format"><text>Steve Rogers</text></answer>
+  </question>
+  <question type="numerical">
+    <questiontext format="html"><text>In which year was Captain America created ?</text></questiontext>
+    <answer fraction="100" format="moodle_auto_format"><text>1941</text><tolerance>1</tolerance></answer>
+  </question>
+  <question type="essay">
+    <questiontext format="html"><text>Tell us about your favorite hero.</text></questiontext>
+  </question>
+</quiz>
+`
+
+func TestQuizService_ConvertQuiz_gift(t *testing.T) {
+	s := NewQuizService(nil)
+
+	imported, err := s.ConvertQuiz("marvel.gift", giftContent, GiftFormat, "", 0)
+	if err != nil {
+		assert.Fail(t, "Can't convert quiz", "%v", err)
+	}
+
+	assert.Equal(t, "marvel.quiz.md", imported.Filename)
+	assert.Equal(t, 5, imported.Questions)
+	assert.Equal(t, `# Marvel Universe (duration: 5min)
+
+Who is Iron Man ? (tags: Marvel Universe)
+- [x] Tony Stark
+  > Right!
+- [ ] Steve Rogers
+  > No, he is Captain America
+> Explanation: Tony Stark built the armor.
+
+---
+
+Thor is a god. (tags: Marvel Universe)
+- [x] True
+- [ ] False
+
+---
+
+Who is the green giant ? (tags: Marvel Universe)
+- [~] Hulk
+- [/] (?i)Bruce.*
+
+---
+
+How many infinity stones are there ? (tags: Marvel Universe)
+= 6
+
+---
+
+Black Widow's name is _____ Romanoff. (tags: Marvel Universe)
+- [~] Natasha
+`, imported.Content)
+
+	assert.Equal(t, Diagnostics{
+		{Filename: "marvel.gift", Line: 16, Column: 1, Severity: WarningSeverity,
+			Message: "matching question can not be converted"},
+		{Filename: "marvel.gift", Line: 18, Column: 1, Severity: WarningSeverity,
+			Message: "essay question can not be converted"},
+		{Filename: "marvel.gift", Line: 20, Column: 1, Severity: WarningSeverity,
+			Message: "answer 'Nat' gives partial credit, it can not be converted"},
+	}, imported.Diagnostics)
+
+	quiz, diagnostics := s.ParseWithDiagnostics(imported.Filename, imported.Content)
+	assert.Empty(t, diagnostics)
+	assert.Len(t, quiz.Questions, 5)
+}
+
+func TestQuizService_ConvertQuiz_moodle_xml(t *testing.T) {
+	s := NewQuizService(nil)
+
+	imported, err := s.ConvertQuiz("avengers.xml", moodleXmlContent, MoodleXmlFormat, "Avengers assemble", 10)
+	if err != nil {
+		assert.Fail(t, "Can't convert quiz", "%v", err)
+	}
+
+	assert.Equal(t, "avengers.quiz.md", imported.Filename)
+	assert.Equal(t, `# Avengers assemble (duration: 10min)
+
+Who are Avengers ? (points: 2) (tags: Avengers)
+- [x] Thor
+  > Yes
+- [x] Hulk
+- [ ] Thanos
+> Explanation: The first Avengers.
+
+---
+
+Thanos is an Avenger. (tags: Avengers)
+- [ ] True
+- [x] False
+
+---
+
+Who is Captain America ? (tags: Avengers)
+- [=] Steve Rogers
+
+---
+
+In which year was Captain America created ? (tags: Avengers)
+= 1941 ± 1
+`, imported.Content)
+
+	assert.Equal(t, Diagnostics{
+		{Filename: "avengers.xml", Line: 28, Column: 3, Severity: WarningSeverity,
+			Message: "essay question can not be converted"},
+	}, imported.Diagnostics)
+
+	quiz, diagnostics := s.ParseWithDiagnostics(imported.Filename, imported.Content)
+	assert.Empty(t, diagnostics)
+	assert.Len(t, quiz.Questions, 4)
+}
+
+func TestQuizService_ConvertQuiz_errors(t *testing.T) {
+	s := NewQuizService(nil)
+
+	_, err := s.ConvertQuiz("avengers.xml", "<quiz><question>", MoodleXmlFormat, "", 0)
+	assert.Error(t, err)
+
+	_, err = s.ConvertQuiz("essay.gift", "Tell us about your favorite hero.{}", GiftFormat, "", 0)
+	assert.Error(t, err)
+}
+
+func TestParseImportFormat(t *testing.T) {
+	format, err := ParseImportFormat("", "avengers.XML")
+	assert.NoError(t, err)
+	assert.Equal(t, MoodleXmlFormat, format)
+
+	format, err = ParseImportFormat("", "marvel.txt")
+	assert.NoError(t, err)
+	assert.Equal(t, GiftFormat, format)
+
+	_, err = ParseImportFormat("qti", "marvel.xml")
+	assert.Error(t, err)
+}
+
+func TestQuizService_ImportQuiz(t *testing.T) {
+	mockQuizRepository := NewMockQuizRepository(t)
+
+	s := NewQuizService(mockQuizRepository)
+
+	mockQuizRepository.On("FindLatestVersionByFilename", context.Background(), importSourceName, "marvel.quiz.md").Return(nil, nil)
+	mockQuizRepository.On("Create", context.Background(), mock.MatchedBy(func(quiz *Quiz) bool {
+		return quiz.Name == "Marvel Universe" && len(quiz.Questions) == 5
+	})).Return(nil)
+
+	stats, err := s.ImportQuiz(context.Background(), "marvel.gift", giftContent, GiftFormat, "", 0)
+	if err != nil {
+		assert.Fail(t, "Can't import quiz", "%v", err)
+	}
+
+	assert.Equal(t, 1, stats.Created)
+	assert.Len(t, stats.Diagnostics, 3)
+
+	mockQuizRepository.AssertExpectations(t)
+}
@@ -0,0 +1,166 @@
+/*
+ * Copyright (c) 2023 Michaël COLL.
+ *
+ * Licensed under the Apache License, Version 2.0 (the "License");
+ * you may not use this file except in compliance with the License.
+ * You may obtain a copy of the License at
+ *
+ *      http://www.apache.org/licenses/LICENSE-2.0
+ *
+ * Unless required by applicable law or agreed to in writing, software
+ * distributed under the License is distributed on an "AS IS" BASIS,
+ * WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+ * See the License for the specific language governing permissions and
+ * limitations under the License.
+ */
+
+package domain
+
+import (
+	"path"
+	"regexp"
+	"slices"
+	"strings"
+
+	"github.com/go-git/go-billy/v5"
+)
+
+var includeRegexp = regexp.MustCompile(`(?m)^!include[ \t]+([^\s#]+)(?:#(\S+))?[ \t]*(?:\n|$)`)
+var sectionRegexp = regexp.MustCompile(`(?m)^##[ \t]+(.+?)[ \t]*(?:\n|$)`)
+var slugRegexp = regexp.MustCompile(`[^a-z0-9_]+`)
+
+// contentSegment is a part of the expanded content of a quiz file copied from the quiz file itself
+// or from one of the files it includes
+type contentSegment struct {
+	start    int    // offset of the segment in the expanded content
+	filename string // file the segment comes from
+	content  string // content of the file the segment comes from
+	offset   int    // offset of the segment in the file it comes from
+}
+
+type includeExpander struct {
+	fs          billy.Filesystem
+	expanded    strings.Builder
+	segments    []contentSegment
+	stack       []string
+	diagnostics Diagnostics
+}
+
+// expandIncludes replaces the `!include path/to/bank.md#section` lines of a quiz file by the content
+// of the targeted file, or of one of its sections, the path being relative to the including file.
+// The included files can include other files, the cycles being reported as errors. The segments
+// tell which file each part of the expanded content comes from
+func expandIncludes(fs billy.Filesystem, filename string, content string) (string, []contentSegment, Diagnostics) {
+	e := &includeExpander{fs: fs}
+	e.expand(filename, content, 0, len(content))
+
+	return e.expanded.String(), e.segments, e.diagnostics
+}
+
+// expand copies the given part of a file, replacing its include directives
+func (e *includeExpander) expand(filename string, content string, from int, to int) {
+	p := &quizParser{filename: filename, content: content}
+	e.stack = append(e.stack, filename)
+	defer func() { e.stack = e.stack[:len(e.stack)-1] }()
+
+	offset := from
+	for _, match := range includeRegexp.FindAllStringSubmatchIndex(content[from:to], -1) {
+		e.write(content[offset:from+match[0]], filename, content, offset)
+		offset = from + match[1]
+
+		target := path.Join(path.Dir(filename), content[from+match[2]:from+match[3]])
+		section := ""
+		if match[4] >= 0 {
+			section = content[from+match[4] : from+match[5]]
+		}
+		e.include(p, from+match[0], target, section)
+	}
+	e.write(content[offset:to], filename, content, offset)
+
+	e.diagnostics = append(e.diagnostics, p.diagnostics...)
+}
+
+// include copies the file targeted by the include directive found at the given offset. A file
+// included without section has its sections separated like questions
+func (e *includeExpander) include(p *quizParser, offset int, filename string, section string) {
+	if i := slices.Index(e.stack, filename); i >= 0 {
+		p.errorf(offset, "include cycle %s", strings.Join(append(slices.Clone(e.stack[i:]), filename), " -> "))
+		return
+	}
+
+	content, err := readTextFile(e.fs, filename)
+	if err != nil {
+		p.errorf(offset, "can't read included file %s : %v", filename, err)
+		return
+	}
+
+	headings := sectionRegexp.FindAllStringSubmatchIndex(content, -1)
+	if len(section) > 0 {
+		for i, heading := range headings {
+			if slug(content[heading[2]:heading[3]]) != slug(section) {
+				continue
+			}
+
+			end := len(content)
+			if i+1 < len(headings) {
+				end = headings[i+1][0]
+			}
+			e.expand(filename, content, heading[1], end)
+			e.endLine(filename, content, end)
+			return
+		}
+
+		p.errorf(offset, "section #%s not found in %s", section, filename)
+		return
+	}
+
+	var parts [][2]int
+	start := 0
+	for _, heading := range headings {
+		parts = append(parts, [2]int{start, heading[0]})
+		start = heading[1]
+	}
+	parts = append(parts, [2]int{start, len(content)})
+
+	first := true
+	for _, part := range parts {
+		if len(strings.TrimSpace(content[part[0]:part[1]])) == 0 {
+			continue
+		}
+		if !first {
+			e.write("---\n", filename, content, part[0])
+		}
+		first = false
+
+		e.expand(filename, content, part[0], part[1])
+		e.endLine(filename, content, part[1])
+	}
+}
+
+// endLine ends the expanded content with a new line so that the included content does not run
+// into the next line of the including file
+func (e *includeExpander) endLine(filename string, content string, offset int) {
+	if e.expanded.Len() > 0 && !strings.HasSuffix(e.expanded.String(), "\n") {
+		e.write("\n", filename, content, offset)
+	}
+}
+
+// write adds text to the expanded content, text coming from the given offset of a file
+func (e *includeExpander) write(text string, filename string, content string, offset int) {
+	if len(text) == 0 {
+		return
+	}
+
+	e.segments = append(e.segments, contentSegment{
+		start:    e.expanded.Len(),
+		filename: filename,
+		content:  content,
+		offset:   offset,
+	})
+	e.expanded.WriteString(text)
+}
+
+// slug gives the anchor of a section title, like `git-basics` for `Git basics`
+func slug(title string) string {
+	return strings.Trim(slugRegexp.ReplaceAllString(strings.ToLower(title), "-"), "-")
+}
@@ -0,0 +1,183 @@
+/*
+ * Copyright (c) 2023 Michaël COLL.
+ *
+ * Licensed under the Apache License, Version 2.0 (the "License");
+ * you may not use this file except in compliance with the License.
+ * You may obtain a copy of the License at
+ *
+ *      http://www.apache.org/licenses/LICENSE-2.0
+ *
+ * Unless required by applicable law or agreed to in writing, software
+ * distributed under the License is distributed on an "AS IS" BASIS,
+ * WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+ * See the License for the specific language governing permissions and
+ * limitations under the License.
+ */
+
+package domain
+
+import (
+	"testing"
+
+	"github.com/go-git/go-billy/v5"
+	"github.com/go-git/go-billy/v5/memfs"
+	"github.com/go-git/go-billy/v5/util"
+	"github.com/stretchr/testify/assert"
+)
+
+const gitBankContent = `## Git basics
+
+Which command creates a commit ?
+
+- [x] git commit
+- [ ] git add
+
+---
+Which command downloads a repository ?
+
+- [x] git clone
+- [ ] git init
+
+## Git branches
+
+Which command creates a branch ?
+
+![branch](images/branch.png)
+
+- [x] git branch
+- [ ] git tag
+`
+
+func includeFs(t *testing.T, files map[string]string) billy.Filesystem {
+	fs := memfs.New()
+	for filename, content := range files {
+		if err := util.WriteFile(fs, filename, []byte(content), 0644); err != nil {
+			assert.Fail(t, "Can't write file", "%v", err)
+		}
+	}
+
+	return fs
+}
+
+func TestQuizService_parseQuizFile_include_section(t *testing.T) {
+	fs := includeFs(t, map[string]string{
+		"git.quiz.md":  "# Git (duration: 5min)\n\n!include banks/git.md#git-basics\n---\nWhich command shows the history ?\n\n- [x] git log\n- [ ] git show\n",
+		"banks/git.md": gitBankContent,
+	})
+
+	s := NewQuizService(nil)
+	quiz, diagnostics, err := s.parseQuizFile(fs, "git.quiz.md")
+	if err != nil {
+		assert.Fail(t, "Can't parse quiz", "%v", err)
+	}
+
+	assert.Empty(t, diagnostics)
+	assert.Len(t, quiz.Questions, 3)
+
+	contents := map[int]string{}
+	for _, question := range quiz.Questions {
+		contents[question.Position] = question.Content
+	}
+	assert.Equal(t, map[int]string{
+		1: "Which command creates a commit ?",
+		2: "Which command downloads a repository ?",
+		3: "Which command shows the history ?",
+	}, contents)
+}
+
+func TestQuizService_parseQuizFile_include_whole_file(t *testing.T) {
+	fs := includeFs(t, map[string]string{
+		"git.quiz.md":             "# Git (duration: 5min)\n\n!include banks/git.md\n",
+		"banks/git.md":            "Which command shows the status ?\n\n- [x] git status\n- [ ] git diff\n---\n!include git-branches.md\n",
+		"banks/git-branches.md":   gitBankContent,
+		"banks/images/branch.png": "branch",
+	})
+
+	s := NewQuizService(nil)
+	quiz, diagnostics, err := s.parseQuizFile(fs, "git.quiz.md")
+	if err != nil {
+		assert.Fail(t, "Can't parse quiz", "%v", err)
+	}
+
+	assert.Empty(t, diagnostics)
+	assert.Len(t, quiz.Questions, 4)
+	assert.Len(t, quiz.Assets, 1)
+	assert.Contains(t, quiz.Assets, getSha1("branch"))
+}
+
+func TestQuizService_parseQuizFile_include_changes_sha1(t *testing.T) {
+	s := NewQuizService(nil)
+	quizContent := "# Git (duration: 5min)\n\n!include banks/git.md#git-branches\n"
+
+	quiz, _, err := s.parseQuizFile(includeFs(t, map[string]string{
+		"git.quiz.md":             quizContent,
+		"banks/git.md":            gitBankContent,
+		"banks/images/branch.png": "branch",
+	}), "git.quiz.md")
+	if err != nil {
+		assert.Fail(t, "Can't parse quiz", "%v", err)
+	}
+
+	changed, _, err := s.parseQuizFile(includeFs(t, map[string]string{
+		"git.quiz.md":             quizContent,
+		"banks/git.md":            gitBankContent + "- [ ] git switch\n",
+		"banks/images/branch.png": "branch",
+	}), "git.quiz.md")
+	if err != nil {
+		assert.Fail(t, "Can't parse quiz", "%v", err)
+	}
+
+	assert.NotEqual(t, quiz.Sha1, changed.Sha1)
+}
+
+func TestQuizService_parseQuizFile_include_errors(t *testing.T) {
+	fs := includeFs(t, map[string]string{
+		"cycle.quiz.md":   "# Cycle (duration: 5min)\n\n!include banks/a.md\n",
+		"missing.quiz.md": "# Missing (duration: 5min)\n\n!include banks/b.md#unknown\n---\n!include banks/missing.md\n",
+		"banks/a.md":      "Question A ?\n\n- [x] A\n---\n!include b.md\n",
+		"banks/b.md":      "Question B ?\n\n- [x] B\n---\n!include a.md\n",
+	})
+	s := NewQuizService(nil)
+
+	quiz, diagnostics, err := s.parseQuizFile(fs, "cycle.quiz.md")
+	if err != nil {
+		assert.Fail(t, "Can't parse quiz", "%v", err)
+	}
+
+	assert.Nil(t, quiz)
+	assert.Equal(t, Diagnostics{
+		{Filename: "banks/b.md", Line: 5, Column: 1, Severity: ErrorSeverity,
+			Message: "include cycle banks/a.md -> banks/b.md -> banks/a.md"},
+	}, diagnostics)
+
+	quiz, diagnostics, err = s.parseQuizFile(fs, "missing.quiz.md")
+	if err != nil {
+		assert.Fail(t, "Can't parse quiz", "%v", err)
+	}
+
+	assert.Nil(t, quiz)
+	assert.Len(t, diagnostics, 2)
+	assert.Equal(t, 3, diagnostics[0].Line)
+	assert.Equal(t, "section #unknown not found in banks/b.md", diagnostics[0].Message)
+	assert.Equal(t, 5, diagnostics[1].Line)
+	assert.Contains(t, diagnostics[1].Message, "can't read included file banks/missing.md")
+}
+
+func TestQuizService_parseQuizFile_include_diagnostics_location(t *testing.T) {
+	fs := includeFs(t, map[string]string{
+		"git.quiz.md":  "# Git (duration: 5min)\n\n!include banks/git.md\n---\nWho is Linus ?\n\n- [ ] Torvalds\n",
+		"banks/git.md": "Which command creates a commit ?\n\n- [x] git commit\n---\nWhich command adds a file ?\n\n- [ ] git add\n",
+	})
+
+	s := NewQuizService(nil)
+	_, diagnostics, err := s.parseQuizFile(fs, "git.quiz.md")
+	if err != nil {
+		assert.Fail(t, "Can't parse quiz", "%v", err)
+	}
+
+	assert.Len(t, diagnostics, 2)
+	assert.Equal(t, "git.quiz.md", diagnostics[0].Filename)
+	assert.Equal(t, 5, diagnostics[0].Line)
+	assert.Equal(t, "banks/git.md", diagnostics[1].Filename)
+	assert.Equal(t, 5, diagnostics[1].Line)
+}
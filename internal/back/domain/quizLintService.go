@@ -0,0 +1,122 @@
+/*
+ * Copyright (c) 2023 Michaël COLL.
+ *
+ * Licensed under the Apache License, Version 2.0 (the "License");
+ * you may not use this file except in compliance with the License.
+ * You may obtain a copy of the License at
+ *
+ *      http://www.apache.org/licenses/LICENSE-2.0
+ *
+ * Unless required by applicable law or agreed to in writing, software
+ * distributed under the License is distributed on an "AS IS" BASIS,
+ * WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+ * See the License for the specific language governing permissions and
+ * limitations under the License.
+ */
+
+package domain
+
+import (
+	"os"
+	"path/filepath"
+	"strings"
+
+	"github.com/go-git/go-billy/v5/osfs"
+)
+
+// Lint checks the quiz files of a local directory, of a zip archive or of a git repository the same
+// way Sync does, without saving anything
+func (s *QuizService) Lint(source string, token string) (*LintReport, error) {
+	fs, _, err := lintSource(source, token).Open()
+	if err != nil {
+		return nil, err
+	}
+
+	filenames, err := quizFilenames(fs)
+	if err != nil {
+		return nil, err
+	}
+
+	_, diagnostics, err := s.scanQuizzes(fs)
+	if err != nil {
+		return nil, err
+	}
+
+	return &LintReport{
+		Files:       len(filenames),
+		Diagnostics: diagnostics,
+	}, nil
+}
+
+// ParseFile parses a local quiz file and collects its assets the same way Sync does. The quiz is nil
+// when the file has errors
+func (s *QuizService) ParseFile(filename string) (*Quiz, Diagnostics, error) {
+	root, relative, err := quizFileRoot(filename)
+	if err != nil {
+		return nil, nil, err
+	}
+
+	return s.parseQuizFile(osfs.New(root), relative)
+}
+
+// quizFileRoot gives the folder the includes and the assets of a local quiz file are read from, with
+// the path of the file in it : the git repository holding the file, else the working directory when
+// it holds the file, else the folder of the file
+func quizFileRoot(filename string) (string, string, error) {
+	absolute, err := filepath.Abs(filename)
+	if err != nil {
+		return "", "", err
+	}
+
+	root := filepath.Dir(absolute)
+	if repository, found := gitRepositoryOf(root); found {
+		root = repository
+	} else if wd, err := os.Getwd(); err == nil && isInside(absolute, wd) {
+		root = wd
+	}
+
+	relative, err := filepath.Rel(root, absolute)
+	if err != nil {
+		return "", "", err
+	}
+
+	return root, filepath.ToSlash(relative), nil
+}
+
+// gitRepositoryOf gives the top folder of the git repository holding the folder
+func gitRepositoryOf(dir string) (string, bool) {
+	for {
+		if _, err := os.Stat(filepath.Join(dir, ".git")); err == nil {
+			return dir, true
+		}
+
+		parent := filepath.Dir(dir)
+		if parent == dir {
+			return "", false
+		}
+		dir = parent
+	}
+}
+
+func isInside(filename string, dir string) bool {
+	relative, err := filepath.Rel(dir, filename)
+
+	return err == nil && relative != ".." && !strings.HasPrefix(relative, ".."+string(filepath.Separator))
+}
+
+// lintSource gives the source of the quiz files to check : a git repository cloned in memory when
+// given a url, a zip archive or a local directory
+func lintSource(source string, token string) QuizSource {
+	if isGitUrl(source) {
+		return gitSource{name: source, url: source, token: token}
+	}
+	if strings.HasSuffix(strings.ToLower(source), ".zip") {
+		return archiveSource{name: source, file: source}
+	}
+
+	return dirSource{name: source, path: source}
+}
+
+func isGitUrl(source string) bool {
+	return strings.Contains(source, "://") || strings.HasPrefix(source, "git@")
+}
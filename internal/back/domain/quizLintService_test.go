@@ -0,0 +1,110 @@
+/*
+ * Copyright (c) 2023 Michaël COLL.
+ *
+ * Licensed under the Apache License, Version 2.0 (the "License");
+ * you may not use this file except in compliance with the License.
+ * You may obtain a copy of the License at
+ *
+ *      http://www.apache.org/licenses/LICENSE-2.0
+ *
+ * Unless required by applicable law or agreed to in writing, software
+ * distributed under the License is distributed on an "AS IS" BASIS,
+ * WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+ * See the License for the specific language governing permissions and
+ * limitations under the License.
+ */
+
+package domain
+
+import (
+	"os"
+	"path/filepath"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+)
+
+func TestQuizService_Lint(t *testing.T) {
+	dir := t.TempDir()
+
+	files := map[string]string{
+		"avengers.quiz.md": "# Avengers (duration: 5min)\n\nWho is Iron Man ?\n\n- [x] Tony Stark\n- [ ] Steve Rogers\n",
+		"broken.quiz.md":   "# Broken\n\nWho is Hulk ?\n\n- [ ] Bruce Banner\n",
+		"README.md":        "# Quizzes\n",
+	}
+	for name, content := range files {
+		if err := os.WriteFile(filepath.Join(dir, name), []byte(content), 0644); err != nil {
+			assert.Fail(t, "Can't write quiz file", "%v", err)
+		}
+	}
+
+	s := NewQuizService(nil)
+
+	report, err := s.Lint(dir, "")
+	if err != nil {
+		assert.Fail(t, "Can't lint quizzes", "%v", err)
+	}
+
+	assert.Equal(t, 2, report.Files)
+	assert.Equal(t, Diagnostics{
+		{Filename: "broken.quiz.md", Line: 1, Column: 1, Severity: ErrorSeverity,
+			Message: "quiz name or quiz duration not found. The first line must be '# <Name> (duration: <duration>min)"},
+		{Filename: "broken.quiz.md", Line: 3, Column: 1, Severity: WarningSeverity,
+			Message: "question has no valid answer, it is right only when no answer is checked"},
+	}, report.Diagnostics)
+}
+
+func TestQuizService_Lint_missing_directory(t *testing.T) {
+	s := NewQuizService(nil)
+
+	_, err := s.Lint(filepath.Join(t.TempDir(), "missing"), "")
+	assert.Error(t, err)
+}
+
+func TestQuizService_Lint_git_repository(t *testing.T) {
+	s := NewQuizService(nil)
+
+	abs, err := filepath.Abs("../../../.")
+	if err != nil {
+		assert.Fail(t, "Can't find the repository", "%v", err)
+	}
+
+	report, err := s.Lint("file://"+abs, "")
+	if err != nil {
+		assert.Fail(t, "Can't lint quizzes", "%v", err)
+	}
+
+	assert.Equal(t, 2, report.Files)
+	assert.Empty(t, report.Diagnostics)
+}
+
+func TestQuizService_ParseFile_include_parent_folder(t *testing.T) {
+	dir := t.TempDir()
+
+	files := map[string]string{
+		"banks/heroes.md":          "Who is Iron Man ?\n\n- [x] Tony Stark\n- [ ] Steve Rogers\n",
+		"quizzes/avengers.quiz.md": "# Avengers (duration: 5min)\n\n!include ../banks/heroes.md\n",
+		".git/HEAD":                "ref: refs/heads/main\n",
+	}
+	for name, content := range files {
+		if err := os.MkdirAll(filepath.Dir(filepath.Join(dir, name)), 0755); err != nil {
+			assert.Fail(t, "Can't create folder", "%v", err)
+		}
+		if err := os.WriteFile(filepath.Join(dir, name), []byte(content), 0644); err != nil {
+			assert.Fail(t, "Can't write quiz file", "%v", err)
+		}
+	}
+
+	s := NewQuizService(nil)
+
+	quiz, diagnostics, err := s.ParseFile(filepath.Join(dir, "quizzes", "avengers.quiz.md"))
+	if err != nil {
+		assert.Fail(t, "Can't parse quiz file", "%v", err)
+	}
+
+	assert.Empty(t, diagnostics)
+	if assert.NotNil(t, quiz) {
+		assert.Equal(t, "quizzes/avengers.quiz.md", quiz.Filename)
+		assert.Len(t, quiz.Questions, 1)
+	}
+}
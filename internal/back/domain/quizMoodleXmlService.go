@@ -0,0 +1,186 @@
+/*
+ * Copyright (c) 2023 Michaël COLL.
+ *
+ * Licensed under the Apache License, Version 2.0 (the "License");
+ * you may not use this file except in compliance with the License.
+ * You may obtain a copy of the License at
+ *
+ *      http://www.apache.org/licenses/LICENSE-2.0
+ *
+ * Unless required by applicable law or agreed to in writing, software
+ * distributed under the License is distributed on an "AS IS" BASIS,
+ * WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+ * See the License for the specific language governing permissions and
+ * limitations under the License.
+ */
+
+package domain
+
+import (
+	"encoding/xml"
+	"errors"
+	"io"
+	"math"
+	"strconv"
+	"strings"
+)
+
+type moodleText struct {
+	Format string `xml:"format,attr"`
+	Text   string `xml:"text"`
+}
+
+// text gives the content of the text, without its markup when it is written in HTML
+func (t moodleText) text() string {
+	if t.Format == "html" {
+		return htmlToText(t.Text)
+	}
+
+	return strings.TrimSpace(t.Text)
+}
+
+type moodleAnswer struct {
+	moodleText
+	Fraction  string     `xml:"fraction,attr"`
+	Feedback  moodleText `xml:"feedback"`
+	Tolerance string     `xml:"tolerance"`
+}
+
+func (a moodleAnswer) fraction() float64 {
+	fraction, _ := strconv.ParseFloat(a.Fraction, 64)
+	return fraction
+}
+
+type moodleQuestion struct {
+	Type            string         `xml:"type,attr"`
+	Category        moodleText     `xml:"category"`
+	QuestionText    moodleText     `xml:"questiontext"`
+	GeneralFeedback moodleText     `xml:"generalfeedback"`
+	DefaultGrade    string         `xml:"defaultgrade"`
+	UseCase         string         `xml:"usecase"`
+	Answers         []moodleAnswer `xml:"answer"`
+}
+
+// readMoodleXml reads the questions of a Moodle XML file and gives the name of its first category.
+// The questions of a category are tagged with the last level of the category
+func readMoodleXml(filename string, content string) ([]QuizQuestion, string, Diagnostics, error) {
+	p := &quizParser{filename: filename, content: content}
+	decoder := xml.NewDecoder(strings.NewReader(content))
+
+	var questions []QuizQuestion
+	firstCategory, tag := "", ""
+	for {
+		offset := int(decoder.InputOffset())
+		token, err := decoder.Token()
+		if errors.Is(err, io.EOF) {
+			break
+		}
+		if err != nil {
+			return nil, "", nil, err
+		}
+
+		start, ok := token.(xml.StartElement)
+		if !ok || start.Name.Local != "question" {
+			continue
+		}
+
+		var mq moodleQuestion
+		if err := decoder.DecodeElement(&mq, &start); err != nil {
+			return nil, "", nil, err
+		}
+
+		if mq.Type == "category" {
+			tag = categoryTag(mq.Category.Text)
+			if firstCategory == "" {
+				firstCategory = tag
+			}
+			continue
+		}
+
+		if question, ok := p.readMoodleQuestion(mq, offset); ok {
+			if tag != "" {
+				question.Tags = []string{tag}
+			}
+			questions = append(questions, question)
+		}
+	}
+
+	return questions, firstCategory, p.diagnostics, nil
+}
+
+// readMoodleQuestion converts a Moodle question, the questions that can not be converted are
+// reported
+func (p *quizParser) readMoodleQuestion(mq moodleQuestion, offset int) (QuizQuestion, bool) {
+	question := QuizQuestion{
+		Content:     mq.QuestionText.text(),
+		Explanation: mq.GeneralFeedback.text(),
+		Points:      1,
+	}
+	var answers []QuizQuestionAnswer
+	if grade, err := strconv.ParseFloat(strings.TrimSpace(mq.DefaultGrade), 64); err == nil && grade > 1 {
+		question.Points = int(math.Round(grade))
+	}
+
+	switch mq.Type {
+	case "multichoice", "truefalse":
+		question.Kind = Choice
+		for _, answer := range mq.Answers {
+			content := answer.text()
+			if mq.Type == "truefalse" {
+				content = strings.ToUpper(content[:min(1, len(content))]) + content[min(1, len(content)):]
+			}
+			answers = append(answers, QuizQuestionAnswer{
+				Content:     content,
+				Valid:       answer.fraction() > 0,
+				Explanation: answer.Feedback.text(),
+			})
+		}
+	case "shortanswer":
+		question.Kind = ShortAnswer
+		for _, answer := range mq.Answers {
+			if answer.fraction() < 100 {
+				p.warnf(offset, "answer '%s' gives partial credit, it can not be converted", answer.text())
+				continue
+			}
+			imported := wildcardAnswer(answer.text(), mq.UseCase == "1")
+			imported.Explanation = answer.Feedback.text()
+			answers = append(answers, imported)
+		}
+	case "numerical":
+		question.Kind = Numeric
+		for _, answer := range mq.Answers {
+			text := answer.text()
+			if answer.fraction() < 100 {
+				p.warnf(offset, "answer '%s' gives partial credit, it can not be converted", text)
+				continue
+			}
+
+			value := text
+			if tolerance := strings.TrimSpace(answer.Tolerance); tolerance != "" {
+				value += ":" + tolerance
+			}
+			content, ok := numericRange(value)
+			if !ok {
+				p.warnf(offset, "answer '%s' is not a valid number, it can not be converted", text)
+				continue
+			}
+			answers = append(answers, QuizQuestionAnswer{
+				Content:     content,
+				Valid:       true,
+				Match:       ToleranceMatch,
+				Explanation: answer.Feedback.text(),
+			})
+		}
+	default:
+		p.warnf(offset, "%s question can not be converted", mq.Type)
+		return QuizQuestion{}, false
+	}
+
+	if len(answers) == 0 {
+		p.warnf(offset, "question has no answer that can be converted")
+		return QuizQuestion{}, false
+	}
+	question.Answers = importedAnswers(answers)
+
+	return question, true
+}
@@ -19,42 +19,158 @@ package domain
 import (
 	"crypto/sha1"
 	"encoding/hex"
+	"errors"
 	"fmt"
+	"io"
 	"regexp"
+	"sort"
 	"strconv"
 	"strings"
 	"time"
+	"unicode/utf8"
+
+	"go.yaml.in/yaml/v3"
 )
 
+const frontMatterDelimiter = "---\n"
+
 var quizNameRegexp = regexp.MustCompile(`^# (?P<quizName>.*) \(duration: (?P<duration>[0-9]+)min\)`)
 var quizQuestionRegexp = regexp.MustCompile(`^# .*\n`)
 var quizquestionCodeRegexp = regexp.MustCompile("```(?P<language>.*)\\n(?s)(?P<code>.*?)\\n```")
-var quizAnswersRegexp = regexp.MustCompile(`(- \[[ xX]] .*\n)+`)
-var quizAnswerRegexp = regexp.MustCompile(`- \[[ xX]] .*`)
+var quizAnswersRegexp = regexp.MustCompile(`((?:- \[[ xX=~/]] |(?m:^)= |(?m:^)[0-9]+\. |(?m:^)- .+ -> |(?m:^)[ \t]+> ).*\n)+`)
+var quizAnswerRegexp = regexp.MustCompile(`(?:- \[[ xX=~/]] |(?m:^)= |(?m:^)[0-9]+\. |(?m:^)- .+ -> ).*`)
 var quizValidAnswerRegexp = regexp.MustCompile(`- \[[xX]] .*`)
+var quizTextAnswerRegexp = regexp.MustCompile(`- \[[=~/]] .*`)
+var quizNumericAnswerRegexp = regexp.MustCompile(`^= .*`)
+var quizOrderingAnswerRegexp = regexp.MustCompile(`^[0-9]+\. `)
+var quizPairAnswerRegexp = regexp.MustCompile(`^- (.+?) -> (.+)$`)
+var quizQuestionMarkerRegexp = regexp.MustCompile(`\s*\((partial credit|points: ([0-9]+)|tags: ([^()\n]*)|id: ([^()\n]*))\)$`)
+var quizExplanationRegexp = regexp.MustCompile(`(?m)^> Explanation:[ \t]*(.*)(?:\n|$)((?:^>.*(?:\n|$))*)`)
+var quizAnswerExplanationRegexp = regexp.MustCompile(`^[ \t]+>[ \t]?(?:Explanation:[ \t]*)?(.*)$`)
+var toleranceSeparatorRegexp = regexp.MustCompile(`±|\+/-`)
+var yamlErrorLineRegexp = regexp.MustCompile(`line ([0-9]+): (.*)`)
+
+// maxAnswers is the maximum number of answers of a question
+const maxAnswers = 10
+
+var answerMatchMapping = map[byte]AnswerMatch{
+	'=': ExactMatch,
+	'~': CaseInsensitiveMatch,
+	'/': RegexMatch,
+}
+var tagRegexp = regexp.MustCompile(`^[^,\n]+$`)
+var questionIdRegexp = regexp.MustCompile(`^[A-Za-z0-9][A-Za-z0-9._-]*$`)
+
+type frontMatter struct {
+	Description      string                            `yaml:"description,omitempty"`
+	Tags             []string                          `yaml:"tags,omitempty"`
+	Author           string                            `yaml:"author,omitempty"`
+	PassMark         int                               `yaml:"pass-mark,omitempty"`
+	ShuffleQuestions bool                              `yaml:"shuffle-questions,omitempty"`
+	ShuffleAnswers   bool                              `yaml:"shuffle-answers,omitempty"`
+	MaxAttempts      int                               `yaml:"max-attempts,omitempty"`
+	Scoring          string                            `yaml:"scoring,omitempty"`
+	Pool             frontMatterPool                   `yaml:"pool,omitempty"`
+	Lang             string                            `yaml:"lang,omitempty"`
+	Id               string                            `yaml:"id,omitempty"`
+	Translations     map[string]frontMatterTranslation `yaml:"translations,omitempty"`
+}
+
+type frontMatterTranslation struct {
+	Name        string `yaml:"name,omitempty"`
+	Description string `yaml:"description,omitempty"`
+}
+
+type frontMatterPool struct {
+	Draw int      `yaml:"draw,omitempty"`
+	Tags []string `yaml:"tags,omitempty"`
+}
+
+var scoringMapping = map[string]ScoringStrategy{
+	"":               AllOrNothing,
+	"all-or-nothing": AllOrNothing,
+	"partial":        Proportional,
+	"negative":       NegativeMarking,
+}
 
-// Parse parse the content of a quiz file
+// quizParser collects the diagnostics found while parsing the content of a quiz file
+type quizParser struct {
+	filename    string
+	content     string
+	segments    []contentSegment
+	diagnostics Diagnostics
+	// lang is the language of the quiz file and translations the texts of the quiz in the other
+	// languages, read from the front-matter and the translation blocks
+	lang         string
+	translations map[string]QuizTranslation
+}
+
+// Parse parse the content of a quiz file. The error is the Diagnostics of the file when it has errors
 func (s *QuizService) Parse(filename string, content string) (*Quiz, error) {
+	quiz, diagnostics := s.ParseWithDiagnostics(filename, content)
+	if diagnostics.HasErrors() {
+		return nil, diagnostics
+	}
 
-	name, duration, err := extractQuizNameAndDuration(content)
-	if err != nil {
-		return nil, err
+	return quiz, nil
+}
+
+// ParseWithDiagnostics parse the content of a quiz file and reports all the problems found in it.
+// The quiz is nil when one of them is an error
+func (s *QuizService) ParseWithDiagnostics(filename string, content string) (*Quiz, Diagnostics) {
+	return s.parse(&quizParser{filename: filename, content: normalizeLineEndings(content)})
+}
+
+// normalizeLineEndings turns the Windows line endings of a text into '\n', the only line ending
+// the parser knows, so that the front-matter of a file saved with CRLF line endings is found
+func normalizeLineEndings(content string) string {
+	return strings.ReplaceAll(content, "\r\n", "\n")
+}
+
+// parse parses the content of the parser, the diagnostics being located in the files the content
+// comes from when the quiz file includes other files
+func (s *QuizService) parse(p *quizParser) (*Quiz, Diagnostics) {
+	filename, content := p.filename, p.content
+
+	metadata, body, found := p.extractFrontMatter()
+	if !found {
+		return nil, p.diagnostics
 	}
+	offset := len(content) - len(body)
+
+	name, duration := p.extractQuizNameAndDuration(body, offset)
+	questions := p.extractQuestions(body, offset)
+	p.checkPool(metadata.Pool, questions)
+
+	sort.SliceStable(p.diagnostics, func(i, j int) bool {
+		if a, b := p.diagnostics[i].Filename, p.diagnostics[j].Filename; a != b {
+			if a == filename || b == filename {
+				return a == filename
+			}
+			return a < b
+		}
+		if p.diagnostics[i].Line != p.diagnostics[j].Line {
+			return p.diagnostics[i].Line < p.diagnostics[j].Line
+		}
+		return p.diagnostics[i].Column < p.diagnostics[j].Column
+	})
 
-	questions, err := extractQuestions(content)
-	if err != nil {
-		return nil, err
+	if p.diagnostics.HasErrors() {
+		return nil, p.diagnostics
 	}
 
 	return &Quiz{
-		Sha1:      getSha1(content),
-		Name:      name,
-		Filename:  filename,
-		CreatedAt: time.Now().Format(time.RFC3339),
-		Version:   1,
-		Duration:  duration,
-		Questions: questions,
-	}, nil
+		Sha1:         getSha1(content),
+		Name:         name,
+		Filename:     filename,
+		Category:     quizCategory(filename),
+		CreatedAt:    time.Now().Format(time.RFC3339),
+		Version:      1,
+		Duration:     duration,
+		Metadata:     metadata,
+		Questions:    questions,
+		Translations: p.translations,
+	}, p.diagnostics
 }
 
 func getSha1(content string) string {
@@ -63,60 +179,415 @@ func getSha1(content string) string {
 	return hex.EncodeToString(algorithm.Sum(nil))
 }
 
-func extractQuizNameAndDuration(content string) (string, int, error) {
+func (p *quizParser) errorf(offset int, format string, a ...interface{}) {
+	p.report(ErrorSeverity, offset, format, a...)
+}
+
+func (p *quizParser) warnf(offset int, format string, a ...interface{}) {
+	p.report(WarningSeverity, offset, format, a...)
+}
+
+// report adds a diagnostic located at the given offset of the file content
+func (p *quizParser) report(severity Severity, offset int, format string, a ...interface{}) {
+	filename, line, column := p.locate(offset)
+
+	p.diagnostics = append(p.diagnostics, Diagnostic{
+		Filename: filename,
+		Line:     line,
+		Column:   column,
+		Severity: severity,
+		Message:  fmt.Sprintf(format, a...),
+	})
+}
+
+// locate gives the file, the line and the column of the given offset of the content, the content
+// being made of segments of several files when the quiz file includes other files
+func (p *quizParser) locate(offset int) (string, int, int) {
+	filename, content := p.filename, p.content
+	offset = min(max(offset, 0), len(content))
+
+	for i := len(p.segments) - 1; i >= 0; i-- {
+		if segment := p.segments[i]; segment.start <= offset {
+			filename, content, offset = segment.filename, segment.content, segment.offset+offset-segment.start
+			break
+		}
+	}
+
+	before := content[:min(offset, len(content))]
+	lineStart := strings.LastIndex(before, "\n") + 1
+
+	return filename, strings.Count(before, "\n") + 1, utf8.RuneCountInString(before[lineStart:]) + 1
+}
+
+// offsetOf gives the offset in the file of the first occurrence of part in text, text starting at
+// the given offset of the file
+func offsetOf(text string, offset int, part string) int {
+	if i := strings.Index(text, part); i >= 0 {
+		return offset + i
+	}
+
+	return offset
+}
+
+// contentStart gives the offset in the file of the first character of text that is not a blank,
+// text starting at the given offset of the file
+func contentStart(text string, offset int) int {
+	return offset + len(text) - len(strings.TrimLeft(text, " \n"))
+}
+
+// extractFrontMatter splits the optional YAML block enclosed in '---' lines at the top of the file
+// from the rest of the content and validates it. The body is not found when the block is not closed
+func (p *quizParser) extractFrontMatter() (QuizMetadata, string, bool) {
+	if !strings.HasPrefix(p.content, frontMatterDelimiter) {
+		return QuizMetadata{}, p.content, true
+	}
+
+	rest := p.content[len(frontMatterDelimiter):]
+	var yamlStr, body string
+	if strings.HasPrefix(rest, frontMatterDelimiter) {
+		body = rest[len(frontMatterDelimiter):]
+	} else {
+		end := strings.Index(rest, "\n"+frontMatterDelimiter)
+		if end == -1 {
+			p.errorf(0, "front-matter is not closed. It must end with a '---' line")
+			return QuizMetadata{}, "", false
+		}
+		yamlStr = rest[:end+1]
+		body = rest[end+1+len(frontMatterDelimiter):]
+	}
+
+	var fm frontMatter
+	decoder := yaml.NewDecoder(strings.NewReader(yamlStr))
+	decoder.KnownFields(true)
+	if err := decoder.Decode(&fm); err != nil && !errors.Is(err, io.EOF) {
+		p.reportYamlError(err)
+	}
+
+	if fm.PassMark < 0 || fm.PassMark > 100 {
+		p.errorf(p.frontMatterKeyOffset("pass-mark"), "front-matter is not valid (pass-mark must be between 0 and 100, got %d)", fm.PassMark)
+	}
+	if fm.MaxAttempts < 0 {
+		p.errorf(p.frontMatterKeyOffset("max-attempts"), "front-matter is not valid (max-attempts must be positive, got %d)", fm.MaxAttempts)
+	}
+	scoring, found := scoringMapping[fm.Scoring]
+	if !found {
+		p.errorf(p.frontMatterKeyOffset("scoring"), "front-matter is not valid (scoring must be all-or-nothing, partial or negative, got '%s')", fm.Scoring)
+	}
+	for _, tag := range fm.Tags {
+		if !tagRegexp.MatchString(tag) {
+			p.errorf(p.frontMatterKeyOffset("tags"), "front-matter is not valid (tag '%s' must not be empty or contain a comma)", tag)
+		}
+	}
+	if fm.Pool.Draw < 0 {
+		p.errorf(p.frontMatterKeyOffset("pool"), "front-matter is not valid (pool draw must be positive, got %d)", fm.Pool.Draw)
+	} else if fm.Pool.Draw == 0 && len(fm.Pool.Tags) > 0 {
+		p.errorf(p.frontMatterKeyOffset("pool"), "front-matter is not valid (pool tags need the number of questions to draw)")
+	}
+	for _, tag := range fm.Pool.Tags {
+		if !tagRegexp.MatchString(tag) {
+			p.errorf(p.frontMatterKeyOffset("pool"), "front-matter is not valid (pool tag '%s' must not be empty or contain a comma)", tag)
+		}
+	}
+	p.checkLangs(fm)
+
+	return QuizMetadata{
+		Description:      strings.TrimSpace(fm.Description),
+		Tags:             fm.Tags,
+		Author:           fm.Author,
+		PassMark:         fm.PassMark,
+		ShuffleQuestions: fm.ShuffleQuestions,
+		ShuffleAnswers:   fm.ShuffleAnswers,
+		MaxAttempts:      fm.MaxAttempts,
+		Scoring:          scoring,
+		Pool:             QuizPool{Draw: fm.Pool.Draw, Tags: fm.Pool.Tags},
+		Lang:             fm.Lang,
+		Id:               fm.Id,
+	}, strings.TrimLeft(body, "\n"), true
+}
+
+// reportYamlError reports the errors of the front-matter at the line given by the YAML decoder
+func (p *quizParser) reportYamlError(err error) {
+	messages := []string{err.Error()}
+	var typeError *yaml.TypeError
+	if errors.As(err, &typeError) {
+		messages = typeError.Errors
+	}
+
+	for _, message := range messages {
+		offset := 0
+		if subMatch := yamlErrorLineRegexp.FindStringSubmatch(message); subMatch != nil {
+			line, _ := strconv.Atoi(subMatch[1])
+			// the YAML block starts on the second line of the file
+			offset = lineOffset(p.content, line+1)
+			message = subMatch[2]
+		}
+
+		p.errorf(offset, "front-matter is not valid (%s)", message)
+	}
+}
+
+// frontMatterKeyOffset gives the offset of the line declaring the given front-matter key
+func (p *quizParser) frontMatterKeyOffset(key string) int {
+	if i := strings.Index(p.content, "\n"+key+":"); i >= 0 {
+		return i + 1
+	}
+
+	return 0
+}
+
+// lineOffset gives the offset of the start of the given line
+func lineOffset(content string, line int) int {
+	offset := 0
+	for i := 1; i < line; i++ {
+		next := strings.Index(content[offset:], "\n")
+		if next == -1 {
+			break
+		}
+		offset += next + 1
+	}
+
+	return offset
+}
+
+func (p *quizParser) extractQuizNameAndDuration(content string, offset int) (string, int) {
 	subMatch := quizNameRegexp.FindStringSubmatch(content)
 
 	if len(subMatch) < 3 {
-		return "", 0, fmt.Errorf("quiz name or quiz duration not found. The first line must be '# <Name> (duration: <duration>min)")
+		p.errorf(offset, "quiz name or quiz duration not found. The first line must be '# <Name> (duration: <duration>min)")
+		return "", 0
 	}
 
 	name := subMatch[1]
 	durationMin, err := strconv.ParseInt(subMatch[2], 10, 32)
 	if err != nil {
-		return "", 0, err
+		p.errorf(offsetOf(content, offset, subMatch[2]), "quiz duration %s is not valid", subMatch[2])
+		return "", 0
 	}
 
-	return name, int(durationMin) * 60, nil
+	return name, int(durationMin) * 60
 }
 
-func extractQuestions(content string) (map[string]QuizQuestion, error) {
+func (p *quizParser) extractQuestions(content string, offset int) map[string]QuizQuestion {
 	quizName := quizQuestionRegexp.FindString(content)
-	questionsStr := strings.ReplaceAll(content, quizName, "")
+	questionsStr := content[len(quizName):]
+	offset += len(quizName)
 	questionsUnParsed := strings.Split(questionsStr, "---\n")
 
 	questions := map[string]QuizQuestion{}
+	questionOffsets := map[string]int{}
+	idOffsets := map[string]int{}
 
 	for i, s := range questionsUnParsed {
-		question, err := extractQuestion(s)
-		if err != nil {
-			return nil, err
-		}
-
+		question := p.extractQuestion(s, offset)
 		question.Position = i + 1
+
+		if previous, found := questionOffsets[question.Sha1]; found {
+			filename, line, _ := p.locate(previous)
+			if filename == p.filename {
+				p.warnf(contentStart(s, offset), "question is a duplicate of the question at line %d, only the last one is kept", line)
+			} else {
+				p.warnf(contentStart(s, offset), "question is a duplicate of the question at line %d of %s, only the last one is kept", line, filename)
+			}
+		} else if previous, found := idOffsets[question.Id]; found {
+			filename, line, _ := p.locate(previous)
+			if filename == p.filename {
+				p.errorf(contentStart(s, offset), "question id '%s' is already the id of the question at line %d", question.Id, line)
+			} else {
+				p.errorf(contentStart(s, offset), "question id '%s' is already the id of the question at line %d of %s", question.Id, line, filename)
+			}
+		}
+		questionOffsets[question.Sha1] = contentStart(s, offset)
+		idOffsets[question.Id] = contentStart(s, offset)
 		questions[question.Sha1] = question
+
+		offset += len(s) + len("---\n")
 	}
 
-	return questions, nil
+	return questions
 }
 
-func extractQuestion(content string) (QuizQuestion, error) {
+func (p *quizParser) extractQuestion(content string, offset int) QuizQuestion {
 
-	answersStr := quizAnswersRegexp.FindString(content)
+	content, blocks := p.extractTranslationBlocks(content, offset)
+	body, explanation := extractExplanation(content)
 
-	questionContent, code, language := extractQuestionCode(strings.ReplaceAll(content, answersStr, ""))
+	// the answers are the last list of the question, a numbered list can also be part of the question content
+	answersStr := ""
+	for _, block := range quizAnswersRegexp.FindAllString(body, -1) {
+		if quizAnswerRegexp.MatchString(block) {
+			answersStr = block
+		}
+	}
 
-	answers, err := extractAnswers(answersStr)
-	if err != nil {
-		return QuizQuestion{}, err
+	questionContent, code, language := extractQuestionCode(strings.ReplaceAll(body, answersStr, ""))
+	questionContent = strings.Trim(questionContent, " \n")
+
+	answersWithoutExplanations, answerExplanations := extractAnswerExplanations(answersStr)
+	answers, kind := p.extractAnswers(answersWithoutExplanations, content, offset)
+	for sha1, answerExplanation := range answerExplanations {
+		if answer, found := answers[sha1]; found {
+			answer.Explanation = answerExplanation
+			answers[sha1] = answer
+		}
 	}
 
-	return QuizQuestion{
-		Sha1:         getSha1(content),
-		Content:      strings.Trim(questionContent, " \n"),
-		Code:         code,
-		CodeLanguage: language,
-		Answers:      answers,
-	}, nil
+	questionContent, markers := p.extractQuestionMarkers(questionContent, kind, content, offset)
+
+	if answersStr == "" {
+		p.warnf(contentStart(content, offset), "question has no answer")
+	} else if kind == Choice {
+		p.checkValidAnswers(answers, contentStart(content, offset))
+	}
+
+	var rightItems map[string]string
+	if kind == Matching {
+		rightItems = map[string]string{}
+		for _, answer := range answers {
+			rightItems[answer.RightSha1] = answer.Right
+		}
+	}
+
+	// the id marker is not part of the sha1 so that an id can be given to a question without
+	// changing it
+	sha1 := getSha1(strings.Replace(content, markers.idMarker, "", 1))
+	id := markers.id
+	if id == "" {
+		id = sha1
+	}
+
+	question := QuizQuestion{
+		Sha1:          sha1,
+		Id:            id,
+		Kind:          kind,
+		Content:       questionContent,
+		Code:          code,
+		CodeLanguage:  language,
+		Answers:       answers,
+		PartialCredit: markers.partialCredit,
+		Points:        markers.points,
+		RightItems:    rightItems,
+		Explanation:   explanation,
+		Tags:          markers.tags,
+	}
+	p.translateQuestion(question, blocks)
+
+	return question
+}
+
+// checkValidAnswers warns about the choice questions without any valid answer
+func (p *quizParser) checkValidAnswers(answers map[string]QuizQuestionAnswer, offset int) {
+	if len(answers) == 0 {
+		return
+	}
+
+	for _, answer := range answers {
+		if answer.Valid {
+			return
+		}
+	}
+	p.warnf(offset, "question has no valid answer, it is right only when no answer is checked")
+}
+
+// extractExplanation removes the '> Explanation:' block of a question from its content. The block can
+// go on with the following lines starting with '>'
+func extractExplanation(content string) (string, string) {
+	subMatch := quizExplanationRegexp.FindStringSubmatch(content)
+	if subMatch == nil {
+		return content, ""
+	}
+
+	lines := []string{subMatch[1]}
+	for _, line := range strings.Split(strings.TrimRight(subMatch[2], "\n"), "\n") {
+		if line != "" {
+			lines = append(lines, strings.TrimPrefix(strings.TrimPrefix(line, ">"), " "))
+		}
+	}
+
+	return strings.Replace(content, subMatch[0], "", 1), strings.TrimSpace(strings.Join(lines, "\n"))
+}
+
+// extractAnswerExplanations removes the indented '> ' lines following an answer and returns them by
+// answer sha1
+func extractAnswerExplanations(answersStr string) (string, map[string]string) {
+	var answers strings.Builder
+	explanations := map[string]string{}
+
+	previous := ""
+	for _, line := range strings.SplitAfter(answersStr, "\n") {
+		subMatch := quizAnswerExplanationRegexp.FindStringSubmatch(strings.TrimRight(line, "\n"))
+		if subMatch != nil && previous != "" {
+			sha1 := getSha1(previous)
+			explanations[sha1] = strings.TrimSpace(explanations[sha1] + "\n" + subMatch[1])
+			continue
+		}
+
+		answers.WriteString(line)
+		previous = strings.TrimRight(line, "\n")
+	}
+
+	return answers.String(), explanations
+}
+
+// questionMarkers are the markers ending the content of a question
+type questionMarkers struct {
+	points        int
+	partialCredit bool
+	tags          []string
+	id            string
+	// idMarker is the text of the '(id: <id>)' marker
+	idMarker string
+}
+
+// extractQuestionMarkers strips the '(points: <n>)', '(partial credit)', '(tags: <tags>)' and
+// '(id: <id>)' markers ending the question content. A question is worth 1 point by default and only
+// Ordering and Matching questions can give partial credit
+func (p *quizParser) extractQuestionMarkers(content string, kind QuestionKind, question string, offset int) (string, questionMarkers) {
+	markers := questionMarkers{points: 1}
+
+	for {
+		subMatch := quizQuestionMarkerRegexp.FindStringSubmatch(content)
+		if subMatch == nil {
+			break
+		}
+
+		markerOffset := offsetOf(question, offset, strings.TrimSpace(subMatch[0]))
+		if strings.HasPrefix(subMatch[1], "tags:") {
+			for _, tag := range strings.Split(subMatch[3], ",") {
+				tag = strings.TrimSpace(tag)
+				if tag == "" {
+					p.errorf(markerOffset, "question tag must not be empty")
+				} else {
+					markers.tags = append(markers.tags, tag)
+				}
+			}
+		} else if strings.HasPrefix(subMatch[1], "id:") {
+			id := strings.TrimSpace(subMatch[4])
+			if !questionIdRegexp.MatchString(id) {
+				p.errorf(markerOffset, "question id '%s' must only contain letters, digits, '.', '_' and '-'", id)
+			} else if markers.id != "" {
+				p.errorf(markerOffset, "question must have only one id")
+			} else {
+				markers.id = id
+				markers.idMarker = subMatch[0]
+			}
+		} else if subMatch[2] != "" {
+			n, err := strconv.Atoi(subMatch[2])
+			if err != nil || n == 0 {
+				p.errorf(markerOffset, "question must be worth at least 1 point")
+			} else {
+				markers.points = n
+			}
+		} else if kind == Ordering || kind == Matching {
+			markers.partialCredit = true
+		} else {
+			p.warnf(markerOffset, "only ordering and matching questions can give partial credit")
+			break
+		}
+
+		content = strings.TrimSuffix(content, subMatch[0])
+	}
+
+	return content, markers
 }
 
 func extractQuestionCode(questionContent string) (content string, code string, language string) {
@@ -133,22 +604,146 @@ func extractQuestionCode(questionContent string) (content string, code string, l
 	return content, code, language
 }
 
-func extractAnswers(answersStr string) (map[string]QuizQuestionAnswer, error) {
+// extractAnswers reads the answers of a question. The question starts at the given offset of the file
+func (p *quizParser) extractAnswers(answersStr string, question string, offset int) (map[string]QuizQuestionAnswer, QuestionKind) {
 
-	answersStrSplit := quizAnswerRegexp.FindAllString(answersStr, 10)
+	answersStrSplit := quizAnswerRegexp.FindAllString(answersStr, -1)
+	if len(answersStrSplit) > maxAnswers {
+		p.warnf(offsetOf(question, offset, answersStrSplit[maxAnswers]),
+			"question has %d answers, only the first %d are kept", len(answersStrSplit), maxAnswers)
+		answersStrSplit = answersStrSplit[:maxAnswers]
+	}
 
 	answers := map[string]QuizQuestionAnswer{}
+	kinds := map[QuestionKind]int{}
 
-	for _, s := range answersStrSplit {
-		valid := quizValidAnswerRegexp.MatchString(s)
+	// the answers are searched one after the other as several of them can have the same text
+	cursor := 0
+	for i, s := range answersStrSplit {
 		sha1Str := getSha1(s)
+		answerOffset := offsetOf(question[cursor:], offset+cursor, s)
+		cursor = answerOffset - offset + len(s)
+
+		if quizNumericAnswerRegexp.MatchString(s) {
+			content := strings.TrimSpace(s[2:])
+			if _, _, err := parseNumericAnswer(content); err != nil {
+				p.errorf(answerOffset+2, "%v", err)
+				continue
+			}
+
+			kinds[Numeric]++
+			answers[sha1Str] = QuizQuestionAnswer{
+				Sha1:    sha1Str,
+				Content: content,
+				Valid:   true,
+				Match:   ToleranceMatch,
+				Ordinal: i + 1,
+			}
+			continue
+		}
+
+		if quizOrderingAnswerRegexp.MatchString(s) {
+			content := quizOrderingAnswerRegexp.ReplaceAllString(s, "")
+			if strings.TrimSpace(content) == "" {
+				p.errorf(answerOffset, "ordering item is empty")
+				continue
+			}
+
+			kinds[Ordering]++
+			answers[sha1Str] = QuizQuestionAnswer{
+				Sha1:     sha1Str,
+				Content:  content,
+				Valid:    true,
+				Position: kinds[Ordering],
+				Ordinal:  i + 1,
+			}
+			continue
+		}
 
+		if subMatch := quizPairAnswerRegexp.FindStringSubmatch(s); subMatch != nil && !strings.HasPrefix(s, "- [") {
+			right := strings.TrimSpace(subMatch[2])
+			if strings.TrimSpace(subMatch[1]) == "" || right == "" {
+				p.errorf(answerOffset, "pair '%s' must have an item on both sides of '->'", strings.TrimSpace(s))
+				continue
+			}
+
+			kinds[Matching]++
+			answers[sha1Str] = QuizQuestionAnswer{
+				Sha1:      sha1Str,
+				Content:   strings.TrimSpace(subMatch[1]),
+				Valid:     true,
+				Position:  kinds[Matching],
+				Right:     right,
+				RightSha1: getSha1(right),
+				Ordinal:   i + 1,
+			}
+			continue
+		}
+
+		content := string([]rune(s)[6:])
+		if strings.TrimSpace(content) == "" {
+			p.errorf(answerOffset, "answer is empty")
+			continue
+		}
+
+		if quizTextAnswerRegexp.MatchString(s) {
+			match := answerMatchMapping[s[3]]
+			if match == RegexMatch {
+				if _, err := regexp.Compile(strings.TrimSpace(content)); err != nil {
+					p.errorf(answerOffset+6, "answer '%s' is not a valid regular expression (%v)", content, err)
+					continue
+				}
+			}
+
+			kinds[ShortAnswer]++
+			answers[sha1Str] = QuizQuestionAnswer{
+				Sha1:    sha1Str,
+				Content: strings.TrimSpace(content),
+				Valid:   true,
+				Match:   match,
+				Ordinal: i + 1,
+			}
+			continue
+		}
+
+		kinds[Choice]++
 		answers[sha1Str] = QuizQuestionAnswer{
 			Sha1:    sha1Str,
-			Content: string([]rune(s)[6:]),
-			Valid:   valid,
+			Content: content,
+			Valid:   quizValidAnswerRegexp.MatchString(s),
+			Ordinal: i + 1,
+		}
+	}
+
+	if len(kinds) > 1 {
+		p.errorf(offsetOf(question, offset, answersStrSplit[0]),
+			"a question can not mix checkbox answers, short answers, numeric answers, ordering items and pairs")
+	}
+
+	kind := Choice
+	for k := range kinds {
+		kind = k
+	}
+
+	return answers, kind
+}
+
+// parseNumericAnswer reads a numeric answer written '<value>' or '<value> ± <tolerance>' ('+/-' is
+// also accepted)
+func parseNumericAnswer(content string) (value float64, tolerance float64, err error) {
+	parts := toleranceSeparatorRegexp.Split(content, 2)
+
+	value, err = strconv.ParseFloat(strings.TrimSpace(parts[0]), 64)
+	if err != nil {
+		return 0, 0, fmt.Errorf("answer '%s' is not a valid number", content)
+	}
+
+	if len(parts) == 2 {
+		tolerance, err = strconv.ParseFloat(strings.TrimSpace(parts[1]), 64)
+		if err != nil || tolerance < 0 {
+			return 0, 0, fmt.Errorf("answer '%s' does not have a valid tolerance", content)
 		}
 	}
 
-	return answers, nil
+	return value, tolerance, nil
 }
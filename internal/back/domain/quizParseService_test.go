@@ -17,7 +17,9 @@
 package domain
 
 import (
+	"fmt"
 	"os"
+	"strings"
 	"testing"
 
 	"github.com/stretchr/testify/assert"
@@ -42,6 +44,7 @@ func TestParse(t *testing.T) {
 	assert.Equal(t, 4, len(actual.Questions["8e713df4a80094c5708dc4a1a2a1725643aa375f"].Answers))
 	assert.Equal(t, "Version Control System", actual.Questions["8e713df4a80094c5708dc4a1a2a1725643aa375f"].Answers["eb3352743a553af25829c32b2492c1a41a739f1e"].Content)
 	assert.Equal(t, true, actual.Questions["8e713df4a80094c5708dc4a1a2a1725643aa375f"].Answers["eb3352743a553af25829c32b2492c1a41a739f1e"].Valid)
+	assert.Equal(t, 1, actual.Questions["8e713df4a80094c5708dc4a1a2a1725643aa375f"].Answers["eb3352743a553af25829c32b2492c1a41a739f1e"].Ordinal)
 	assert.Equal(t, false, actual.Questions["0340bede2f41b9b2ce12b867cc5bf0cb1bd4eabd"].Answers["332b7ca50a406b2337e339332f66f3676d885fef"].Valid)
 	assert.Equal(t, false, actual.Questions["0340bede2f41b9b2ce12b867cc5bf0cb1bd4eabd"].Answers["22128893c69197141a17149b7de81419aca57e67"].Valid)
 	assert.Equal(t, "Question with a `command` ?", actual.Questions["37f33e32ba7d312df5de7c0bcf03ced422a3413b"].Content)
@@ -51,17 +54,553 @@ func TestParse(t *testing.T) {
 
 func Test_extractQuizNameAndDuration(t *testing.T) {
 	content := "# Marvel Universe (duration: 14min)"
-	name, duration, err := extractQuizNameAndDuration(content)
-	if err != nil {
-		assert.Failf(t, "Fail to extract quiz name", "%v", err)
-	}
+	p := &quizParser{filename: "quiz.md", content: content}
+	name, duration := p.extractQuizNameAndDuration(content, 0)
+	assert.Empty(t, p.diagnostics)
 
 	assert.Equal(t, "Marvel Universe", name)
 	assert.Equal(t, 14*60, duration)
 
 	content = "# Marvel Universe"
-	_, _, err = extractQuizNameAndDuration(content)
-	if err == nil {
-		assert.Failf(t, "extract quiz name should have failed", "")
+	p = &quizParser{filename: "quiz.md", content: content}
+	p.extractQuizNameAndDuration(content, 0)
+	assert.True(t, p.diagnostics.HasErrors())
+}
+
+// frontMatterDiagnostics extracts the front-matter of the given content and returns the diagnostics found
+func frontMatterDiagnostics(content string) Diagnostics {
+	p := &quizParser{filename: "quiz.md", content: content}
+	p.extractFrontMatter()
+
+	return p.diagnostics
+}
+
+func Test_extractFrontMatter(t *testing.T) {
+	content := `---
+description: |
+  A quiz about version control
+tags: [git, vcs]
+author: Michaël COLL
+pass-mark: 60
+shuffle-questions: true
+max-attempts: 2
+scoring: negative
+---
+
+# Version Control System (duration: 15min)
+`
+	p := &quizParser{filename: "quiz.md", content: content}
+	metadata, body, found := p.extractFrontMatter()
+	assert.True(t, found)
+	assert.Empty(t, p.diagnostics)
+
+	assert.Equal(t, "A quiz about version control", metadata.Description)
+	assert.Equal(t, []string{"git", "vcs"}, metadata.Tags)
+	assert.Equal(t, "Michaël COLL", metadata.Author)
+	assert.Equal(t, 60, metadata.PassMark)
+	assert.True(t, metadata.ShuffleQuestions)
+	assert.False(t, metadata.ShuffleAnswers)
+	assert.Equal(t, 2, metadata.MaxAttempts)
+	assert.Equal(t, NegativeMarking, metadata.Scoring)
+	assert.Equal(t, "# Version Control System (duration: 15min)\n", body)
+
+	content = "# Marvel Universe (duration: 14min)\n"
+	p = &quizParser{filename: "quiz.md", content: content}
+	metadata, body, found = p.extractFrontMatter()
+	assert.True(t, found)
+	assert.Empty(t, p.diagnostics)
+
+	assert.Equal(t, QuizMetadata{}, metadata)
+	assert.Equal(t, content, body)
+
+	diagnostics := frontMatterDiagnostics("---\nauthor: me\npass-mark: 101\n---\n# Quiz (duration: 1min)\n")
+	assert.Equal(t, Diagnostics{{Filename: "quiz.md", Line: 3, Column: 1, Severity: ErrorSeverity,
+		Message: "front-matter is not valid (pass-mark must be between 0 and 100, got 101)"}}, diagnostics)
+
+	diagnostics = frontMatterDiagnostics("---\nauthor: me\nunknown: 1\n---\n# Quiz (duration: 1min)\n")
+	assert.True(t, diagnostics.HasErrors())
+	assert.Equal(t, 3, diagnostics[0].Line)
+
+	diagnostics = frontMatterDiagnostics("---\nscoring: fair\n---\n# Quiz (duration: 1min)\n")
+	assert.True(t, diagnostics.HasErrors())
+	assert.Equal(t, 2, diagnostics[0].Line)
+
+	diagnostics = frontMatterDiagnostics("---\nauthor: me\n# Quiz (duration: 1min)\n")
+	assert.True(t, diagnostics.HasErrors())
+	assert.Equal(t, 1, diagnostics[0].Line)
+}
+
+func TestParse_withFrontMatter(t *testing.T) {
+	body, err := os.ReadFile("quiz.md")
+	if err != nil {
+		assert.Failf(t, "Fail to read quiz.md file", "%v", err)
+	}
+
+	s := NewQuizService(nil)
+
+	actual, err := s.Parse("quiz.md", "---\ntags: [vcs]\n---\n"+string(body))
+	if err != nil {
+		assert.Failf(t, "Fail to parse", "%v", err)
+	}
+
+	assert.Equal(t, "Version Control System", actual.Name)
+	assert.Equal(t, []string{"vcs"}, actual.Metadata.Tags)
+	assert.Equal(t, 3, len(actual.Questions))
+	assert.Contains(t, actual.Questions, "8e713df4a80094c5708dc4a1a2a1725643aa375f")
+}
+
+func TestParse_withCrlfLineEndings(t *testing.T) {
+	s := NewQuizService(nil)
+
+	lf := "---\ntags: [vcs]\nauthor: me\n---\n# Git (duration: 5min)\n\nWhat is a commit ?\n\n- [x] A snapshot\n- [ ] A branch\n"
+	actual, diagnostics := s.ParseWithDiagnostics("quiz.md", strings.ReplaceAll(lf, "\n", "\r\n"))
+	if actual == nil {
+		assert.FailNow(t, "Fail to parse", "%v", diagnostics)
+	}
+
+	expected, _ := s.ParseWithDiagnostics("quiz.md", lf)
+	assert.Empty(t, diagnostics)
+	assert.Equal(t, []string{"vcs"}, actual.Metadata.Tags)
+	assert.Equal(t, "me", actual.Metadata.Author)
+	assert.Equal(t, expected.Sha1, actual.Sha1)
+	assert.Equal(t, expected.Questions, actual.Questions)
+}
+
+func TestParse_withShortAnswer(t *testing.T) {
+	content := `# Marvel Universe (duration: 14min)
+
+What is the real name of Captain America ?
+- [~] Steve Rogers
+- [/] S(teve|\.) ?Rogers
+`
+	s := NewQuizService(nil)
+
+	actual, err := s.Parse("short.md", content)
+	if err != nil {
+		assert.Failf(t, "Fail to parse", "%v", err)
+	}
+
+	assert.Equal(t, 1, len(actual.Questions))
+	for _, question := range actual.Questions {
+		assert.Equal(t, ShortAnswer, question.Kind)
+		assert.Equal(t, 2, len(question.Answers))
+		for _, answer := range question.Answers {
+			assert.True(t, answer.Valid)
+			assert.NotEqual(t, NoMatch, answer.Match)
+		}
 	}
+
+	_, err = s.Parse("mixed.md", `# Marvel Universe (duration: 14min)
+
+What is the real name of Captain America ?
+- [x] Steve Rogers
+- [=] Steve Rogers
+`)
+	assert.Error(t, err)
+
+	_, err = s.Parse("regex.md", `# Marvel Universe (duration: 14min)
+
+What is the real name of Captain America ?
+- [/] Steve (Rogers
+`)
+	assert.Error(t, err)
+}
+
+func TestParse_withNumericAnswer(t *testing.T) {
+	content := `# Physics (duration: 10min)
+
+What is the value of pi ?
+= 3.14 ± 0.01
+= 22/7
+`
+	s := NewQuizService(nil)
+
+	_, err := s.Parse("numeric.md", content)
+	assert.Error(t, err)
+
+	content = `# Physics (duration: 10min)
+
+What is the value of pi ?
+= 3.14 ± 0.01
+= 3.1416 +/- 0.0001
+
+---
+
+What is the speed of light (m/s) ?
+= 299792458
+`
+	actual, err := s.Parse("numeric.md", content)
+	if err != nil {
+		assert.Failf(t, "Fail to parse", "%v", err)
+	}
+
+	assert.Equal(t, 2, len(actual.Questions))
+	for _, question := range actual.Questions {
+		assert.Equal(t, Numeric, question.Kind)
+		for _, answer := range question.Answers {
+			assert.True(t, answer.Valid)
+			assert.Equal(t, ToleranceMatch, answer.Match)
+		}
+	}
+
+	_, err = s.Parse("mixed.md", `# Physics (duration: 10min)
+
+What is the value of pi ?
+- [x] 3.14
+= 3.14 ± 0.01
+`)
+	assert.Error(t, err)
+}
+
+func Test_parseNumericAnswer(t *testing.T) {
+	value, tolerance, err := parseNumericAnswer("3.14 ± 0.01")
+	assert.NoError(t, err)
+	assert.Equal(t, 3.14, value)
+	assert.Equal(t, 0.01, tolerance)
+
+	value, tolerance, err = parseNumericAnswer("-1.5e3+/-10")
+	assert.NoError(t, err)
+	assert.Equal(t, -1500.0, value)
+	assert.Equal(t, 10.0, tolerance)
+
+	value, tolerance, err = parseNumericAnswer("42")
+	assert.NoError(t, err)
+	assert.Equal(t, 42.0, value)
+	assert.Equal(t, 0.0, tolerance)
+
+	_, _, err = parseNumericAnswer("3.14 ± -1")
+	assert.Error(t, err)
+
+	_, _, err = parseNumericAnswer("pi")
+	assert.Error(t, err)
+}
+
+func TestParse_withOrdering(t *testing.T) {
+	content := `# Git (duration: 10min)
+
+Put the steps of a commit in the right order
+1. git add
+2. git commit
+3. git push
+
+---
+
+Put the steps of a merge in the right order (partial credit)
+1. git fetch
+1. git merge
+`
+	s := NewQuizService(nil)
+
+	actual, err := s.Parse("ordering.md", content)
+	if err != nil {
+		assert.Failf(t, "Fail to parse", "%v", err)
+	}
+
+	assert.Equal(t, 2, len(actual.Questions))
+	for _, question := range actual.Questions {
+		assert.Equal(t, Ordering, question.Kind)
+
+		positions := map[string]int{}
+		for _, answer := range question.Answers {
+			assert.True(t, answer.Valid)
+			positions[answer.Content] = answer.Position
+		}
+
+		if question.Position == 1 {
+			assert.Equal(t, "Put the steps of a commit in the right order", question.Content)
+			assert.False(t, question.PartialCredit)
+			assert.Equal(t, map[string]int{"git add": 1, "git commit": 2, "git push": 3}, positions)
+		} else {
+			assert.Equal(t, "Put the steps of a merge in the right order", question.Content)
+			assert.True(t, question.PartialCredit)
+			assert.Equal(t, map[string]int{"git fetch": 1, "git merge": 2}, positions)
+		}
+	}
+}
+
+func TestParse_withMatching(t *testing.T) {
+	content := `# Git (duration: 10min)
+
+Pair each command with what it does (partial credit)
+- git add -> Stages the changes
+- git commit -> Records the changes
+- git push -> Uploads the commits
+`
+	s := NewQuizService(nil)
+
+	actual, err := s.Parse("matching.md", content)
+	if err != nil {
+		assert.Failf(t, "Fail to parse", "%v", err)
+	}
+
+	assert.Equal(t, 1, len(actual.Questions))
+	for _, question := range actual.Questions {
+		assert.Equal(t, Matching, question.Kind)
+		assert.True(t, question.PartialCredit)
+		assert.Equal(t, "Pair each command with what it does", question.Content)
+		assert.Equal(t, 3, len(question.RightItems))
+
+		pairs := map[string]string{}
+		for _, answer := range question.Answers {
+			pairs[answer.Content] = answer.Right
+			assert.Equal(t, answer.Right, question.RightItems[answer.RightSha1])
+		}
+		assert.Equal(t, map[string]string{
+			"git add":    "Stages the changes",
+			"git commit": "Records the changes",
+			"git push":   "Uploads the commits",
+		}, pairs)
+	}
+}
+
+func TestParse_withPoints(t *testing.T) {
+	content := `# Git (duration: 10min)
+
+What is a commit ? (points: 3)
+- [x] A snapshot of the repository
+- [ ] A branch
+
+---
+
+Put the steps of a commit in the right order (partial credit) (points: 2)
+1. git add
+2. git commit
+
+---
+
+What does VCS stand for ?
+- [=] Version Control System
+`
+	s := NewQuizService(nil)
+
+	actual, err := s.Parse("points.md", content)
+	if err != nil {
+		assert.Failf(t, "Fail to parse", "%v", err)
+	}
+
+	assert.Equal(t, 3, len(actual.Questions))
+	for _, question := range actual.Questions {
+		switch question.Position {
+		case 1:
+			assert.Equal(t, "What is a commit ?", question.Content)
+			assert.Equal(t, 3, question.Points)
+		case 2:
+			assert.Equal(t, "Put the steps of a commit in the right order", question.Content)
+			assert.Equal(t, 2, question.Points)
+			assert.True(t, question.PartialCredit)
+		default:
+			assert.Equal(t, 1, question.Points)
+		}
+	}
+
+	_, err = s.Parse("points.md", `# Git (duration: 10min)
+
+What is a commit ? (points: 0)
+- [x] A snapshot of the repository
+`)
+	assert.Error(t, err)
+}
+
+func TestParse_withId(t *testing.T) {
+	content := `# Git (duration: 10min)
+
+What is a commit ? (points: 3) (id: commit)
+- [x] A snapshot of the repository
+- [ ] A branch
+
+---
+
+What does VCS stand for ?
+- [=] Version Control System
+`
+	s := NewQuizService(nil)
+
+	actual, err := s.Parse("id.md", content)
+	if err != nil {
+		assert.Failf(t, "Fail to parse", "%v", err)
+	}
+
+	withoutId, err := s.Parse("id.md", strings.Replace(content, " (id: commit)", "", 1))
+	if err != nil {
+		assert.Failf(t, "Fail to parse", "%v", err)
+	}
+
+	for sha1, question := range actual.Questions {
+		// the id marker does not change the sha1 of the question
+		assert.Contains(t, withoutId.Questions, sha1)
+		if question.Position == 1 {
+			assert.Equal(t, "commit", question.Id)
+			assert.Equal(t, "What is a commit ?", question.Content)
+			assert.Equal(t, 3, question.Points)
+		} else {
+			assert.Equal(t, sha1, question.Id)
+		}
+	}
+
+	_, diagnostics := s.ParseWithDiagnostics("id.md", strings.Replace(content, "stand for ?", "stand for ? (id: commit)", 1))
+	assert.Equal(t, "question id 'commit' is already the id of the question at line 3", diagnostics[0].Message)
+
+	_, diagnostics = s.ParseWithDiagnostics("id.md", strings.Replace(content, "(id: commit)", "(id: a commit)", 1))
+	assert.Equal(t, "question id 'a commit' must only contain letters, digits, '.', '_' and '-'", diagnostics[0].Message)
+}
+
+func TestParse_withPool(t *testing.T) {
+	content := `---
+pool:
+  draw: 1
+  tags: [marvel]
+---
+# Super heroes (duration: 10min)
+
+Who is Iron Man ? (tags: marvel, avengers) (points: 2)
+- [x] Tony Stark
+- [ ] Bruce Wayne
+
+---
+
+Who is Batman ? (tags: dc)
+- [ ] Tony Stark
+- [x] Bruce Wayne
+`
+	s := NewQuizService(nil)
+
+	actual, diagnostics := s.ParseWithDiagnostics("pool.md", content)
+	assert.Empty(t, diagnostics)
+
+	assert.Equal(t, QuizPool{Draw: 1, Tags: []string{"marvel"}}, actual.Metadata.Pool)
+	for _, question := range actual.Questions {
+		if question.Position == 1 {
+			assert.Equal(t, "Who is Iron Man ?", question.Content)
+			assert.Equal(t, []string{"marvel", "avengers"}, question.Tags)
+			assert.Equal(t, 2, question.Points)
+		} else {
+			assert.Equal(t, "Who is Batman ?", question.Content)
+			assert.Equal(t, []string{"dc"}, question.Tags)
+		}
+	}
+
+	_, diagnostics = s.ParseWithDiagnostics("pool.md", strings.Replace(content, "draw: 1", "draw: 2", 1))
+	assert.Equal(t, Diagnostics{{Filename: "pool.md", Line: 2, Column: 1, Severity: WarningSeverity,
+		Message: "pool draws 2 questions but only 1 can be drawn, all of them are asked"}}, diagnostics)
+
+	_, diagnostics = s.ParseWithDiagnostics("pool.md", strings.Replace(content, "tags: [marvel]", "tags: [image]", 1))
+	assert.Equal(t, Diagnostics{{Filename: "pool.md", Line: 2, Column: 1, Severity: ErrorSeverity,
+		Message: "pool has no question to draw, no question has one of the tags image"}}, diagnostics)
+
+	diagnostics = frontMatterDiagnostics("---\npool:\n  tags: [marvel]\n---\n# Quiz (duration: 1min)\n")
+	assert.True(t, diagnostics.HasErrors())
+
+	diagnostics = frontMatterDiagnostics("---\npool:\n  draw: -1\n---\n# Quiz (duration: 1min)\n")
+	assert.True(t, diagnostics.HasErrors())
+
+	_, diagnostics = s.ParseWithDiagnostics("pool.md", "# Quiz (duration: 1min)\n\nWho is Iron Man ? (tags: marvel, )\n- [x] Tony Stark\n")
+	assert.Equal(t, Diagnostics{{Filename: "pool.md", Line: 3, Column: 19, Severity: ErrorSeverity,
+		Message: "question tag must not be empty"}}, diagnostics)
+}
+
+func TestParse_withExplanation(t *testing.T) {
+	content := `# Git (duration: 10min)
+
+What is a commit ?
+- [x] A snapshot of the repository
+- [ ] A branch
+  > A branch is only a pointer to a commit
+- [ ] A remote
+> Explanation: Git stores the whole content
+> of the repository on each commit
+
+---
+
+What does VCS stand for ?
+- [=] Version Control System
+`
+	s := NewQuizService(nil)
+
+	actual, err := s.Parse("explanation.md", content)
+	if err != nil {
+		assert.Failf(t, "Fail to parse", "%v", err)
+	}
+
+	assert.Equal(t, 2, len(actual.Questions))
+	for _, question := range actual.Questions {
+		if question.Position == 2 {
+			assert.Empty(t, question.Explanation)
+			continue
+		}
+
+		assert.Equal(t, "What is a commit ?", question.Content)
+		assert.Equal(t, "Git stores the whole content\nof the repository on each commit", question.Explanation)
+		assert.Equal(t, 3, len(question.Answers))
+
+		explanations := map[string]string{}
+		for _, answer := range question.Answers {
+			explanations[answer.Content] = answer.Explanation
+		}
+		assert.Equal(t, map[string]string{
+			"A snapshot of the repository": "",
+			"A branch":                     "A branch is only a pointer to a commit",
+			"A remote":                     "",
+		}, explanations)
+	}
+}
+
+func TestParseWithDiagnostics(t *testing.T) {
+	content := `# Marvel Universe (duration: 14min)
+
+Who is Iron Man ? (points: 0)
+
+- [ ] Steve Rogers
+- [ ] 
+---
+Who is Captain America ?
+
+- [ ] Tony Stark
+- [ ] Bruce Banner
+---
+Who is Captain America ?
+
+- [ ] Tony Stark
+- [ ] Bruce Banner
+---
+How many infinity stones are there ?
+
+= six
+`
+
+	s := NewQuizService(nil)
+
+	quiz, diagnostics := s.ParseWithDiagnostics("marvel.md", content)
+	assert.Nil(t, quiz)
+
+	expected := []string{
+		"marvel.md:3:1: warning: question has no valid answer, it is right only when no answer is checked",
+		"marvel.md:3:19: error: question must be worth at least 1 point",
+		"marvel.md:6:1: error: answer is empty",
+		"marvel.md:8:1: warning: question has no valid answer, it is right only when no answer is checked",
+		"marvel.md:13:1: warning: question has no valid answer, it is right only when no answer is checked",
+		"marvel.md:13:1: warning: question is a duplicate of the question at line 8, only the last one is kept",
+		"marvel.md:20:3: error: answer 'six' is not a valid number",
+	}
+	actual := make([]string, len(diagnostics))
+	for i, diagnostic := range diagnostics {
+		actual[i] = diagnostic.String()
+	}
+	assert.Equal(t, expected, actual)
+
+	_, err := s.Parse("marvel.md", content)
+	assert.ErrorContains(t, err, "marvel.md:20:3: error: answer 'six' is not a valid number")
+}
+
+func TestParseWithDiagnostics_too_many_answers(t *testing.T) {
+	content := "# Alphabet (duration: 1min)\n\nPut the letters in order\n\n"
+	for _, letter := range "abcdefghijk" {
+		content += fmt.Sprintf("%d. %c\n", letter-'a'+1, letter)
+	}
+
+	s := NewQuizService(nil)
+
+	quiz, diagnostics := s.ParseWithDiagnostics("alphabet.md", content)
+	assert.Len(t, quiz.Questions[getSha1(strings.TrimPrefix(content, "# Alphabet (duration: 1min)\n"))].Answers, 10)
+	assert.Equal(t, Diagnostics{{Filename: "alphabet.md", Line: 15, Column: 1, Severity: WarningSeverity,
+		Message: "question has 11 answers, only the first 10 are kept"}}, diagnostics)
 }
@@ -0,0 +1,81 @@
+/*
+ * Copyright (c) 2023 Michaël COLL.
+ *
+ * Licensed under the Apache License, Version 2.0 (the "License");
+ * you may not use this file except in compliance with the License.
+ * You may obtain a copy of the License at
+ *
+ *      http://www.apache.org/licenses/LICENSE-2.0
+ *
+ * Unless required by applicable law or agreed to in writing, software
+ * distributed under the License is distributed on an "AS IS" BASIS,
+ * WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+ * See the License for the specific language governing permissions and
+ * limitations under the License.
+ */
+
+package domain
+
+import (
+	"math/rand/v2"
+	"slices"
+	"sort"
+	"strings"
+)
+
+// candidates gives the sha1 of the questions the pool draws from, ordered by position
+func (p QuizPool) candidates(questions map[string]QuizQuestion) []string {
+	candidates := make([]string, 0, len(questions))
+	for sha1, question := range questions {
+		if len(p.Tags) == 0 || slices.ContainsFunc(question.Tags, func(tag string) bool {
+			return slices.Contains(p.Tags, tag)
+		}) {
+			candidates = append(candidates, sha1)
+		}
+	}
+
+	sort.Slice(candidates, func(i, j int) bool {
+		left, right := questions[candidates[i]], questions[candidates[j]]
+		if left.Position != right.Position {
+			return left.Position < right.Position
+		}
+		return left.Sha1 < right.Sha1
+	})
+
+	return candidates
+}
+
+// draw picks the sha1 of the questions asked in a session. The same seed always draws the same
+// questions so that the questions of a session can be audited
+func (p QuizPool) draw(questions map[string]QuizQuestion, seed int64) []string {
+	candidates := p.candidates(questions)
+	if p.Draw == 0 || p.Draw >= len(candidates) {
+		return candidates
+	}
+
+	r := rand.New(rand.NewPCG(uint64(seed), 0))
+	r.Shuffle(len(candidates), func(i, j int) {
+		candidates[i], candidates[j] = candidates[j], candidates[i]
+	})
+	drawn := candidates[:p.Draw]
+
+	sort.Slice(drawn, func(i, j int) bool {
+		return questions[drawn[i]].Position < questions[drawn[j]].Position
+	})
+
+	return drawn
+}
+
+// checkPool reports the pools that can not draw the number of questions they ask for
+func (p *quizParser) checkPool(pool QuizPool, questions map[string]QuizQuestion) {
+	if pool.Draw == 0 {
+		return
+	}
+
+	candidates := len(pool.candidates(questions))
+	if candidates == 0 {
+		p.errorf(p.frontMatterKeyOffset("pool"), "pool has no question to draw, no question has one of the tags %s", strings.Join(pool.Tags, ", "))
+	} else if candidates < pool.Draw {
+		p.warnf(p.frontMatterKeyOffset("pool"), "pool draws %d questions but only %d can be drawn, all of them are asked", pool.Draw, candidates)
+	}
+}
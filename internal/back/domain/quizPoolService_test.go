@@ -0,0 +1,115 @@
+/*
+ * Copyright (c) 2023 Michaël COLL.
+ *
+ * Licensed under the Apache License, Version 2.0 (the "License");
+ * you may not use this file except in compliance with the License.
+ * You may obtain a copy of the License at
+ *
+ *      http://www.apache.org/licenses/LICENSE-2.0
+ *
+ * Unless required by applicable law or agreed to in writing, software
+ * distributed under the License is distributed on an "AS IS" BASIS,
+ * WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+ * See the License for the specific language governing permissions and
+ * limitations under the License.
+ */
+
+package domain
+
+import (
+	"context"
+	"fmt"
+	"testing"
+
+	"github.com/google/uuid"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/mock"
+)
+
+func poolQuestions() map[string]QuizQuestion {
+	questions := map[string]QuizQuestion{}
+	for i := 1; i <= 10; i++ {
+		sha1 := fmt.Sprintf("q%d", i)
+		tags := []string{"odd"}
+		if i%2 == 0 {
+			tags = []string{"even"}
+		}
+		questions[sha1] = QuizQuestion{Sha1: sha1, Position: i, Tags: tags}
+	}
+
+	return questions
+}
+
+func TestQuizPool_draw(t *testing.T) {
+	questions := poolQuestions()
+
+	pool := QuizPool{Draw: 4}
+	drawn := pool.draw(questions, 42)
+	assert.Len(t, drawn, 4)
+	assert.Equal(t, drawn, pool.draw(questions, 42))
+	assert.IsIncreasing(t, []int{
+		questions[drawn[0]].Position,
+		questions[drawn[1]].Position,
+		questions[drawn[2]].Position,
+		questions[drawn[3]].Position,
+	})
+
+	different := false
+	for seed := int64(0); seed < 10 && !different; seed++ {
+		different = fmt.Sprint(drawn) != fmt.Sprint(pool.draw(questions, seed))
+	}
+	assert.True(t, different)
+}
+
+func TestQuizPool_draw_withTags(t *testing.T) {
+	questions := poolQuestions()
+
+	pool := QuizPool{Draw: 3, Tags: []string{"even"}}
+	for _, sha1 := range pool.draw(questions, 42) {
+		assert.Equal(t, []string{"even"}, questions[sha1].Tags)
+	}
+
+	pool = QuizPool{Draw: 8, Tags: []string{"even"}}
+	assert.Equal(t, []string{"q2", "q4", "q6", "q8", "q10"}, pool.draw(questions, 42))
+}
+
+func TestQuizPool_draw_withoutPool(t *testing.T) {
+	pool := QuizPool{}
+	assert.Equal(t, []string{"q1", "q2", "q3", "q4", "q5", "q6", "q7", "q8", "q9", "q10"}, pool.draw(poolQuestions(), 42))
+}
+
+func TestQuizService_StartSession(t *testing.T) {
+
+	mockQuizRepository := NewMockQuizRepository(t)
+
+	s := NewQuizService(mockQuizRepository)
+
+	sessionId := uuid.New()
+	mockQuizRepository.On("FindPoolBySha1", context.Background(), "quiz").Return(&Quiz{
+		Sha1:      "quiz",
+		Metadata:  QuizMetadata{Pool: QuizPool{Draw: 2}},
+		Questions: poolQuestions(),
+	}, nil)
+	mockQuizRepository.On("StartSession", context.Background(), "user", "quiz", mock.AnythingOfType("int64"),
+		mock.MatchedBy(func(questionSha1s []string) bool { return len(questionSha1s) == 2 })).Return(sessionId, nil)
+
+	actual, err := s.StartSession(context.Background(), "user", "quiz")
+	assert.NoError(t, err)
+	assert.Equal(t, sessionId, actual)
+
+	mockQuizRepository.AssertExpectations(t)
+}
+
+func TestQuizService_StartSession_not_found(t *testing.T) {
+
+	mockQuizRepository := NewMockQuizRepository(t)
+
+	s := NewQuizService(mockQuizRepository)
+
+	mockQuizRepository.On("FindPoolBySha1", context.Background(), "unknown").Return(nil, nil)
+
+	_, err := s.StartSession(context.Background(), "user", "unknown")
+	assert.Error(t, err)
+
+	mockQuizRepository.AssertExpectations(t)
+}
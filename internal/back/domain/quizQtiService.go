@@ -0,0 +1,247 @@
+/*
+ * Copyright (c) 2023 Michaël COLL.
+ *
+ * Licensed under the Apache License, Version 2.0 (the "License");
+ * you may not use this file except in compliance with the License.
+ * You may obtain a copy of the License at
+ *
+ *      http://www.apache.org/licenses/LICENSE-2.0
+ *
+ * Unless required by applicable law or agreed to in writing, software
+ * distributed under the License is distributed on an "AS IS" BASIS,
+ * WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+ * See the License for the specific language governing permissions and
+ * limitations under the License.
+ */
+
+package domain
+
+import (
+	"archive/zip"
+	"bytes"
+	"fmt"
+	"html"
+	"strings"
+	"text/template"
+)
+
+const qtiNamespaces = `xmlns="http://www.imsglobal.org/xsd/imsqti_v2p1" xmlns:xsi="http://www.w3.org/2001/XMLSchema-instance" xsi:schemaLocation="http://www.imsglobal.org/xsd/imsqti_v2p1 http://www.imsglobal.org/xsd/qti/qtiv2p1/imsqti_v2p1.xsd"`
+
+const qtiItemXml = `<?xml version="1.0" encoding="UTF-8"?>
+<assessmentItem ` + qtiNamespaces + ` identifier="{{.Id}}" title="Question {{.Question.Position}}" adaptive="false" timeDependent="false">
+{{- $question := .Question}}
+{{- if eq .Question.Kind 0}}
+<responseDeclaration identifier="RESPONSE" cardinality="{{if eq .ValidCount 1}}single{{else}}multiple{{end}}" baseType="identifier">
+<correctResponse>
+{{- range .Answers}}{{if .Valid}}<value>A{{.Ordinal}}</value>{{end}}{{end}}
+</correctResponse>
+</responseDeclaration>
+{{- else if eq .Question.Kind 1}}
+<responseDeclaration identifier="RESPONSE" cardinality="single" baseType="string">
+<correctResponse><value>{{(index .Answers 0).Content | xml}}</value></correctResponse>
+<mapping defaultValue="0">
+{{- range .Answers}}<mapEntry mapKey="{{.Content | xml}}" mappedValue="1" caseSensitive="{{if eq .Match 1}}true{{else}}false{{end}}"/>{{end}}
+</mapping>
+</responseDeclaration>
+{{- else if eq .Question.Kind 2}}
+<responseDeclaration identifier="RESPONSE" cardinality="single" baseType="float">
+<correctResponse><value>{{.Value}}</value></correctResponse>
+</responseDeclaration>
+{{- else if eq .Question.Kind 3}}
+<responseDeclaration identifier="RESPONSE" cardinality="ordered" baseType="identifier">
+<correctResponse>
+{{- range .Answers}}<value>A{{.Position}}</value>{{end}}
+</correctResponse>
+</responseDeclaration>
+{{- else}}
+<responseDeclaration identifier="RESPONSE" cardinality="multiple" baseType="directedPair">
+<correctResponse>
+{{- range .Answers}}<value>A{{.Position}} R{{.Position}}</value>{{end}}
+</correctResponse>
+</responseDeclaration>
+{{- end}}
+<outcomeDeclaration identifier="SCORE" cardinality="single" baseType="float">
+<defaultValue><value>0</value></defaultValue>
+</outcomeDeclaration>
+<itemBody>
+{{- range .Paragraphs}}
+<p>{{. | xml}}</p>
+{{- end}}
+{{- if .Question.Code}}
+<pre>{{.Question.Code | xml}}</pre>
+{{- end}}
+{{- if eq .Question.Kind 0}}
+<choiceInteraction responseIdentifier="RESPONSE" shuffle="{{.Shuffle}}" maxChoices="{{if eq .ValidCount 1}}1{{else}}0{{end}}">
+{{- range .Answers}}
+<simpleChoice identifier="A{{.Ordinal}}">{{.Content | xml}}</simpleChoice>
+{{- end}}
+</choiceInteraction>
+{{- else if eq .Question.Kind 3}}
+<orderInteraction responseIdentifier="RESPONSE" shuffle="true">
+{{- range .Answers}}
+<simpleChoice identifier="A{{.Position}}">{{.Content | xml}}</simpleChoice>
+{{- end}}
+</orderInteraction>
+{{- else if eq .Question.Kind 4}}
+<matchInteraction responseIdentifier="RESPONSE" shuffle="true" maxAssociations="{{len .Answers}}">
+<simpleMatchSet>
+{{- range .Answers}}
+<simpleAssociableChoice identifier="A{{.Position}}" matchMax="1">{{.Content | xml}}</simpleAssociableChoice>
+{{- end}}
+</simpleMatchSet>
+<simpleMatchSet>
+{{- range .Answers}}
+<simpleAssociableChoice identifier="R{{.Position}}" matchMax="1">{{.Right | xml}}</simpleAssociableChoice>
+{{- end}}
+</simpleMatchSet>
+</matchInteraction>
+{{- else}}
+<p><textEntryInteraction responseIdentifier="RESPONSE"/></p>
+{{- end}}
+</itemBody>
+{{- if eq .Question.Kind 1}}
+<responseProcessing template="http://www.imsglobal.org/question/qti_v2p1/rptemplates/map_response"/>
+{{- else if eq .Question.Kind 2}}
+<responseProcessing>
+<responseCondition>
+<responseIf>
+<equal toleranceMode="absolute" tolerance="{{.Tolerance}} {{.Tolerance}}"><variable identifier="RESPONSE"/><correct identifier="RESPONSE"/></equal>
+<setOutcomeValue identifier="SCORE"><baseValue baseType="float">1</baseValue></setOutcomeValue>
+</responseIf>
+</responseCondition>
+</responseProcessing>
+{{- else}}
+<responseProcessing template="http://www.imsglobal.org/question/qti_v2p1/rptemplates/match_correct"/>
+{{- end}}
+</assessmentItem>
+`
+
+const qtiTestXml = `<?xml version="1.0" encoding="UTF-8"?>
+<assessmentTest ` + qtiNamespaces + ` identifier="test" title="{{.Quiz.Name | xml}}">
+<timeLimits maxTime="{{.Quiz.Duration}}"/>
+<testPart identifier="part" navigationMode="nonlinear" submissionMode="simultaneous">
+<assessmentSection identifier="section" title="{{.Quiz.Name | xml}}" visible="true">
+{{- range .Items}}
+<assessmentItemRef identifier="{{.Id}}" href="{{.Id}}.xml">
+<weight identifier="WEIGHT" value="{{.Question.Points}}"/>
+</assessmentItemRef>
+{{- end}}
+</assessmentSection>
+</testPart>
+</assessmentTest>
+`
+
+const qtiManifestXml = `<?xml version="1.0" encoding="UTF-8"?>
+<manifest xmlns="http://www.imsglobal.org/xsd/imscp_v1p1" xmlns:xsi="http://www.w3.org/2001/XMLSchema-instance" xsi:schemaLocation="http://www.imsglobal.org/xsd/imscp_v1p1 http://www.imsglobal.org/xsd/qti/qtiv2p1/qtiv2p1_imscpv1p2_v1p0.xsd" identifier="manifest-{{.Quiz.Sha1}}">
+<metadata>
+<schema>QTIv2.1 Package</schema>
+<schemaversion>1.0.0</schemaversion>
+</metadata>
+<organizations/>
+<resources>
+<resource identifier="test" type="imsqti_test_xmlv2p1" href="test.xml">
+<file href="test.xml"/>
+{{- range .Items}}
+<dependency identifierref="{{.Id}}"/>
+{{- end}}
+</resource>
+{{- range .Items}}
+<resource identifier="{{.Id}}" type="imsqti_item_xmlv2p1" href="{{.Id}}.xml">
+<file href="{{.Id}}.xml"/>
+</resource>
+{{- end}}
+</resources>
+</manifest>
+`
+
+var qtiFuncs = template.FuncMap{"xml": html.EscapeString}
+var qtiItemTemplate = template.Must(template.New("item").Funcs(qtiFuncs).Parse(qtiItemXml))
+var qtiTestTemplate = template.Must(template.New("test").Funcs(qtiFuncs).Parse(qtiTestXml))
+var qtiManifestTemplate = template.Must(template.New("manifest").Funcs(qtiFuncs).Parse(qtiManifestXml))
+
+// qtiItem is a question written as a QTI assessment item
+type qtiItem struct {
+	Id         string
+	Question   QuizQuestion
+	Paragraphs []string
+	Answers    []QuizQuestionAnswer
+	ValidCount int
+	Shuffle    bool
+	Value      string
+	Tolerance  string
+}
+
+// writeQtiPackage writes a quiz as an IMS QTI 2.1 content package holding an assessment item per
+// question and an assessment test giving the duration of the quiz and the points of each question.
+// The short answers matched by a regular expression have no QTI equivalent and are left out, like
+// the short answer and numeric questions left without answer. The explanations are not exported
+func writeQtiPackage(quiz *Quiz) ([]byte, error) {
+	var items []qtiItem
+	for _, question := range sortedQuestions(quiz.Questions) {
+		if item, ok := newQtiItem(quiz, question); ok {
+			items = append(items, item)
+		}
+	}
+
+	var buf bytes.Buffer
+	archive := zip.NewWriter(&buf)
+
+	write := func(filename string, t *template.Template, data any) error {
+		w, err := archive.Create(filename)
+		if err != nil {
+			return err
+		}
+		return t.Execute(w, data)
+	}
+
+	data := map[string]any{"Quiz": quiz, "Items": items}
+	if err := write("imsmanifest.xml", qtiManifestTemplate, data); err != nil {
+		return nil, err
+	}
+	if err := write("test.xml", qtiTestTemplate, data); err != nil {
+		return nil, err
+	}
+	for _, item := range items {
+		if err := write(item.Id+".xml", qtiItemTemplate, item); err != nil {
+			return nil, err
+		}
+	}
+
+	if err := archive.Close(); err != nil {
+		return nil, err
+	}
+
+	return buf.Bytes(), nil
+}
+
+// newQtiItem prepares the writing of a question, the questions without answer that QTI can check
+// being left out
+func newQtiItem(quiz *Quiz, question QuizQuestion) (qtiItem, bool) {
+	item := qtiItem{
+		Id:         fmt.Sprintf("question-%d", question.Position),
+		Question:   question,
+		Paragraphs: strings.Split(question.Content, "\n\n"),
+		Shuffle:    quiz.Metadata.ShuffleAnswers,
+	}
+
+	for _, sha1 := range sortedAnswerSha1s(question.Answers) {
+		answer := question.Answers[sha1]
+		if question.Kind == ShortAnswer && answer.Match == RegexMatch {
+			continue
+		}
+		if answer.Valid {
+			item.ValidCount++
+		}
+		item.Answers = append(item.Answers, answer)
+	}
+
+	if question.Kind == Numeric && len(item.Answers) > 0 {
+		value, tolerance, err := parseNumericAnswer(item.Answers[0].Content)
+		if err != nil {
+			return item, false
+		}
+		item.Value, item.Tolerance = formatFloat(value), formatFloat(tolerance)
+	}
+
+	return item, len(item.Answers) > 0
+}
@@ -0,0 +1,161 @@
+/*
+ * Copyright (c) 2023 Michaël COLL.
+ *
+ * Licensed under the Apache License, Version 2.0 (the "License");
+ * you may not use this file except in compliance with the License.
+ * You may obtain a copy of the License at
+ *
+ *      http://www.apache.org/licenses/LICENSE-2.0
+ *
+ * Unless required by applicable law or agreed to in writing, software
+ * distributed under the License is distributed on an "AS IS" BASIS,
+ * WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+ * See the License for the specific language governing permissions and
+ * limitations under the License.
+ */
+
+package domain
+
+import (
+	"context"
+	"math"
+
+	"github.com/google/uuid"
+)
+
+// answerCount sums up the answers given to a question
+type answerCount struct {
+	right    int
+	wrong    int
+	expected int
+}
+
+// score computes the result of a finished session with the scoring strategy of its quiz and sets
+// the score of each of its questions
+func (s *QuizService) score(detail *QuizSessionDetail) *SessionResult {
+	result := &SessionResult{}
+
+	for sha1, question := range detail.Questions {
+		count := countAnswers(question)
+
+		question.Score = scoreQuestion(detail.Scoring, question, count)
+		detail.Questions[sha1] = question
+
+		result.TotalAnswer++
+		if count.right == count.expected && count.wrong == 0 {
+			result.GoodAnswer++
+		}
+		result.PointsEarned += question.Score
+		result.PointsPossible += question.Points
+	}
+
+	result.PointsEarned = math.Max(result.PointsEarned, 0)
+
+	return result
+}
+
+// scoreSessions computes the result of finished sessions, their answers being loaded with a single
+// query. The sessions that can't be found are left without result
+func (s *QuizService) scoreSessions(ctx context.Context, sessionUuids []uuid.UUID) (map[uuid.UUID]*SessionResult, error) {
+	results := make(map[uuid.UUID]*SessionResult, len(sessionUuids))
+	if len(sessionUuids) == 0 {
+		return results, nil
+	}
+
+	details, err := s.r.FindQuizSessionsByUuids(ctx, sessionUuids)
+	if err != nil {
+		return nil, err
+	}
+
+	for sessionUuid, detail := range details {
+		results[sessionUuid] = s.score(detail)
+	}
+
+	return results, nil
+}
+
+// countAnswers counts the good and wrong answers given to a question and the good answers expected.
+// A wrong answer is a checked invalid answer, an item put at the wrong position or paired with the
+// wrong item, or a typed answer that does not match
+func countAnswers(question QuizQuestion) answerCount {
+	count := answerCount{}
+
+	switch question.Kind {
+	case ShortAnswer, Numeric:
+		count.expected = 1
+		for _, answer := range question.Answers {
+			if answer.Checked {
+				count.right = 1
+			}
+		}
+		if count.right == 0 && question.Text != "" {
+			count.wrong = 1
+		}
+	case Ordering:
+		for _, answer := range question.Answers {
+			count.expected++
+			if answer.AnsweredPosition == answer.Position {
+				count.right++
+			} else if answer.AnsweredPosition != 0 {
+				count.wrong++
+			}
+		}
+	case Matching:
+		for _, answer := range question.Answers {
+			count.expected++
+			if answer.AnsweredRight == answer.Right {
+				count.right++
+			} else if answer.AnsweredRight != "" {
+				count.wrong++
+			}
+		}
+	default:
+		for _, answer := range question.Answers {
+			if answer.Valid {
+				count.expected++
+				if answer.Checked {
+					count.right++
+				}
+			} else if answer.Checked {
+				count.wrong++
+			}
+		}
+	}
+
+	return count
+}
+
+// scoreQuestion turns the answers given to a question into points:
+//   - AllOrNothing gives all the points when the question is entirely right, nothing otherwise,
+//     unless the question itself gives partial credit
+//   - Proportional gives the points in proportion of the good answers, a wrong checkbox cancelling a
+//     good one so that checking everything is worth nothing
+//   - NegativeMarking gives the points in proportion of the good answers minus the wrong ones, down
+//     to minus the points of the question
+func scoreQuestion(strategy ScoringStrategy, question QuizQuestion, count answerCount) float64 {
+	points := float64(question.Points)
+
+	// a choice question without any valid answer is right when nothing is checked
+	if count.expected == 0 {
+		count.expected = 1
+		if count.wrong == 0 {
+			count.right = 1
+		}
+	}
+
+	switch {
+	case strategy == NegativeMarking:
+		return math.Max(points*float64(count.right-count.wrong)/float64(count.expected), -points)
+	case strategy == Proportional || question.PartialCredit:
+		good := count.right
+		if question.Kind == Choice {
+			good -= count.wrong
+		}
+		return points * float64(max(good, 0)) / float64(count.expected)
+	default:
+		if count.right == count.expected && count.wrong == 0 {
+			return points
+		}
+		return 0
+	}
+}
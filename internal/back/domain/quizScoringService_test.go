@@ -0,0 +1,173 @@
+/*
+ * Copyright (c) 2023 Michaël COLL.
+ *
+ * Licensed under the Apache License, Version 2.0 (the "License");
+ * you may not use this file except in compliance with the License.
+ * You may obtain a copy of the License at
+ *
+ *      http://www.apache.org/licenses/LICENSE-2.0
+ *
+ * Unless required by applicable law or agreed to in writing, software
+ * distributed under the License is distributed on an "AS IS" BASIS,
+ * WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+ * See the License for the specific language governing permissions and
+ * limitations under the License.
+ */
+
+package domain
+
+import (
+	"context"
+	"testing"
+
+	"github.com/google/uuid"
+	"github.com/stretchr/testify/assert"
+)
+
+// choiceQuestion creates a choice question worth 2 points with 2 valid and 2 invalid answers
+func choiceQuestion(validChecked int, invalidChecked int) QuizQuestion {
+	answers := map[string]QuizQuestionAnswer{}
+	for i, sha1 := range []string{"v1", "v2"} {
+		answers[sha1] = QuizQuestionAnswer{Sha1: sha1, Valid: true, Checked: i < validChecked}
+	}
+	for i, sha1 := range []string{"i1", "i2"} {
+		answers[sha1] = QuizQuestionAnswer{Sha1: sha1, Checked: i < invalidChecked}
+	}
+
+	return QuizQuestion{Kind: Choice, Points: 2, Answers: answers}
+}
+
+// orderingQuestion creates an ordering question worth 3 points answered with the given order
+func orderingQuestion(partialCredit bool, answeredPositions ...int) QuizQuestion {
+	answers := map[string]QuizQuestionAnswer{}
+	for i, sha1 := range []string{"a", "b", "c"} {
+		answer := QuizQuestionAnswer{Sha1: sha1, Valid: true, Position: i + 1}
+		if len(answeredPositions) > 0 {
+			answer.AnsweredPosition = answeredPositions[i]
+		}
+		answers[sha1] = answer
+	}
+
+	return QuizQuestion{Kind: Ordering, Points: 3, PartialCredit: partialCredit, Answers: answers}
+}
+
+// shortAnswerQuestion creates a short-answer question worth 1 point
+func shortAnswerQuestion(text string, right bool) QuizQuestion {
+	return QuizQuestion{
+		Kind:    ShortAnswer,
+		Points:  1,
+		Text:    text,
+		Answers: map[string]QuizQuestionAnswer{"a": {Sha1: "a", Valid: true, Checked: right}},
+	}
+}
+
+func Test_scoreQuestion(t *testing.T) {
+	matching := QuizQuestion{
+		Kind:   Matching,
+		Points: 2,
+		Answers: map[string]QuizQuestionAnswer{
+			"a": {Sha1: "a", Right: "1", AnsweredRight: "1"},
+			"b": {Sha1: "b", Right: "2", AnsweredRight: "3"},
+			"c": {Sha1: "c", Right: "3", AnsweredRight: "2"},
+			"d": {Sha1: "d", Right: "4", AnsweredRight: "4"},
+		},
+	}
+
+	tests := []struct {
+		name     string
+		strategy ScoringStrategy
+		question QuizQuestion
+		want     float64
+	}{
+		{"all-or-nothing, choice right", AllOrNothing, choiceQuestion(2, 0), 2},
+		{"all-or-nothing, choice missing a valid answer", AllOrNothing, choiceQuestion(1, 0), 0},
+		{"all-or-nothing, choice with a wrong tick", AllOrNothing, choiceQuestion(2, 1), 0},
+		{"all-or-nothing, choice unanswered", AllOrNothing, choiceQuestion(0, 0), 0},
+		{"partial, choice right", Proportional, choiceQuestion(2, 0), 2},
+		{"partial, choice missing a valid answer", Proportional, choiceQuestion(1, 0), 1},
+		{"partial, choice with a wrong tick", Proportional, choiceQuestion(2, 1), 1},
+		{"partial, choice all checked", Proportional, choiceQuestion(2, 2), 0},
+		{"partial, choice only wrong ticks", Proportional, choiceQuestion(0, 2), 0},
+		{"negative, choice right", NegativeMarking, choiceQuestion(2, 0), 2},
+		{"negative, choice with a wrong tick", NegativeMarking, choiceQuestion(2, 1), 1},
+		{"negative, choice only wrong ticks", NegativeMarking, choiceQuestion(0, 2), -2},
+		{"negative, choice unanswered", NegativeMarking, choiceQuestion(0, 0), 0},
+		{"all-or-nothing, ordering right", AllOrNothing, orderingQuestion(false, 1, 2, 3), 3},
+		{"all-or-nothing, ordering swapped", AllOrNothing, orderingQuestion(false, 1, 3, 2), 0},
+		{"all-or-nothing, ordering with partial credit", AllOrNothing, orderingQuestion(true, 1, 3, 2), 1},
+		{"partial, ordering swapped", Proportional, orderingQuestion(false, 1, 3, 2), 1},
+		{"partial, ordering unanswered", Proportional, orderingQuestion(false), 0},
+		{"negative, ordering swapped", NegativeMarking, orderingQuestion(false, 1, 3, 2), -1},
+		{"negative, ordering unanswered", NegativeMarking, orderingQuestion(false), 0},
+		{"all-or-nothing, matching swapped", AllOrNothing, matching, 0},
+		{"partial, matching swapped", Proportional, matching, 1},
+		{"negative, matching swapped", NegativeMarking, matching, 0},
+		{"all-or-nothing, short answer right", AllOrNothing, shortAnswerQuestion("Steve Rogers", true), 1},
+		{"partial, short answer wrong", Proportional, shortAnswerQuestion("Tony Stark", false), 0},
+		{"negative, short answer wrong", NegativeMarking, shortAnswerQuestion("Tony Stark", false), -1},
+		{"negative, short answer unanswered", NegativeMarking, shortAnswerQuestion("", false), 0},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			assert.InDelta(t, tt.want, scoreQuestion(tt.strategy, tt.question, countAnswers(tt.question)), 1e-9)
+		})
+	}
+}
+
+func TestQuizService_score(t *testing.T) {
+	tests := []struct {
+		name     string
+		strategy ScoringStrategy
+		want     SessionResult
+	}{
+		{"all-or-nothing", AllOrNothing, SessionResult{GoodAnswer: 1, TotalAnswer: 3, PointsEarned: 2, PointsPossible: 6}},
+		{"partial", Proportional, SessionResult{GoodAnswer: 1, TotalAnswer: 3, PointsEarned: 3, PointsPossible: 6}},
+		{"negative", NegativeMarking, SessionResult{GoodAnswer: 1, TotalAnswer: 3, PointsEarned: 0, PointsPossible: 6}},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			s := NewQuizService(nil)
+			detail := &QuizSessionDetail{
+				Scoring: tt.strategy,
+				Questions: map[string]QuizQuestion{
+					"q1": choiceQuestion(2, 0),
+					"q2": orderingQuestion(false, 1, 3, 2),
+					"q3": shortAnswerQuestion("Tony Stark", false),
+				},
+			}
+
+			assert.Equal(t, tt.want, *s.score(detail))
+			assert.Equal(t, 2.0, detail.Questions["q1"].Score)
+		})
+	}
+}
+
+func TestQuizService_FindAllSessions_score(t *testing.T) {
+
+	mockQuizRepository := NewMockQuizRepository(t)
+
+	s := NewQuizService(mockQuizRepository)
+
+	finished := &Session{Id: uuid.New()}
+	running := &Session{Id: uuid.New(), RemainingSec: 60}
+	detail := &QuizSessionDetail{
+		SessionId: finished.Id,
+		Scoring:   Proportional,
+		Questions: map[string]QuizQuestion{"q1": choiceQuestion(1, 0)},
+	}
+
+	mockQuizRepository.On("FindAllSessions", context.Background(), true, "", uint16(10), uint16(0)).Return([]*Session{finished, running}, nil)
+	mockQuizRepository.On("CountAllSessions", context.Background(), true, "").Return(uint32(2), nil)
+	mockQuizRepository.On("FindQuizSessionsByUuids", context.Background(), []uuid.UUID{finished.Id}).
+		Return(map[uuid.UUID]*QuizSessionDetail{finished.Id: detail}, nil)
+
+	sessions, _, err := s.FindAllSessions(context.Background(), true, "", 10, 0)
+	if err != nil {
+		assert.Failf(t, "Fail to find sessions : %w", err.Error())
+	}
+
+	assert.Equal(t, &SessionResult{GoodAnswer: 0, TotalAnswer: 1, PointsEarned: 1, PointsPossible: 2}, sessions[0].Result)
+	assert.Nil(t, sessions[1].Result)
+
+	mockQuizRepository.AssertExpectations(t)
+}
@@ -19,7 +19,13 @@ package domain
 import (
 	"context"
 	"fmt"
+	"math"
+	"math/rand/v2"
+	"os"
+	"regexp"
+	"sort"
 	"strconv"
+	"strings"
 
 	"github.com/fatih/color"
 	"github.com/google/uuid"
@@ -27,29 +33,87 @@ import (
 )
 
 type QuizService struct {
-	r QuizRepository
+	r       QuizRepository
+	sources *sourceSync
 }
 
 func NewQuizService(r QuizRepository) QuizService {
-	return QuizService{r: r}
+	return QuizService{r: r, sources: newSourceSync()}
 }
 
-func (s *QuizService) FindFullBySha1(ctx context.Context, sha1 string, userId string) (*Quiz, error) {
+// FindFullBySha1 gives a quiz visible by the user, every quiz being visible when userId is empty,
+// in the first of the preferred languages it is available in. The explanations are only revealed
+// with the sessions and the answer key is hidden from the students
+func (s *QuizService) FindFullBySha1(ctx context.Context, sha1 string, userId string, preferred []string) (*Quiz, error) {
 	quiz, err := s.r.FindFullBySha1(ctx, sha1, userId)
 	if err != nil {
 		return nil, err
 	}
 
+	err = s.TranslateQuiz(ctx, quiz, preferred)
+	if err != nil {
+		return nil, err
+	}
+	quiz.HideSolutions(userId != "")
+
 	return quiz, nil
 }
 
-func (s *QuizService) FindAllActive(ctx context.Context, userId string, limit uint16, offset uint16) ([]*Quiz, uint32, error) {
-	quizzes, err := s.r.FindAllActive(ctx, userId, limit, offset)
+// HideSolutions removes what is only revealed with the sessions: the explanations and, for the
+// students, the answer key
+func (q *Quiz) HideSolutions(student bool) {
+	for questionSha1, question := range q.Questions {
+		question.Explanation = ""
+		for answerSha1, answer := range question.Answers {
+			answer.Explanation = ""
+			question.Answers[answerSha1] = answer
+		}
+		if student {
+			question.hideAnswerKey()
+		}
+		q.Questions[questionSha1] = question
+	}
+}
+
+// hideAnswerKey removes what gives the right answers away: the accepted answers of the ShortAnswer
+// and Numeric questions, the expected positions of the Ordering items, which are shuffled as they
+// are written in the expected order, and the expected pairs of the Matching questions, only their
+// right-hand items being kept
+func (q *QuizQuestion) hideAnswerKey() {
+	switch q.Kind {
+	case ShortAnswer, Numeric:
+		for answerSha1, answer := range q.Answers {
+			answer.Content = ""
+			answer.Match = NoMatch
+			q.Answers[answerSha1] = answer
+		}
+	case Ordering, Matching:
+		answerSha1s := sortedAnswerSha1s(q.Answers)
+		if q.Kind == Ordering {
+			rand.Shuffle(len(answerSha1s), func(i, j int) {
+				answerSha1s[i], answerSha1s[j] = answerSha1s[j], answerSha1s[i]
+			})
+		}
+		for i, answerSha1 := range answerSha1s {
+			answer := q.Answers[answerSha1]
+			answer.Position = 0
+			answer.Right = ""
+			answer.RightSha1 = ""
+			answer.Ordinal = i + 1
+			q.Answers[answerSha1] = answer
+		}
+	}
+}
+
+// FindAllActive gives the active quizzes of the given category and of its sub-categories, all the
+// active quizzes when the category is empty
+func (s *QuizService) FindAllActive(ctx context.Context, userId string, category string, limit uint16, offset uint16) ([]*Quiz, uint32, error) {
+	quizzes, err := s.r.FindAllActive(ctx, userId, category, limit, offset)
 	if err != nil {
 		return nil, 0, err
 	}
 
-	count, err := s.r.CountAllActive(ctx, userId)
+	count, err := s.r.CountAllActive(ctx, userId, category)
 	if err != nil {
 		return nil, 0, err
 	}
@@ -57,42 +121,115 @@ func (s *QuizService) FindAllActive(ctx context.Context, userId string, limit ui
 	return quizzes, count, nil
 }
 
-func (s *QuizService) Sync(ctx context.Context) error {
+// Sync saves the quizzes of every source and reports the diagnostics of their quiz files. The files
+// with errors are not saved, the previous version of their quiz stays active. The quiz files of a
+// source are not parsed again while its version does not change
+func (s *QuizService) Sync(ctx context.Context) (*SyncStats, error) {
+	sources, err := quizSourcesFromConfig()
+	if err != nil {
+		return nil, err
+	}
+
+	s.sources.mu.Lock()
+	defer s.sources.mu.Unlock()
+
+	syncStats := SyncStats{}
+	for _, source := range sources {
+		stats, err := s.syncSource(ctx, source, len(sources) > 1)
+		if err != nil {
+			return nil, fmt.Errorf("can't sync source %s (%v)", source.Name(), err)
+		}
+
+		diagnostics := append(syncStats.Diagnostics, stats.Diagnostics...)
+		syncStats = addStats(syncStats, stats)
+		syncStats.Diagnostics = diagnostics
+	}
+
+	return &syncStats, nil
+}
+
+// syncSource saves the quizzes of a source. The diagnostics tell their source when several sources
+// are configured. s.sources.mu must be held
+func (s *QuizService) syncSource(ctx context.Context, source QuizSource, named bool) (*SyncStats, error) {
+	label := "Repo"
+	if named {
+		label = "Source " + source.Name()
+	}
 
-	quizzes, err := s.ScanGitRepo()
+	fs, version, err := source.Open()
 	if err != nil {
-		return err
+		return nil, err
+	}
+
+	if version != "" && version == s.sources.versions[source.Name()] {
+		fmt.Printf("%s %s synced %s\n",
+			color.GreenString("✓"),
+			label,
+			color.BlueString(color.New(color.FgHiBlack).Sprintf(" — no changes since version %s", shortVersion(version))))
+		return &SyncStats{Diagnostics: s.sources.diagnostics[source.Name()]}, nil
 	}
 
-	var syncStats SyncStats
+	quizzes, diagnostics, err := s.scanQuizzes(fs)
+	if err != nil {
+		return nil, err
+	}
+
+	if named {
+		for i := range diagnostics {
+			diagnostics[i].Source = source.Name()
+		}
+	}
+	diagnostics.Print(os.Stdout)
+
+	syncStats := SyncStats{}
 	for _, quiz := range quizzes {
+		quiz.Source = source.Name()
+		quiz.Sha1 = sourceSha1(quiz.Source, quiz.Sha1)
 		stats, err := s.SaveQuiz(ctx, quiz)
 		if err != nil {
-			return err
+			return nil, err
 		}
 
 		syncStats = addStats(syncStats, stats)
 	}
+	syncStats.Diagnostics = diagnostics
+	s.sources.versions[source.Name()] = version
+	s.sources.diagnostics[source.Name()] = diagnostics
 
 	if syncStats.Created > 0 || syncStats.Updated > 0 {
-		fmt.Printf("%s Repo synced (%s quiz(zes) created, %s quiz(zes) updated)\n",
+		fmt.Printf("%s %s synced (%s quiz(zes) created, %s quiz(zes) updated)\n",
 			color.GreenString("✓"),
+			label,
 			color.BlueString(strconv.Itoa(syncStats.Created)),
 			color.BlueString(strconv.Itoa(syncStats.Updated)))
 	} else {
-		fmt.Printf("%s Repo synced %s\n",
+		fmt.Printf("%s %s synced %s\n",
 			color.GreenString("✓"),
+			label,
 			color.BlueString(color.New(color.FgHiBlack).Sprintf(" — no changes")))
 	}
 
-	return nil
+	return &syncStats, nil
+}
+
+// shortVersion shortens the hash of a commit or of an archive the way git does
+func shortVersion(version string) string {
+	if len(version) > 7 {
+		return version[:7]
+	}
+
+	return version
 }
 
 func (s *QuizService) SaveQuiz(ctx context.Context, quiz *Quiz) (*SyncStats, error) {
 
 	verbose := viper.GetBool("verbose")
 
-	latestQuiz, err := s.r.FindLatestVersionByFilename(ctx, quiz.Filename)
+	if quiz.Source == "" {
+		quiz.Source = defaultSourceName
+	}
+
+	latestQuiz, err := s.r.FindLatestVersionByFilename(ctx, quiz.Source, quiz.Filename)
 	if err != nil {
 		return nil, err
 	}
@@ -126,7 +263,7 @@ func (s *QuizService) SaveQuiz(ctx context.Context, quiz *Quiz) (*SyncStats, err
 			return nil, err
 		}
 
-		err := s.r.ActivateOnlyVersion(ctx, quiz.Filename, quiz.Version)
+		err := s.r.ActivateOnlyVersion(ctx, quiz.Source, quiz.Filename, quiz.Version)
 		if err != nil {
 			return nil, err
 		}
@@ -161,27 +298,213 @@ func (s *QuizService) FindAllSessions(ctx context.Context, quizActive bool, user
 		return nil, 0, err
 	}
 
+	var finished []uuid.UUID
+	for _, session := range sessions {
+		if session.RemainingSec == 0 {
+			finished = append(finished, session.Id)
+		}
+	}
+	results, err := s.scoreSessions(ctx, finished)
+	if err != nil {
+		return nil, 0, err
+	}
+	for _, session := range sessions {
+		if result, found := results[session.Id]; found {
+			session.Result = result
+		}
+	}
+
 	return sessions, count, nil
 }
 
+// StartSession starts a session on the quiz with the questions drawn by its pool
 func (s *QuizService) StartSession(ctx context.Context, userId string, quizSha1 string) (uuid.UUID, error) {
-	return s.r.StartSession(ctx, userId, quizSha1)
+	quiz, err := s.r.FindPoolBySha1(ctx, quizSha1)
+	if err != nil {
+		return uuid.UUID{}, err
+	}
+	if quiz == nil {
+		return uuid.UUID{}, Errorf(NotFound, "quiz with sha1: %s was not found.", quizSha1)
+	}
+
+	seed := rand.Int64()
+
+	return s.r.StartSession(ctx, userId, quizSha1, seed, quiz.Metadata.Pool.draw(quiz.Questions, seed))
 }
 
 func (s *QuizService) AddSessionAnswer(ctx context.Context, sessionUuid uuid.UUID, userId string, questionSha1 string, answerSha1 string, checked bool) error {
 	return s.r.AddSessionAnswer(ctx, sessionUuid, questionSha1, answerSha1, checked)
 }
 
+func (s *QuizService) AddSessionTextAnswer(ctx context.Context, sessionUuid uuid.UUID, userId string, questionSha1 string, text string) error {
+	return s.addSessionTypedAnswer(ctx, sessionUuid, questionSha1, ShortAnswer, text)
+}
+
+func (s *QuizService) AddSessionNumericAnswer(ctx context.Context, sessionUuid uuid.UUID, userId string, questionSha1 string, number float64) error {
+	return s.addSessionTypedAnswer(ctx, sessionUuid, questionSha1, Numeric, strconv.FormatFloat(number, 'f', -1, 64))
+}
+
+func (s *QuizService) AddSessionOrderAnswer(ctx context.Context, sessionUuid uuid.UUID, userId string, questionSha1 string, order []string) error {
+	question, err := s.findQuestionOfKind(ctx, sessionUuid, questionSha1, Ordering)
+	if err != nil {
+		return err
+	}
+
+	positions := make(map[string]int, len(order))
+	for i, answerSha1 := range order {
+		if _, found := question.Answers[answerSha1]; !found {
+			return Errorf(InvalidArgument, "item with sha1: %s is not part of the question %s", answerSha1, questionSha1)
+		}
+		positions[answerSha1] = i + 1
+	}
+	if len(positions) != len(question.Answers) || len(order) != len(question.Answers) {
+		return Errorf(InvalidArgument, "the order must contain every item of the question exactly once")
+	}
+
+	return s.r.AddSessionOrderAnswer(ctx, sessionUuid, questionSha1, positions)
+}
+
+func (s *QuizService) AddSessionPairAnswer(ctx context.Context, sessionUuid uuid.UUID, userId string, questionSha1 string, pairs map[string]string) error {
+	question, err := s.findQuestionOfKind(ctx, sessionUuid, questionSha1, Matching)
+	if err != nil {
+		return err
+	}
+
+	rights := make(map[string]string, len(pairs))
+	for answerSha1, rightSha1 := range pairs {
+		if _, found := question.Answers[answerSha1]; !found {
+			return Errorf(InvalidArgument, "item with sha1: %s is not part of the question %s", answerSha1, questionSha1)
+		}
+		right, found := question.RightItems[rightSha1]
+		if !found {
+			return Errorf(InvalidArgument, "item with sha1: %s is not part of the question %s", rightSha1, questionSha1)
+		}
+		rights[answerSha1] = right
+	}
+	if len(rights) != len(question.Answers) {
+		return Errorf(InvalidArgument, "every item of the question must be paired")
+	}
+
+	return s.r.AddSessionPairAnswer(ctx, sessionUuid, questionSha1, rights)
+}
+
+func (s *QuizService) addSessionTypedAnswer(ctx context.Context, sessionUuid uuid.UUID, questionSha1 string, kind QuestionKind, text string) error {
+	question, err := s.findQuestionOfKind(ctx, sessionUuid, questionSha1, kind)
+	if err != nil {
+		return err
+	}
+
+	if kind == ShortAnswer {
+		translations, err := s.r.FindSessionAnswerTranslations(ctx, sessionUuid, questionSha1)
+		if err != nil {
+			return err
+		}
+		for sha1, answer := range question.Answers {
+			answer.Translations = translations[sha1]
+			question.Answers[sha1] = answer
+		}
+	}
+
+	return s.r.AddSessionTextAnswer(ctx, sessionUuid, questionSha1, matchTextAnswer(question.Answers, text), text)
+}
+
+// findQuestionOfKind gives a question of the quiz of the session, as written in this quiz
+func (s *QuizService) findQuestionOfKind(ctx context.Context, sessionUuid uuid.UUID, questionSha1 string, kind QuestionKind) (*QuizQuestion, error) {
+	question, err := s.r.FindSessionQuestion(ctx, sessionUuid, questionSha1)
+	if err != nil {
+		return nil, err
+	}
+	if question == nil {
+		return nil, Errorf(NotFound, "question with sha1: %s was not found.", questionSha1)
+	}
+	if question.Kind != kind {
+		return nil, Errorf(InvalidArgument, "question with sha1: %s does not accept this kind of answer", questionSha1)
+	}
+
+	return question, nil
+}
+
+// numericEpsilon absorbs the floating point rounding so that a value on the edge of the tolerance is accepted
+const numericEpsilon = 1e-9
+
+// matchTextAnswer returns, for each expected answer, if the given text matches it. Only the first
+// matching answer (in sha1 order) is checked so a question can never be counted twice.
+func matchTextAnswer(answers map[string]QuizQuestionAnswer, text string) map[string]bool {
+	sha1s := make([]string, 0, len(answers))
+	for sha1 := range answers {
+		sha1s = append(sha1s, sha1)
+	}
+	sort.Strings(sha1s)
+
+	checked := make(map[string]bool, len(answers))
+	matched := false
+	for _, sha1 := range sha1s {
+		checked[sha1] = !matched && answers[sha1].matches(text)
+		matched = matched || checked[sha1]
+	}
+
+	return checked
+}
+
+func (a QuizQuestionAnswer) matches(text string) bool {
+	for _, translation := range a.Translations {
+		if (QuizQuestionAnswer{Content: translation, Match: a.Match}).matches(text) {
+			return true
+		}
+	}
+
+	text = strings.TrimSpace(text)
+
+	switch a.Match {
+	case ExactMatch:
+		return text == a.Content
+	case CaseInsensitiveMatch:
+		return strings.EqualFold(text, a.Content)
+	case RegexMatch:
+		matched, err := regexp.MatchString("^(?:"+a.Content+")$", text)
+		return err == nil && matched
+	case ToleranceMatch:
+		number, err := strconv.ParseFloat(text, 64)
+		if err != nil {
+			return false
+		}
+		value, tolerance, err := parseNumericAnswer(a.Content)
+		return err == nil && math.Abs(number-value) <= tolerance+numericEpsilon
+	}
+
+	return false
+}
+
 func (s *QuizService) FindAllQuizSessions(ctx context.Context, userId string, classId string, limit uint16, offset uint16) ([]*QuizSession, uint32, error) {
 	quizzes, err := s.r.FindAllQuizSessions(ctx, userId, classId, limit, offset)
 	if err != nil {
 		return nil, 0, err
 	}
 
-	count, err := s.r.CountAllActive(ctx, userId)
+	count, err := s.r.CountAllActive(ctx, userId, "")
+	if err != nil {
+		return nil, 0, err
+	}
+
+	var finished []uuid.UUID
+	for _, quiz := range quizzes {
+		for _, userSession := range quiz.UserSessions {
+			if userSession.RemainingSec == 0 && userSession.SessionId != uuid.Nil {
+				finished = append(finished, userSession.SessionId)
+			}
+		}
+	}
+	results, err := s.scoreSessions(ctx, finished)
 	if err != nil {
 		return nil, 0, err
 	}
+	for _, quiz := range quizzes {
+		for _, userSession := range quiz.UserSessions {
+			if result, found := results[userSession.SessionId]; found {
+				userSession.Result = result
+			}
+		}
+	}
 
 	return quizzes, count, nil
 }
@@ -192,5 +515,10 @@ func (s *QuizService) FindQuizSessionByUuid(ctx context.Context, sessionUuid uui
 		return nil, err
 	}
 
+	sessionDetail.arrange()
+	if sessionDetail.RemainingSec == 0 {
+		sessionDetail.Result = s.score(sessionDetail)
+	}
+
 	return sessionDetail, nil
 }
@@ -19,7 +19,9 @@ package domain
 import (
 	"testing"
 
+	"github.com/google/uuid"
 	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/mock"
 	"golang.org/x/net/context"
 )
 
@@ -43,7 +45,7 @@ func TestQuizService_saveQuiz_create_quiz(t *testing.T) {
 		Version:  1,
 	}
 
-	mockQuizRepository.On("FindLatestVersionByFilename", context.Background(), Filename).Return(nil, nil)
+	mockQuizRepository.On("FindLatestVersionByFilename", context.Background(), defaultSourceName, Filename).Return(nil, nil)
 	mockQuizRepository.On("Create", context.Background(), q).Return(nil)
 
 	// Test creation of quiz
@@ -73,14 +75,15 @@ func TestQuizService_saveQuiz_update_quiz(t *testing.T) {
 
 	quizUpdate := &Quiz{
 		Sha1:     Sha1Update,
+		Source:   defaultSourceName,
 		Filename: Filename,
 		Name:     Name,
 		Version:  2,
 	}
 
-	mockQuizRepository.On("FindLatestVersionByFilename", context.Background(), Filename).Return(lastQuiz, nil)
+	mockQuizRepository.On("FindLatestVersionByFilename", context.Background(), defaultSourceName, Filename).Return(lastQuiz, nil)
 	mockQuizRepository.On("Create", context.Background(), quizUpdate).Return(nil)
-	mockQuizRepository.On("ActivateOnlyVersion", context.Background(), Filename, 2).Return(nil)
+	mockQuizRepository.On("ActivateOnlyVersion", context.Background(), defaultSourceName, Filename, 2).Return(nil)
 
 	// Test update of quiz
 	stats, err := s.SaveQuiz(context.Background(), &Quiz{
@@ -98,3 +101,308 @@ func TestQuizService_saveQuiz_update_quiz(t *testing.T) {
 
 	mockQuizRepository.AssertExpectations(t)
 }
+
+func Test_matchTextAnswer(t *testing.T) {
+	answers := map[string]QuizQuestionAnswer{
+		"a": {Sha1: "a", Content: "Steve Rogers", Valid: true, Match: ExactMatch},
+		"b": {Sha1: "b", Content: "steve rogers", Valid: true, Match: CaseInsensitiveMatch},
+		"c": {Sha1: "c", Content: "S(teve|\\.) ?Rogers", Valid: true, Match: RegexMatch},
+	}
+
+	assert.Equal(t, map[string]bool{"a": true, "b": false, "c": false}, matchTextAnswer(answers, " Steve Rogers "))
+	assert.Equal(t, map[string]bool{"a": false, "b": true, "c": false}, matchTextAnswer(answers, "STEVE ROGERS"))
+	assert.Equal(t, map[string]bool{"a": false, "b": false, "c": true}, matchTextAnswer(answers, "S. Rogers"))
+	assert.Equal(t, map[string]bool{"a": false, "b": false, "c": false}, matchTextAnswer(answers, "Tony Stark"))
+	assert.Equal(t, map[string]bool{"a": false, "b": false, "c": false}, matchTextAnswer(answers, "Mr S. Rogers"))
+}
+
+func Test_matchTextAnswer_tolerance(t *testing.T) {
+	answers := map[string]QuizQuestionAnswer{
+		"a": {Sha1: "a", Content: "3.14 ± 0.01", Valid: true, Match: ToleranceMatch},
+	}
+
+	assert.Equal(t, map[string]bool{"a": true}, matchTextAnswer(answers, "3.14"))
+	assert.Equal(t, map[string]bool{"a": true}, matchTextAnswer(answers, "3.15"))
+	assert.Equal(t, map[string]bool{"a": true}, matchTextAnswer(answers, "3.13"))
+	assert.Equal(t, map[string]bool{"a": false}, matchTextAnswer(answers, "3.16"))
+	assert.Equal(t, map[string]bool{"a": false}, matchTextAnswer(answers, "pi"))
+}
+
+func TestQuizService_AddSessionTextAnswer(t *testing.T) {
+
+	mockQuizRepository := NewMockQuizRepository(t)
+
+	s := NewQuizService(mockQuizRepository)
+
+	sessionUuid := uuid.New()
+	question := &QuizQuestion{
+		Sha1: "q",
+		Kind: ShortAnswer,
+		Answers: map[string]QuizQuestionAnswer{
+			"a": {Sha1: "a", Content: "Steve Rogers", Valid: true, Match: CaseInsensitiveMatch},
+		},
+	}
+
+	mockQuizRepository.On("FindSessionQuestion", context.Background(), sessionUuid, "q").Return(question, nil)
+	mockQuizRepository.On("FindSessionAnswerTranslations", context.Background(), sessionUuid, "q").Return(map[string][]string{}, nil)
+	mockQuizRepository.On("AddSessionTextAnswer", context.Background(), sessionUuid, "q", map[string]bool{"a": true}, "steve rogers").Return(nil)
+
+	err := s.AddSessionTextAnswer(context.Background(), sessionUuid, "user", "q", "steve rogers")
+	if err != nil {
+		assert.Failf(t, "Fail to add answer : %w", err.Error())
+	}
+
+	mockQuizRepository.AssertExpectations(t)
+}
+
+func TestQuizService_FindFullBySha1_hides_short_answers(t *testing.T) {
+
+	mockQuizRepository := NewMockQuizRepository(t)
+
+	s := NewQuizService(mockQuizRepository)
+
+	quiz := &Quiz{
+		Sha1: Sha1Create,
+		Questions: map[string]QuizQuestion{
+			"q": {
+				Sha1: "q",
+				Kind: ShortAnswer,
+				Answers: map[string]QuizQuestionAnswer{
+					"a": {Sha1: "a", Content: "Steve Rogers", Valid: true, Match: CaseInsensitiveMatch},
+				},
+			},
+		},
+	}
+
+	mockQuizRepository.On("FindFullBySha1", context.Background(), Sha1Create, "user").Return(quiz, nil)
+	mockQuizRepository.On("FindTranslations", context.Background(), Sha1Create).Return(map[string]QuizTranslation{}, nil)
+
+	found, err := s.FindFullBySha1(context.Background(), Sha1Create, "user", nil)
+	if err != nil {
+		assert.Failf(t, "Fail to find quiz : %w", err.Error())
+	}
+
+	answer := found.Questions["q"].Answers["a"]
+	assert.Empty(t, answer.Content)
+	assert.Equal(t, NoMatch, answer.Match)
+}
+
+func TestQuizService_FindFullBySha1_hides_numeric_answers(t *testing.T) {
+
+	mockQuizRepository := NewMockQuizRepository(t)
+
+	s := NewQuizService(mockQuizRepository)
+
+	quiz := &Quiz{
+		Sha1: Sha1Create,
+		Questions: map[string]QuizQuestion{
+			"q": {
+				Sha1: "q",
+				Kind: Numeric,
+				Answers: map[string]QuizQuestionAnswer{
+					"a": {Sha1: "a", Content: "1962 ± 1", Valid: true, Match: ToleranceMatch},
+				},
+			},
+		},
+	}
+
+	mockQuizRepository.On("FindFullBySha1", context.Background(), Sha1Create, "user").Return(quiz, nil)
+	mockQuizRepository.On("FindTranslations", context.Background(), Sha1Create).Return(map[string]QuizTranslation{}, nil)
+
+	found, err := s.FindFullBySha1(context.Background(), Sha1Create, "user", nil)
+	if err != nil {
+		assert.Failf(t, "Fail to find quiz : %w", err.Error())
+	}
+
+	answer := found.Questions["q"].Answers["a"]
+	assert.Empty(t, answer.Content)
+	assert.Equal(t, NoMatch, answer.Match)
+}
+
+func TestQuizService_FindFullBySha1_hides_ordering_and_pairs(t *testing.T) {
+
+	mockQuizRepository := NewMockQuizRepository(t)
+
+	s := NewQuizService(mockQuizRepository)
+
+	quiz := &Quiz{
+		Sha1: Sha1Create,
+		Questions: map[string]QuizQuestion{
+			"o": {
+				Sha1: "o",
+				Kind: Ordering,
+				Answers: map[string]QuizQuestionAnswer{
+					"o1": {Sha1: "o1", Content: "Iron Man", Valid: true, Position: 1, Ordinal: 1},
+					"o2": {Sha1: "o2", Content: "The Avengers", Valid: true, Position: 2, Ordinal: 2},
+				},
+			},
+			"m": {
+				Sha1: "m",
+				Kind: Matching,
+				Answers: map[string]QuizQuestionAnswer{
+					"m1": {Sha1: "m1", Content: "Thor", Valid: true, Position: 1, Right: "Asgard", RightSha1: "r1", Ordinal: 1},
+				},
+				RightItems: map[string]string{"r1": "Asgard"},
+			},
+		},
+	}
+
+	mockQuizRepository.On("FindFullBySha1", context.Background(), Sha1Create, "user").Return(quiz, nil)
+	mockQuizRepository.On("FindTranslations", context.Background(), Sha1Create).Return(map[string]QuizTranslation{}, nil)
+
+	found, err := s.FindFullBySha1(context.Background(), Sha1Create, "user", nil)
+	if err != nil {
+		assert.Failf(t, "Fail to find quiz : %w", err.Error())
+	}
+
+	for _, answer := range found.Questions["o"].Answers {
+		assert.Zero(t, answer.Position)
+	}
+	pair := found.Questions["m"].Answers["m1"]
+	assert.Zero(t, pair.Position)
+	assert.Empty(t, pair.Right)
+	assert.Empty(t, pair.RightSha1)
+	assert.Equal(t, map[string]string{"r1": "Asgard"}, found.Questions["m"].RightItems)
+}
+
+func TestQuizService_FindFullBySha1_translated_hides_solutions(t *testing.T) {
+
+	mockQuizRepository := NewMockQuizRepository(t)
+
+	s := NewQuizService(mockQuizRepository)
+
+	quiz := &Quiz{
+		Sha1:     Sha1Create,
+		Metadata: QuizMetadata{Lang: "en"},
+		Questions: map[string]QuizQuestion{
+			"s": {
+				Sha1:        "s",
+				Kind:        ShortAnswer,
+				Explanation: "Captain America is Steve Rogers",
+				Answers: map[string]QuizQuestionAnswer{
+					"a": {Sha1: "a", Content: "Steve Rogers", Valid: true, Match: CaseInsensitiveMatch, Explanation: "His real name"},
+				},
+			},
+			"m": {
+				Sha1: "m",
+				Kind: Matching,
+				Answers: map[string]QuizQuestionAnswer{
+					"m1": {Sha1: "m1", Content: "Thor", Valid: true, Position: 1, Right: "Asgard", RightSha1: "r1", Ordinal: 1},
+				},
+				RightItems: map[string]string{"r1": "Asgard"},
+			},
+		},
+	}
+
+	mockQuizRepository.On("FindFullBySha1", context.Background(), Sha1Create, "user").Return(quiz, nil)
+	mockQuizRepository.On("FindTranslations", context.Background(), Sha1Create).Return(map[string]QuizTranslation{
+		"fr": {
+			Questions: map[string]QuestionTranslation{
+				"s": {
+					Explanation: "Captain America est Steve Rogers",
+					Answers:     map[string]AnswerTranslation{"a": {Content: "Steve Rogers", Explanation: "Son vrai nom"}},
+				},
+				"m": {
+					Answers: map[string]AnswerTranslation{"m1": {Content: "Thor", Right: "Asgard (fr)"}},
+				},
+			},
+		},
+	}, nil)
+
+	found, err := s.FindFullBySha1(context.Background(), Sha1Create, "user", []string{"fr"})
+	if err != nil {
+		assert.Failf(t, "Fail to find quiz : %w", err.Error())
+	}
+
+	assert.Equal(t, "fr", found.Metadata.Lang)
+	assert.Empty(t, found.Questions["s"].Explanation)
+	answer := found.Questions["s"].Answers["a"]
+	assert.Empty(t, answer.Content)
+	assert.Empty(t, answer.Explanation)
+	assert.Equal(t, NoMatch, answer.Match)
+	pair := found.Questions["m"].Answers["m1"]
+	assert.Empty(t, pair.Right)
+	assert.Empty(t, pair.RightSha1)
+	assert.Zero(t, pair.Position)
+	assert.Equal(t, map[string]string{"r1": "Asgard (fr)"}, found.Questions["m"].RightItems)
+}
+
+func TestQuizService_AddSessionNumericAnswer_wrong_kind(t *testing.T) {
+
+	mockQuizRepository := NewMockQuizRepository(t)
+
+	s := NewQuizService(mockQuizRepository)
+
+	question := &QuizQuestion{
+		Sha1: "q",
+		Kind: ShortAnswer,
+	}
+
+	mockQuizRepository.On("FindSessionQuestion", context.Background(), mock.Anything, "q").Return(question, nil)
+
+	err := s.AddSessionNumericAnswer(context.Background(), uuid.New(), "user", "q", 3.14)
+	assert.Error(t, err)
+
+	mockQuizRepository.AssertExpectations(t)
+}
+
+func TestQuizService_AddSessionOrderAnswer_missing_item(t *testing.T) {
+
+	mockQuizRepository := NewMockQuizRepository(t)
+
+	s := NewQuizService(mockQuizRepository)
+
+	question := &QuizQuestion{
+		Sha1: "q",
+		Kind: Ordering,
+		Answers: map[string]QuizQuestionAnswer{
+			"a": {Sha1: "a", Position: 1, Valid: true},
+			"b": {Sha1: "b", Position: 2, Valid: true},
+		},
+	}
+
+	mockQuizRepository.On("FindSessionQuestion", context.Background(), mock.Anything, "q").Return(question, nil)
+
+	err := s.AddSessionOrderAnswer(context.Background(), uuid.New(), "user", "q", []string{"a", "a"})
+	assert.Error(t, err)
+
+	err = s.AddSessionOrderAnswer(context.Background(), uuid.New(), "user", "q", []string{"a", "c"})
+	assert.Error(t, err)
+
+	mockQuizRepository.AssertExpectations(t)
+}
+
+func TestQuizService_AddSessionPairAnswer(t *testing.T) {
+
+	mockQuizRepository := NewMockQuizRepository(t)
+
+	s := NewQuizService(mockQuizRepository)
+
+	sessionUuid := uuid.New()
+	question := &QuizQuestion{
+		Sha1: "q",
+		Kind: Matching,
+		Answers: map[string]QuizQuestionAnswer{
+			"a": {Sha1: "a", Position: 1, Valid: true, Right: "one", RightSha1: "r1"},
+			"b": {Sha1: "b", Position: 2, Valid: true, Right: "two", RightSha1: "r2"},
+		},
+		RightItems: map[string]string{"r1": "one", "r2": "two"},
+	}
+
+	mockQuizRepository.On("FindSessionQuestion", context.Background(), sessionUuid, "q").Return(question, nil)
+	mockQuizRepository.On("AddSessionPairAnswer", context.Background(), sessionUuid, "q",
+		map[string]string{"a": "one", "b": "two"}).Return(nil)
+
+	err := s.AddSessionPairAnswer(context.Background(), sessionUuid, "user", "q", map[string]string{"a": "r1", "b": "r2"})
+	if err != nil {
+		assert.Failf(t, "Fail to add answer : %w", err.Error())
+	}
+
+	err = s.AddSessionPairAnswer(context.Background(), sessionUuid, "user", "q", map[string]string{"a": "r1"})
+	assert.Error(t, err)
+
+	err = s.AddSessionPairAnswer(context.Background(), sessionUuid, "user", "q", map[string]string{"a": "r1", "b": "r3"})
+	assert.Error(t, err)
+
+	mockQuizRepository.AssertExpectations(t)
+}
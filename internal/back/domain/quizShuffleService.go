@@ -0,0 +1,85 @@
+/*
+ * Copyright (c) 2023 Michaël COLL.
+ *
+ * Licensed under the Apache License, Version 2.0 (the "License");
+ * you may not use this file except in compliance with the License.
+ * You may obtain a copy of the License at
+ *
+ *      http://www.apache.org/licenses/LICENSE-2.0
+ *
+ * Unless required by applicable law or agreed to in writing, software
+ * distributed under the License is distributed on an "AS IS" BASIS,
+ * WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+ * See the License for the specific language governing permissions and
+ * limitations under the License.
+ */
+
+package domain
+
+import (
+	"math/rand/v2"
+	"sort"
+)
+
+// arrange numbers the questions of the session from 1 and the answers of each question in the
+// order they are shown to the user. When the quiz asks for it, the questions and the answers are
+// shuffled with the seed of the session so that a reload or the review of a teacher shows the
+// order the user saw. The items of an ordering question are always shuffled as they are written
+// in the expected order in the quiz file
+func (qd *QuizSessionDetail) arrange() {
+	// the stream 0 of the seed is the one drawing the questions of the session
+	r := rand.New(rand.NewPCG(uint64(qd.Seed), 1))
+
+	questionSha1s := make([]string, 0, len(qd.Questions))
+	for sha1 := range qd.Questions {
+		questionSha1s = append(questionSha1s, sha1)
+	}
+	sort.Slice(questionSha1s, func(i, j int) bool {
+		left, right := qd.Questions[questionSha1s[i]], qd.Questions[questionSha1s[j]]
+		if left.Position != right.Position {
+			return left.Position < right.Position
+		}
+		return left.Sha1 < right.Sha1
+	})
+	if qd.ShuffleQuestions {
+		r.Shuffle(len(questionSha1s), func(i, j int) {
+			questionSha1s[i], questionSha1s[j] = questionSha1s[j], questionSha1s[i]
+		})
+	}
+
+	for i, sha1 := range questionSha1s {
+		question := qd.Questions[sha1]
+		question.Position = i + 1
+
+		answerSha1s := sortedAnswerSha1s(question.Answers)
+		if qd.ShuffleAnswers || question.Kind == Ordering {
+			r.Shuffle(len(answerSha1s), func(i, j int) {
+				answerSha1s[i], answerSha1s[j] = answerSha1s[j], answerSha1s[i]
+			})
+		}
+		for j, answerSha1 := range answerSha1s {
+			answer := question.Answers[answerSha1]
+			answer.Ordinal = j + 1
+			question.Answers[answerSha1] = answer
+		}
+
+		qd.Questions[sha1] = question
+	}
+}
+
+// sortedAnswerSha1s gives the sha1 of the answers in the order they are written in the quiz file
+func sortedAnswerSha1s(answers map[string]QuizQuestionAnswer) []string {
+	sha1s := make([]string, 0, len(answers))
+	for sha1 := range answers {
+		sha1s = append(sha1s, sha1)
+	}
+	sort.Slice(sha1s, func(i, j int) bool {
+		left, right := answers[sha1s[i]], answers[sha1s[j]]
+		if left.Ordinal != right.Ordinal {
+			return left.Ordinal < right.Ordinal
+		}
+		return left.Sha1 < right.Sha1
+	})
+
+	return sha1s
+}
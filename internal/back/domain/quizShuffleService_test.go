@@ -0,0 +1,109 @@
+/*
+ * Copyright (c) 2023 Michaël COLL.
+ *
+ * Licensed under the Apache License, Version 2.0 (the "License");
+ * you may not use this file except in compliance with the License.
+ * You may obtain a copy of the License at
+ *
+ *      http://www.apache.org/licenses/LICENSE-2.0
+ *
+ * Unless required by applicable law or agreed to in writing, software
+ * distributed under the License is distributed on an "AS IS" BASIS,
+ * WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+ * See the License for the specific language governing permissions and
+ * limitations under the License.
+ */
+
+package domain
+
+import (
+	"fmt"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+)
+
+// shuffleSessionDetail creates a session on 5 choice questions at the positions 2, 4, 6, 8 and 10
+// (as drawn by a pool) of 4 answers each, and an ordering question at the position 12
+func shuffleSessionDetail(seed int64, shuffleQuestions bool, shuffleAnswers bool) *QuizSessionDetail {
+	questions := map[string]QuizQuestion{}
+	for i := 1; i <= 6; i++ {
+		question := QuizQuestion{Sha1: fmt.Sprintf("q%d", i), Position: i * 2, Answers: map[string]QuizQuestionAnswer{}}
+		if i == 6 {
+			question.Kind = Ordering
+		}
+		for j := 1; j <= 4; j++ {
+			sha1 := fmt.Sprintf("q%da%d", i, j)
+			question.Answers[sha1] = QuizQuestionAnswer{Sha1: sha1, Ordinal: j}
+		}
+		questions[question.Sha1] = question
+	}
+
+	return &QuizSessionDetail{
+		Seed:             seed,
+		ShuffleQuestions: shuffleQuestions,
+		ShuffleAnswers:   shuffleAnswers,
+		Questions:        questions,
+	}
+}
+
+// order gives the sha1 of the questions of the session followed by the sha1 of their answers, in
+// the order they are shown
+func order(qd *QuizSessionDetail) []string {
+	questionSha1s := make([]string, len(qd.Questions))
+	for _, question := range qd.Questions {
+		questionSha1s[question.Position-1] = question.Sha1
+	}
+
+	var sha1s []string
+	for _, sha1 := range questionSha1s {
+		sha1s = append(sha1s, sha1)
+		answerSha1s := make([]string, len(qd.Questions[sha1].Answers))
+		for _, answer := range qd.Questions[sha1].Answers {
+			answerSha1s[answer.Ordinal-1] = answer.Sha1
+		}
+		sha1s = append(sha1s, answerSha1s...)
+	}
+
+	return sha1s
+}
+
+func TestQuizSessionDetail_arrange_withoutShuffle(t *testing.T) {
+	qd := shuffleSessionDetail(42, false, false)
+	qd.arrange()
+
+	actual := order(qd)
+	assert.Equal(t, []string{"q1", "q1a1", "q1a2", "q1a3", "q1a4"}, actual[:5])
+	assert.Equal(t, "q5", actual[20])
+	assert.Equal(t, "q6", actual[25])
+	assert.Equal(t, []string{"q5a1", "q5a2", "q5a3", "q5a4"}, actual[21:25])
+}
+
+func TestQuizSessionDetail_arrange_orderingItemsAreAlwaysShuffled(t *testing.T) {
+	shuffled := false
+	for seed := int64(0); seed < 10 && !shuffled; seed++ {
+		qd := shuffleSessionDetail(seed, false, false)
+		qd.arrange()
+		shuffled = fmt.Sprint(order(qd)[26:]) != "[q6a1 q6a2 q6a3 q6a4]"
+	}
+
+	assert.True(t, shuffled)
+}
+
+func TestQuizSessionDetail_arrange_withShuffle(t *testing.T) {
+	qd := shuffleSessionDetail(42, true, true)
+	qd.arrange()
+	actual := order(qd)
+
+	same := shuffleSessionDetail(42, true, true)
+	same.arrange()
+	assert.Equal(t, actual, order(same))
+
+	different := false
+	for seed := int64(0); seed < 10 && !different; seed++ {
+		other := shuffleSessionDetail(seed, true, true)
+		other.arrange()
+		different = fmt.Sprint(actual[:5]) != fmt.Sprint(order(other)[:5])
+	}
+	assert.True(t, different)
+}
@@ -0,0 +1,430 @@
+/*
+ * Copyright (c) 2023 Michaël COLL.
+ *
+ * Licensed under the Apache License, Version 2.0 (the "License");
+ * you may not use this file except in compliance with the License.
+ * You may obtain a copy of the License at
+ *
+ *      http://www.apache.org/licenses/LICENSE-2.0
+ *
+ * Unless required by applicable law or agreed to in writing, software
+ * distributed under the License is distributed on an "AS IS" BASIS,
+ * WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+ * See the License for the specific language governing permissions and
+ * limitations under the License.
+ */
+
+package domain
+
+import (
+	"archive/zip"
+	"bytes"
+	"context"
+	"crypto/sha1"
+	"encoding/hex"
+	"errors"
+	"fmt"
+	"io"
+	"net/url"
+	"os"
+	"path"
+	"path/filepath"
+	"regexp"
+	"strings"
+	"sync"
+
+	"github.com/fatih/color"
+	"github.com/go-git/go-billy/v5"
+	"github.com/go-git/go-billy/v5/memfs"
+	"github.com/go-git/go-billy/v5/osfs"
+	"github.com/go-git/go-billy/v5/util"
+	"github.com/spf13/viper"
+)
+
+const (
+	// defaultSourceName is the name of the repository set by the flags of the serve command, the
+	// quizzes saved before the sources existed belong to it
+	defaultSourceName = "default"
+	// importSourceName is the name given to the quizzes imported from GIFT or Moodle XML files
+	importSourceName = "import"
+)
+
+// sourcesDir is the folder of db-location holding the working copies and the archives of the
+// sources declared in the configuration file
+const sourcesDir = "sources"
+
+// maxArchiveSize is the maximum size of the files of an archive once uncompressed
+const maxArchiveSize = 64 << 20
+
+// QuizSource is a place the quiz files are read from
+type QuizSource interface {
+	// Name identifies the source, the quizzes of two sources never colliding even with the same
+	// filename
+	Name() string
+	// Open gives the files of the source with their version, a sync being skipped while the version
+	// does not change. The version is empty when the source can't tell it, the files being parsed
+	// on every sync
+	Open() (billy.Filesystem, string, error)
+}
+
+// sourceSync remembers the version each source was last synced from, with the diagnostics of its
+// files, and holds the syncs queued by QueueSync
+type sourceSync struct {
+	mu          sync.Mutex
+	versions    map[string]string
+	diagnostics map[string]Diagnostics
+
+	queue  chan struct{}
+	worker sync.Once
+}
+
+func newSourceSync() *sourceSync {
+	return &sourceSync{
+		versions:    map[string]string{},
+		diagnostics: map[string]Diagnostics{},
+		queue:       make(chan struct{}, 1),
+	}
+}
+
+// QueueSync asks for a sync run in the background. The requests made while a sync is waiting are
+// merged into it, so that a burst of pushes gives a single sync
+func (s *QuizService) QueueSync() {
+	s.sources.worker.Do(func() {
+		go s.runQueuedSyncs()
+	})
+
+	select {
+	case s.sources.queue <- struct{}{}:
+	default:
+	}
+}
+
+func (s *QuizService) runQueuedSyncs() {
+	for range s.sources.queue {
+		if _, err := s.Sync(context.Background()); err != nil {
+			fmt.Printf("%s Can't sync quizzes (%v)\n", color.RedString("✗"), err)
+		}
+	}
+}
+
+// TracksBranch tells if a git source reads the quizzes from the given branch of the repository with
+// one of the given urls, the sources without ref reading them from the default branch of their
+// repository
+func (s *QuizService) TracksBranch(repositoryUrls []string, branch string, defaultBranch string) (bool, error) {
+	sources, err := quizSourcesFromConfig()
+	if err != nil {
+		return false, err
+	}
+
+	repositories := map[string]bool{}
+	for _, url := range repositoryUrls {
+		if url != "" {
+			repositories[repositoryKey(url)] = true
+		}
+	}
+
+	for _, source := range sources {
+		git, ok := source.(gitSource)
+		if !ok || !repositories[repositoryKey(git.url)] {
+			continue
+		}
+
+		ref := git.ref
+		if ref == "" {
+			ref = defaultBranch
+		}
+		if ref != "" && ref == branch {
+			return true, nil
+		}
+	}
+
+	return false, nil
+}
+
+// repositoryKey reduces the url of a git repository to its host and path, so that its https, ssh
+// and web urls give the same key
+func repositoryKey(repositoryUrl string) string {
+	key := strings.TrimSpace(repositoryUrl)
+	if parsed, err := url.Parse(key); err == nil && parsed.Scheme != "" && parsed.Host != "" {
+		key = parsed.Hostname() + parsed.Path
+	} else if user, rest, found := strings.Cut(key, "@"); found && !strings.Contains(user, "/") {
+		// scp-like syntax of ssh: git@host:owner/repository.git
+		key = strings.Replace(rest, ":", "/", 1)
+	}
+
+	key = strings.TrimSuffix(strings.TrimSuffix(key, "/"), ".git")
+
+	return strings.ToLower(key)
+}
+
+// ScanSource parses the quiz files of the source. The files with errors are left out, their
+// diagnostics are returned with the ones of the other files
+func (s *QuizService) ScanSource(source QuizSource) ([]*Quiz, Diagnostics, error) {
+	fs, _, err := source.Open()
+	if err != nil {
+		return nil, nil, err
+	}
+
+	return s.scanQuizzes(fs)
+}
+
+// sourceSha1 gives the sha1 identifying a quiz of the source. The quizzes of the default source keep
+// the sha1 of their content, the name of the other sources is mixed in so that the same file read
+// from two sources gives two quizzes
+func sourceSha1(source string, sha1 string) string {
+	if source == defaultSourceName {
+		return sha1
+	}
+
+	return getSha1(source + "\n" + sha1)
+}
+
+// UploadArchive replaces the archive of an archive source and saves its quizzes the same way Sync
+// does
+func (s *QuizService) UploadArchive(ctx context.Context, name string, content []byte) (*SyncStats, error) {
+	sources, err := quizSourcesFromConfig()
+	if err != nil {
+		return nil, err
+	}
+
+	for _, source := range sources {
+		if source.Name() != name {
+			continue
+		}
+
+		archive, ok := source.(archiveSource)
+		if !ok {
+			return nil, Errorf(InvalidArgument, "source %s is not an archive source", name)
+		}
+
+		s.sources.mu.Lock()
+		defer s.sources.mu.Unlock()
+
+		if err := archive.write(content); err != nil {
+			return nil, err
+		}
+
+		return s.syncSource(ctx, archive, len(sources) > 1)
+	}
+
+	return nil, Errorf(NotFound, "source %s was not found", name)
+}
+
+// quizSourceConfig is a source declared in the sources list of the configuration file
+type quizSourceConfig struct {
+	Name string `mapstructure:"name"`
+	// Type is git, directory or archive
+	Type           string `mapstructure:"type"`
+	Url            string `mapstructure:"url"`
+	Ref            string `mapstructure:"ref"`
+	Dir            string `mapstructure:"dir"`
+	Token          string `mapstructure:"token"`
+	SshKey         string `mapstructure:"ssh-key"`
+	SshKeyPassword string `mapstructure:"ssh-key-password"`
+	KnownHosts     string `mapstructure:"known-hosts"`
+	// Path is the folder of a directory source or the zip file of an archive source
+	Path string `mapstructure:"path"`
+}
+
+var sourceNameRegexp = regexp.MustCompile(`^[A-Za-z0-9_-]+$`)
+
+// quizSourcesFromConfig gives the sources declared in the configuration file, or the repository set
+// by the flags of the serve command when there is none
+func quizSourcesFromConfig() ([]QuizSource, error) {
+	var configs []quizSourceConfig
+	if err := viper.UnmarshalKey("sources", &configs); err != nil {
+		return nil, fmt.Errorf("sources of the configuration file are not valid (%v)", err)
+	}
+
+	if len(configs) == 0 {
+		return []QuizSource{defaultGitSource()}, nil
+	}
+
+	sources := make([]QuizSource, 0, len(configs))
+	names := map[string]bool{}
+	for _, config := range configs {
+		if !sourceNameRegexp.MatchString(config.Name) {
+			return nil, fmt.Errorf("source name '%s' is not valid, it must only have letters, digits, '-' and '_'", config.Name)
+		}
+		if config.Name == importSourceName {
+			return nil, fmt.Errorf("source name '%s' is reserved to the imported quizzes", config.Name)
+		}
+		if names[config.Name] {
+			return nil, fmt.Errorf("source name '%s' is used more than once", config.Name)
+		}
+		names[config.Name] = true
+
+		source, err := config.toQuizSource()
+		if err != nil {
+			return nil, fmt.Errorf("source %s is not valid (%v)", config.Name, err)
+		}
+		sources = append(sources, source)
+	}
+
+	return sources, nil
+}
+
+func (c quizSourceConfig) toQuizSource() (QuizSource, error) {
+	dbLocation := viper.GetString("db-location")
+
+	switch c.Type {
+	case "git":
+		if c.Url == "" {
+			return nil, fmt.Errorf("url is missing")
+		}
+		location := ""
+		if dbLocation != "" {
+			location = filepath.Join(dbLocation, sourcesDir, c.Name)
+		}
+		return gitSource{
+			name:           c.Name,
+			location:       location,
+			url:            c.Url,
+			ref:            c.Ref,
+			dir:            c.Dir,
+			token:          c.Token,
+			sshKey:         c.SshKey,
+			sshKeyPassword: c.SshKeyPassword,
+			knownHosts:     c.KnownHosts,
+		}, nil
+	case "directory":
+		if c.Path == "" {
+			return nil, fmt.Errorf("path is missing")
+		}
+		return dirSource{name: c.Name, path: c.Path}, nil
+	case "archive":
+		file := c.Path
+		if file == "" && dbLocation != "" {
+			file = filepath.Join(dbLocation, sourcesDir, c.Name+".zip")
+		}
+		if file == "" {
+			return nil, fmt.Errorf("path is missing")
+		}
+		return archiveSource{name: c.Name, file: file}, nil
+	}
+
+	return nil, fmt.Errorf("type '%s' is not supported, supported types : git, directory, archive", c.Type)
+}
+
+// dirSource is a local directory the quizzes are read from, like a folder shared with the server
+type dirSource struct {
+	name string
+	path string
+}
+
+func (d dirSource) Name() string {
+	return d.name
+}
+
+// Open gives the files of the directory, no path resolving outside of it. A directory has no
+// version, its files are parsed on every sync
+func (d dirSource) Open() (billy.Filesystem, string, error) {
+	info, err := os.Stat(d.path)
+	if err != nil {
+		return nil, "", err
+	}
+	if !info.IsDir() {
+		return nil, "", fmt.Errorf("%s is not a directory", d.path)
+	}
+
+	return osfs.New(d.path, osfs.WithBoundOS()), "", nil
+}
+
+// archiveSource is a zip file of quizzes uploaded by an admin
+type archiveSource struct {
+	name string
+	file string
+}
+
+func (a archiveSource) Name() string {
+	return a.name
+}
+
+// Open gives the files of the archive, its version being the sha1 of its content. The source is
+// empty while no archive has been uploaded
+func (a archiveSource) Open() (billy.Filesystem, string, error) {
+	content, err := os.ReadFile(a.file)
+	if errors.Is(err, os.ErrNotExist) {
+		return memfs.New(), "", nil
+	} else if err != nil {
+		return nil, "", err
+	}
+
+	fs, err := readArchive(content)
+	if err != nil {
+		return nil, "", err
+	}
+
+	hash := sha1.Sum(content)
+
+	return fs, hex.EncodeToString(hash[:]), nil
+}
+
+// write replaces the archive of the source. The archive is checked first, and written beside the
+// file before replacing it so that a failed upload keeps the previous archive
+func (a archiveSource) write(content []byte) error {
+	if _, err := readArchive(content); err != nil {
+		return Errorf(InvalidArgument, "the archive is not valid (%v)", err)
+	}
+
+	if err := os.MkdirAll(filepath.Dir(a.file), 0o755); err != nil {
+		return err
+	}
+
+	tmpFile := a.file + ".tmp"
+	if err := os.WriteFile(tmpFile, content, 0o644); err != nil {
+		return err
+	}
+
+	return os.Rename(tmpFile, a.file)
+}
+
+// readArchive extracts the files of a zip archive in memory. The files whose path leaves the archive
+// are refused
+func readArchive(content []byte) (billy.Filesystem, error) {
+	reader, err := zip.NewReader(bytes.NewReader(content), int64(len(content)))
+	if err != nil {
+		return nil, err
+	}
+
+	fs := memfs.New()
+	var size uint64
+	for _, file := range reader.File {
+		if file.FileInfo().IsDir() {
+			continue
+		}
+
+		filename := path.Clean(strings.ReplaceAll(file.Name, "\\", "/"))
+		if path.IsAbs(filename) || filename == ".." || strings.HasPrefix(filename, "../") {
+			return nil, fmt.Errorf("file %s is outside of the archive", file.Name)
+		}
+
+		data, err := readArchiveFile(file, maxArchiveSize-size)
+		if err != nil {
+			return nil, err
+		}
+
+		// the sizes given by the archive are not trusted
+		size += uint64(len(data))
+		if size > maxArchiveSize {
+			return nil, fmt.Errorf("files are bigger than %d MB once uncompressed", maxArchiveSize>>20)
+		}
+		if err := util.WriteFile(fs, filename, data, 0o644); err != nil {
+			return nil, err
+		}
+	}
+
+	return fs, nil
+}
+
+// readArchiveFile reads a file of an archive, reading at most one byte more than the limit so that
+// a bigger file is detected
+func readArchiveFile(file *zip.File, limit uint64) ([]byte, error) {
+	reader, err := file.Open()
+	if err != nil {
+		return nil, err
+	}
+	defer reader.Close()
+
+	return io.ReadAll(io.LimitReader(reader, int64(limit)+1))
+}
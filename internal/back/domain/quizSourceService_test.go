@@ -0,0 +1,376 @@
+/*
+ * Copyright (c) 2023 Michaël COLL.
+ *
+ * Licensed under the Apache License, Version 2.0 (the "License");
+ * you may not use this file except in compliance with the License.
+ * You may obtain a copy of the License at
+ *
+ *      http://www.apache.org/licenses/LICENSE-2.0
+ *
+ * Unless required by applicable law or agreed to in writing, software
+ * distributed under the License is distributed on an "AS IS" BASIS,
+ * WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+ * See the License for the specific language governing permissions and
+ * limitations under the License.
+ */
+
+package domain
+
+import (
+	"archive/zip"
+	"bytes"
+	"context"
+	"os"
+	"path/filepath"
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/spf13/viper"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/mock"
+)
+
+// zipArchive creates a zip archive holding the given files
+func zipArchive(t *testing.T, files map[string]string) []byte {
+	buf := new(bytes.Buffer)
+	writer := zip.NewWriter(buf)
+	for filename, content := range files {
+		file, err := writer.Create(filename)
+		if err != nil {
+			assert.FailNow(t, "Can't create archive file", "%v", err)
+		}
+		if _, err := file.Write([]byte(content)); err != nil {
+			assert.FailNow(t, "Can't write archive file", "%v", err)
+		}
+	}
+	if err := writer.Close(); err != nil {
+		assert.FailNow(t, "Can't close archive", "%v", err)
+	}
+
+	return buf.Bytes()
+}
+
+func setSources(t *testing.T, sources []map[string]any) {
+	viper.Set("sources", sources)
+	viper.Set("db-location", t.TempDir())
+	t.Cleanup(func() {
+		viper.Set("sources", nil)
+		viper.Set("db-location", "")
+	})
+}
+
+func Test_quizSourcesFromConfig(t *testing.T) {
+	setSources(t, nil)
+	viper.Set("repository-url", "https://example.com/quizzes.git")
+
+	sources, err := quizSourcesFromConfig()
+	if err != nil {
+		assert.FailNow(t, "Can't read sources", "%v", err)
+	}
+	assert.Len(t, sources, 1)
+	assert.Equal(t, defaultSourceName, sources[0].Name())
+	assert.Equal(t, filepath.Join(viper.GetString("db-location"), gitRepoDir), sources[0].(gitSource).location)
+
+	setSources(t, []map[string]any{
+		{"name": "main", "type": "git", "url": "git@example.com:quizzes.git", "ref": "v1", "ssh-key": "/keys/id_ed25519"},
+		{"name": "shared", "type": "directory", "path": "/srv/quizzes"},
+		{"name": "exams", "type": "archive"},
+	})
+
+	sources, err = quizSourcesFromConfig()
+	if err != nil {
+		assert.FailNow(t, "Can't read sources", "%v", err)
+	}
+	dbLocation := viper.GetString("db-location")
+	assert.Equal(t, []QuizSource{
+		gitSource{name: "main", location: filepath.Join(dbLocation, sourcesDir, "main"), url: "git@example.com:quizzes.git", ref: "v1", sshKey: "/keys/id_ed25519"},
+		dirSource{name: "shared", path: "/srv/quizzes"},
+		archiveSource{name: "exams", file: filepath.Join(dbLocation, sourcesDir, "exams.zip")},
+	}, sources)
+}
+
+func Test_quizSourcesFromConfig_errors(t *testing.T) {
+	tests := []struct {
+		name    string
+		sources []map[string]any
+		message string
+	}{
+		{"duplicated name", []map[string]any{{"name": "a", "type": "directory", "path": "/a"}, {"name": "a", "type": "directory", "path": "/b"}},
+			"source name 'a' is used more than once"},
+		{"reserved name", []map[string]any{{"name": "import", "type": "directory", "path": "/a"}},
+			"source name 'import' is reserved to the imported quizzes"},
+		{"invalid name", []map[string]any{{"name": "../a", "type": "directory", "path": "/a"}},
+			"source name '../a' is not valid, it must only have letters, digits, '-' and '_'"},
+		{"unknown type", []map[string]any{{"name": "a", "type": "svn"}},
+			"source a is not valid (type 'svn' is not supported, supported types : git, directory, archive)"},
+		{"missing url", []map[string]any{{"name": "a", "type": "git"}},
+			"source a is not valid (url is missing)"},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			setSources(t, tt.sources)
+
+			_, err := quizSourcesFromConfig()
+			assert.EqualError(t, err, tt.message)
+		})
+	}
+}
+
+func TestDirSource_Open(t *testing.T) {
+	dir := t.TempDir()
+	if err := os.WriteFile(filepath.Join(dir, "git.quiz.md"), []byte(gitQuizContent), 0644); err != nil {
+		assert.FailNow(t, "Can't write quiz file", "%v", err)
+	}
+
+	fs, version, err := dirSource{name: "shared", path: dir}.Open()
+	if err != nil {
+		assert.FailNow(t, "Can't open source", "%v", err)
+	}
+	assert.Empty(t, version)
+
+	content, err := readFileContent(fs, "git.quiz.md")
+	assert.NoError(t, err)
+	assert.Equal(t, gitQuizContent, content)
+
+	_, _, err = dirSource{name: "shared", path: filepath.Join(dir, "git.quiz.md")}.Open()
+	assert.Error(t, err)
+}
+
+func TestDirSource_Open_symlinked_asset(t *testing.T) {
+	secret := filepath.Join(t.TempDir(), "secret.png")
+	if err := os.WriteFile(secret, []byte("secret"), 0644); err != nil {
+		assert.FailNow(t, "Can't write file", "%v", err)
+	}
+	dir := t.TempDir()
+	if err := os.WriteFile(filepath.Join(dir, "git.quiz.md"), []byte(gitQuizContent+"\n![logo](images/logo.png)\n"), 0644); err != nil {
+		assert.FailNow(t, "Can't write quiz file", "%v", err)
+	}
+	if err := os.Symlink(filepath.Dir(secret), filepath.Join(dir, "images")); err != nil {
+		assert.FailNow(t, "Can't create symbolic link", "%v", err)
+	}
+	if err := os.Symlink(secret, filepath.Join(dir, "logo.png")); err != nil {
+		assert.FailNow(t, "Can't create symbolic link", "%v", err)
+	}
+
+	fs, _, err := dirSource{name: "shared", path: dir}.Open()
+	if err != nil {
+		assert.FailNow(t, "Can't open source", "%v", err)
+	}
+
+	_, err = readFileContent(fs, "logo.png")
+	assert.EqualError(t, err, "logo.png is a symbolic link")
+	_, err = readFileContent(fs, "images/secret.png")
+	assert.EqualError(t, err, "images is a symbolic link")
+
+	s := NewQuizService(nil)
+	quiz, diagnostics, err := s.parseQuizFile(fs, "git.quiz.md")
+	assert.NoError(t, err)
+	assert.Nil(t, quiz)
+	assert.True(t, diagnostics.HasErrors())
+}
+
+func TestArchiveSource_Open(t *testing.T) {
+	source := archiveSource{name: "exams", file: filepath.Join(t.TempDir(), "sources", "exams.zip")}
+
+	// the source is empty until an archive is uploaded
+	fs, version, err := source.Open()
+	if err != nil {
+		assert.FailNow(t, "Can't open source", "%v", err)
+	}
+	assert.Empty(t, version)
+	filenames, _ := quizFilenames(fs)
+	assert.Empty(t, filenames)
+
+	err = source.write(zipArchive(t, map[string]string{"vcs/git.quiz.md": gitQuizContent}))
+	if err != nil {
+		assert.FailNow(t, "Can't write archive", "%v", err)
+	}
+
+	fs, version, err = source.Open()
+	if err != nil {
+		assert.FailNow(t, "Can't open source", "%v", err)
+	}
+	assert.Len(t, version, 40)
+	content, err := readFileContent(fs, "vcs/git.quiz.md")
+	assert.NoError(t, err)
+	assert.Equal(t, gitQuizContent, content)
+
+	// an invalid archive keeps the previous one
+	err = source.write([]byte("not a zip"))
+	code, _ := GetCodeFromError(err)
+	assert.Equal(t, ErrorCode(InvalidArgument), code)
+	_, sameVersion, _ := source.Open()
+	assert.Equal(t, version, sameVersion)
+}
+
+func Test_readArchive_outside_files(t *testing.T) {
+	_, err := readArchive(zipArchive(t, map[string]string{"../git.quiz.md": gitQuizContent}))
+	assert.EqualError(t, err, "file ../git.quiz.md is outside of the archive")
+
+	_, err = readArchive(zipArchive(t, map[string]string{"/etc/git.quiz.md": gitQuizContent}))
+	assert.EqualError(t, err, "file /etc/git.quiz.md is outside of the archive")
+}
+
+func Test_readArchive_too_big(t *testing.T) {
+	half := strings.Repeat("0", maxArchiveSize/2+1)
+
+	_, err := readArchive(zipArchive(t, map[string]string{"a.md": half, "b.md": half}))
+	assert.EqualError(t, err, "files are bigger than 64 MB once uncompressed")
+
+	_, err = readArchive(zipArchive(t, map[string]string{"a.md": half}))
+	assert.NoError(t, err)
+}
+
+func TestQuizService_Sync_sources_with_same_filename(t *testing.T) {
+	dirs := map[string]string{"first": t.TempDir(), "second": t.TempDir()}
+	for _, dir := range dirs {
+		if err := os.WriteFile(filepath.Join(dir, "git.quiz.md"), []byte(gitQuizContent), 0644); err != nil {
+			assert.FailNow(t, "Can't write quiz file", "%v", err)
+		}
+	}
+	if err := os.WriteFile(filepath.Join(dirs["second"], "broken.quiz.md"), []byte("# Broken\n"), 0644); err != nil {
+		assert.FailNow(t, "Can't write quiz file", "%v", err)
+	}
+	setSources(t, []map[string]any{
+		{"name": "first", "type": "directory", "path": dirs["first"]},
+		{"name": "second", "type": "directory", "path": dirs["second"]},
+	})
+
+	mockQuizRepository := NewMockQuizRepository(t)
+	s := NewQuizService(mockQuizRepository)
+	ctx := context.Background()
+
+	mockQuizRepository.On("FindLatestVersionByFilename", ctx, "first", "git.quiz.md").Return(nil, nil).Once()
+	mockQuizRepository.On("FindLatestVersionByFilename", ctx, "second", "git.quiz.md").Return(nil, nil).Once()
+	mockQuizRepository.On("Create", ctx, mock.MatchedBy(func(quiz *Quiz) bool { return quiz.Source == "first" })).Return(nil).Once()
+	mockQuizRepository.On("Create", ctx, mock.MatchedBy(func(quiz *Quiz) bool { return quiz.Source == "second" })).Return(nil).Once()
+
+	stats, err := s.Sync(ctx)
+	if err != nil {
+		assert.FailNow(t, "Can't sync", "%v", err)
+	}
+
+	assert.Equal(t, 2, stats.Created)
+	sha1s := map[string]bool{}
+	for _, call := range mockQuizRepository.Calls {
+		if call.Method == "Create" {
+			sha1s[call.Arguments.Get(1).(*Quiz).Sha1] = true
+		}
+	}
+	assert.Len(t, sha1s, 2, "the same file of two sources must give two quizzes")
+	assert.True(t, stats.Diagnostics.HasErrors())
+	assert.Equal(t, "second", stats.Diagnostics[0].Source)
+	assert.Equal(t, "broken.quiz.md", stats.Diagnostics[0].Filename)
+}
+
+func TestQuizService_UploadArchive(t *testing.T) {
+	setSources(t, []map[string]any{
+		{"name": "exams", "type": "archive"},
+		{"name": "shared", "type": "directory", "path": t.TempDir()},
+	})
+
+	mockQuizRepository := NewMockQuizRepository(t)
+	s := NewQuizService(mockQuizRepository)
+	ctx := context.Background()
+
+	mockQuizRepository.On("FindLatestVersionByFilename", ctx, "exams", "vcs/git.quiz.md").Return(nil, nil).Once()
+	mockQuizRepository.On("Create", ctx, mock.Anything).Return(nil).Once()
+
+	archive := zipArchive(t, map[string]string{"vcs/git.quiz.md": gitQuizContent})
+	stats, err := s.UploadArchive(ctx, "exams", archive)
+	if err != nil {
+		assert.FailNow(t, "Can't upload archive", "%v", err)
+	}
+	assert.Equal(t, 1, stats.Created)
+
+	// the same archive is not parsed again
+	stats, err = s.UploadArchive(ctx, "exams", archive)
+	if err != nil {
+		assert.FailNow(t, "Can't upload archive", "%v", err)
+	}
+	assert.Equal(t, 0, stats.Created)
+
+	_, err = s.UploadArchive(ctx, "shared", archive)
+	code, _ := GetCodeFromError(err)
+	assert.Equal(t, ErrorCode(InvalidArgument), code)
+
+	_, err = s.UploadArchive(ctx, "missing", archive)
+	code, _ = GetCodeFromError(err)
+	assert.Equal(t, ErrorCode(NotFound), code)
+}
+
+func TestQuizService_TracksBranch(t *testing.T) {
+	setSources(t, []map[string]any{
+		{"name": "main", "type": "git", "url": "https://example.com/quizzes.git"},
+		{"name": "exams", "type": "git", "url": "https://example.com/quizzes.git", "ref": "exams"},
+		{"name": "shared", "type": "directory", "path": "/srv/quizzes"},
+	})
+
+	s := NewQuizService(nil)
+	urls := []string{"https://example.com/quizzes.git", "https://example.com/quizzes", "git@example.com:quizzes.git"}
+
+	for branch, expected := range map[string]bool{"main": true, "exams": true, "drafts": false} {
+		tracked, err := s.TracksBranch(urls, branch, "main")
+		assert.NoError(t, err)
+		assert.Equal(t, expected, tracked, branch)
+	}
+
+	// the default branch is unknown, only the sources with a ref can match
+	tracked, err := s.TracksBranch(urls, "main", "")
+	assert.NoError(t, err)
+	assert.False(t, tracked)
+}
+
+func TestQuizService_TracksBranch_other_repository(t *testing.T) {
+	setSources(t, []map[string]any{
+		{"name": "main", "type": "git", "url": "https://example.com/quizzes.git"},
+	})
+
+	s := NewQuizService(nil)
+
+	tracked, err := s.TracksBranch([]string{"https://example.com/other.git", "https://example.com/other"}, "main", "main")
+	assert.NoError(t, err)
+	assert.False(t, tracked)
+
+	tracked, err = s.TracksBranch(nil, "main", "main")
+	assert.NoError(t, err)
+	assert.False(t, tracked)
+}
+
+func Test_repositoryKey(t *testing.T) {
+	for _, url := range []string{
+		"https://example.com/owner/quizzes.git",
+		"https://token@example.com/owner/quizzes",
+		"https://Example.com/owner/quizzes/",
+		"ssh://git@example.com:2222/owner/quizzes.git",
+		"git@example.com:owner/quizzes.git",
+	} {
+		assert.Equal(t, "example.com/owner/quizzes", repositoryKey(url), url)
+	}
+}
+
+func TestQuizService_QueueSync(t *testing.T) {
+	dir := t.TempDir()
+	if err := os.WriteFile(filepath.Join(dir, "git.quiz.md"), []byte(gitQuizContent), 0644); err != nil {
+		assert.FailNow(t, "Can't write quiz file", "%v", err)
+	}
+	setSources(t, []map[string]any{{"name": "shared", "type": "directory", "path": dir}})
+
+	mockQuizRepository := NewMockQuizRepository(t)
+	s := NewQuizService(mockQuizRepository)
+
+	synced := make(chan struct{}, 1)
+	mockQuizRepository.On("FindLatestVersionByFilename", mock.Anything, "shared", "git.quiz.md").Return(nil, nil).Once()
+	mockQuizRepository.On("Create", mock.Anything, mock.Anything).Return(nil).Once().Run(func(mock.Arguments) {
+		synced <- struct{}{}
+	})
+
+	s.QueueSync()
+
+	select {
+	case <-synced:
+	case <-time.After(5 * time.Second):
+		assert.Fail(t, "The queued sync did not run")
+	}
+}
@@ -0,0 +1,560 @@
+/*
+ * Copyright (c) 2023 Michaël COLL.
+ *
+ * Licensed under the Apache License, Version 2.0 (the "License");
+ * you may not use this file except in compliance with the License.
+ * You may obtain a copy of the License at
+ *
+ *      http://www.apache.org/licenses/LICENSE-2.0
+ *
+ * Unless required by applicable law or agreed to in writing, software
+ * distributed under the License is distributed on an "AS IS" BASIS,
+ * WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+ * See the License for the specific language governing permissions and
+ * limitations under the License.
+ */
+
+package domain
+
+import (
+	"context"
+	"fmt"
+	"path"
+	"regexp"
+	"sort"
+	"strings"
+
+	"go.yaml.in/yaml/v3"
+)
+
+var langRegexp = regexp.MustCompile(`^[a-z]{2,3}(-[A-Za-z0-9]{2,8})*$`)
+var quizIdRegexp = regexp.MustCompile(`^[A-Za-z0-9][A-Za-z0-9._-]*$`)
+var translationStartRegexp = regexp.MustCompile(`(?m)^:::(\S+)[ \t]*(?:\n|$)`)
+var translationEndRegexp = regexp.MustCompile(`(?m)^:::[ \t]*(?:\n|$)`)
+
+// translationBlock is a ':::<lang>' block ending a question, holding the question in another
+// language
+type translationBlock struct {
+	lang string
+	// start is the offset of the ':::<lang>' line in the file and offset the one of the content
+	start   int
+	offset  int
+	content string
+}
+
+// checkLangs validates the language, the id and the translations declared by the front-matter and
+// keeps the translated names of the quiz
+func (p *quizParser) checkLangs(fm frontMatter) {
+	p.lang = fm.Lang
+
+	if fm.Lang != "" && !langRegexp.MatchString(fm.Lang) {
+		p.errorf(p.frontMatterKeyOffset("lang"), "front-matter is not valid (lang '%s' must be a language code like en or pt-BR)", fm.Lang)
+	}
+	if fm.Id != "" && !quizIdRegexp.MatchString(fm.Id) {
+		p.errorf(p.frontMatterKeyOffset("id"), "front-matter is not valid (id '%s' must only contain letters, digits, '.', '_' and '-')", fm.Id)
+	}
+	if len(fm.Translations) > 0 && fm.Lang == "" {
+		p.errorf(p.frontMatterKeyOffset("translations"), "front-matter is not valid (translations need the language of the quiz, given by lang)")
+	}
+
+	langs := make([]string, 0, len(fm.Translations))
+	for lang := range fm.Translations {
+		langs = append(langs, lang)
+	}
+	sort.Strings(langs)
+
+	for _, lang := range langs {
+		if !langRegexp.MatchString(lang) {
+			p.errorf(p.frontMatterKeyOffset("translations"), "front-matter is not valid (translation '%s' must be a language code like en or pt-BR)", lang)
+			continue
+		}
+		if lang == fm.Lang {
+			p.errorf(p.frontMatterKeyOffset("translations"), "front-matter is not valid (translation '%s' is the language of the quiz)", lang)
+			continue
+		}
+
+		translation := p.translation(lang)
+		translation.Name = strings.TrimSpace(fm.Translations[lang].Name)
+		translation.Description = strings.TrimSpace(fm.Translations[lang].Description)
+		p.translations[lang] = translation
+	}
+}
+
+// translation gives the translation of the quiz in the given language read so far
+func (p *quizParser) translation(lang string) QuizTranslation {
+	if p.translations == nil {
+		p.translations = map[string]QuizTranslation{}
+	}
+
+	translation, found := p.translations[lang]
+	if !found {
+		translation = QuizTranslation{Questions: map[string]QuestionTranslation{}}
+	}
+
+	return translation
+}
+
+// extractTranslationBlocks splits the translation blocks ending a question from the question. A
+// block starts with a ':::<lang>' line and ends with a ':::' line, only blank lines can separate
+// two blocks. The question starts at the given offset of the file
+func (p *quizParser) extractTranslationBlocks(content string, offset int) (string, []translationBlock) {
+	loc := translationStartRegexp.FindStringIndex(content)
+	if loc == nil {
+		return content, nil
+	}
+
+	var blocks []translationBlock
+	for cursor := loc[0]; cursor < len(content); {
+		rest := strings.TrimLeft(content[cursor:], " \t\n")
+		cursor = len(content) - len(rest)
+		if rest == "" {
+			break
+		}
+
+		subMatch := translationStartRegexp.FindStringSubmatchIndex(rest)
+		if subMatch == nil || subMatch[0] != 0 {
+			p.errorf(offset+cursor, "only translation blocks can follow a translation block")
+			break
+		}
+
+		block := translationBlock{
+			lang:   rest[subMatch[2]:subMatch[3]],
+			start:  offset + cursor,
+			offset: offset + cursor + subMatch[1],
+		}
+
+		inner := rest[subMatch[1]:]
+		end := translationEndRegexp.FindStringIndex(inner)
+		if end == nil {
+			p.errorf(block.start, "translation block '%s' is not closed. It must end with a ':::' line", block.lang)
+			break
+		}
+
+		block.content = inner[:end[0]]
+		blocks = append(blocks, block)
+		cursor += subMatch[1] + end[1]
+	}
+
+	return content[:loc[0]], blocks
+}
+
+// translateQuestion reads the translation blocks of a question. A translation must have the
+// answers of the question in the same order, so that they can be matched by position
+func (p *quizParser) translateQuestion(question QuizQuestion, blocks []translationBlock) {
+	for _, block := range blocks {
+		if !langRegexp.MatchString(block.lang) {
+			p.errorf(block.start, "translation block '%s' must be a language code like en or pt-BR", block.lang)
+			continue
+		}
+		if p.lang == "" {
+			p.errorf(block.start, "translation blocks need the language of the quiz, given by lang in the front-matter")
+			continue
+		}
+		if block.lang == p.lang {
+			p.errorf(block.start, "translation block '%s' is the language of the quiz", block.lang)
+			continue
+		}
+
+		translated := p.extractQuestion(block.content, block.offset)
+		answers, ok := translateAnswers(question, translated)
+		if !ok {
+			p.errorf(block.start, "translation block '%s' must have the answers of the question, in the same order", block.lang)
+			continue
+		}
+
+		translation := p.translation(block.lang)
+		translation.Questions[question.Sha1] = QuestionTranslation{
+			Content:     translated.Content,
+			Code:        translated.Code,
+			Explanation: translated.Explanation,
+			Answers:     answers,
+		}
+		p.translations[block.lang] = translation
+	}
+}
+
+// translateAnswers matches the answers of a question with the ones of its translation by position.
+// The translation must be the same kind of question, with the same valid answers
+func translateAnswers(question QuizQuestion, translated QuizQuestion) (map[string]AnswerTranslation, bool) {
+	if translated.Kind != question.Kind || len(translated.Answers) != len(question.Answers) {
+		return nil, false
+	}
+
+	byOrdinal := make(map[int]QuizQuestionAnswer, len(translated.Answers))
+	for _, answer := range translated.Answers {
+		byOrdinal[answer.Ordinal] = answer
+	}
+
+	answers := make(map[string]AnswerTranslation, len(question.Answers))
+	for sha1, answer := range question.Answers {
+		t, found := byOrdinal[answer.Ordinal]
+		if !found || t.Valid != answer.Valid || t.Match != answer.Match || t.Position != answer.Position {
+			return nil, false
+		}
+
+		answers[sha1] = AnswerTranslation{
+			Content:     t.Content,
+			Right:       t.Right,
+			Explanation: t.Explanation,
+		}
+	}
+
+	return answers, true
+}
+
+// mergeTranslations turns the quizzes of a folder sharing an id into a single quiz. The first file
+// in filename order holds the texts of the quiz and the others its translations, the quiz keeping
+// the filename of the first file so that its versions follow the history of that file. The quizzes
+// of failedIds (see translationGroup) are left out, a file of theirs having errors
+func mergeTranslations(quizzes []*Quiz, failedIds map[string]bool) ([]*Quiz, Diagnostics) {
+	groups := map[string][]*Quiz{}
+	var merged []*Quiz
+	var diagnostics Diagnostics
+
+	for _, quiz := range quizzes {
+		if quiz.Metadata.Id == "" {
+			merged = append(merged, quiz)
+		} else {
+			key := translationGroup(quiz.Filename, quiz.Metadata.Id)
+			groups[key] = append(groups[key], quiz)
+		}
+	}
+
+	keys := make([]string, 0, len(groups))
+	for key := range groups {
+		keys = append(keys, key)
+	}
+	sort.Strings(keys)
+
+	for _, key := range keys {
+		group := groups[key]
+		sort.Slice(group, func(i, j int) bool {
+			return group[i].Filename < group[j].Filename
+		})
+
+		id := group[0].Metadata.Id
+		if failedIds[key] {
+			diagnostics = append(diagnostics, fileDiagnostic(WarningSeverity, group[0].Filename,
+				"quiz %s is not saved as one of its files has errors", id))
+			continue
+		}
+
+		quiz, groupDiagnostics := mergeGroup(id, group)
+		diagnostics = append(diagnostics, groupDiagnostics...)
+		if quiz == nil {
+			continue
+		}
+
+		merged = append(merged, quiz)
+	}
+
+	return merged, diagnostics
+}
+
+// translationGroup gives the key of the files of a folder sharing an id, the quizzes of different
+// folders being distinct even with the same id
+func translationGroup(filename string, id string) string {
+	return path.Join(path.Dir(filename), id)
+}
+
+// mergeGroup merges the quizzes sharing the given id, the first one holding the texts of the quiz.
+// The quiz is nil when the translations do not match it
+func mergeGroup(id string, group []*Quiz) (*Quiz, Diagnostics) {
+	main := *group[0]
+	if len(group) == 1 {
+		return &main, nil
+	}
+
+	var diagnostics Diagnostics
+	translations := map[string]QuizTranslation{}
+	for lang, translation := range main.Translations {
+		translations[lang] = translation
+	}
+	assets := map[string]*Asset{}
+	for sha1, asset := range main.Assets {
+		assets[sha1] = asset
+	}
+
+	sha1s := []string{main.Sha1}
+	questions := sortedQuestions(main.Questions)
+	for _, variant := range group[1:] {
+		sha1s = append(sha1s, variant.Sha1)
+		lang := variant.Metadata.Lang
+
+		if main.Metadata.Lang == "" || lang == "" {
+			diagnostics = append(diagnostics, fileDiagnostic(ErrorSeverity, variant.Filename,
+				"quiz files sharing the id %s must give their language with lang", id))
+			continue
+		}
+		if _, found := translations[lang]; found || lang == main.Metadata.Lang {
+			diagnostics = append(diagnostics, fileDiagnostic(ErrorSeverity, variant.Filename,
+				"quiz %s is already translated in %s", id, lang))
+			continue
+		}
+		if len(variant.Translations) > 0 {
+			diagnostics = append(diagnostics, fileDiagnostic(WarningSeverity, variant.Filename,
+				"translation blocks are ignored, only the ones of %s are kept", group[0].Filename))
+		}
+
+		variantQuestions := sortedQuestions(variant.Questions)
+		if len(variantQuestions) != len(questions) {
+			diagnostics = append(diagnostics, fileDiagnostic(ErrorSeverity, variant.Filename,
+				"quiz has %d questions, its translation %s has %d", len(questions), group[0].Filename, len(variantQuestions)))
+			continue
+		}
+
+		translation := QuizTranslation{
+			Name:        variant.Name,
+			Description: variant.Metadata.Description,
+			Questions:   make(map[string]QuestionTranslation, len(questions)),
+		}
+		for i, question := range questions {
+			answers, ok := translateAnswers(question, variantQuestions[i])
+			if !ok {
+				diagnostics = append(diagnostics, fileDiagnostic(ErrorSeverity, variant.Filename,
+					"question %d must have the answers of the question %d of %s, in the same order", i+1, i+1, group[0].Filename))
+				continue
+			}
+
+			translation.Questions[question.Sha1] = QuestionTranslation{
+				Content:     variantQuestions[i].Content,
+				Code:        variantQuestions[i].Code,
+				Explanation: variantQuestions[i].Explanation,
+				Answers:     answers,
+			}
+		}
+		translations[lang] = translation
+
+		for sha1, asset := range variant.Assets {
+			assets[sha1] = asset
+		}
+	}
+
+	if diagnostics.HasErrors() {
+		return nil, diagnostics
+	}
+
+	main.Sha1 = getSha1(strings.Join(sha1s, "\n"))
+	main.Translations = translations
+	main.Assets = assets
+
+	return &main, diagnostics
+}
+
+// fileDiagnostic reports a problem concerning a whole quiz file
+func fileDiagnostic(severity Severity, filename string, format string, a ...interface{}) Diagnostic {
+	return Diagnostic{
+		Filename: filename,
+		Line:     1,
+		Column:   1,
+		Severity: severity,
+		Message:  fmt.Sprintf(format, a...),
+	}
+}
+
+// frontMatterId reads the id declared by the front-matter of a quiz file, even when the file has
+// errors
+func frontMatterId(content string) string {
+	if !strings.HasPrefix(content, frontMatterDelimiter) {
+		return ""
+	}
+
+	rest := content[len(frontMatterDelimiter):]
+	end := strings.Index(rest, "\n"+frontMatterDelimiter)
+	if end == -1 {
+		return ""
+	}
+
+	var fm struct {
+		Id string `yaml:"id"`
+	}
+	_ = yaml.Unmarshal([]byte(rest[:end+1]), &fm)
+
+	return fm.Id
+}
+
+// availableLangs gives the language of a quiz followed by the ones of its translations
+func availableLangs(lang string, translations map[string]QuizTranslation) []string {
+	var langs []string
+	if lang != "" {
+		langs = append(langs, lang)
+	}
+
+	translationLangs := make([]string, 0, len(translations))
+	for translationLang := range translations {
+		translationLangs = append(translationLangs, translationLang)
+	}
+	sort.Strings(translationLangs)
+
+	return append(langs, translationLangs...)
+}
+
+// chooseLang gives the first of the preferred languages the quiz is available in, a language also
+// matching its regional variants (fr matches fr-CA and the other way round) when the exact one is
+// not available. The quiz is given in
+// its own language when none of them is available
+func chooseLang(lang string, translations map[string]QuizTranslation, preferred []string) string {
+	available := availableLangs(lang, translations)
+
+	base := func(lang string) string {
+		return strings.ToLower(strings.SplitN(lang, "-", 2)[0])
+	}
+
+	for _, want := range preferred {
+		for _, exact := range []bool{true, false} {
+			for _, have := range available {
+				if strings.EqualFold(want, have) || !exact && base(want) == base(have) {
+					return have
+				}
+			}
+		}
+	}
+
+	return lang
+}
+
+// translate gives the question with the texts of the translation. Only the texts the question
+// reveals are translated, an explanation hidden until the end of a session stays hidden
+func (q QuizQuestion) translate(t QuestionTranslation) QuizQuestion {
+	if t.Content != "" {
+		q.Content = t.Content
+	}
+	if t.Code != "" {
+		q.Code = t.Code
+	}
+	if t.Explanation != "" && q.Explanation != "" {
+		q.Explanation = t.Explanation
+	}
+
+	// the right-hand items of a Matching question by sha1
+	rights := map[string]string{}
+	answers := make(map[string]QuizQuestionAnswer, len(q.Answers))
+	for sha1, answer := range q.Answers {
+		if at, found := t.Answers[sha1]; found {
+			if at.Content != "" && answer.Content != "" {
+				answer.Content = at.Content
+			}
+			if at.Explanation != "" && answer.Explanation != "" {
+				answer.Explanation = at.Explanation
+			}
+			if at.Right != "" && answer.RightSha1 != "" {
+				rights[answer.RightSha1] = at.Right
+			}
+			if at.Right != "" && answer.Right != "" {
+				answer.Right = at.Right
+			}
+		}
+		answers[sha1] = answer
+	}
+
+	if q.RightItems != nil {
+		translatedRights := map[string]string{}
+		rightItems := make(map[string]string, len(q.RightItems))
+		for sha1, right := range q.RightItems {
+			if translated, found := rights[sha1]; found {
+				translatedRights[right] = translated
+				right = translated
+			}
+			rightItems[sha1] = right
+		}
+		q.RightItems = rightItems
+
+		for sha1, answer := range answers {
+			if translated, found := translatedRights[answer.AnsweredRight]; found {
+				answer.AnsweredRight = translated
+				answers[sha1] = answer
+			}
+		}
+	}
+	q.Answers = answers
+
+	return q
+}
+
+// translateQuestions gives the questions with the texts of the translation
+func translateQuestions(questions map[string]QuizQuestion, translation QuizTranslation) map[string]QuizQuestion {
+	translated := make(map[string]QuizQuestion, len(questions))
+	for sha1, question := range questions {
+		translated[sha1] = question.translate(translation.Questions[sha1])
+	}
+
+	return translated
+}
+
+// TranslateQuiz gives the texts of the quiz in the first of the preferred languages it is available
+// in. Metadata.Lang is set to the language of the texts, the quiz staying in its own language when
+// none of the preferred ones is available. Langs is set to the available languages
+func (s *QuizService) TranslateQuiz(ctx context.Context, quiz *Quiz, preferred []string) error {
+	translations, err := s.r.FindTranslations(ctx, quiz.Sha1)
+	if err != nil {
+		return err
+	}
+	quiz.Langs = availableLangs(quiz.Metadata.Lang, translations)
+
+	lang := chooseLang(quiz.Metadata.Lang, translations, preferred)
+	translation, found := translations[lang]
+	if !found {
+		return nil
+	}
+
+	if translation.Name != "" {
+		quiz.Name = translation.Name
+	}
+	if translation.Description != "" {
+		quiz.Metadata.Description = translation.Description
+	}
+	quiz.Questions = translateQuestions(quiz.Questions, translation)
+	quiz.Metadata.Lang = lang
+
+	return nil
+}
+
+// TranslateQuizzes gives the names and the descriptions of the active quizzes in the first of the
+// preferred languages they are available in
+func (s *QuizService) TranslateQuizzes(ctx context.Context, quizzes []*Quiz, preferred []string) error {
+	translations, err := s.r.FindActiveQuizTranslations(ctx)
+	if err != nil {
+		return err
+	}
+
+	for _, quiz := range quizzes {
+		lang := chooseLang(quiz.Metadata.Lang, translations[quiz.Sha1], preferred)
+		translation, found := translations[quiz.Sha1][lang]
+		if !found {
+			continue
+		}
+
+		if translation.Name != "" {
+			quiz.Name = translation.Name
+		}
+		if translation.Description != "" {
+			quiz.Metadata.Description = translation.Description
+		}
+		quiz.Metadata.Lang = lang
+	}
+
+	return nil
+}
+
+// TranslateQuizSession gives the texts of a session in the first of the preferred languages its quiz
+// is available in. The answers stay the ones of the quiz, whatever the language they were given in
+func (s *QuizService) TranslateQuizSession(ctx context.Context, detail *QuizSessionDetail, preferred []string) error {
+	translations, err := s.r.FindTranslations(ctx, detail.QuizSha1)
+	if err != nil {
+		return err
+	}
+
+	lang := chooseLang(detail.Lang, translations, preferred)
+	translation, found := translations[lang]
+	if !found {
+		return nil
+	}
+
+	if translation.Name != "" {
+		detail.Name = translation.Name
+	}
+	detail.Questions = translateQuestions(detail.Questions, translation)
+	detail.Lang = lang
+
+	return nil
+}
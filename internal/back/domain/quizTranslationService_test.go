@@ -0,0 +1,270 @@
+/*
+ * Copyright (c) 2023 Michaël COLL.
+ *
+ * Licensed under the Apache License, Version 2.0 (the "License");
+ * you may not use this file except in compliance with the License.
+ * You may obtain a copy of the License at
+ *
+ *      http://www.apache.org/licenses/LICENSE-2.0
+ *
+ * Unless required by applicable law or agreed to in writing, software
+ * distributed under the License is distributed on an "AS IS" BASIS,
+ * WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+ * See the License for the specific language governing permissions and
+ * limitations under the License.
+ */
+
+package domain
+
+import (
+	"context"
+	"strings"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+)
+
+const frGitContent = `---
+lang: fr
+id: git
+translations:
+  en:
+    name: Git commands
+---
+# Commandes Git (duration: 5min)
+
+Quelle commande crée un commit ?
+
+- [x] git commit
+- [ ] git add
+
+:::en
+Which command creates a commit ?
+
+- [x] git commit
+- [ ] git add
+:::
+---
+Associez chaque commande à son effet
+
+- git add -> indexe un fichier
+- git rm -> supprime un fichier
+`
+
+const enGitContent = `---
+lang: en
+id: git
+---
+# Git commands (duration: 5min)
+
+Which command creates a commit ?
+
+- [x] git commit
+- [ ] git add
+
+---
+Match each command with what it does
+
+- git add -> stages a file
+- git rm -> removes a file
+`
+
+func TestQuizService_Parse_translation_blocks(t *testing.T) {
+	s := NewQuizService(nil)
+
+	quiz, err := s.Parse("git.fr.quiz.md", frGitContent)
+	if err != nil {
+		assert.Fail(t, "Can't parse quiz", "%v", err)
+	}
+
+	assert.Equal(t, "fr", quiz.Metadata.Lang)
+	assert.Equal(t, "git", quiz.Metadata.Id)
+	assert.Len(t, quiz.Questions, 2)
+
+	translation := quiz.Translations["en"]
+	assert.Equal(t, "Git commands", translation.Name)
+	assert.Len(t, translation.Questions, 1)
+
+	for sha1, question := range quiz.Questions {
+		if question.Position != 1 {
+			continue
+		}
+		assert.Equal(t, "Quelle commande crée un commit ?", question.Content)
+		assert.Equal(t, "Which command creates a commit ?", translation.Questions[sha1].Content)
+		assert.Len(t, translation.Questions[sha1].Answers, 2)
+	}
+
+	// the translation blocks do not change the sha1 of the question
+	block := ":::en\nWhich command creates a commit ?\n\n- [x] git commit\n- [ ] git add\n:::\n"
+	plain, err := s.Parse("plain.quiz.md", strings.Replace(frGitContent, block, "", 1))
+	if err != nil {
+		assert.Fail(t, "Can't parse quiz", "%v", err)
+	}
+	for sha1, question := range plain.Questions {
+		if question.Position == 1 {
+			assert.Contains(t, translation.Questions, sha1)
+		}
+	}
+}
+
+func TestQuizService_Parse_translation_blocks_errors(t *testing.T) {
+	s := NewQuizService(nil)
+
+	_, diagnostics := s.ParseWithDiagnostics("quiz.md", "# Quiz (duration: 5min)\n\nQuestion ?\n\n- [x] yes\n- [ ] no\n\n:::en\nQuestion ?\n\n- [x] yes\n- [ ] no\n:::\n")
+	assert.Equal(t, "translation blocks need the language of the quiz, given by lang in the front-matter", diagnostics[0].Message)
+
+	_, diagnostics = s.ParseWithDiagnostics("quiz.md", "---\nlang: fr\n---\n# Quiz (duration: 5min)\n\nQuestion ?\n\n- [x] oui\n- [ ] non\n\n:::en\nQuestion ?\n\n- [ ] yes\n- [x] no\n:::\n")
+	assert.Equal(t, "translation block 'en' must have the answers of the question, in the same order", diagnostics[0].Message)
+
+	_, diagnostics = s.ParseWithDiagnostics("quiz.md", "---\nlang: fr\n---\n# Quiz (duration: 5min)\n\nQuestion ?\n\n- [x] oui\n- [ ] non\n\n:::en\nQuestion ?\n\n- [x] yes\n- [ ] no\n")
+	assert.Equal(t, "translation block 'en' is not closed. It must end with a ':::' line", diagnostics[0].Message)
+
+	_, diagnostics = s.ParseWithDiagnostics("quiz.md", "---\nlang: french\n---\n# Quiz (duration: 5min)\n\nQuestion ?\n\n- [x] oui\n- [ ] non\n")
+	assert.Equal(t, "front-matter is not valid (lang 'french' must be a language code like en or pt-BR)", diagnostics[0].Message)
+}
+
+func TestQuizService_scanQuizzes_translated_files(t *testing.T) {
+	fs := includeFs(t, map[string]string{
+		"git.fr.quiz.md": frGitContent,
+		"git.en.quiz.md": enGitContent,
+	})
+
+	s := NewQuizService(nil)
+	quizzes, diagnostics, err := s.scanQuizzes(fs)
+	if err != nil {
+		assert.Fail(t, "Can't scan quizzes", "%v", err)
+	}
+
+	// the inline translation of git.fr.quiz.md is ignored, git.en.quiz.md coming first
+	assert.Equal(t, 1, diagnostics.Count(WarningSeverity))
+	assert.False(t, diagnostics.HasErrors())
+	assert.Len(t, quizzes, 1)
+
+	quiz := quizzes[0]
+	assert.Equal(t, "git.en.quiz.md", quiz.Filename)
+	assert.Equal(t, "Git commands", quiz.Name)
+	assert.Equal(t, "en", quiz.Metadata.Lang)
+	assert.Equal(t, "Commandes Git", quiz.Translations["fr"].Name)
+	assert.Len(t, quiz.Translations["fr"].Questions, 2)
+
+	for sha1, question := range quiz.Questions {
+		if question.Kind != Matching {
+			continue
+		}
+		answers := quiz.Translations["fr"].Questions[sha1].Answers
+		for answerSha1, answer := range question.Answers {
+			if answer.Content == "git add" {
+				assert.Equal(t, "indexe un fichier", answers[answerSha1].Right)
+			}
+		}
+	}
+}
+
+func TestQuizService_scanQuizzes_translated_files_mismatch(t *testing.T) {
+	fs := includeFs(t, map[string]string{
+		"git.fr.quiz.md": frGitContent,
+		"git.en.quiz.md": "---\nlang: en\nid: git\n---\n# Git commands (duration: 5min)\n\nWhich command creates a commit ?\n\n- [x] git commit\n- [ ] git add\n",
+	})
+
+	s := NewQuizService(nil)
+	quizzes, diagnostics, err := s.scanQuizzes(fs)
+	if err != nil {
+		assert.Fail(t, "Can't scan quizzes", "%v", err)
+	}
+
+	assert.True(t, diagnostics.HasErrors())
+	assert.Empty(t, quizzes)
+}
+
+func TestQuizService_scanQuizzes_translated_files_in_folders(t *testing.T) {
+	fs := includeFs(t, map[string]string{
+		"vcs/git.fr.quiz.md":    frGitContent,
+		"vcs/git.en.quiz.md":    enGitContent,
+		"basics/git.en.quiz.md": enGitContent,
+	})
+
+	s := NewQuizService(nil)
+	quizzes, diagnostics, err := s.scanQuizzes(fs)
+	if err != nil {
+		assert.Fail(t, "Can't scan quizzes", "%v", err)
+	}
+
+	// the files sharing an id are only merged when they are in the same folder
+	assert.False(t, diagnostics.HasErrors())
+	assert.Len(t, quizzes, 2)
+	assert.Equal(t, "basics/git.en.quiz.md", quizzes[0].Filename)
+	assert.Equal(t, "basics", quizzes[0].Category)
+	assert.Empty(t, quizzes[0].Translations)
+	assert.Equal(t, "vcs/git.en.quiz.md", quizzes[1].Filename)
+	assert.Equal(t, "vcs", quizzes[1].Category)
+	assert.Contains(t, quizzes[1].Translations, "fr")
+}
+
+func Test_chooseLang(t *testing.T) {
+	translations := map[string]QuizTranslation{"en": {}, "pt-BR": {}}
+
+	assert.Equal(t, "en", chooseLang("fr", translations, []string{"en-US", "fr"}))
+	assert.Equal(t, "fr", chooseLang("fr", translations, []string{"fr-CA", "en"}))
+	assert.Equal(t, "pt-BR", chooseLang("fr", translations, []string{"pt"}))
+	assert.Equal(t, "fr", chooseLang("fr", translations, []string{"de"}))
+	assert.Equal(t, "fr", chooseLang("fr", translations, nil))
+	assert.Equal(t, "", chooseLang("", nil, []string{"en"}))
+}
+
+func TestQuizQuestion_translate(t *testing.T) {
+	question := QuizQuestion{
+		Content:    "Associez",
+		RightItems: map[string]string{"r1": "indexe", "r2": "supprime"},
+		Answers: map[string]QuizQuestionAnswer{
+			"a1": {Content: "git add", RightSha1: "r1", AnsweredRight: "supprime"},
+			"a2": {Content: "git rm", RightSha1: "r2"},
+		},
+	}
+
+	translated := question.translate(QuestionTranslation{
+		Content:     "Match",
+		Explanation: "hidden",
+		Answers: map[string]AnswerTranslation{
+			"a1": {Content: "git add", Right: "stages", Explanation: "hidden"},
+			"a2": {Content: "git rm", Right: "removes"},
+		},
+	})
+
+	assert.Equal(t, "Match", translated.Content)
+	assert.Equal(t, map[string]string{"r1": "stages", "r2": "removes"}, translated.RightItems)
+	assert.Equal(t, "removes", translated.Answers["a1"].AnsweredRight)
+	// the explanations and the expected pairs hidden by a running session stay hidden
+	assert.Empty(t, translated.Explanation)
+	assert.Empty(t, translated.Answers["a1"].Explanation)
+	assert.Empty(t, translated.Answers["a1"].Right)
+}
+
+func TestQuizService_TranslateQuiz(t *testing.T) {
+	mockQuizRepository := NewMockQuizRepository(t)
+	s := NewQuizService(mockQuizRepository)
+	ctx := context.Background()
+
+	mockQuizRepository.On("FindTranslations", ctx, "sha1").Return(map[string]QuizTranslation{
+		"en": {
+			Name:      "Git commands",
+			Questions: map[string]QuestionTranslation{"q": {Content: "Which command creates a commit ?"}},
+		},
+	}, nil)
+
+	quiz := &Quiz{
+		Sha1:      "sha1",
+		Name:      "Commandes Git",
+		Metadata:  QuizMetadata{Lang: "fr"},
+		Questions: map[string]QuizQuestion{"q": {Sha1: "q", Content: "Quelle commande crée un commit ?"}},
+	}
+
+	err := s.TranslateQuiz(ctx, quiz, []string{"en-GB"})
+	if err != nil {
+		assert.Fail(t, "Can't translate quiz", "%v", err)
+	}
+
+	assert.Equal(t, "Git commands", quiz.Name)
+	assert.Equal(t, "en", quiz.Metadata.Lang)
+	assert.Equal(t, []string{"fr", "en"}, quiz.Langs)
+	assert.Equal(t, "Which command creates a commit ?", quiz.Questions["q"].Content)
+}
@@ -25,19 +25,29 @@ import (
 //go:generate mockery --name QuizRepository
 type QuizRepository interface {
 	FindFullBySha1(ctx context.Context, sha1 string, userId string) (*Quiz, error)
-	FindLatestVersionByFilename(ctx context.Context, filename string) (*Quiz, error)
-	FindAllActive(ctx context.Context, userId string, limit uint16, offset uint16) ([]*Quiz, error)
-	CountAllActive(ctx context.Context, userId string) (uint32, error)
+	FindLatestVersionByFilename(ctx context.Context, source string, filename string) (*Quiz, error)
+	FindAllActive(ctx context.Context, userId string, category string, limit uint16, offset uint16) ([]*Quiz, error)
+	CountAllActive(ctx context.Context, userId string, category string) (uint32, error)
 	Create(ctx context.Context, quiz *Quiz) error
-	ActivateOnlyVersion(ctx context.Context, filename string, version int) error
+	ActivateOnlyVersion(ctx context.Context, source string, filename string, version int) error
+	FindAssetBySha1(ctx context.Context, sha1 string) (*Asset, error)
+	FindPoolBySha1(ctx context.Context, sha1 string) (*Quiz, error)
+	FindTranslations(ctx context.Context, quizSha1 string) (map[string]QuizTranslation, error)
+	FindActiveQuizTranslations(ctx context.Context) (map[string]map[string]QuizTranslation, error)
 
 	FindAllSessions(ctx context.Context, quizActive bool, userId string, limit uint16, offset uint16) ([]*Session, error)
 	CountAllSessions(ctx context.Context, quizActive bool, userId string) (uint32, error)
-	StartSession(ctx context.Context, userId string, quizSha1 string) (uuid.UUID, error)
+	StartSession(ctx context.Context, userId string, quizSha1 string, seed int64, questionSha1s []string) (uuid.UUID, error)
 	AddSessionAnswer(ctx context.Context, sessionUuid uuid.UUID, questionSha1 string, answerSha1 string, checked bool) error
+	AddSessionTextAnswer(ctx context.Context, sessionUuid uuid.UUID, questionSha1 string, answers map[string]bool, text string) error
+	AddSessionOrderAnswer(ctx context.Context, sessionUuid uuid.UUID, questionSha1 string, positions map[string]int) error
+	AddSessionPairAnswer(ctx context.Context, sessionUuid uuid.UUID, questionSha1 string, rights map[string]string) error
+	FindSessionQuestion(ctx context.Context, sessionUuid uuid.UUID, questionSha1 string) (*QuizQuestion, error)
+	FindSessionAnswerTranslations(ctx context.Context, sessionUuid uuid.UUID, questionSha1 string) (map[string][]string, error)
 
 	FindAllQuizSessions(ctx context.Context, userId string, classId string, limit uint16, offset uint16) ([]*QuizSession, error)
 	FindQuizSessionByUuid(ctx context.Context, sessionUuid uuid.UUID) (*QuizSessionDetail, error)
+	FindQuizSessionsByUuids(ctx context.Context, sessionUuids []uuid.UUID) (map[uuid.UUID]*QuizSessionDetail, error)
 }
 
 //go:generate mockery --name AuthRepository
@@ -56,6 +66,7 @@ type UserRepository interface {
 	UpdateUserRole(ctx context.Context, userId string, role Role) error
 	UpdateUserInfo(ctx context.Context, user *User) error
 	AssignUserToClass(ctx context.Context, userId string, classId uuid.UUID) error
+	UpdateUserLang(ctx context.Context, userId string, lang string) error
 }
 
 //go:generate mockery --name ClassRepository
@@ -67,3 +67,13 @@ func (s *UserService) UpdateUserRole(ctx context.Context, userId string, role Ro
 
 	return nil
 }
+
+// UpdateUserLang sets the language the user prefers the quizzes in, an empty language letting the
+// Accept-Language header of the requests choose it
+func (s *UserService) UpdateUserLang(ctx context.Context, userId string, lang string) error {
+	if lang != "" && !langRegexp.MatchString(lang) {
+		return Errorf(InvalidArgument, "lang '%s' must be a language code like en or pt-BR", lang)
+	}
+
+	return s.r.UpdateUserLang(ctx, userId, lang)
+}
@@ -38,3 +38,17 @@ func TestUserService_FindUserById(t *testing.T) {
 		}
 	}
 }
+
+func TestUserService_UpdateUserLang(t *testing.T) {
+	mockUserRepository := NewMockUserRepository(t)
+	service := NewUserService(mockUserRepository)
+
+	mockUserRepository.On("UpdateUserLang", context.Background(), sub, "pt-BR").Return(nil)
+
+	err := service.UpdateUserLang(context.Background(), sub, "pt-BR")
+	assert.NoError(t, err)
+
+	err = service.UpdateUserLang(context.Background(), sub, "french")
+	code, _ := GetCodeFromError(err)
+	assert.Equal(t, ErrorCode(InvalidArgument), code)
+}
@@ -17,6 +17,7 @@
 package infrastructure
 
 import (
+	"context"
 	"database/sql"
 
 	"github.com/michaelcoll/quiz-app/internal/back/infrastructure/db"
@@ -42,6 +43,23 @@ func (w *ConnectionWrapper) queries() *sqlc.Queries {
 	return sqlc.New(w.c)
 }
 
+// inTx runs the queries of fn in a single transaction, rolled back when fn fails
+func (w *ConnectionWrapper) inTx(ctx context.Context, fn func(q *sqlc.Queries) error) error {
+	queries := w.queries()
+
+	tx, err := w.c.BeginTx(ctx, nil)
+	if err != nil {
+		return err
+	}
+
+	if err := fn(queries.WithTx(tx)); err != nil {
+		_ = tx.Rollback()
+		return err
+	}
+
+	return tx.Commit()
+}
+
 func (w *ConnectionWrapper) Close() error {
 	defer func() {
 		w.isClosed = true
@@ -343,14 +343,1600 @@ FROM quiz q
 WHERE q.active = TRUE;
 `
 
+const v3QuizMetadata = `
+ALTER TABLE quiz
+    ADD COLUMN description TEXT NOT NULL DEFAULT '';
+ALTER TABLE quiz
+    ADD COLUMN tags TEXT NOT NULL DEFAULT '';
+ALTER TABLE quiz
+    ADD COLUMN author TEXT NOT NULL DEFAULT '';
+ALTER TABLE quiz
+    ADD COLUMN pass_mark INTEGER NOT NULL DEFAULT 0;
+ALTER TABLE quiz
+    ADD COLUMN shuffle_questions INTEGER NOT NULL DEFAULT 0;
+ALTER TABLE quiz
+    ADD COLUMN shuffle_answers INTEGER NOT NULL DEFAULT 0;
+ALTER TABLE quiz
+    ADD COLUMN max_attempts INTEGER NOT NULL DEFAULT 0;
+
+DROP VIEW quiz_class_view;
+
+CREATE VIEW quiz_class_view
+AS
+SELECT q.*,
+       CASE WHEN sc.uuid IS NULL THEN '' ELSE sc.uuid END AS class_uuid,
+       CASE WHEN sc.name IS NULL THEN '' ELSE sc.name END AS class_name
+FROM quiz q
+         LEFT JOIN quiz_class_visibility qcv ON q.sha1 = qcv.quiz_sha1
+         LEFT JOIN student_class sc ON sc.uuid = qcv.class_uuid;
+`
+
+const v4ShortAnswer = `
+ALTER TABLE quiz_question
+    ADD COLUMN kind INTEGER NOT NULL DEFAULT 0;
+
+ALTER TABLE quiz_answer
+    ADD COLUMN match_mode INTEGER NOT NULL DEFAULT 0;
+
+ALTER TABLE session_answer
+    ADD COLUMN content TEXT;
+
+DROP VIEW quiz_answer_count_view;
+
+CREATE VIEW quiz_answer_count_view
+AS
+SELECT q.sha1                                                  AS quiz_sha1,
+       SUM(CASE WHEN qq.kind = 0 THEN 1 ELSE 0 END) +
+       COUNT(DISTINCT CASE WHEN qq.kind <> 0 THEN qq.sha1 END) AS checked_answers
+FROM quiz q
+         JOIN quiz_question_quiz qqq ON q.sha1 = qqq.quiz_sha1
+         JOIN quiz_question qq ON qq.sha1 = qqq.question_sha1
+         JOIN quiz_question_answer qqa ON qqq.question_sha1 = qqa.question_sha1
+         JOIN quiz_answer qa ON qa.sha1 = qqa.answer_sha1
+WHERE qa.valid = 1
+GROUP BY q.sha1;
+
+DROP VIEW session_response_view;
+
+CREATE VIEW session_response_view
+AS
+SELECT qqq.quiz_sha1,
+       qqq.question_sha1,
+       qqa.answer_sha1,
+       s.uuid  AS session_uuid,
+       s.user_id,
+       sa.checked,
+       sa.content,
+       CASE
+           WHEN checked IS NOT NULL
+               THEN CASE
+                        WHEN qa.valid == sa.checked
+                            THEN 1
+                        ELSE 0
+               END
+           END AS result
+FROM quiz_question_quiz qqq
+         JOIN quiz_question_answer qqa ON qqq.question_sha1 = qqa.question_sha1
+         JOIN quiz_answer qa ON qa.sha1 = qqa.answer_sha1
+         LEFT JOIN session s ON qqq.quiz_sha1 = s.quiz_sha1
+         LEFT JOIN session_answer sa
+                   ON sa.session_uuid = s.uuid
+                       AND qa.sha1 = sa.answer_sha1
+                       AND sa.question_sha1 = qqq.question_sha1
+                       AND sa.answer_sha1 = qqa.answer_sha1;
+
+DROP VIEW quiz_session_detail_view;
+
+CREATE VIEW quiz_session_detail_view
+AS
+SELECT qsv.session_uuid                                          AS session_uuid,
+       qsv.user_id                                               AS user_id,
+       qsv.remaining_sec                                         AS remaining_sec,
+       qsv.quiz_sha1                                             AS quiz_sha1,
+       qsv.quiz_name                                             AS quiz_name,
+       qsv.quiz_duration                                         AS quiz_duration,
+       qsv.checked_answers                                       AS checked_answers,
+       qsv.results                                               AS results,
+       srv.question_sha1                                         AS question_sha1,
+       qq.kind                                                   AS question_kind,
+       qq.position                                               AS question_position,
+       qq.content                                                AS question_content,
+       qq.code                                                   AS question_code,
+       qq.code_language                                          AS question_code_language,
+       srv.answer_sha1                                           AS answer_sha1,
+       qa.content                                                AS answer_content,
+       CASE WHEN srv.checked IS NULL THEN 0 ELSE srv.checked END AS answer_checked,
+       qa.valid                                                  AS answer_valid,
+       qa.match_mode                                             AS answer_match_mode,
+       srv.content                                               AS answer_text
+FROM quiz_session_view qsv
+         JOIN session_response_view srv ON qsv.session_uuid = srv.session_uuid
+         JOIN quiz_question qq ON srv.question_sha1 = qq.sha1
+         JOIN quiz_answer qa ON srv.answer_sha1 = qa.sha1
+ORDER BY qq.position;
+`
+
+const v5Ordering = `
+ALTER TABLE quiz_question
+    ADD COLUMN partial_credit INTEGER NOT NULL DEFAULT 0;
+
+ALTER TABLE quiz_answer
+    ADD COLUMN position INTEGER NOT NULL DEFAULT 0;
+
+ALTER TABLE session_answer
+    ADD COLUMN position INTEGER;
+
+DROP VIEW quiz_answer_count_view;
+
+CREATE VIEW quiz_answer_count_view
+AS
+SELECT q.sha1                                                                          AS quiz_sha1,
+       SUM(CASE WHEN qq.kind = 0 OR qq.partial_credit = 1 THEN 1 ELSE 0 END) +
+       COUNT(DISTINCT CASE WHEN qq.kind <> 0 AND qq.partial_credit = 0 THEN qq.sha1 END) AS checked_answers
+FROM quiz q
+         JOIN quiz_question_quiz qqq ON q.sha1 = qqq.quiz_sha1
+         JOIN quiz_question qq ON qq.sha1 = qqq.question_sha1
+         JOIN quiz_question_answer qqa ON qqq.question_sha1 = qqa.question_sha1
+         JOIN quiz_answer qa ON qa.sha1 = qqa.answer_sha1
+WHERE qa.valid = 1
+GROUP BY q.sha1;
+
+DROP VIEW session_response_view;
+
+CREATE VIEW session_response_view
+AS
+SELECT qqq.quiz_sha1,
+       qqq.question_sha1,
+       qqa.answer_sha1,
+       s.uuid  AS session_uuid,
+       s.user_id,
+       sa.checked,
+       sa.content,
+       sa.position,
+       CASE
+           WHEN checked IS NOT NULL
+               THEN CASE
+                        WHEN qa.valid == sa.checked
+                            THEN 1
+                        ELSE 0
+               END
+           END AS result
+FROM quiz_question_quiz qqq
+         JOIN quiz_question_answer qqa ON qqq.question_sha1 = qqa.question_sha1
+         JOIN quiz_answer qa ON qa.sha1 = qqa.answer_sha1
+         LEFT JOIN session s ON qqq.quiz_sha1 = s.quiz_sha1
+         LEFT JOIN session_answer sa
+                   ON sa.session_uuid = s.uuid
+                       AND qa.sha1 = sa.answer_sha1
+                       AND sa.question_sha1 = qqq.question_sha1
+                       AND sa.answer_sha1 = qqa.answer_sha1;
+
+DROP VIEW quiz_session_detail_view;
+
+CREATE VIEW quiz_session_detail_view
+AS
+SELECT qsv.session_uuid                                          AS session_uuid,
+       qsv.user_id                                               AS user_id,
+       qsv.remaining_sec                                         AS remaining_sec,
+       qsv.quiz_sha1                                             AS quiz_sha1,
+       qsv.quiz_name                                             AS quiz_name,
+       qsv.quiz_duration                                         AS quiz_duration,
+       qsv.checked_answers                                       AS checked_answers,
+       qsv.results                                               AS results,
+       srv.question_sha1                                         AS question_sha1,
+       qq.kind                                                   AS question_kind,
+       qq.position                                               AS question_position,
+       qq.content                                                AS question_content,
+       qq.code                                                   AS question_code,
+       qq.code_language                                          AS question_code_language,
+       qq.partial_credit                                         AS question_partial_credit,
+       srv.answer_sha1                                           AS answer_sha1,
+       qa.content                                                AS answer_content,
+       CASE WHEN srv.checked IS NULL THEN 0 ELSE srv.checked END AS answer_checked,
+       qa.valid                                                  AS answer_valid,
+       qa.match_mode                                             AS answer_match_mode,
+       srv.content                                               AS answer_text,
+       qa.position                                               AS answer_position,
+       srv.position                                              AS answer_answered_position
+FROM quiz_session_view qsv
+         JOIN session_response_view srv ON qsv.session_uuid = srv.session_uuid
+         JOIN quiz_question qq ON srv.question_sha1 = qq.sha1
+         JOIN quiz_answer qa ON srv.answer_sha1 = qa.sha1
+ORDER BY qq.position;
+`
+
+const v6Matching = `
+CREATE TABLE quiz_question_pair
+(
+    question_sha1 TEXT NOT NULL,
+    answer_sha1   TEXT NOT NULL,
+    right_sha1    TEXT NOT NULL,
+    right_content TEXT NOT NULL,
+
+    PRIMARY KEY (question_sha1, answer_sha1),
+    FOREIGN KEY (question_sha1) REFERENCES quiz_question (sha1),
+    FOREIGN KEY (answer_sha1) REFERENCES quiz_answer (sha1)
+);
+
+DROP VIEW quiz_session_detail_view;
+
+CREATE VIEW quiz_session_detail_view
+AS
+SELECT qsv.session_uuid                                          AS session_uuid,
+       qsv.user_id                                               AS user_id,
+       qsv.remaining_sec                                         AS remaining_sec,
+       qsv.quiz_sha1                                             AS quiz_sha1,
+       qsv.quiz_name                                             AS quiz_name,
+       qsv.quiz_duration                                         AS quiz_duration,
+       qsv.checked_answers                                       AS checked_answers,
+       qsv.results                                               AS results,
+       srv.question_sha1                                         AS question_sha1,
+       qq.kind                                                   AS question_kind,
+       qq.position                                               AS question_position,
+       qq.content                                                AS question_content,
+       qq.code                                                   AS question_code,
+       qq.code_language                                          AS question_code_language,
+       qq.partial_credit                                         AS question_partial_credit,
+       srv.answer_sha1                                           AS answer_sha1,
+       qa.content                                                AS answer_content,
+       CASE WHEN srv.checked IS NULL THEN 0 ELSE srv.checked END AS answer_checked,
+       qa.valid                                                  AS answer_valid,
+       qa.match_mode                                             AS answer_match_mode,
+       srv.content                                               AS answer_text,
+       qa.position                                               AS answer_position,
+       srv.position                                              AS answer_answered_position,
+       qqp.right_sha1                                            AS answer_right_sha1,
+       qqp.right_content                                         AS answer_right_content
+FROM quiz_session_view qsv
+         JOIN session_response_view srv ON qsv.session_uuid = srv.session_uuid
+         JOIN quiz_question qq ON srv.question_sha1 = qq.sha1
+         JOIN quiz_answer qa ON srv.answer_sha1 = qa.sha1
+         LEFT JOIN quiz_question_pair qqp
+                   ON qqp.question_sha1 = srv.question_sha1
+                       AND qqp.answer_sha1 = srv.answer_sha1
+ORDER BY qq.position;
+`
+
+const v7QuestionPoints = `
+ALTER TABLE quiz_question
+    ADD COLUMN points INTEGER NOT NULL DEFAULT 1;
+
+CREATE VIEW session_question_score_view
+AS
+SELECT s.uuid                                                        AS session_uuid,
+       qqq.quiz_sha1                                                 AS quiz_sha1,
+       qq.sha1                                                       AS question_sha1,
+       qq.points                                                     AS points,
+       CASE
+           WHEN qq.kind = 0
+               THEN qq.points * MIN(qa.valid = COALESCE(sa.checked, 0))
+           WHEN qq.partial_credit = 1
+               THEN qq.points * AVG(COALESCE(sa.checked, 0))
+           ELSE qq.points * MAX(COALESCE(sa.checked, 0))
+           END                                                       AS score
+FROM session s
+         JOIN quiz_question_quiz qqq ON s.quiz_sha1 = qqq.quiz_sha1
+         JOIN quiz_question qq ON qq.sha1 = qqq.question_sha1
+         JOIN quiz_question_answer qqa ON qq.sha1 = qqa.question_sha1
+         JOIN quiz_answer qa ON qa.sha1 = qqa.answer_sha1
+         LEFT JOIN session_answer sa
+                   ON sa.session_uuid = s.uuid
+                       AND sa.question_sha1 = qq.sha1
+                       AND sa.answer_sha1 = qa.sha1
+GROUP BY s.uuid, qqq.quiz_sha1, qq.sha1, qq.points, qq.kind, qq.partial_credit;
+
+CREATE VIEW quiz_points_view
+AS
+SELECT qqq.quiz_sha1   AS quiz_sha1,
+       SUM(qq.points) AS points_possible
+FROM quiz_question_quiz qqq
+         JOIN quiz_question qq ON qq.sha1 = qqq.question_sha1
+GROUP BY qqq.quiz_sha1;
+
+DROP VIEW session_view;
+
+CREATE VIEW session_view
+AS
+SELECT s.uuid                                                                                       AS uuid,
+       q.sha1                                                                                       AS quiz_sha1,
+       q.name                                                                                       AS quiz_name,
+       q.active                                                                                     AS quiz_active,
+       u.id                                                                                         AS user_id,
+       u.name                                                                                       AS user_name,
+       u.picture                                                                                    AS user_picture,
+       CAST(MAX(q.duration - (STRFTIME('%s', 'now') - STRFTIME('%s', s.created_at)), 0) AS INTEGER) AS remaining_sec,
+       checked_answers,
+       SUM(srv.result)                                                                              AS results,
+       (SELECT CAST(SUM(sqsv.score) AS REAL)
+        FROM session_question_score_view sqsv
+        WHERE sqsv.session_uuid = s.uuid)                                                           AS points_earned,
+       qpv.points_possible                                                                          AS points_possible
+FROM session s
+         JOIN quiz q ON q.sha1 = s.quiz_sha1
+         JOIN user u ON u.id = s.user_id
+         JOIN quiz_answer_count_view qacv ON s.quiz_sha1 = qacv.quiz_sha1
+         JOIN quiz_points_view qpv ON s.quiz_sha1 = qpv.quiz_sha1
+         JOIN session_response_view srv ON s.uuid = srv.session_uuid
+GROUP BY s.uuid, q.sha1, q.name, q.active, u.id, u.name, u.picture;
+
+DROP VIEW quiz_session_view;
+
+CREATE VIEW quiz_session_view
+AS
+SELECT q.sha1                                                                  AS quiz_sha1,
+       q.name                                                                  AS quiz_name,
+       q.filename                                                              AS quiz_filename,
+       q.version                                                               AS quiz_version,
+       q.duration                                                              AS quiz_duration,
+       q.created_at                                                            AS quiz_created_at,
+       CASE WHEN s.uuid IS NULL THEN '' ELSE s.uuid END                        AS session_uuid,
+       CASE WHEN s.user_id IS NULL THEN '' ELSE s.user_id END                  AS user_id,
+       CASE WHEN u.name IS NULL THEN '' ELSE u.name END                        AS user_name,
+       CASE WHEN u.picture IS NULL THEN '' ELSE u.picture END                  AS user_picture,
+       CASE WHEN sc.uuid IS NULL THEN '' ELSE sc.uuid END                      AS class_uuid,
+       CASE WHEN sc.name IS NULL THEN '' ELSE sc.name END                      AS class_name,
+       CASE WHEN sv.remaining_sec IS NULL THEN 0 ELSE sv.remaining_sec END     AS remaining_sec,
+       CASE WHEN sv.checked_answers IS NULL THEN 0 ELSE sv.checked_answers END AS checked_answers,
+       CASE WHEN sv.results IS NULL THEN 0 ELSE sv.results END                 AS results,
+       CASE WHEN sv.points_earned IS NULL THEN 0 ELSE sv.points_earned END     AS points_earned,
+       CASE WHEN sv.points_possible IS NULL THEN 0 ELSE sv.points_possible END AS points_possible
+FROM quiz q
+         LEFT JOIN session s ON q.sha1 = s.quiz_sha1
+         LEFT JOIN user u ON s.user_id = u.id
+         LEFT JOIN student_class sc ON u.class_uuid = sc.uuid
+         LEFT JOIN session_view sv ON q.sha1 = sv.quiz_sha1 AND s.uuid = sv.uuid
+WHERE q.active = TRUE;
+
+DROP VIEW quiz_session_detail_view;
+
+CREATE VIEW quiz_session_detail_view
+AS
+SELECT qsv.session_uuid                                          AS session_uuid,
+       qsv.user_id                                               AS user_id,
+       qsv.remaining_sec                                         AS remaining_sec,
+       qsv.quiz_sha1                                             AS quiz_sha1,
+       qsv.quiz_name                                             AS quiz_name,
+       qsv.quiz_duration                                         AS quiz_duration,
+       qsv.checked_answers                                       AS checked_answers,
+       qsv.results                                               AS results,
+       qsv.points_earned                                         AS points_earned,
+       qsv.points_possible                                       AS points_possible,
+       srv.question_sha1                                         AS question_sha1,
+       qq.kind                                                   AS question_kind,
+       qq.position                                               AS question_position,
+       qq.content                                                AS question_content,
+       qq.code                                                   AS question_code,
+       qq.code_language                                          AS question_code_language,
+       qq.partial_credit                                         AS question_partial_credit,
+       qq.points                                                 AS question_points,
+       sqsv.score                                                AS question_score,
+       srv.answer_sha1                                           AS answer_sha1,
+       qa.content                                                AS answer_content,
+       CASE WHEN srv.checked IS NULL THEN 0 ELSE srv.checked END AS answer_checked,
+       qa.valid                                                  AS answer_valid,
+       qa.match_mode                                             AS answer_match_mode,
+       srv.content                                               AS answer_text,
+       qa.position                                               AS answer_position,
+       srv.position                                              AS answer_answered_position,
+       qqp.right_sha1                                            AS answer_right_sha1,
+       qqp.right_content                                         AS answer_right_content
+FROM quiz_session_view qsv
+         JOIN session_response_view srv ON qsv.session_uuid = srv.session_uuid
+         JOIN quiz_question qq ON srv.question_sha1 = qq.sha1
+         JOIN quiz_answer qa ON srv.answer_sha1 = qa.sha1
+         JOIN session_question_score_view sqsv
+              ON sqsv.session_uuid = srv.session_uuid
+                  AND sqsv.question_sha1 = srv.question_sha1
+         LEFT JOIN quiz_question_pair qqp
+                   ON qqp.question_sha1 = srv.question_sha1
+                       AND qqp.answer_sha1 = srv.answer_sha1
+ORDER BY qq.position;
+`
+
+const v8ScoringStrategy = `
+ALTER TABLE quiz
+    ADD COLUMN scoring INTEGER NOT NULL DEFAULT 0;
+
+DROP VIEW quiz_class_view;
+
+CREATE VIEW quiz_class_view
+AS
+SELECT q.*,
+       CASE WHEN sc.uuid IS NULL THEN '' ELSE sc.uuid END AS class_uuid,
+       CASE WHEN sc.name IS NULL THEN '' ELSE sc.name END AS class_name
+FROM quiz q
+         LEFT JOIN quiz_class_visibility qcv ON q.sha1 = qcv.quiz_sha1
+         LEFT JOIN student_class sc ON sc.uuid = qcv.class_uuid;
+
+DROP VIEW quiz_session_detail_view;
+DROP VIEW quiz_session_view;
+DROP VIEW session_view;
+DROP VIEW session_question_score_view;
+DROP VIEW quiz_points_view;
+DROP VIEW quiz_answer_count_view;
+DROP VIEW session_response_view;
+
+CREATE VIEW session_response_view
+AS
+SELECT qqq.quiz_sha1,
+       qqq.question_sha1,
+       qqa.answer_sha1,
+       s.uuid AS session_uuid,
+       s.user_id,
+       sa.checked,
+       sa.content,
+       sa.position
+FROM quiz_question_quiz qqq
+         JOIN quiz_question_answer qqa ON qqq.question_sha1 = qqa.question_sha1
+         JOIN quiz_answer qa ON qa.sha1 = qqa.answer_sha1
+         LEFT JOIN session s ON qqq.quiz_sha1 = s.quiz_sha1
+         LEFT JOIN session_answer sa
+                   ON sa.session_uuid = s.uuid
+                       AND qa.sha1 = sa.answer_sha1
+                       AND sa.question_sha1 = qqq.question_sha1
+                       AND sa.answer_sha1 = qqa.answer_sha1;
+
+CREATE VIEW session_view
+AS
+SELECT s.uuid                                                                                       AS uuid,
+       q.sha1                                                                                       AS quiz_sha1,
+       q.name                                                                                       AS quiz_name,
+       q.active                                                                                     AS quiz_active,
+       u.id                                                                                         AS user_id,
+       u.name                                                                                       AS user_name,
+       u.picture                                                                                    AS user_picture,
+       CAST(MAX(q.duration - (STRFTIME('%s', 'now') - STRFTIME('%s', s.created_at)), 0) AS INTEGER) AS remaining_sec
+FROM session s
+         JOIN quiz q ON q.sha1 = s.quiz_sha1
+         JOIN user u ON u.id = s.user_id;
+
+CREATE VIEW quiz_session_view
+AS
+SELECT q.sha1                                                              AS quiz_sha1,
+       q.name                                                              AS quiz_name,
+       q.filename                                                          AS quiz_filename,
+       q.version                                                           AS quiz_version,
+       q.duration                                                          AS quiz_duration,
+       q.created_at                                                        AS quiz_created_at,
+       CASE WHEN s.uuid IS NULL THEN '' ELSE s.uuid END                    AS session_uuid,
+       CASE WHEN s.user_id IS NULL THEN '' ELSE s.user_id END              AS user_id,
+       CASE WHEN u.name IS NULL THEN '' ELSE u.name END                    AS user_name,
+       CASE WHEN u.picture IS NULL THEN '' ELSE u.picture END              AS user_picture,
+       CASE WHEN sc.uuid IS NULL THEN '' ELSE sc.uuid END                  AS class_uuid,
+       CASE WHEN sc.name IS NULL THEN '' ELSE sc.name END                  AS class_name,
+       CASE WHEN sv.remaining_sec IS NULL THEN 0 ELSE sv.remaining_sec END AS remaining_sec
+FROM quiz q
+         LEFT JOIN session s ON q.sha1 = s.quiz_sha1
+         LEFT JOIN user u ON s.user_id = u.id
+         LEFT JOIN student_class sc ON u.class_uuid = sc.uuid
+         LEFT JOIN session_view sv ON q.sha1 = sv.quiz_sha1 AND s.uuid = sv.uuid
+WHERE q.active = TRUE;
+
+CREATE VIEW quiz_session_detail_view
+AS
+SELECT qsv.session_uuid                                          AS session_uuid,
+       qsv.user_id                                               AS user_id,
+       qsv.remaining_sec                                         AS remaining_sec,
+       qsv.quiz_sha1                                             AS quiz_sha1,
+       qsv.quiz_name                                             AS quiz_name,
+       qsv.quiz_duration                                         AS quiz_duration,
+       q.scoring                                                 AS quiz_scoring,
+       srv.question_sha1                                         AS question_sha1,
+       qq.kind                                                   AS question_kind,
+       qq.position                                               AS question_position,
+       qq.content                                                AS question_content,
+       qq.code                                                   AS question_code,
+       qq.code_language                                          AS question_code_language,
+       qq.partial_credit                                         AS question_partial_credit,
+       qq.points                                                 AS question_points,
+       srv.answer_sha1                                           AS answer_sha1,
+       qa.content                                                AS answer_content,
+       CASE WHEN srv.checked IS NULL THEN 0 ELSE srv.checked END AS answer_checked,
+       qa.valid                                                  AS answer_valid,
+       qa.match_mode                                             AS answer_match_mode,
+       srv.content                                               AS answer_text,
+       qa.position                                               AS answer_position,
+       srv.position                                              AS answer_answered_position,
+       qqp.right_sha1                                            AS answer_right_sha1,
+       qqp.right_content                                         AS answer_right_content
+FROM quiz_session_view qsv
+         JOIN quiz q ON qsv.quiz_sha1 = q.sha1
+         JOIN session_response_view srv ON qsv.session_uuid = srv.session_uuid
+         JOIN quiz_question qq ON srv.question_sha1 = qq.sha1
+         JOIN quiz_answer qa ON srv.answer_sha1 = qa.sha1
+         LEFT JOIN quiz_question_pair qqp
+                   ON qqp.question_sha1 = srv.question_sha1
+                       AND qqp.answer_sha1 = srv.answer_sha1
+ORDER BY qq.position;
+`
+
+const v9Explanations = `
+ALTER TABLE quiz_question
+    ADD COLUMN explanation TEXT NOT NULL DEFAULT '';
+
+ALTER TABLE quiz_question_answer
+    ADD COLUMN explanation TEXT NOT NULL DEFAULT '';
+
+DROP VIEW quiz_session_detail_view;
+
+CREATE VIEW quiz_session_detail_view
+AS
+SELECT qsv.session_uuid                                          AS session_uuid,
+       qsv.user_id                                               AS user_id,
+       qsv.remaining_sec                                         AS remaining_sec,
+       qsv.quiz_sha1                                             AS quiz_sha1,
+       qsv.quiz_name                                             AS quiz_name,
+       qsv.quiz_duration                                         AS quiz_duration,
+       q.scoring                                                 AS quiz_scoring,
+       srv.question_sha1                                         AS question_sha1,
+       qq.kind                                                   AS question_kind,
+       qq.position                                               AS question_position,
+       qq.content                                                AS question_content,
+       qq.code                                                   AS question_code,
+       qq.code_language                                          AS question_code_language,
+       qq.partial_credit                                         AS question_partial_credit,
+       qq.points                                                 AS question_points,
+       qq.explanation                                            AS question_explanation,
+       srv.answer_sha1                                           AS answer_sha1,
+       qa.content                                                AS answer_content,
+       CASE WHEN srv.checked IS NULL THEN 0 ELSE srv.checked END AS answer_checked,
+       qa.valid                                                  AS answer_valid,
+       qa.match_mode                                             AS answer_match_mode,
+       srv.content                                               AS answer_text,
+       qa.position                                               AS answer_position,
+       srv.position                                              AS answer_answered_position,
+       qqp.right_sha1                                            AS answer_right_sha1,
+       qqp.right_content                                         AS answer_right_content,
+       qqa.explanation                                           AS answer_explanation
+FROM quiz_session_view qsv
+         JOIN quiz q ON qsv.quiz_sha1 = q.sha1
+         JOIN session_response_view srv ON qsv.session_uuid = srv.session_uuid
+         JOIN quiz_question qq ON srv.question_sha1 = qq.sha1
+         JOIN quiz_answer qa ON srv.answer_sha1 = qa.sha1
+         JOIN quiz_question_answer qqa
+              ON qqa.question_sha1 = srv.question_sha1
+                  AND qqa.answer_sha1 = srv.answer_sha1
+         LEFT JOIN quiz_question_pair qqp
+                   ON qqp.question_sha1 = srv.question_sha1
+                       AND qqp.answer_sha1 = srv.answer_sha1
+ORDER BY qq.position;
+`
+
+const v10Assets = `
+CREATE TABLE asset
+(
+    sha1         TEXT PRIMARY KEY,
+    filename     TEXT NOT NULL,
+    content_type TEXT NOT NULL,
+    content      BLOB NOT NULL
+);
+`
+
+const v11QuestionPools = `
+ALTER TABLE quiz
+    ADD COLUMN pool_draw INTEGER NOT NULL DEFAULT 0;
+ALTER TABLE quiz
+    ADD COLUMN pool_tags TEXT NOT NULL DEFAULT '';
+
+ALTER TABLE quiz_question
+    ADD COLUMN tags TEXT NOT NULL DEFAULT '';
+
+ALTER TABLE session
+    ADD COLUMN seed INTEGER NOT NULL DEFAULT 0;
+
+CREATE TABLE session_question
+(
+    session_uuid  TEXT NOT NULL,
+    question_sha1 TEXT NOT NULL,
+
+    PRIMARY KEY (session_uuid, question_sha1),
+    FOREIGN KEY (session_uuid) REFERENCES session (uuid),
+    FOREIGN KEY (question_sha1) REFERENCES quiz_question (sha1)
+);
+
+INSERT INTO session_question (session_uuid, question_sha1)
+SELECT s.uuid, qqq.question_sha1
+FROM session s
+         JOIN quiz_question_quiz qqq ON qqq.quiz_sha1 = s.quiz_sha1;
+
+CREATE TRIGGER verify_session_question_create
+    BEFORE INSERT
+    ON session_answer
+BEGIN
+    SELECT CASE
+               WHEN NOT EXISTS (SELECT 1
+                                FROM session_question sq
+                                WHERE sq.session_uuid = new.session_uuid
+                                  AND sq.question_sha1 = new.question_sha1) THEN
+                   RAISE(ABORT, 'question is not part of the session')
+               END;
+END;
+
+CREATE TRIGGER verify_session_question_update
+    BEFORE UPDATE
+    ON session_answer
+BEGIN
+    SELECT CASE
+               WHEN NOT EXISTS (SELECT 1
+                                FROM session_question sq
+                                WHERE sq.session_uuid = new.session_uuid
+                                  AND sq.question_sha1 = new.question_sha1) THEN
+                   RAISE(ABORT, 'question is not part of the session')
+               END;
+END;
+
+DROP VIEW quiz_class_view;
+
+CREATE VIEW quiz_class_view
+AS
+SELECT q.*,
+       CASE WHEN sc.uuid IS NULL THEN '' ELSE sc.uuid END AS class_uuid,
+       CASE WHEN sc.name IS NULL THEN '' ELSE sc.name END AS class_name
+FROM quiz q
+         LEFT JOIN quiz_class_visibility qcv ON q.sha1 = qcv.quiz_sha1
+         LEFT JOIN student_class sc ON sc.uuid = qcv.class_uuid;
+
+DROP VIEW quiz_session_detail_view;
+DROP VIEW session_response_view;
+
+CREATE VIEW session_response_view
+AS
+SELECT qqq.quiz_sha1,
+       qqq.question_sha1,
+       qqa.answer_sha1,
+       s.uuid AS session_uuid,
+       s.user_id,
+       sa.checked,
+       sa.content,
+       sa.position
+FROM quiz_question_quiz qqq
+         JOIN quiz_question_answer qqa ON qqq.question_sha1 = qqa.question_sha1
+         JOIN quiz_answer qa ON qa.sha1 = qqa.answer_sha1
+         LEFT JOIN session s ON qqq.quiz_sha1 = s.quiz_sha1
+         LEFT JOIN session_question sq
+                   ON sq.session_uuid = s.uuid
+                       AND sq.question_sha1 = qqq.question_sha1
+         LEFT JOIN session_answer sa
+                   ON sa.session_uuid = s.uuid
+                       AND qa.sha1 = sa.answer_sha1
+                       AND sa.question_sha1 = qqq.question_sha1
+                       AND sa.answer_sha1 = qqa.answer_sha1
+WHERE s.uuid IS NULL
+   OR sq.session_uuid IS NOT NULL;
+
+CREATE VIEW quiz_session_detail_view
+AS
+SELECT qsv.session_uuid                                          AS session_uuid,
+       qsv.user_id                                               AS user_id,
+       qsv.remaining_sec                                         AS remaining_sec,
+       s.seed                                                    AS session_seed,
+       qsv.quiz_sha1                                             AS quiz_sha1,
+       qsv.quiz_name                                             AS quiz_name,
+       qsv.quiz_duration                                         AS quiz_duration,
+       q.scoring                                                 AS quiz_scoring,
+       srv.question_sha1                                         AS question_sha1,
+       qq.kind                                                   AS question_kind,
+       qq.position                                               AS question_position,
+       qq.content                                                AS question_content,
+       qq.code                                                   AS question_code,
+       qq.code_language                                          AS question_code_language,
+       qq.partial_credit                                         AS question_partial_credit,
+       qq.points                                                 AS question_points,
+       qq.explanation                                            AS question_explanation,
+       srv.answer_sha1                                           AS answer_sha1,
+       qa.content                                                AS answer_content,
+       CASE WHEN srv.checked IS NULL THEN 0 ELSE srv.checked END AS answer_checked,
+       qa.valid                                                  AS answer_valid,
+       qa.match_mode                                             AS answer_match_mode,
+       srv.content                                               AS answer_text,
+       qa.position                                               AS answer_position,
+       srv.position                                              AS answer_answered_position,
+       qqp.right_sha1                                            AS answer_right_sha1,
+       qqp.right_content                                         AS answer_right_content,
+       qqa.explanation                                           AS answer_explanation
+FROM quiz_session_view qsv
+         JOIN quiz q ON qsv.quiz_sha1 = q.sha1
+         JOIN session s ON qsv.session_uuid = s.uuid
+         JOIN session_response_view srv ON qsv.session_uuid = srv.session_uuid
+         JOIN quiz_question qq ON srv.question_sha1 = qq.sha1
+         JOIN quiz_answer qa ON srv.answer_sha1 = qa.sha1
+         JOIN quiz_question_answer qqa
+              ON qqa.question_sha1 = srv.question_sha1
+                  AND qqa.answer_sha1 = srv.answer_sha1
+         LEFT JOIN quiz_question_pair qqp
+                   ON qqp.question_sha1 = srv.question_sha1
+                       AND qqp.answer_sha1 = srv.answer_sha1
+ORDER BY qq.position;
+`
+
+const v12AnswerOrder = `
+ALTER TABLE quiz_question_answer
+    ADD COLUMN ordinal INTEGER NOT NULL DEFAULT 0;
+
+DROP VIEW quiz_session_detail_view;
+
+CREATE VIEW quiz_session_detail_view
+AS
+SELECT qsv.session_uuid                                          AS session_uuid,
+       qsv.user_id                                               AS user_id,
+       qsv.remaining_sec                                         AS remaining_sec,
+       s.seed                                                    AS session_seed,
+       qsv.quiz_sha1                                             AS quiz_sha1,
+       qsv.quiz_name                                             AS quiz_name,
+       qsv.quiz_duration                                         AS quiz_duration,
+       q.scoring                                                 AS quiz_scoring,
+       q.shuffle_questions                                       AS quiz_shuffle_questions,
+       q.shuffle_answers                                         AS quiz_shuffle_answers,
+       srv.question_sha1                                         AS question_sha1,
+       qq.kind                                                   AS question_kind,
+       qq.position                                               AS question_position,
+       qq.content                                                AS question_content,
+       qq.code                                                   AS question_code,
+       qq.code_language                                          AS question_code_language,
+       qq.partial_credit                                         AS question_partial_credit,
+       qq.points                                                 AS question_points,
+       qq.explanation                                            AS question_explanation,
+       srv.answer_sha1                                           AS answer_sha1,
+       qa.content                                                AS answer_content,
+       CASE WHEN srv.checked IS NULL THEN 0 ELSE srv.checked END AS answer_checked,
+       qa.valid                                                  AS answer_valid,
+       qa.match_mode                                             AS answer_match_mode,
+       srv.content                                               AS answer_text,
+       qa.position                                               AS answer_position,
+       srv.position                                              AS answer_answered_position,
+       qqp.right_sha1                                            AS answer_right_sha1,
+       qqp.right_content                                         AS answer_right_content,
+       qqa.explanation                                           AS answer_explanation,
+       qqa.ordinal                                               AS answer_ordinal
+FROM quiz_session_view qsv
+         JOIN quiz q ON qsv.quiz_sha1 = q.sha1
+         JOIN session s ON qsv.session_uuid = s.uuid
+         JOIN session_response_view srv ON qsv.session_uuid = srv.session_uuid
+         JOIN quiz_question qq ON srv.question_sha1 = qq.sha1
+         JOIN quiz_answer qa ON srv.answer_sha1 = qa.sha1
+         JOIN quiz_question_answer qqa
+              ON qqa.question_sha1 = srv.question_sha1
+                  AND qqa.answer_sha1 = srv.answer_sha1
+         LEFT JOIN quiz_question_pair qqp
+                   ON qqp.question_sha1 = srv.question_sha1
+                       AND qqp.answer_sha1 = srv.answer_sha1
+ORDER BY qq.position;
+`
+
+const v13Translations = `
+ALTER TABLE quiz
+    ADD COLUMN lang TEXT NOT NULL DEFAULT '';
+
+ALTER TABLE user
+    ADD COLUMN lang TEXT NOT NULL DEFAULT '';
+
+CREATE TABLE quiz_translation
+(
+    quiz_sha1   TEXT NOT NULL,
+    lang        TEXT NOT NULL,
+    name        TEXT NOT NULL,
+    description TEXT NOT NULL DEFAULT '',
+
+    PRIMARY KEY (quiz_sha1, lang),
+    FOREIGN KEY (quiz_sha1) REFERENCES quiz (sha1)
+);
+
+CREATE TABLE quiz_question_translation
+(
+    quiz_sha1     TEXT NOT NULL,
+    lang          TEXT NOT NULL,
+    question_sha1 TEXT NOT NULL,
+    content       TEXT NOT NULL,
+    code          TEXT NOT NULL DEFAULT '',
+    explanation   TEXT NOT NULL DEFAULT '',
+
+    PRIMARY KEY (quiz_sha1, lang, question_sha1),
+    FOREIGN KEY (quiz_sha1) REFERENCES quiz (sha1),
+    FOREIGN KEY (question_sha1) REFERENCES quiz_question (sha1)
+);
+
+CREATE TABLE quiz_answer_translation
+(
+    quiz_sha1     TEXT NOT NULL,
+    lang          TEXT NOT NULL,
+    question_sha1 TEXT NOT NULL,
+    answer_sha1   TEXT NOT NULL,
+    content       TEXT NOT NULL,
+    right_content TEXT NOT NULL DEFAULT '',
+    explanation   TEXT NOT NULL DEFAULT '',
+
+    PRIMARY KEY (quiz_sha1, lang, question_sha1, answer_sha1),
+    FOREIGN KEY (quiz_sha1) REFERENCES quiz (sha1),
+    FOREIGN KEY (question_sha1) REFERENCES quiz_question (sha1),
+    FOREIGN KEY (answer_sha1) REFERENCES quiz_answer (sha1)
+);
+
+DROP VIEW user_class_view;
+
+CREATE VIEW user_class_view
+AS
+SELECT u.id,
+       u.login,
+       u.name,
+       u.picture,
+       u.active,
+       u.role_id,
+       CASE WHEN u.class_uuid IS NULL THEN '' ELSE u.class_uuid END AS class_uuid,
+       CASE WHEN sc.name IS NULL THEN '' ELSE sc.name END           AS class_name,
+       u.lang
+FROM user u
+         LEFT JOIN student_class sc ON u.class_uuid = sc.uuid;
+
+DROP VIEW quiz_class_view;
+
+CREATE VIEW quiz_class_view
+AS
+SELECT q.*,
+       CASE WHEN sc.uuid IS NULL THEN '' ELSE sc.uuid END AS class_uuid,
+       CASE WHEN sc.name IS NULL THEN '' ELSE sc.name END AS class_name
+FROM quiz q
+         LEFT JOIN quiz_class_visibility qcv ON q.sha1 = qcv.quiz_sha1
+         LEFT JOIN student_class sc ON sc.uuid = qcv.class_uuid;
+
+DROP VIEW quiz_session_detail_view;
+
+CREATE VIEW quiz_session_detail_view
+AS
+SELECT qsv.session_uuid                                          AS session_uuid,
+       qsv.user_id                                               AS user_id,
+       qsv.remaining_sec                                         AS remaining_sec,
+       s.seed                                                    AS session_seed,
+       qsv.quiz_sha1                                             AS quiz_sha1,
+       qsv.quiz_name                                             AS quiz_name,
+       qsv.quiz_duration                                         AS quiz_duration,
+       q.scoring                                                 AS quiz_scoring,
+       q.shuffle_questions                                       AS quiz_shuffle_questions,
+       q.shuffle_answers                                         AS quiz_shuffle_answers,
+       q.lang                                                    AS quiz_lang,
+       srv.question_sha1                                         AS question_sha1,
+       qq.kind                                                   AS question_kind,
+       qq.position                                               AS question_position,
+       qq.content                                                AS question_content,
+       qq.code                                                   AS question_code,
+       qq.code_language                                          AS question_code_language,
+       qq.partial_credit                                         AS question_partial_credit,
+       qq.points                                                 AS question_points,
+       qq.explanation                                            AS question_explanation,
+       srv.answer_sha1                                           AS answer_sha1,
+       qa.content                                                AS answer_content,
+       CASE WHEN srv.checked IS NULL THEN 0 ELSE srv.checked END AS answer_checked,
+       qa.valid                                                  AS answer_valid,
+       qa.match_mode                                             AS answer_match_mode,
+       srv.content                                               AS answer_text,
+       qa.position                                               AS answer_position,
+       srv.position                                              AS answer_answered_position,
+       qqp.right_sha1                                            AS answer_right_sha1,
+       qqp.right_content                                         AS answer_right_content,
+       qqa.explanation                                           AS answer_explanation,
+       qqa.ordinal                                               AS answer_ordinal
+FROM quiz_session_view qsv
+         JOIN quiz q ON qsv.quiz_sha1 = q.sha1
+         JOIN session s ON qsv.session_uuid = s.uuid
+         JOIN session_response_view srv ON qsv.session_uuid = srv.session_uuid
+         JOIN quiz_question qq ON srv.question_sha1 = qq.sha1
+         JOIN quiz_answer qa ON srv.answer_sha1 = qa.sha1
+         JOIN quiz_question_answer qqa
+              ON qqa.question_sha1 = srv.question_sha1
+                  AND qqa.answer_sha1 = srv.answer_sha1
+         LEFT JOIN quiz_question_pair qqp
+                   ON qqp.question_sha1 = srv.question_sha1
+                       AND qqp.answer_sha1 = srv.answer_sha1
+ORDER BY qq.position;
+`
+
+const v14QuestionIds = `
+ALTER TABLE quiz_question_quiz
+    ADD COLUMN position INTEGER NOT NULL DEFAULT 0;
+
+UPDATE quiz_question_quiz
+SET position = (SELECT qq.position FROM quiz_question qq WHERE qq.sha1 = quiz_question_quiz.question_sha1);
+
+CREATE TABLE quiz_question_identity
+(
+    quiz_filename TEXT NOT NULL,
+    question_sha1 TEXT NOT NULL,
+    question_id   TEXT NOT NULL,
+
+    PRIMARY KEY (quiz_filename, question_sha1),
+    FOREIGN KEY (question_sha1) REFERENCES quiz_question (sha1)
+);
+
+INSERT INTO quiz_question_identity (quiz_filename, question_sha1, question_id)
+SELECT DISTINCT q.filename, qqq.question_sha1, qqq.question_sha1
+FROM quiz_question_quiz qqq
+         JOIN quiz q ON q.sha1 = qqq.quiz_sha1;
+
+DROP VIEW quiz_session_detail_view;
+
+ALTER TABLE quiz_question
+    DROP COLUMN position;
+
+CREATE VIEW quiz_session_detail_view
+AS
+SELECT qsv.session_uuid                                          AS session_uuid,
+       qsv.user_id                                               AS user_id,
+       qsv.remaining_sec                                         AS remaining_sec,
+       s.seed                                                    AS session_seed,
+       qsv.quiz_sha1                                             AS quiz_sha1,
+       qsv.quiz_name                                             AS quiz_name,
+       qsv.quiz_duration                                         AS quiz_duration,
+       q.scoring                                                 AS quiz_scoring,
+       q.shuffle_questions                                       AS quiz_shuffle_questions,
+       q.shuffle_answers                                         AS quiz_shuffle_answers,
+       q.lang                                                    AS quiz_lang,
+       srv.question_sha1                                         AS question_sha1,
+       qqi.question_id                                           AS question_id,
+       qq.kind                                                   AS question_kind,
+       qqq.position                                              AS question_position,
+       qq.content                                                AS question_content,
+       qq.code                                                   AS question_code,
+       qq.code_language                                          AS question_code_language,
+       qq.partial_credit                                         AS question_partial_credit,
+       qq.points                                                 AS question_points,
+       qq.explanation                                            AS question_explanation,
+       srv.answer_sha1                                           AS answer_sha1,
+       qa.content                                                AS answer_content,
+       CASE WHEN srv.checked IS NULL THEN 0 ELSE srv.checked END AS answer_checked,
+       qa.valid                                                  AS answer_valid,
+       qa.match_mode                                             AS answer_match_mode,
+       srv.content                                               AS answer_text,
+       qa.position                                               AS answer_position,
+       srv.position                                              AS answer_answered_position,
+       qqp.right_sha1                                            AS answer_right_sha1,
+       qqp.right_content                                         AS answer_right_content,
+       qqa.explanation                                           AS answer_explanation,
+       qqa.ordinal                                               AS answer_ordinal
+FROM quiz_session_view qsv
+         JOIN quiz q ON qsv.quiz_sha1 = q.sha1
+         JOIN session s ON qsv.session_uuid = s.uuid
+         JOIN session_response_view srv ON qsv.session_uuid = srv.session_uuid
+         JOIN quiz_question qq ON srv.question_sha1 = qq.sha1
+         JOIN quiz_question_quiz qqq
+              ON qqq.quiz_sha1 = qsv.quiz_sha1
+                  AND qqq.question_sha1 = srv.question_sha1
+         JOIN quiz_question_identity qqi
+              ON qqi.quiz_filename = q.filename
+                  AND qqi.question_sha1 = srv.question_sha1
+         JOIN quiz_answer qa ON srv.answer_sha1 = qa.sha1
+         JOIN quiz_question_answer qqa
+              ON qqa.question_sha1 = srv.question_sha1
+                  AND qqa.answer_sha1 = srv.answer_sha1
+         LEFT JOIN quiz_question_pair qqp
+                   ON qqp.question_sha1 = srv.question_sha1
+                       AND qqp.answer_sha1 = srv.answer_sha1
+ORDER BY qqq.position;
+`
+
+const v15QuizCategories = `
+ALTER TABLE quiz
+    ADD COLUMN category TEXT NOT NULL DEFAULT '';
+
+DROP VIEW quiz_class_view;
+
+CREATE VIEW quiz_class_view
+AS
+SELECT q.*,
+       CASE WHEN sc.uuid IS NULL THEN '' ELSE sc.uuid END AS class_uuid,
+       CASE WHEN sc.name IS NULL THEN '' ELSE sc.name END AS class_name
+FROM quiz q
+         LEFT JOIN quiz_class_visibility qcv ON q.sha1 = qcv.quiz_sha1
+         LEFT JOIN student_class sc ON sc.uuid = qcv.class_uuid;
+`
+
+const v16QuizSources = `
+PRAGMA foreign_keys = OFF;
+
+DROP TRIGGER verify_remaining_time_create;
+DROP TRIGGER verify_remaining_time_update;
+
+DROP VIEW quiz_class_view;
+DROP VIEW quiz_session_detail_view;
+DROP VIEW quiz_session_view;
+DROP VIEW session_view;
+
+CREATE TABLE quiz_with_source
+(
+    sha1              TEXT PRIMARY KEY,
+    name              TEXT    NOT NULL,
+    filename          TEXT    NOT NULL,
+    version           INTEGER NOT NULL DEFAULT 1,
+    active            INTEGER NOT NULL DEFAULT 1,
+    created_at        TEXT    NOT NULL,
+    duration          INTEGER NOT NULL,
+    description       TEXT    NOT NULL DEFAULT '',
+    tags              TEXT    NOT NULL DEFAULT '',
+    author            TEXT    NOT NULL DEFAULT '',
+    pass_mark         INTEGER NOT NULL DEFAULT 0,
+    shuffle_questions INTEGER NOT NULL DEFAULT 0,
+    shuffle_answers   INTEGER NOT NULL DEFAULT 0,
+    max_attempts      INTEGER NOT NULL DEFAULT 0,
+    scoring           INTEGER NOT NULL DEFAULT 0,
+    pool_draw         INTEGER NOT NULL DEFAULT 0,
+    pool_tags         TEXT    NOT NULL DEFAULT '',
+    lang              TEXT    NOT NULL DEFAULT '',
+    category          TEXT    NOT NULL DEFAULT '',
+    source            TEXT    NOT NULL DEFAULT 'default',
+
+    UNIQUE (source, filename, version)
+);
+
+INSERT INTO quiz_with_source (sha1, name, filename, version, active, created_at, duration, description, tags, author,
+                              pass_mark, shuffle_questions, shuffle_answers, max_attempts, scoring, pool_draw,
+                              pool_tags, lang, category)
+SELECT sha1,
+       name,
+       filename,
+       version,
+       active,
+       created_at,
+       duration,
+       description,
+       tags,
+       author,
+       pass_mark,
+       shuffle_questions,
+       shuffle_answers,
+       max_attempts,
+       scoring,
+       pool_draw,
+       pool_tags,
+       lang,
+       category
+FROM quiz;
+
+DROP TABLE quiz;
+
+ALTER TABLE quiz_with_source
+    RENAME TO quiz;
+
+CREATE TABLE quiz_question_identity_with_source
+(
+    quiz_source   TEXT NOT NULL DEFAULT 'default',
+    quiz_filename TEXT NOT NULL,
+    question_sha1 TEXT NOT NULL,
+    question_id   TEXT NOT NULL,
+
+    PRIMARY KEY (quiz_source, quiz_filename, question_sha1),
+    FOREIGN KEY (question_sha1) REFERENCES quiz_question (sha1)
+);
+
+INSERT INTO quiz_question_identity_with_source (quiz_filename, question_sha1, question_id)
+SELECT quiz_filename, question_sha1, question_id
+FROM quiz_question_identity;
+
+DROP TABLE quiz_question_identity;
+
+ALTER TABLE quiz_question_identity_with_source
+    RENAME TO quiz_question_identity;
+
+CREATE VIEW session_view
+AS
+SELECT s.uuid                                                                                       AS uuid,
+       q.sha1                                                                                       AS quiz_sha1,
+       q.name                                                                                       AS quiz_name,
+       q.active                                                                                     AS quiz_active,
+       u.id                                                                                         AS user_id,
+       u.name                                                                                       AS user_name,
+       u.picture                                                                                    AS user_picture,
+       CAST(MAX(q.duration - (STRFTIME('%s', 'now') - STRFTIME('%s', s.created_at)), 0) AS INTEGER) AS remaining_sec
+FROM session s
+         JOIN quiz q ON q.sha1 = s.quiz_sha1
+         JOIN user u ON u.id = s.user_id;
+
+CREATE VIEW quiz_session_view
+AS
+SELECT q.sha1                                                              AS quiz_sha1,
+       q.name                                                              AS quiz_name,
+       q.filename                                                          AS quiz_filename,
+       q.version                                                           AS quiz_version,
+       q.duration                                                          AS quiz_duration,
+       q.created_at                                                        AS quiz_created_at,
+       CASE WHEN s.uuid IS NULL THEN '' ELSE s.uuid END                    AS session_uuid,
+       CASE WHEN s.user_id IS NULL THEN '' ELSE s.user_id END              AS user_id,
+       CASE WHEN u.name IS NULL THEN '' ELSE u.name END                    AS user_name,
+       CASE WHEN u.picture IS NULL THEN '' ELSE u.picture END              AS user_picture,
+       CASE WHEN sc.uuid IS NULL THEN '' ELSE sc.uuid END                  AS class_uuid,
+       CASE WHEN sc.name IS NULL THEN '' ELSE sc.name END                  AS class_name,
+       CASE WHEN sv.remaining_sec IS NULL THEN 0 ELSE sv.remaining_sec END AS remaining_sec
+FROM quiz q
+         LEFT JOIN session s ON q.sha1 = s.quiz_sha1
+         LEFT JOIN user u ON s.user_id = u.id
+         LEFT JOIN student_class sc ON u.class_uuid = sc.uuid
+         LEFT JOIN session_view sv ON q.sha1 = sv.quiz_sha1 AND s.uuid = sv.uuid
+WHERE q.active = TRUE;
+
+CREATE VIEW quiz_session_detail_view
+AS
+SELECT qsv.session_uuid                                          AS session_uuid,
+       qsv.user_id                                               AS user_id,
+       qsv.remaining_sec                                         AS remaining_sec,
+       s.seed                                                    AS session_seed,
+       qsv.quiz_sha1                                             AS quiz_sha1,
+       qsv.quiz_name                                             AS quiz_name,
+       qsv.quiz_duration                                         AS quiz_duration,
+       q.scoring                                                 AS quiz_scoring,
+       q.shuffle_questions                                       AS quiz_shuffle_questions,
+       q.shuffle_answers                                         AS quiz_shuffle_answers,
+       q.lang                                                    AS quiz_lang,
+       srv.question_sha1                                         AS question_sha1,
+       qqi.question_id                                           AS question_id,
+       qq.kind                                                   AS question_kind,
+       qqq.position                                              AS question_position,
+       qq.content                                                AS question_content,
+       qq.code                                                   AS question_code,
+       qq.code_language                                          AS question_code_language,
+       qq.partial_credit                                         AS question_partial_credit,
+       qq.points                                                 AS question_points,
+       qq.explanation                                            AS question_explanation,
+       srv.answer_sha1                                           AS answer_sha1,
+       qa.content                                                AS answer_content,
+       CASE WHEN srv.checked IS NULL THEN 0 ELSE srv.checked END AS answer_checked,
+       qa.valid                                                  AS answer_valid,
+       qa.match_mode                                             AS answer_match_mode,
+       srv.content                                               AS answer_text,
+       qa.position                                               AS answer_position,
+       srv.position                                              AS answer_answered_position,
+       qqp.right_sha1                                            AS answer_right_sha1,
+       qqp.right_content                                         AS answer_right_content,
+       qqa.explanation                                           AS answer_explanation,
+       qqa.ordinal                                               AS answer_ordinal
+FROM quiz_session_view qsv
+         JOIN quiz q ON qsv.quiz_sha1 = q.sha1
+         JOIN session s ON qsv.session_uuid = s.uuid
+         JOIN session_response_view srv ON qsv.session_uuid = srv.session_uuid
+         JOIN quiz_question qq ON srv.question_sha1 = qq.sha1
+         JOIN quiz_question_quiz qqq
+              ON qqq.quiz_sha1 = qsv.quiz_sha1
+                  AND qqq.question_sha1 = srv.question_sha1
+         JOIN quiz_question_identity qqi
+              ON qqi.quiz_source = q.source
+                  AND qqi.quiz_filename = q.filename
+                  AND qqi.question_sha1 = srv.question_sha1
+         JOIN quiz_answer qa ON srv.answer_sha1 = qa.sha1
+         JOIN quiz_question_answer qqa
+              ON qqa.question_sha1 = srv.question_sha1
+                  AND qqa.answer_sha1 = srv.answer_sha1
+         LEFT JOIN quiz_question_pair qqp
+                   ON qqp.question_sha1 = srv.question_sha1
+                       AND qqp.answer_sha1 = srv.answer_sha1
+ORDER BY qqq.position;
+
+CREATE VIEW quiz_class_view
+AS
+SELECT q.*,
+       CASE WHEN sc.uuid IS NULL THEN '' ELSE sc.uuid END AS class_uuid,
+       CASE WHEN sc.name IS NULL THEN '' ELSE sc.name END AS class_name
+FROM quiz q
+         LEFT JOIN quiz_class_visibility qcv ON q.sha1 = qcv.quiz_sha1
+         LEFT JOIN student_class sc ON sc.uuid = qcv.class_uuid;
+
+CREATE TRIGGER verify_remaining_time_create
+    BEFORE INSERT
+    ON session_answer
+BEGIN
+    SELECT CASE
+               WHEN (SELECT remaining_sec FROM session_view sv WHERE sv.uuid = new.session_uuid) = 0 THEN
+                   RAISE(ABORT, 'session is over')
+               END;
+END;
+
+CREATE TRIGGER verify_remaining_time_update
+    BEFORE UPDATE
+    ON session_answer
+BEGIN
+    SELECT CASE
+               WHEN (SELECT remaining_sec FROM session_view sv WHERE sv.uuid = new.session_uuid) = 0 THEN
+                   RAISE(ABORT, 'session is over')
+               END;
+END;
+
+PRAGMA foreign_keys = ON;
+`
+
+const v17AnswerPositions = `
+PRAGMA foreign_keys = OFF;
+
+DROP VIEW quiz_session_detail_view;
+DROP VIEW session_response_view;
+
+CREATE TABLE quiz_question_answer_with_position
+(
+    question_sha1 TEXT    NOT NULL,
+    answer_sha1   TEXT    NOT NULL,
+    explanation   TEXT    NOT NULL DEFAULT '',
+    ordinal       INTEGER NOT NULL DEFAULT 0,
+    position      INTEGER NOT NULL DEFAULT 0,
+
+    PRIMARY KEY (question_sha1, answer_sha1),
+    FOREIGN KEY (question_sha1) REFERENCES quiz_question (sha1),
+    FOREIGN KEY (answer_sha1) REFERENCES quiz_answer (sha1)
+);
+
+INSERT OR REPLACE INTO quiz_question_answer_with_position (question_sha1, answer_sha1, explanation, ordinal, position)
+SELECT qqa.question_sha1,
+       qqa.answer_sha1,
+       qqa.explanation,
+       qqa.ordinal,
+       qa.position
+FROM quiz_question_answer qqa
+         JOIN quiz_answer qa ON qa.sha1 = qqa.answer_sha1
+ORDER BY qqa.rowid;
+
+DROP TABLE quiz_question_answer;
+
+ALTER TABLE quiz_question_answer_with_position
+    RENAME TO quiz_question_answer;
+
+ALTER TABLE quiz_answer
+    DROP COLUMN position;
+
+CREATE VIEW session_response_view
+AS
+SELECT qqq.quiz_sha1,
+       qqq.question_sha1,
+       qqa.answer_sha1,
+       s.uuid AS session_uuid,
+       s.user_id,
+       sa.checked,
+       sa.content,
+       sa.position
+FROM quiz_question_quiz qqq
+         JOIN quiz_question_answer qqa ON qqq.question_sha1 = qqa.question_sha1
+         JOIN quiz_answer qa ON qa.sha1 = qqa.answer_sha1
+         LEFT JOIN session s ON qqq.quiz_sha1 = s.quiz_sha1
+         LEFT JOIN session_question sq
+                   ON sq.session_uuid = s.uuid
+                       AND sq.question_sha1 = qqq.question_sha1
+         LEFT JOIN session_answer sa
+                   ON sa.session_uuid = s.uuid
+                       AND qa.sha1 = sa.answer_sha1
+                       AND sa.question_sha1 = qqq.question_sha1
+                       AND sa.answer_sha1 = qqa.answer_sha1
+WHERE s.uuid IS NULL
+   OR sq.session_uuid IS NOT NULL;
+
+CREATE VIEW quiz_session_detail_view
+AS
+SELECT qsv.session_uuid                                          AS session_uuid,
+       qsv.user_id                                               AS user_id,
+       qsv.remaining_sec                                         AS remaining_sec,
+       s.seed                                                    AS session_seed,
+       qsv.quiz_sha1                                             AS quiz_sha1,
+       qsv.quiz_name                                             AS quiz_name,
+       qsv.quiz_duration                                         AS quiz_duration,
+       q.scoring                                                 AS quiz_scoring,
+       q.shuffle_questions                                       AS quiz_shuffle_questions,
+       q.shuffle_answers                                         AS quiz_shuffle_answers,
+       q.lang                                                    AS quiz_lang,
+       srv.question_sha1                                         AS question_sha1,
+       qqi.question_id                                           AS question_id,
+       qq.kind                                                   AS question_kind,
+       qqq.position                                              AS question_position,
+       qq.content                                                AS question_content,
+       qq.code                                                   AS question_code,
+       qq.code_language                                          AS question_code_language,
+       qq.partial_credit                                         AS question_partial_credit,
+       qq.points                                                 AS question_points,
+       qq.explanation                                            AS question_explanation,
+       srv.answer_sha1                                           AS answer_sha1,
+       qa.content                                                AS answer_content,
+       CASE WHEN srv.checked IS NULL THEN 0 ELSE srv.checked END AS answer_checked,
+       qa.valid                                                  AS answer_valid,
+       qa.match_mode                                             AS answer_match_mode,
+       srv.content                                               AS answer_text,
+       qqa.position                                              AS answer_position,
+       srv.position                                              AS answer_answered_position,
+       qqp.right_sha1                                            AS answer_right_sha1,
+       qqp.right_content                                         AS answer_right_content,
+       qqa.explanation                                           AS answer_explanation,
+       qqa.ordinal                                               AS answer_ordinal
+FROM quiz_session_view qsv
+         JOIN quiz q ON qsv.quiz_sha1 = q.sha1
+         JOIN session s ON qsv.session_uuid = s.uuid
+         JOIN session_response_view srv ON qsv.session_uuid = srv.session_uuid
+         JOIN quiz_question qq ON srv.question_sha1 = qq.sha1
+         JOIN quiz_question_quiz qqq
+              ON qqq.quiz_sha1 = qsv.quiz_sha1
+                  AND qqq.question_sha1 = srv.question_sha1
+         JOIN quiz_question_identity qqi
+              ON qqi.quiz_source = q.source
+                  AND qqi.quiz_filename = q.filename
+                  AND qqi.question_sha1 = srv.question_sha1
+         JOIN quiz_answer qa ON srv.answer_sha1 = qa.sha1
+         JOIN quiz_question_answer qqa
+              ON qqa.question_sha1 = srv.question_sha1
+                  AND qqa.answer_sha1 = srv.answer_sha1
+         LEFT JOIN quiz_question_pair qqp
+                   ON qqp.question_sha1 = srv.question_sha1
+                       AND qqp.answer_sha1 = srv.answer_sha1
+ORDER BY qqq.position;
+
+PRAGMA foreign_keys = ON;
+`
+
+const v18QuizQuestions = `
+PRAGMA foreign_keys = OFF;
+
+DROP VIEW quiz_session_detail_view;
+DROP VIEW session_response_view;
+
+CREATE TABLE quiz_question_quiz_with_content
+(
+    quiz_sha1      TEXT    NOT NULL,
+    question_sha1  TEXT    NOT NULL,
+    position       INTEGER NOT NULL DEFAULT 0,
+    kind           INTEGER NOT NULL DEFAULT 0,
+    content        TEXT    NOT NULL,
+    code           TEXT,
+    code_language  TEXT,
+    partial_credit INTEGER NOT NULL DEFAULT 0,
+    points         INTEGER NOT NULL DEFAULT 1,
+    explanation    TEXT    NOT NULL DEFAULT '',
+    tags           TEXT    NOT NULL DEFAULT '',
+
+    PRIMARY KEY (quiz_sha1, question_sha1),
+    FOREIGN KEY (quiz_sha1) REFERENCES quiz (sha1),
+    FOREIGN KEY (question_sha1) REFERENCES quiz_question (sha1)
+);
+
+INSERT INTO quiz_question_quiz_with_content (quiz_sha1, question_sha1, position, kind, content, code, code_language,
+                                             partial_credit, points, explanation, tags)
+SELECT qqq.quiz_sha1,
+       qqq.question_sha1,
+       qqq.position,
+       qq.kind,
+       qq.content,
+       qq.code,
+       qq.code_language,
+       qq.partial_credit,
+       qq.points,
+       qq.explanation,
+       qq.tags
+FROM quiz_question_quiz qqq
+         JOIN quiz_question qq ON qq.sha1 = qqq.question_sha1;
+
+DROP TABLE quiz_question_quiz;
+
+ALTER TABLE quiz_question_quiz_with_content
+    RENAME TO quiz_question_quiz;
+
+CREATE TABLE quiz_question_answer_with_content
+(
+    quiz_sha1     TEXT    NOT NULL,
+    question_sha1 TEXT    NOT NULL,
+    answer_sha1   TEXT    NOT NULL,
+    content       TEXT    NOT NULL,
+    valid         INTEGER NOT NULL,
+    match_mode    INTEGER NOT NULL DEFAULT 0,
+    explanation   TEXT    NOT NULL DEFAULT '',
+    ordinal       INTEGER NOT NULL DEFAULT 0,
+    position      INTEGER NOT NULL DEFAULT 0,
+
+    PRIMARY KEY (quiz_sha1, question_sha1, answer_sha1),
+    FOREIGN KEY (quiz_sha1) REFERENCES quiz (sha1),
+    FOREIGN KEY (question_sha1) REFERENCES quiz_question (sha1),
+    FOREIGN KEY (answer_sha1) REFERENCES quiz_answer (sha1)
+);
+
+INSERT INTO quiz_question_answer_with_content (quiz_sha1, question_sha1, answer_sha1, content, valid, match_mode,
+                                               explanation, ordinal, position)
+SELECT qqq.quiz_sha1,
+       qqa.question_sha1,
+       qqa.answer_sha1,
+       qa.content,
+       qa.valid,
+       qa.match_mode,
+       qqa.explanation,
+       qqa.ordinal,
+       qqa.position
+FROM quiz_question_quiz qqq
+         JOIN quiz_question_answer qqa ON qqa.question_sha1 = qqq.question_sha1
+         JOIN quiz_answer qa ON qa.sha1 = qqa.answer_sha1;
+
+DROP TABLE quiz_question_answer;
+
+ALTER TABLE quiz_question_answer_with_content
+    RENAME TO quiz_question_answer;
+
+CREATE TABLE quiz_question_pair_with_quiz
+(
+    quiz_sha1     TEXT NOT NULL,
+    question_sha1 TEXT NOT NULL,
+    answer_sha1   TEXT NOT NULL,
+    right_sha1    TEXT NOT NULL,
+    right_content TEXT NOT NULL,
+
+    PRIMARY KEY (quiz_sha1, question_sha1, answer_sha1),
+    FOREIGN KEY (quiz_sha1) REFERENCES quiz (sha1),
+    FOREIGN KEY (question_sha1) REFERENCES quiz_question (sha1),
+    FOREIGN KEY (answer_sha1) REFERENCES quiz_answer (sha1)
+);
+
+INSERT INTO quiz_question_pair_with_quiz (quiz_sha1, question_sha1, answer_sha1, right_sha1, right_content)
+SELECT qqq.quiz_sha1,
+       qqp.question_sha1,
+       qqp.answer_sha1,
+       qqp.right_sha1,
+       qqp.right_content
+FROM quiz_question_quiz qqq
+         JOIN quiz_question_pair qqp ON qqp.question_sha1 = qqq.question_sha1;
+
+DROP TABLE quiz_question_pair;
+
+ALTER TABLE quiz_question_pair_with_quiz
+    RENAME TO quiz_question_pair;
+
+ALTER TABLE quiz_question
+    DROP COLUMN content;
+ALTER TABLE quiz_question
+    DROP COLUMN code;
+ALTER TABLE quiz_question
+    DROP COLUMN code_language;
+ALTER TABLE quiz_question
+    DROP COLUMN kind;
+ALTER TABLE quiz_question
+    DROP COLUMN partial_credit;
+ALTER TABLE quiz_question
+    DROP COLUMN points;
+ALTER TABLE quiz_question
+    DROP COLUMN explanation;
+ALTER TABLE quiz_question
+    DROP COLUMN tags;
+
+ALTER TABLE quiz_answer
+    DROP COLUMN valid;
+ALTER TABLE quiz_answer
+    DROP COLUMN content;
+ALTER TABLE quiz_answer
+    DROP COLUMN match_mode;
+
+CREATE VIEW session_response_view
+AS
+SELECT qqq.quiz_sha1,
+       qqq.question_sha1,
+       qqa.answer_sha1,
+       s.uuid AS session_uuid,
+       s.user_id,
+       sa.checked,
+       sa.content,
+       sa.position
+FROM quiz_question_quiz qqq
+         JOIN quiz_question_answer qqa
+              ON qqa.quiz_sha1 = qqq.quiz_sha1
+                  AND qqa.question_sha1 = qqq.question_sha1
+         LEFT JOIN session s ON qqq.quiz_sha1 = s.quiz_sha1
+         LEFT JOIN session_question sq
+                   ON sq.session_uuid = s.uuid
+                       AND sq.question_sha1 = qqq.question_sha1
+         LEFT JOIN session_answer sa
+                   ON sa.session_uuid = s.uuid
+                       AND sa.question_sha1 = qqq.question_sha1
+                       AND sa.answer_sha1 = qqa.answer_sha1
+WHERE s.uuid IS NULL
+   OR sq.session_uuid IS NOT NULL;
+
+CREATE VIEW quiz_session_detail_view
+AS
+SELECT qsv.session_uuid                                          AS session_uuid,
+       qsv.user_id                                               AS user_id,
+       qsv.remaining_sec                                         AS remaining_sec,
+       s.seed                                                    AS session_seed,
+       qsv.quiz_sha1                                             AS quiz_sha1,
+       qsv.quiz_name                                             AS quiz_name,
+       qsv.quiz_duration                                         AS quiz_duration,
+       q.scoring                                                 AS quiz_scoring,
+       q.shuffle_questions                                       AS quiz_shuffle_questions,
+       q.shuffle_answers                                         AS quiz_shuffle_answers,
+       q.lang                                                    AS quiz_lang,
+       srv.question_sha1                                         AS question_sha1,
+       qqi.question_id                                           AS question_id,
+       qqq.kind                                                  AS question_kind,
+       qqq.position                                              AS question_position,
+       qqq.content                                               AS question_content,
+       qqq.code                                                  AS question_code,
+       qqq.code_language                                         AS question_code_language,
+       qqq.partial_credit                                        AS question_partial_credit,
+       qqq.points                                                AS question_points,
+       qqq.explanation                                           AS question_explanation,
+       srv.answer_sha1                                           AS answer_sha1,
+       qqa.content                                               AS answer_content,
+       CASE WHEN srv.checked IS NULL THEN 0 ELSE srv.checked END AS answer_checked,
+       qqa.valid                                                 AS answer_valid,
+       qqa.match_mode                                            AS answer_match_mode,
+       srv.content                                               AS answer_text,
+       qqa.position                                              AS answer_position,
+       srv.position                                              AS answer_answered_position,
+       qqp.right_sha1                                            AS answer_right_sha1,
+       qqp.right_content                                         AS answer_right_content,
+       qqa.explanation                                           AS answer_explanation,
+       qqa.ordinal                                               AS answer_ordinal
+FROM quiz_session_view qsv
+         JOIN quiz q ON qsv.quiz_sha1 = q.sha1
+         JOIN session s ON qsv.session_uuid = s.uuid
+         JOIN session_response_view srv ON qsv.session_uuid = srv.session_uuid
+         JOIN quiz_question_quiz qqq
+              ON qqq.quiz_sha1 = qsv.quiz_sha1
+                  AND qqq.question_sha1 = srv.question_sha1
+         JOIN quiz_question_identity qqi
+              ON qqi.quiz_source = q.source
+                  AND qqi.quiz_filename = q.filename
+                  AND qqi.question_sha1 = srv.question_sha1
+         JOIN quiz_question_answer qqa
+              ON qqa.quiz_sha1 = qsv.quiz_sha1
+                  AND qqa.question_sha1 = srv.question_sha1
+                  AND qqa.answer_sha1 = srv.answer_sha1
+         LEFT JOIN quiz_question_pair qqp
+                   ON qqp.quiz_sha1 = qsv.quiz_sha1
+                       AND qqp.question_sha1 = srv.question_sha1
+                       AND qqp.answer_sha1 = srv.answer_sha1
+ORDER BY qqq.position;
+
+PRAGMA foreign_keys = ON;
+`
+
+const v19QuizIds = `
+ALTER TABLE quiz
+    ADD COLUMN id TEXT NOT NULL DEFAULT '';
+
+DROP VIEW quiz_class_view;
+
+CREATE VIEW quiz_class_view
+AS
+SELECT q.*,
+       CASE WHEN sc.uuid IS NULL THEN '' ELSE sc.uuid END AS class_uuid,
+       CASE WHEN sc.name IS NULL THEN '' ELSE sc.name END AS class_name
+FROM quiz q
+         LEFT JOIN quiz_class_visibility qcv ON q.sha1 = qcv.quiz_sha1
+         LEFT JOIN student_class sc ON sc.uuid = qcv.class_uuid;
+`
+
 var migrations = map[int]string{
-	1: v1Init,
-	2: v2FixQuizSessionView,
+	1:  v1Init,
+	2:  v2FixQuizSessionView,
+	3:  v3QuizMetadata,
+	4:  v4ShortAnswer,
+	5:  v5Ordering,
+	6:  v6Matching,
+	7:  v7QuestionPoints,
+	8:  v8ScoringStrategy,
+	9:  v9Explanations,
+	10: v10Assets,
+	11: v11QuestionPools,
+	12: v12AnswerOrder,
+	13: v13Translations,
+	14: v14QuestionIds,
+	15: v15QuizCategories,
+	16: v16QuizSources,
+	17: v17AnswerPositions,
+	18: v18QuizQuestions,
+	19: v19QuizIds,
 }
 
 var migrationVersions = []int{
 	1,
 	2,
+	3,
+	4,
+	5,
+	6,
+	7,
+	8,
+	9,
+	10,
+	11,
+	12,
+	13,
+	14,
+	15,
+	16,
+	17,
+	18,
+	19,
 }
 
 type DB interface {
@@ -18,6 +18,7 @@ package infrastructure
 
 import (
 	"sort"
+	"strings"
 
 	"github.com/michaelcoll/quiz-app/internal/back/domain"
 	"github.com/michaelcoll/quiz-app/internal/back/infrastructure/sqlc"
@@ -26,15 +27,62 @@ import (
 func (r *QuizDBRepository) toQuiz(entity sqlc.Quiz) *domain.Quiz {
 	return &domain.Quiz{
 		Sha1:      entity.Sha1,
+		Source:    entity.Source,
 		Filename:  entity.Filename,
 		Name:      entity.Name,
+		Category:  entity.Category,
 		Version:   entity.Version,
 		Duration:  entity.Duration,
 		Active:    entity.Active,
 		CreatedAt: entity.CreatedAt,
+		Metadata: domain.QuizMetadata{
+			Description:      entity.Description,
+			Tags:             toTags(entity.Tags),
+			Author:           entity.Author,
+			PassMark:         entity.PassMark,
+			ShuffleQuestions: entity.ShuffleQuestions,
+			ShuffleAnswers:   entity.ShuffleAnswers,
+			MaxAttempts:      entity.MaxAttempts,
+			Scoring:          domain.ScoringStrategy(entity.Scoring),
+			Pool:             domain.QuizPool{Draw: entity.PoolDraw, Tags: toTags(entity.PoolTags)},
+			Lang:             entity.Lang,
+			Id:               entity.ID,
+		},
 	}
 }
 
+// toTags splits the comma separated tags stored in the quiz and quiz_question tables
+func toTags(tags string) []string {
+	if tags == "" {
+		return nil
+	}
+
+	return strings.Split(tags, ",")
+}
+
+func fromTags(tags []string) string {
+	return strings.Join(tags, ",")
+}
+
+// questionId gives the id stored in the quiz_question_identity table, a question without id being
+// identified by its sha1
+func questionId(question domain.QuizQuestion) string {
+	if question.Id == "" {
+		return question.Sha1
+	}
+
+	return question.Id
+}
+
+// toRightItems creates the map holding the right-hand items of a Matching question
+func toRightItems(kind int8) map[string]string {
+	if domain.QuestionKind(kind) != domain.Matching {
+		return nil
+	}
+
+	return map[string]string{}
+}
+
 func (r *QuizDBRepository) toSession(entity sqlc.SessionView) *domain.Session {
 
 	d := domain.Session{
@@ -47,13 +95,6 @@ func (r *QuizDBRepository) toSession(entity sqlc.SessionView) *domain.Session {
 		RemainingSec: entity.RemainingSec,
 	}
 
-	if entity.RemainingSec == 0 {
-		d.Result = &domain.SessionResult{
-			GoodAnswer:  entity.Results,
-			TotalAnswer: entity.CheckedAnswers,
-		}
-	}
-
 	return &d
 }
 
@@ -91,13 +132,6 @@ func (r *QuizDBRepository) toQuizSession(entity sqlc.QuizSessionView, userId str
 			RemainingSec: entity.RemainingSec,
 		}
 
-		if entity.RemainingSec == 0 {
-			userSession.Result = &domain.SessionResult{
-				GoodAnswer:  entity.Results,
-				TotalAnswer: entity.CheckedAnswers,
-			}
-		}
-
 		if userSession.UserId != "" {
 			userSessions := make([]*domain.UserSession, 1)
 			d.UserSessions = userSessions
@@ -133,3 +167,129 @@ func (r *QuizDBRepository) toQuizSessionArray(entities []sqlc.QuizSessionView, u
 
 	return domains
 }
+
+// toTranslations gathers the translated texts of a quiz, its questions and its answers by language
+func (r *QuizDBRepository) toTranslations(quizEntities []sqlc.QuizTranslation, questionEntities []sqlc.QuizQuestionTranslation, answerEntities []sqlc.QuizAnswerTranslation) map[string]domain.QuizTranslation {
+	translations := make(map[string]domain.QuizTranslation, len(quizEntities))
+	for _, entity := range quizEntities {
+		translations[entity.Lang] = domain.QuizTranslation{
+			Name:        entity.Name,
+			Description: entity.Description,
+			Questions:   map[string]domain.QuestionTranslation{},
+		}
+	}
+
+	for _, entity := range questionEntities {
+		if translation, found := translations[entity.Lang]; found {
+			translation.Questions[entity.QuestionSha1] = domain.QuestionTranslation{
+				Content:     entity.Content,
+				Code:        entity.Code,
+				Explanation: entity.Explanation,
+				Answers:     map[string]domain.AnswerTranslation{},
+			}
+		}
+	}
+
+	for _, entity := range answerEntities {
+		if question, found := translations[entity.Lang].Questions[entity.QuestionSha1]; found {
+			question.Answers[entity.AnswerSha1] = domain.AnswerTranslation{
+				Content:     entity.Content,
+				Right:       entity.RightContent,
+				Explanation: entity.Explanation,
+			}
+		}
+	}
+
+	return translations
+}
+
+// toQuizSessionDetail maps the rows of a session. The expected answers are only revealed once the
+// session is over
+func toQuizSessionDetail(details []sqlc.QuizSessionDetailView) *domain.QuizSessionDetail {
+
+	sessionDetail := domain.QuizSessionDetail{}
+
+	for _, entity := range details {
+		if sessionDetail.QuizSha1 == "" {
+			sessionDetail.SessionId = entity.SessionUuid
+			sessionDetail.UserId = entity.UserID
+			sessionDetail.RemainingSec = entity.RemainingSec
+			sessionDetail.Seed = entity.SessionSeed
+			sessionDetail.QuizSha1 = entity.QuizSha1
+			sessionDetail.Name = entity.QuizName
+			sessionDetail.QuizDuration = entity.QuizDuration
+			sessionDetail.Scoring = domain.ScoringStrategy(entity.QuizScoring)
+			sessionDetail.ShuffleQuestions = entity.QuizShuffleQuestions
+			sessionDetail.ShuffleAnswers = entity.QuizShuffleAnswers
+			sessionDetail.Lang = entity.QuizLang
+			sessionDetail.Questions = map[string]domain.QuizQuestion{}
+		}
+
+		kind := domain.QuestionKind(entity.QuestionKind)
+
+		if _, found := sessionDetail.Questions[entity.QuestionSha1]; !found {
+			newQuestion := domain.QuizQuestion{
+				Sha1:          entity.QuestionSha1,
+				Id:            entity.QuestionID,
+				Kind:          kind,
+				Position:      entity.QuestionPosition,
+				Content:       entity.QuestionContent,
+				Code:          entity.QuestionCode.String,
+				CodeLanguage:  entity.QuestionCodeLanguage.String,
+				PartialCredit: entity.QuestionPartialCredit,
+				Points:        entity.QuestionPoints,
+				RightItems:    toRightItems(entity.QuestionKind),
+				Answers:       map[string]domain.QuizQuestionAnswer{},
+			}
+			if kind == domain.ShortAnswer || kind == domain.Numeric {
+				newQuestion.Text = entity.AnswerText.String
+			}
+			if sessionDetail.RemainingSec == 0 {
+				newQuestion.Explanation = entity.QuestionExplanation
+			}
+			sessionDetail.Questions[entity.QuestionSha1] = newQuestion
+		}
+
+		// the expected answers of a short-answer or numeric question are only revealed once the session is over
+		if (kind == domain.ShortAnswer || kind == domain.Numeric) && sessionDetail.RemainingSec > 0 {
+			continue
+		}
+
+		if entity.AnswerRightSha1.Valid {
+			sessionDetail.Questions[entity.QuestionSha1].RightItems[entity.AnswerRightSha1.String] = entity.AnswerRightContent.String
+		}
+
+		answerValid := false
+		answerPosition := 0
+		answerRight := ""
+		answerExplanation := ""
+		if sessionDetail.RemainingSec == 0 {
+			answerValid = entity.AnswerValid
+			answerPosition = entity.AnswerPosition
+			answerRight = entity.AnswerRightContent.String
+			answerExplanation = entity.AnswerExplanation
+		}
+
+		answeredRight := ""
+		if kind == domain.Matching {
+			answeredRight = entity.AnswerText.String
+		}
+
+		sessionDetail.Questions[entity.QuestionSha1].Answers[entity.AnswerSha1] = domain.QuizQuestionAnswer{
+			Sha1:             entity.AnswerSha1,
+			Content:          entity.AnswerContent,
+			Checked:          entity.AnswerChecked,
+			Valid:            answerValid,
+			Match:            domain.AnswerMatch(entity.AnswerMatchMode),
+			Position:         answerPosition,
+			AnsweredPosition: int(entity.AnswerAnsweredPosition.Int64),
+			Right:            answerRight,
+			RightSha1:        entity.AnswerRightSha1.String,
+			AnsweredRight:    answeredRight,
+			Explanation:      answerExplanation,
+			Ordinal:          entity.AnswerOrdinal,
+		}
+	}
+
+	return &sessionDetail
+}
@@ -46,8 +46,8 @@ func isAdmin(userId string) bool {
 
 func (r *QuizDBRepository) FindFullBySha1(ctx context.Context, sha1 string, userId string) (*domain.Quiz, error) {
 	entities, err := r.w.queries().FindQuizFullBySha1(ctx, sqlc.FindQuizFullBySha1Params{
-		Sha1: sha1,
-		ID:   userId,
+		Sha1:   sha1,
+		UserId: userId,
 	})
 	if err != nil {
 		return nil, err
@@ -64,39 +64,116 @@ func (r *QuizDBRepository) FindFullBySha1(ctx context.Context, sha1 string, user
 			quiz.Sha1 = entity.QuizSha1
 			quiz.Filename = entity.QuizFilename
 			quiz.Name = entity.QuizName
+			quiz.Category = entity.QuizCategory
+			quiz.Source = entity.QuizSource
 			quiz.Active = entity.QuizActive
 			quiz.Version = entity.QuizVersion
 			quiz.Duration = entity.QuizDuration
 			quiz.CreatedAt = entity.QuizCreatedAt
+			quiz.Metadata = domain.QuizMetadata{
+				Description:      entity.QuizDescription,
+				Tags:             toTags(entity.QuizTags),
+				Author:           entity.QuizAuthor,
+				PassMark:         entity.QuizPassMark,
+				ShuffleQuestions: entity.QuizShuffleQuestions,
+				ShuffleAnswers:   entity.QuizShuffleAnswers,
+				MaxAttempts:      entity.QuizMaxAttempts,
+				Scoring:          domain.ScoringStrategy(entity.QuizScoring),
+				Pool:             domain.QuizPool{Draw: entity.QuizPoolDraw, Tags: toTags(entity.QuizPoolTags)},
+				Lang:             entity.QuizLang,
+				Id:               entity.QuizID,
+			}
 			quiz.Questions = map[string]domain.QuizQuestion{}
 		}
 
 		if _, found := quiz.Questions[entity.QuestionSha1]; !found {
 			newQuestion := domain.QuizQuestion{
-				Sha1:         entity.QuestionSha1,
-				Position:     entity.QuestionPosition,
-				Content:      entity.QuestionContent,
-				Code:         entity.QuestionCode.String,
-				CodeLanguage: entity.QuestionCodeLanguage.String,
-				Answers:      map[string]domain.QuizQuestionAnswer{},
+				Sha1:          entity.QuestionSha1,
+				Id:            entity.QuestionID,
+				Kind:          domain.QuestionKind(entity.QuestionKind),
+				Position:      entity.QuestionPosition,
+				Content:       entity.QuestionContent,
+				Code:          entity.QuestionCode.String,
+				CodeLanguage:  entity.QuestionCodeLanguage.String,
+				PartialCredit: entity.QuestionPartialCredit,
+				Points:        entity.QuestionPoints,
+				Explanation:   entity.QuestionExplanation,
+				Tags:          toTags(entity.QuestionTags),
+				RightItems:    toRightItems(entity.QuestionKind),
+				Answers:       map[string]domain.QuizQuestionAnswer{},
 			}
 			quiz.Questions[entity.QuestionSha1] = newQuestion
 		}
 
 		quiz.Questions[entity.QuestionSha1].Answers[entity.AnswerSha1] = domain.QuizQuestionAnswer{
-			Sha1:    entity.AnswerSha1,
-			Content: entity.AnswerContent,
-			Valid:   entity.AnswerValid,
+			Sha1:        entity.AnswerSha1,
+			Content:     entity.AnswerContent,
+			Valid:       entity.AnswerValid,
+			Match:       domain.AnswerMatch(entity.AnswerMatchMode),
+			Position:    entity.AnswerPosition,
+			Right:       entity.AnswerRightContent.String,
+			RightSha1:   entity.AnswerRightSha1.String,
+			Explanation: entity.AnswerExplanation,
+			Ordinal:     entity.AnswerOrdinal,
+		}
+
+		if entity.AnswerRightSha1.Valid {
+			quiz.Questions[entity.QuestionSha1].RightItems[entity.AnswerRightSha1.String] = entity.AnswerRightContent.String
 		}
 	}
 
 	return &quiz, nil
 }
 
-func (r *QuizDBRepository) FindAllActive(ctx context.Context, userId string, limit uint16, offset uint16) ([]*domain.Quiz, error) {
+func (r *QuizDBRepository) FindSessionQuestion(ctx context.Context, sessionUuid uuid.UUID, questionSha1 string) (*domain.QuizQuestion, error) {
+	entities, err := r.w.queries().FindSessionQuestion(ctx, sqlc.FindSessionQuestionParams{
+		SessionUuid:  sessionUuid,
+		QuestionSha1: questionSha1,
+	})
+	if err != nil {
+		return nil, err
+	}
+
+	if len(entities) == 0 {
+		return nil, nil
+	}
+
+	question := domain.QuizQuestion{
+		Sha1:          entities[0].QuestionSha1,
+		Kind:          domain.QuestionKind(entities[0].QuestionKind),
+		Content:       entities[0].QuestionContent,
+		Code:          entities[0].QuestionCode.String,
+		CodeLanguage:  entities[0].QuestionCodeLanguage.String,
+		PartialCredit: entities[0].QuestionPartialCredit,
+		Points:        entities[0].QuestionPoints,
+		RightItems:    toRightItems(entities[0].QuestionKind),
+		Answers:       map[string]domain.QuizQuestionAnswer{},
+	}
+
+	for _, entity := range entities {
+		question.Answers[entity.AnswerSha1] = domain.QuizQuestionAnswer{
+			Sha1:      entity.AnswerSha1,
+			Content:   entity.AnswerContent,
+			Valid:     entity.AnswerValid,
+			Match:     domain.AnswerMatch(entity.AnswerMatchMode),
+			Position:  entity.AnswerPosition,
+			Right:     entity.AnswerRightContent.String,
+			RightSha1: entity.AnswerRightSha1.String,
+		}
+
+		if entity.AnswerRightSha1.Valid {
+			question.RightItems[entity.AnswerRightSha1.String] = entity.AnswerRightContent.String
+		}
+	}
+
+	return &question, nil
+}
+
+func (r *QuizDBRepository) FindAllActive(ctx context.Context, userId string, category string, limit uint16, offset uint16) ([]*domain.Quiz, error) {
 	entities, err := r.w.queries().FindAllActiveQuiz(ctx, sqlc.FindAllActiveQuizParams{
-		Limit:  int64(limit),
-		Offset: int64(offset),
+		Category: category,
+		Limit:    int64(limit),
+		Offset:   int64(offset),
 	})
 	if err != nil {
 		return nil, err
@@ -109,8 +186,22 @@ func (r *QuizDBRepository) FindAllActive(ctx context.Context, userId string, lim
 			domainsMap[entity.Sha1] = &domain.Quiz{
 				Sha1:     entity.Sha1,
 				Name:     entity.Name,
+				Category: entity.Category,
 				Duration: entity.Duration,
-				Classes:  map[uuid.UUID]string{},
+				Metadata: domain.QuizMetadata{
+					Description:      entity.Description,
+					Tags:             toTags(entity.Tags),
+					Author:           entity.Author,
+					PassMark:         entity.PassMark,
+					ShuffleQuestions: entity.ShuffleQuestions,
+					ShuffleAnswers:   entity.ShuffleAnswers,
+					MaxAttempts:      entity.MaxAttempts,
+					Scoring:          domain.ScoringStrategy(entity.Scoring),
+					Pool:             domain.QuizPool{Draw: entity.PoolDraw, Tags: toTags(entity.PoolTags)},
+					Lang:             entity.Lang,
+					Id:               entity.ID,
+				},
+				Classes: map[uuid.UUID]string{},
 			}
 		}
 
@@ -120,6 +211,7 @@ func (r *QuizDBRepository) FindAllActive(ctx context.Context, userId string, lim
 
 		if isAdmin(userId) {
 			domainsMap[entity.Sha1].Filename = entity.Filename
+			domainsMap[entity.Sha1].Source = entity.Source
 			domainsMap[entity.Sha1].Version = entity.Version
 			domainsMap[entity.Sha1].Active = entity.Active
 			domainsMap[entity.Sha1].CreatedAt = entity.CreatedAt
@@ -139,10 +231,10 @@ func (r *QuizDBRepository) FindAllActive(ctx context.Context, userId string, lim
 	return domains, nil
 }
 
-func (r *QuizDBRepository) CountAllActive(ctx context.Context, userId string) (uint32, error) {
+func (r *QuizDBRepository) CountAllActive(ctx context.Context, userId string, category string) (uint32, error) {
 
 	if isAdmin(userId) {
-		count, err := r.w.queries().CountAllActiveQuiz(ctx)
+		count, err := r.w.queries().CountAllActiveQuiz(ctx, category)
 		if err != nil {
 			return 0, err
 		}
@@ -150,7 +242,10 @@ func (r *QuizDBRepository) CountAllActive(ctx context.Context, userId string) (u
 		return uint32(count), nil
 	}
 
-	count, err := r.w.queries().CountAllActiveQuizForUser(ctx, userId)
+	count, err := r.w.queries().CountAllActiveQuizForUser(ctx, sqlc.CountAllActiveQuizForUserParams{
+		ID:       userId,
+		Category: category,
+	})
 	if err != nil {
 		return 0, err
 	}
@@ -159,9 +254,12 @@ func (r *QuizDBRepository) CountAllActive(ctx context.Context, userId string) (u
 
 }
 
-func (r *QuizDBRepository) FindLatestVersionByFilename(ctx context.Context, filename string) (*domain.Quiz, error) {
+func (r *QuizDBRepository) FindLatestVersionByFilename(ctx context.Context, source string, filename string) (*domain.Quiz, error) {
 
-	quiz, err := r.w.queries().FindQuizByFilenameAndLatestVersion(ctx, filename)
+	quiz, err := r.w.queries().FindQuizByFilenameAndLatestVersion(ctx, sqlc.FindQuizByFilenameAndLatestVersionParams{
+		Source:   source,
+		Filename: filename,
+	})
 	if err != nil && errors.Is(err, sql.ErrNoRows) {
 		return nil, nil
 	} else if err != nil {
@@ -174,62 +272,276 @@ func (r *QuizDBRepository) FindLatestVersionByFilename(ctx context.Context, file
 func (r *QuizDBRepository) Create(ctx context.Context, quiz *domain.Quiz) error {
 
 	err := r.w.queries().CreateOrReplaceQuiz(ctx, sqlc.CreateOrReplaceQuizParams{
-		Sha1:      quiz.Sha1,
-		Name:      quiz.Name,
-		Filename:  quiz.Filename,
-		Version:   quiz.Version,
-		Duration:  quiz.Duration,
-		CreatedAt: quiz.CreatedAt,
+		Sha1:             quiz.Sha1,
+		Name:             quiz.Name,
+		Filename:         quiz.Filename,
+		Version:          quiz.Version,
+		Duration:         quiz.Duration,
+		CreatedAt:        quiz.CreatedAt,
+		Description:      quiz.Metadata.Description,
+		Tags:             fromTags(quiz.Metadata.Tags),
+		Author:           quiz.Metadata.Author,
+		PassMark:         quiz.Metadata.PassMark,
+		ShuffleQuestions: quiz.Metadata.ShuffleQuestions,
+		ShuffleAnswers:   quiz.Metadata.ShuffleAnswers,
+		MaxAttempts:      quiz.Metadata.MaxAttempts,
+		Scoring:          int8(quiz.Metadata.Scoring),
+		PoolDraw:         quiz.Metadata.Pool.Draw,
+		PoolTags:         fromTags(quiz.Metadata.Pool.Tags),
+		Lang:             quiz.Metadata.Lang,
+		Category:         quiz.Category,
+		Source:           quiz.Source,
+		ID:               quiz.Metadata.Id,
 	})
 	if err != nil {
 		return err
 	}
 
 	for _, question := range quiz.Questions {
-		err := r.w.queries().CreateOrReplaceQuestion(ctx, sqlc.CreateOrReplaceQuestionParams{
-			Sha1:         question.Sha1,
-			Position:     question.Position,
-			Content:      question.Content,
-			Code:         sql.NullString{String: question.Code, Valid: true},
-			CodeLanguage: sql.NullString{String: question.CodeLanguage, Valid: true},
-		})
+		err := r.w.queries().CreateQuestion(ctx, question.Sha1)
 		if err != nil {
 			return err
 		}
 
+		// the questions and the answers are shared by the quizzes by sha1, their content being the one
+		// of each quiz as the assets they link to can differ
 		err = r.w.queries().LinkQuestion(ctx, sqlc.LinkQuestionParams{
-			QuizSha1:     quiz.Sha1,
+			QuizSha1:      quiz.Sha1,
+			QuestionSha1:  question.Sha1,
+			Position:      question.Position,
+			Kind:          int8(question.Kind),
+			Content:       question.Content,
+			Code:          sql.NullString{String: question.Code, Valid: true},
+			CodeLanguage:  sql.NullString{String: question.CodeLanguage, Valid: true},
+			PartialCredit: question.PartialCredit,
+			Points:        question.Points,
+			Explanation:   question.Explanation,
+			Tags:          fromTags(question.Tags),
+		})
+		if err != nil {
+			return err
+		}
+
+		// the latest version of the quiz gives the id of the question to all its versions
+		err = r.w.queries().CreateOrReplaceQuestionIdentity(ctx, sqlc.CreateOrReplaceQuestionIdentityParams{
+			QuizSource:   quiz.Source,
+			QuizFilename: quiz.Filename,
 			QuestionSha1: question.Sha1,
+			QuestionID:   questionId(question),
 		})
 		if err != nil {
 			return err
 		}
 
 		for _, answer := range question.Answers {
-			err := r.w.queries().CreateOrReplaceAnswer(ctx, sqlc.CreateOrReplaceAnswerParams{
-				Sha1:    answer.Sha1,
-				Content: answer.Content,
-				Valid:   answer.Valid,
-			})
+			err := r.w.queries().CreateAnswer(ctx, answer.Sha1)
 			if err != nil {
 				return err
 			}
 
 			err = r.w.queries().LinkAnswer(ctx, sqlc.LinkAnswerParams{
+				QuizSha1:     quiz.Sha1,
 				QuestionSha1: question.Sha1,
 				AnswerSha1:   answer.Sha1,
+				Content:      answer.Content,
+				Valid:        answer.Valid,
+				MatchMode:    int8(answer.Match),
+				Explanation:  answer.Explanation,
+				Ordinal:      answer.Ordinal,
+				Position:     answer.Position,
+			})
+			if err != nil {
+				return err
+			}
+
+			if answer.RightSha1 != "" {
+				err = r.w.queries().CreateOrReplacePair(ctx, sqlc.CreateOrReplacePairParams{
+					QuizSha1:     quiz.Sha1,
+					QuestionSha1: question.Sha1,
+					AnswerSha1:   answer.Sha1,
+					RightSha1:    answer.RightSha1,
+					RightContent: answer.Right,
+				})
+				if err != nil {
+					return err
+				}
+			}
+		}
+	}
+
+	err = r.createTranslations(ctx, quiz)
+	if err != nil {
+		return err
+	}
+
+	for _, asset := range quiz.Assets {
+		err := r.w.queries().CreateOrReplaceAsset(ctx, sqlc.CreateOrReplaceAssetParams{
+			Sha1:        asset.Sha1,
+			Filename:    asset.Filename,
+			ContentType: asset.ContentType,
+			Content:     asset.Content,
+		})
+		if err != nil {
+			return err
+		}
+	}
+
+	return nil
+}
+
+func (r *QuizDBRepository) createTranslations(ctx context.Context, quiz *domain.Quiz) error {
+	for lang, translation := range quiz.Translations {
+		err := r.w.queries().CreateOrReplaceQuizTranslation(ctx, sqlc.CreateOrReplaceQuizTranslationParams{
+			QuizSha1:    quiz.Sha1,
+			Lang:        lang,
+			Name:        translation.Name,
+			Description: translation.Description,
+		})
+		if err != nil {
+			return err
+		}
+
+		for questionSha1, question := range translation.Questions {
+			err := r.w.queries().CreateOrReplaceQuestionTranslation(ctx, sqlc.CreateOrReplaceQuestionTranslationParams{
+				QuizSha1:     quiz.Sha1,
+				Lang:         lang,
+				QuestionSha1: questionSha1,
+				Content:      question.Content,
+				Code:         question.Code,
+				Explanation:  question.Explanation,
 			})
 			if err != nil {
 				return err
 			}
+
+			for answerSha1, answer := range question.Answers {
+				err := r.w.queries().CreateOrReplaceAnswerTranslation(ctx, sqlc.CreateOrReplaceAnswerTranslationParams{
+					QuizSha1:     quiz.Sha1,
+					Lang:         lang,
+					QuestionSha1: questionSha1,
+					AnswerSha1:   answerSha1,
+					Content:      answer.Content,
+					RightContent: answer.Right,
+					Explanation:  answer.Explanation,
+				})
+				if err != nil {
+					return err
+				}
+			}
 		}
 	}
 
 	return nil
 }
 
-func (r *QuizDBRepository) ActivateOnlyVersion(ctx context.Context, filename string, version int) error {
+func (r *QuizDBRepository) FindAssetBySha1(ctx context.Context, sha1 string) (*domain.Asset, error) {
+
+	entity, err := r.w.queries().FindAssetBySha1(ctx, sha1)
+	if err != nil && errors.Is(err, sql.ErrNoRows) {
+		return nil, nil
+	} else if err != nil {
+		return nil, err
+	}
+
+	return &domain.Asset{
+		Sha1:        entity.Sha1,
+		Filename:    entity.Filename,
+		ContentType: entity.ContentType,
+		Content:     entity.Content,
+	}, nil
+}
+
+func (r *QuizDBRepository) FindPoolBySha1(ctx context.Context, sha1 string) (*domain.Quiz, error) {
+	entities, err := r.w.queries().FindQuizPoolBySha1(ctx, sha1)
+	if err != nil {
+		return nil, err
+	}
+
+	if len(entities) == 0 {
+		return nil, nil
+	}
+
+	quiz := &domain.Quiz{
+		Sha1: sha1,
+		Metadata: domain.QuizMetadata{
+			Pool: domain.QuizPool{
+				Draw: entities[0].QuizPoolDraw,
+				Tags: toTags(entities[0].QuizPoolTags),
+			},
+		},
+		Questions: map[string]domain.QuizQuestion{},
+	}
+
+	for _, entity := range entities {
+		quiz.Questions[entity.QuestionSha1] = domain.QuizQuestion{
+			Sha1:     entity.QuestionSha1,
+			Position: entity.QuestionPosition,
+			Tags:     toTags(entity.QuestionTags),
+		}
+	}
+
+	return quiz, nil
+}
+
+func (r *QuizDBRepository) FindTranslations(ctx context.Context, quizSha1 string) (map[string]domain.QuizTranslation, error) {
+	quizEntities, err := r.w.queries().FindQuizTranslations(ctx, quizSha1)
+	if err != nil {
+		return nil, err
+	}
+
+	questionEntities, err := r.w.queries().FindQuestionTranslations(ctx, quizSha1)
+	if err != nil {
+		return nil, err
+	}
+
+	answerEntities, err := r.w.queries().FindAnswerTranslations(ctx, quizSha1)
+	if err != nil {
+		return nil, err
+	}
+
+	return r.toTranslations(quizEntities, questionEntities, answerEntities), nil
+}
+
+func (r *QuizDBRepository) FindActiveQuizTranslations(ctx context.Context) (map[string]map[string]domain.QuizTranslation, error) {
+	entities, err := r.w.queries().FindActiveQuizTranslations(ctx)
+	if err != nil {
+		return nil, err
+	}
+
+	translations := map[string]map[string]domain.QuizTranslation{}
+	for _, entity := range entities {
+		if translations[entity.QuizSha1] == nil {
+			translations[entity.QuizSha1] = map[string]domain.QuizTranslation{}
+		}
+		translations[entity.QuizSha1][entity.Lang] = domain.QuizTranslation{
+			Name:        entity.Name,
+			Description: entity.Description,
+		}
+	}
+
+	return translations, nil
+}
+
+func (r *QuizDBRepository) FindSessionAnswerTranslations(ctx context.Context, sessionUuid uuid.UUID, questionSha1 string) (map[string][]string, error) {
+	entities, err := r.w.queries().FindSessionAnswerTranslations(ctx, sqlc.FindSessionAnswerTranslationsParams{
+		SessionUuid:  sessionUuid,
+		QuestionSha1: questionSha1,
+	})
+	if err != nil {
+		return nil, err
+	}
+
+	translations := map[string][]string{}
+	for _, entity := range entities {
+		translations[entity.AnswerSha1] = append(translations[entity.AnswerSha1], entity.Content)
+	}
+
+	return translations, nil
+}
+
+func (r *QuizDBRepository) ActivateOnlyVersion(ctx context.Context, source string, filename string, version int) error {
 	err := r.w.queries().ActivateOnlyVersion(ctx, sqlc.ActivateOnlyVersionParams{
+		Source:   source,
 		Filename: filename,
 		Version:  version,
 	})
@@ -288,21 +600,39 @@ func (r *QuizDBRepository) CountAllSessions(ctx context.Context, quizActive bool
 	return uint32(count), nil
 }
 
-func (r *QuizDBRepository) StartSession(ctx context.Context, userId string, quizSha1 string) (uuid.UUID, error) {
+func (r *QuizDBRepository) StartSession(ctx context.Context, userId string, quizSha1 string, seed int64, questionSha1s []string) (uuid.UUID, error) {
 	sessionUuid := uuid.New()
 
 	err := r.w.queries().CreateOrReplaceSession(ctx, sqlc.CreateOrReplaceSessionParams{
 		Uuid:     sessionUuid,
 		QuizSha1: quizSha1,
 		UserID:   userId,
+		Seed:     seed,
 	})
 	if err != nil {
 		return uuid.UUID{}, err
 	}
 
+	for _, questionSha1 := range questionSha1s {
+		err := r.w.queries().CreateSessionQuestion(ctx, sqlc.CreateSessionQuestionParams{
+			SessionUuid:  sessionUuid,
+			QuestionSha1: questionSha1,
+		})
+		if err != nil {
+			return uuid.UUID{}, err
+		}
+	}
+
 	return sessionUuid, nil
 }
 
+// isRejectedSessionAnswer tells if the answer was rejected by a constraint of the session_answer table
+func isRejectedSessionAnswer(err error) bool {
+	return err.Error() == "FOREIGN KEY constraint failed" ||
+		err.Error() == "session is over" ||
+		err.Error() == "question is not part of the session"
+}
+
 func (r *QuizDBRepository) AddSessionAnswer(ctx context.Context, sessionUuid uuid.UUID, questionSha1 string, answerSha1 string, checked bool) error {
 
 	err := r.w.queries().CreateOrReplaceSessionAnswer(ctx, sqlc.CreateOrReplaceSessionAnswerParams{
@@ -312,7 +642,85 @@ func (r *QuizDBRepository) AddSessionAnswer(ctx context.Context, sessionUuid uui
 		Checked:      checked,
 	})
 	if err != nil {
-		if err.Error() == "FOREIGN KEY constraint failed" || err.Error() == "session is over" {
+		if isRejectedSessionAnswer(err) {
+			return domain.Errorf(domain.InvalidArgument, "%s", err.Error())
+		}
+		return err
+	}
+
+	return nil
+}
+
+// AddSessionTextAnswer saves the typed text on every accepted answer of the question, checking the
+// ones it matches. The answers are replaced together so that a failure keeps the previous text
+func (r *QuizDBRepository) AddSessionTextAnswer(ctx context.Context, sessionUuid uuid.UUID, questionSha1 string, answers map[string]bool, text string) error {
+
+	return r.addSessionAnswers(ctx, func(q *sqlc.Queries) error {
+		for answerSha1, checked := range answers {
+			err := q.CreateOrReplaceSessionAnswer(ctx, sqlc.CreateOrReplaceSessionAnswerParams{
+				SessionUuid:  sessionUuid,
+				QuestionSha1: questionSha1,
+				AnswerSha1:   answerSha1,
+				Checked:      checked,
+				Content:      sql.NullString{String: text, Valid: true},
+			})
+			if err != nil {
+				return err
+			}
+		}
+
+		return nil
+	})
+}
+
+// AddSessionOrderAnswer saves the position given to every item of the question, the items being
+// replaced together
+func (r *QuizDBRepository) AddSessionOrderAnswer(ctx context.Context, sessionUuid uuid.UUID, questionSha1 string, positions map[string]int) error {
+
+	return r.addSessionAnswers(ctx, func(q *sqlc.Queries) error {
+		for answerSha1, position := range positions {
+			err := q.CreateOrReplaceSessionAnswer(ctx, sqlc.CreateOrReplaceSessionAnswerParams{
+				SessionUuid:  sessionUuid,
+				QuestionSha1: questionSha1,
+				AnswerSha1:   answerSha1,
+				Position:     sql.NullInt64{Int64: int64(position), Valid: true},
+			})
+			if err != nil {
+				return err
+			}
+		}
+
+		return nil
+	})
+}
+
+// AddSessionPairAnswer saves the right-hand item paired with every left-hand item of the question,
+// the pairs being replaced together
+func (r *QuizDBRepository) AddSessionPairAnswer(ctx context.Context, sessionUuid uuid.UUID, questionSha1 string, rights map[string]string) error {
+
+	return r.addSessionAnswers(ctx, func(q *sqlc.Queries) error {
+		for answerSha1, right := range rights {
+			err := q.CreateOrReplaceSessionAnswer(ctx, sqlc.CreateOrReplaceSessionAnswerParams{
+				SessionUuid:  sessionUuid,
+				QuestionSha1: questionSha1,
+				AnswerSha1:   answerSha1,
+				Content:      sql.NullString{String: right, Valid: true},
+			})
+			if err != nil {
+				return err
+			}
+		}
+
+		return nil
+	})
+}
+
+// addSessionAnswers saves the answers of a question in a single transaction, the answers rejected by
+// the database being reported as invalid arguments
+func (r *QuizDBRepository) addSessionAnswers(ctx context.Context, fn func(q *sqlc.Queries) error) error {
+	err := r.w.inTx(ctx, fn)
+	if err != nil {
+		if isRejectedSessionAnswer(err) {
 			return domain.Errorf(domain.InvalidArgument, "%s", err.Error())
 		}
 		return err
@@ -358,51 +766,27 @@ func (r *QuizDBRepository) FindQuizSessionByUuid(ctx context.Context, sessionUui
 		return nil, pm.Errorf(http.StatusNotFound, "session with uuid: %s was not found.", sessionUuid)
 	}
 
-	sessionDetail := domain.QuizSessionDetail{}
+	return toQuizSessionDetail(details), nil
+}
 
-	for _, entity := range details {
-		if sessionDetail.QuizSha1 == "" {
-			sessionDetail.SessionId = entity.SessionUuid
-			sessionDetail.UserId = entity.UserID
-			sessionDetail.RemainingSec = entity.RemainingSec
-			sessionDetail.QuizSha1 = entity.QuizSha1
-			sessionDetail.Name = entity.QuizName
-			sessionDetail.QuizDuration = entity.QuizDuration
-			sessionDetail.Questions = map[string]domain.QuizQuestion{}
-
-			if sessionDetail.RemainingSec == 0 {
-				sessionDetail.Result = &domain.SessionResult{
-					GoodAnswer:  entity.Results,
-					TotalAnswer: entity.CheckedAnswers,
-				}
-			}
+// FindQuizSessionsByUuids loads the sessions with a single query, the sessions not found being left
+// out of the result
+func (r *QuizDBRepository) FindQuizSessionsByUuids(ctx context.Context, sessionUuids []uuid.UUID) (map[uuid.UUID]*domain.QuizSessionDetail, error) {
 
-		}
-
-		if _, found := sessionDetail.Questions[entity.QuestionSha1]; !found {
-			newQuestion := domain.QuizQuestion{
-				Sha1:         entity.QuestionSha1,
-				Position:     entity.QuestionPosition,
-				Content:      entity.QuestionContent,
-				Code:         entity.QuestionCode.String,
-				CodeLanguage: entity.QuestionCodeLanguage.String,
-				Answers:      map[string]domain.QuizQuestionAnswer{},
-			}
-			sessionDetail.Questions[entity.QuestionSha1] = newQuestion
-		}
+	details, err := r.w.queries().FindQuizSessionsByUuids(ctx, sessionUuids)
+	if err != nil {
+		return nil, err
+	}
 
-		answerValid := false
-		if sessionDetail.RemainingSec == 0 {
-			answerValid = entity.AnswerValid
-		}
+	bySession := map[uuid.UUID][]sqlc.QuizSessionDetailView{}
+	for _, entity := range details {
+		bySession[entity.SessionUuid] = append(bySession[entity.SessionUuid], entity)
+	}
 
-		sessionDetail.Questions[entity.QuestionSha1].Answers[entity.AnswerSha1] = domain.QuizQuestionAnswer{
-			Sha1:    entity.AnswerSha1,
-			Content: entity.AnswerContent,
-			Checked: entity.AnswerChecked,
-			Valid:   answerValid,
-		}
+	sessionDetails := make(map[uuid.UUID]*domain.QuizSessionDetail, len(bySession))
+	for sessionUuid, entities := range bySession {
+		sessionDetails[sessionUuid] = toQuizSessionDetail(entities)
 	}
 
-	return &sessionDetail, nil
+	return sessionDetails, nil
 }
@@ -17,6 +17,10 @@
 package infrastructure
 
 import (
+	"context"
+	"database/sql"
+	"fmt"
+	"strings"
 	"testing"
 
 	"github.com/google/uuid"
@@ -31,18 +35,16 @@ func TestQuizDBRepository_toQuizSessionArray_2quiz_1session(t *testing.T) {
 	r := NewQuizRepository(nil)
 	sessions := make([]sqlc.QuizSessionView, 2)
 	sessions[0] = sqlc.QuizSessionView{
-		QuizSha1:       sha1Quiz1,
-		QuizName:       quizName1,
-		QuizFilename:   quizFilename1,
-		QuizVersion:    quizVersion1,
-		QuizDuration:   quizDuration1,
-		QuizCreatedAt:  quizCreatedAt1,
-		SessionUuid:    uuid.New(),
-		UserID:         userId1,
-		UserName:       name,
-		RemainingSec:   remainingSec1,
-		CheckedAnswers: checkedAnswers1,
-		Results:        results1,
+		QuizSha1:      sha1Quiz1,
+		QuizName:      quizName1,
+		QuizFilename:  quizFilename1,
+		QuizVersion:   quizVersion1,
+		QuizDuration:  quizDuration1,
+		QuizCreatedAt: quizCreatedAt1,
+		SessionUuid:   uuid.New(),
+		UserID:        userId1,
+		UserName:      name,
+		RemainingSec:  remainingSec1,
 	}
 	sessions[1] = sqlc.QuizSessionView{
 		QuizSha1:      sha1Quiz2,
@@ -79,32 +81,28 @@ func TestQuizDBRepository_toQuizSessionArray_3quiz_2session(t *testing.T) {
 	r := NewQuizRepository(nil)
 	sessions := make([]sqlc.QuizSessionView, 3)
 	sessions[0] = sqlc.QuizSessionView{
-		QuizSha1:       sha1Quiz1,
-		QuizName:       quizName1,
-		QuizFilename:   quizFilename1,
-		QuizVersion:    quizVersion1,
-		QuizDuration:   quizDuration1,
-		QuizCreatedAt:  quizCreatedAt1,
-		SessionUuid:    uuid.New(),
-		UserID:         userId1,
-		UserName:       name,
-		RemainingSec:   remainingSec1,
-		CheckedAnswers: checkedAnswers1,
-		Results:        results1,
+		QuizSha1:      sha1Quiz1,
+		QuizName:      quizName1,
+		QuizFilename:  quizFilename1,
+		QuizVersion:   quizVersion1,
+		QuizDuration:  quizDuration1,
+		QuizCreatedAt: quizCreatedAt1,
+		SessionUuid:   uuid.New(),
+		UserID:        userId1,
+		UserName:      name,
+		RemainingSec:  remainingSec1,
 	}
 	sessions[1] = sqlc.QuizSessionView{
-		QuizSha1:       sha1Quiz1,
-		QuizName:       quizName1,
-		QuizFilename:   quizFilename1,
-		QuizVersion:    quizVersion1,
-		QuizDuration:   quizDuration1,
-		QuizCreatedAt:  quizCreatedAt1,
-		SessionUuid:    uuid.New(),
-		UserID:         userId2,
-		UserName:       name,
-		RemainingSec:   remainingSec2,
-		CheckedAnswers: checkedAnswers2,
-		Results:        results2,
+		QuizSha1:      sha1Quiz1,
+		QuizName:      quizName1,
+		QuizFilename:  quizFilename1,
+		QuizVersion:   quizVersion1,
+		QuizDuration:  quizDuration1,
+		QuizCreatedAt: quizCreatedAt1,
+		SessionUuid:   uuid.New(),
+		UserID:        userId2,
+		UserName:      name,
+		RemainingSec:  remainingSec2,
 	}
 	sessions[2] = sqlc.QuizSessionView{
 		QuizSha1:      sha1Quiz2,
@@ -137,3 +135,908 @@ func TestQuizDBRepository_toQuizSessionArray_3quiz_2session(t *testing.T) {
 	assert.NotEmpty(t, actualMap[sha1Quiz1].UserSessions[1])
 	assert.NotEmpty(t, actualMap[sha1Quiz1].UserSessions[1].UserId)
 }
+
+// quizWithQuestion creates a quiz holding a single question
+func quizWithQuestion(sha1 string, filename string, question domain.QuizQuestion) *domain.Quiz {
+	return &domain.Quiz{
+		Sha1:      sha1,
+		Source:    "default",
+		Filename:  filename,
+		Name:      filename,
+		Version:   1,
+		Active:    true,
+		CreatedAt: quizCreatedAt1,
+		Duration:  quizDuration1,
+		Questions: map[string]domain.QuizQuestion{question.Sha1: question},
+	}
+}
+
+// orderingQuiz creates a quiz with a single Ordering question whose items are expected in the given order
+func orderingQuiz(sha1 string, filename string, questionSha1 string, items ...string) *domain.Quiz {
+	answers := map[string]domain.QuizQuestionAnswer{}
+	for i, item := range items {
+		answers[item] = domain.QuizQuestionAnswer{
+			Sha1:     item,
+			Content:  item,
+			Position: i + 1,
+			Ordinal:  i,
+		}
+	}
+
+	return quizWithQuestion(sha1, filename, domain.QuizQuestion{
+		Sha1:    questionSha1,
+		Kind:    domain.Ordering,
+		Content: questionSha1,
+		Points:  1,
+		Answers: answers,
+	})
+}
+
+// startSession starts a session of a new user on the quiz
+func startSession(t *testing.T, r *QuizDBRepository, quizSha1 string) uuid.UUID {
+	userId := uuid.NewString()
+	err := NewUserRepository(r.w).CreateOrReplaceUser(context.Background(), &domain.User{
+		Id:    userId,
+		Login: login,
+		Name:  name,
+		Role:  domain.Student,
+	})
+	if err != nil {
+		assert.FailNow(t, "Fail to create user", "%v", err)
+	}
+
+	quiz, err := r.FindPoolBySha1(context.Background(), quizSha1)
+	if err != nil {
+		assert.FailNow(t, "Fail to get quiz", "%v", err)
+	}
+	var questionSha1s []string
+	for sha1 := range quiz.Questions {
+		questionSha1s = append(questionSha1s, sha1)
+	}
+
+	sessionUuid, err := r.StartSession(context.Background(), userId, quizSha1, 42, questionSha1s)
+	if err != nil {
+		assert.FailNow(t, "Fail to start session", "%v", err)
+	}
+
+	return sessionUuid
+}
+
+// endSession moves the start of the session back by a day so that it is over
+func endSession(t *testing.T, connection *sql.DB, sessionUuid uuid.UUID) {
+	_, err := connection.Exec("UPDATE session SET created_at = datetime(created_at, '-1 day') WHERE uuid = ?", sessionUuid)
+	if err != nil {
+		assert.FailNow(t, "Fail to end session", "%v", err)
+	}
+}
+
+func TestQuizDBRepository_Create_orderingItemSharedByTwoQuestions(t *testing.T) {
+	connection := getDBConnection(t, true)
+	defer connection.Close()
+
+	r := NewQuizRepository(NewConnectionWrapperForTest("data", connection))
+
+	// Given
+	err := r.Create(context.Background(), orderingQuiz(sha1Quiz1, quizFilename1, "question-1", "first", "shared", "last"))
+	if err != nil {
+		assert.Failf(t, "Fail to create quiz", "%v", err)
+	}
+	err = r.Create(context.Background(), orderingQuiz(sha1Quiz2, quizFilename2, "question-2", "shared", "first"))
+	if err != nil {
+		assert.Failf(t, "Fail to create quiz", "%v", err)
+	}
+
+	// When
+	quiz1, err := r.FindFullBySha1(context.Background(), sha1Quiz1, "")
+	if err != nil {
+		assert.Failf(t, "Fail to get quiz", "%v", err)
+	}
+	quiz2, err := r.FindFullBySha1(context.Background(), sha1Quiz2, "")
+	if err != nil {
+		assert.Failf(t, "Fail to get quiz", "%v", err)
+	}
+	question1, err := r.FindSessionQuestion(context.Background(), startSession(t, r, sha1Quiz1), "question-1")
+	if err != nil {
+		assert.Failf(t, "Fail to get question", "%v", err)
+	}
+
+	// Then
+	assert.Len(t, quiz1.Questions["question-1"].Answers, 3)
+	assert.Equal(t, 2, quiz1.Questions["question-1"].Answers["shared"].Position)
+	assert.Equal(t, 3, quiz1.Questions["question-1"].Answers["last"].Position)
+	assert.Len(t, quiz2.Questions["question-2"].Answers, 2)
+	assert.Equal(t, 1, quiz2.Questions["question-2"].Answers["shared"].Position)
+	assert.Equal(t, 2, quiz2.Questions["question-2"].Answers["first"].Position)
+	assert.Equal(t, 2, question1.Answers["shared"].Position)
+}
+
+func TestQuizDBRepository_Create_questionSharedByTwoQuizzes(t *testing.T) {
+	connection := getDBConnection(t, true)
+	defer connection.Close()
+
+	r := NewQuizRepository(NewConnectionWrapperForTest("data", connection))
+
+	// Given the same question linking to an image that differs between the two quizzes
+	question := func(asset string, points int, explanation string) domain.QuizQuestion {
+		return domain.QuizQuestion{
+			Sha1:    "question",
+			Kind:    domain.Choice,
+			Content: "Who is this ?\n\n![hero](" + domain.AssetPath + asset + ")",
+			Points:  points,
+			Answers: map[string]domain.QuizQuestionAnswer{
+				"answer": {Sha1: "answer", Content: "![hero](" + domain.AssetPath + asset + ")", Valid: true, Explanation: explanation},
+				"other":  {Sha1: "other", Content: "Nobody"},
+			},
+		}
+	}
+	err := r.Create(context.Background(), quizWithQuestion(sha1Quiz1, quizFilename1, question("hulk", 1, "The Hulk")))
+	if err != nil {
+		assert.Failf(t, "Fail to create quiz", "%v", err)
+	}
+	err = r.Create(context.Background(), quizWithQuestion(sha1Quiz2, quizFilename2, question("thor", 2, "Thor")))
+	if err != nil {
+		assert.Failf(t, "Fail to create quiz", "%v", err)
+	}
+
+	// When
+	quiz1, err := r.FindFullBySha1(context.Background(), sha1Quiz1, "")
+	if err != nil {
+		assert.Failf(t, "Fail to get quiz", "%v", err)
+	}
+	session1 := startSession(t, r, sha1Quiz1)
+	sessionQuestion1, err := r.FindSessionQuestion(context.Background(), session1, "question")
+	if err != nil {
+		assert.Failf(t, "Fail to get question", "%v", err)
+	}
+	sessionDetail1, err := r.FindQuizSessionByUuid(context.Background(), session1)
+	if err != nil {
+		assert.Failf(t, "Fail to get session", "%v", err)
+	}
+	sessionQuestion2, err := r.FindSessionQuestion(context.Background(), startSession(t, r, sha1Quiz2), "question")
+	if err != nil {
+		assert.Failf(t, "Fail to get question", "%v", err)
+	}
+
+	// Then each quiz keeps its own version of the question
+	assert.Len(t, quiz1.Questions["question"].Answers, 2)
+	assert.Equal(t, question("hulk", 1, "The Hulk").Content, quiz1.Questions["question"].Content)
+	assert.Equal(t, 1, quiz1.Questions["question"].Points)
+	assert.Equal(t, "![hero]("+domain.AssetPath+"hulk)", quiz1.Questions["question"].Answers["answer"].Content)
+	assert.Equal(t, "The Hulk", quiz1.Questions["question"].Answers["answer"].Explanation)
+
+	assert.Equal(t, question("hulk", 1, "The Hulk").Content, sessionQuestion1.Content)
+	assert.Equal(t, 1, sessionQuestion1.Points)
+	assert.Equal(t, question("hulk", 1, "The Hulk").Content, sessionDetail1.Questions["question"].Content)
+	assert.Len(t, sessionDetail1.Questions["question"].Answers, 2)
+
+	assert.Equal(t, question("thor", 2, "Thor").Content, sessionQuestion2.Content)
+	assert.Equal(t, 2, sessionQuestion2.Points)
+	assert.Equal(t, "![hero]("+domain.AssetPath+"thor)", sessionQuestion2.Answers["answer"].Content)
+}
+
+func TestQuizDBRepository_Create_ids(t *testing.T) {
+	connection := getDBConnection(t, true)
+	defer connection.Close()
+
+	r := NewQuizRepository(NewConnectionWrapperForTest("data", connection))
+
+	// Given a quiz with an id, a question with an id and a question without
+	quiz := quizWithQuestion(sha1Quiz1, quizFilename1, domain.QuizQuestion{
+		Sha1:    "hulk",
+		Id:      "hulk-id",
+		Content: "Who is Hulk ?",
+		Points:  1,
+		Answers: map[string]domain.QuizQuestionAnswer{"banner": {Sha1: "banner", Content: "Bruce Banner", Valid: true}},
+	})
+	quiz.Metadata.Id = "avengers"
+	quiz.Questions["thor"] = domain.QuizQuestion{
+		Sha1:     "thor",
+		Content:  "Who is Thor ?",
+		Position: 1,
+		Points:   1,
+		Answers:  map[string]domain.QuizQuestionAnswer{"odinson": {Sha1: "odinson", Content: "Odinson", Valid: true}},
+	}
+	err := r.Create(context.Background(), quiz)
+	if err != nil {
+		assert.Failf(t, "Fail to create quiz", "%v", err)
+	}
+
+	// When
+	full, err := r.FindFullBySha1(context.Background(), sha1Quiz1, "")
+	if err != nil {
+		assert.Failf(t, "Fail to get quiz", "%v", err)
+	}
+	latest, err := r.FindLatestVersionByFilename(context.Background(), "default", quizFilename1)
+	if err != nil {
+		assert.Failf(t, "Fail to get quiz", "%v", err)
+	}
+
+	// Then
+	assert.Equal(t, "avengers", full.Metadata.Id)
+	assert.Equal(t, "avengers", latest.Metadata.Id)
+	assert.Equal(t, "hulk-id", full.Questions["hulk"].Id)
+	assert.Equal(t, "thor", full.Questions["thor"].Id)
+}
+
+func TestQuizDBRepository_Create_metadata(t *testing.T) {
+	connection := getDBConnection(t, true)
+	defer connection.Close()
+
+	r := NewQuizRepository(NewConnectionWrapperForTest("data", connection))
+
+	// Given
+	metadata := domain.QuizMetadata{
+		Description:      "The Avengers quiz",
+		Tags:             []string{"marvel", "avengers"},
+		Author:           "Nick Fury",
+		PassMark:         60,
+		ShuffleQuestions: true,
+		ShuffleAnswers:   true,
+		MaxAttempts:      2,
+		Scoring:          domain.Proportional,
+		Pool:             domain.QuizPool{Draw: 1, Tags: []string{"team"}},
+		Lang:             "en",
+	}
+	quiz := quizWithQuestion(sha1Quiz1, quizFilename1, domain.QuizQuestion{
+		Sha1:    "question",
+		Content: "Who is Hulk ?",
+		Points:  1,
+		Tags:    []string{"team"},
+		Answers: map[string]domain.QuizQuestionAnswer{"banner": {Sha1: "banner", Content: "Bruce Banner", Valid: true}},
+	})
+	quiz.Metadata = metadata
+	err := r.Create(context.Background(), quiz)
+	if err != nil {
+		assert.Failf(t, "Fail to create quiz", "%v", err)
+	}
+
+	// When
+	full, err := r.FindFullBySha1(context.Background(), sha1Quiz1, "")
+	if err != nil {
+		assert.Failf(t, "Fail to get quiz", "%v", err)
+	}
+	latest, err := r.FindLatestVersionByFilename(context.Background(), "default", quizFilename1)
+	if err != nil {
+		assert.Failf(t, "Fail to get quiz", "%v", err)
+	}
+	active, err := r.FindAllActive(context.Background(), "", "", 10, 0)
+	if err != nil {
+		assert.Failf(t, "Fail to get quizzes", "%v", err)
+	}
+
+	// Then
+	assert.Equal(t, metadata, full.Metadata)
+	assert.Equal(t, metadata, latest.Metadata)
+	assert.Len(t, active, 1)
+	assert.Equal(t, metadata, active[0].Metadata)
+	assert.Equal(t, []string{"team"}, full.Questions["question"].Tags)
+}
+
+func TestQuizDBRepository_AddSessionTextAnswer(t *testing.T) {
+	connection := getDBConnection(t, true)
+	defer connection.Close()
+
+	r := NewQuizRepository(NewConnectionWrapperForTest("data", connection))
+
+	// Given a short-answer question accepting two answers
+	err := r.Create(context.Background(), quizWithQuestion(sha1Quiz1, quizFilename1, domain.QuizQuestion{
+		Sha1:    "question",
+		Kind:    domain.ShortAnswer,
+		Content: "Who assembles ?",
+		Points:  1,
+		Answers: map[string]domain.QuizQuestionAnswer{
+			"exact": {Sha1: "exact", Content: "Avengers", Valid: true, Match: domain.ExactMatch, Ordinal: 1},
+			"regex": {Sha1: "regex", Content: "^aveng.*", Valid: true, Match: domain.RegexMatch, Ordinal: 2},
+		},
+	}))
+	if err != nil {
+		assert.Failf(t, "Fail to create quiz", "%v", err)
+	}
+	sessionUuid := startSession(t, r, sha1Quiz1)
+
+	// When
+	err = r.AddSessionTextAnswer(context.Background(), sessionUuid, "question", map[string]bool{"exact": false, "regex": true}, "avengers")
+	if err != nil {
+		assert.Failf(t, "Fail to add answer", "%v", err)
+	}
+	rejected := r.AddSessionTextAnswer(context.Background(), sessionUuid, "question", map[string]bool{"exact": true, "unknown": true}, "Avengers")
+	question, err := r.FindSessionQuestion(context.Background(), sessionUuid, "question")
+	if err != nil {
+		assert.Failf(t, "Fail to get question", "%v", err)
+	}
+	detail, err := r.FindQuizSessionByUuid(context.Background(), sessionUuid)
+	if err != nil {
+		assert.Failf(t, "Fail to get session", "%v", err)
+	}
+
+	// Then the text of a rejected answer does not replace the previous one
+	assert.Error(t, rejected)
+	assert.Equal(t, domain.ExactMatch, question.Answers["exact"].Match)
+	assert.Equal(t, domain.RegexMatch, question.Answers["regex"].Match)
+	assert.Equal(t, "avengers", detail.Questions["question"].Text)
+	// and the expected answers are hidden while the session is running
+	assert.Empty(t, detail.Questions["question"].Answers)
+}
+
+func TestQuizDBRepository_AddSessionTextAnswer_numeric_sessionOver(t *testing.T) {
+	connection := getDBConnection(t, true)
+	defer connection.Close()
+
+	r := NewQuizRepository(NewConnectionWrapperForTest("data", connection))
+
+	// Given a numeric question of a quiz whose sessions are over as soon as they start
+	quiz := quizWithQuestion(sha1Quiz1, quizFilename1, domain.QuizQuestion{
+		Sha1:    "question",
+		Kind:    domain.Numeric,
+		Content: "In which year was Captain America created ?",
+		Points:  1,
+		Answers: map[string]domain.QuizQuestionAnswer{
+			"year": {Sha1: "year", Content: "1941 ± 1", Valid: true, Match: domain.ToleranceMatch, Ordinal: 1},
+		},
+	})
+	quiz.Duration = 0
+	err := r.Create(context.Background(), quiz)
+	if err != nil {
+		assert.Failf(t, "Fail to create quiz", "%v", err)
+	}
+	sessionUuid := startSession(t, r, sha1Quiz1)
+
+	// When
+	err = r.AddSessionTextAnswer(context.Background(), sessionUuid, "question", map[string]bool{"year": true}, "1942")
+	detail, findErr := r.FindQuizSessionByUuid(context.Background(), sessionUuid)
+	if findErr != nil {
+		assert.Failf(t, "Fail to get session", "%v", findErr)
+	}
+
+	// Then the answer is rejected and the expected answer revealed
+	assert.Error(t, err)
+	assert.Equal(t, 0, detail.RemainingSec)
+	assert.Equal(t, "", detail.Questions["question"].Text)
+	assert.Equal(t, "1941 ± 1", detail.Questions["question"].Answers["year"].Content)
+	assert.Equal(t, domain.ToleranceMatch, detail.Questions["question"].Answers["year"].Match)
+	assert.True(t, detail.Questions["question"].Answers["year"].Valid)
+}
+
+func TestQuizDBRepository_AddSessionPairAnswer(t *testing.T) {
+	connection := getDBConnection(t, true)
+	defer connection.Close()
+
+	r := NewQuizRepository(NewConnectionWrapperForTest("data", connection))
+
+	// Given
+	err := r.Create(context.Background(), quizWithQuestion(sha1Quiz1, quizFilename1, domain.QuizQuestion{
+		Sha1:    "question",
+		Kind:    domain.Matching,
+		Content: "Pair the heroes",
+		Points:  1,
+		Answers: map[string]domain.QuizQuestionAnswer{
+			"thor": {Sha1: "thor", Content: "Thor", Valid: true, Right: "Asgard", RightSha1: "asgard", Ordinal: 1},
+			"hulk": {Sha1: "hulk", Content: "Hulk", Valid: true, Right: "Earth", RightSha1: "earth", Ordinal: 2},
+		},
+	}))
+	if err != nil {
+		assert.Failf(t, "Fail to create quiz", "%v", err)
+	}
+	sessionUuid := startSession(t, r, sha1Quiz1)
+
+	// When
+	err = r.AddSessionPairAnswer(context.Background(), sessionUuid, "question", map[string]string{"thor": "Earth", "hulk": "Asgard"})
+	if err != nil {
+		assert.Failf(t, "Fail to add answer", "%v", err)
+	}
+	full, err := r.FindFullBySha1(context.Background(), sha1Quiz1, "")
+	if err != nil {
+		assert.Failf(t, "Fail to get quiz", "%v", err)
+	}
+	detail, err := r.FindQuizSessionByUuid(context.Background(), sessionUuid)
+	if err != nil {
+		assert.Failf(t, "Fail to get session", "%v", err)
+	}
+
+	// Then
+	expectedRightItems := map[string]string{"asgard": "Asgard", "earth": "Earth"}
+	assert.Equal(t, expectedRightItems, full.Questions["question"].RightItems)
+	assert.Equal(t, "Asgard", full.Questions["question"].Answers["thor"].Right)
+	assert.Equal(t, "asgard", full.Questions["question"].Answers["thor"].RightSha1)
+
+	assert.Equal(t, expectedRightItems, detail.Questions["question"].RightItems)
+	assert.Equal(t, "Earth", detail.Questions["question"].Answers["thor"].AnsweredRight)
+	assert.Equal(t, "Asgard", detail.Questions["question"].Answers["hulk"].AnsweredRight)
+	// the expected pairs are hidden while the session is running
+	assert.Equal(t, "", detail.Questions["question"].Answers["thor"].Right)
+}
+
+// choiceQuestion creates a Choice question whose answers are valid when their name starts with "valid"
+func choiceQuestion(sha1 string, position int, points int, answers ...string) domain.QuizQuestion {
+	question := domain.QuizQuestion{
+		Sha1:     sha1,
+		Kind:     domain.Choice,
+		Content:  sha1,
+		Position: position,
+		Points:   points,
+		Answers:  map[string]domain.QuizQuestionAnswer{},
+	}
+	for i, answer := range answers {
+		question.Answers[sha1+"-"+answer] = domain.QuizQuestionAnswer{
+			Sha1:    sha1 + "-" + answer,
+			Content: answer,
+			Valid:   strings.HasPrefix(answer, "valid"),
+			Ordinal: i + 1,
+		}
+	}
+
+	return question
+}
+
+func TestQuizDBRepository_FindQuizSessionByUuid_points(t *testing.T) {
+	connection := getDBConnection(t, true)
+	defer connection.Close()
+
+	r := NewQuizRepository(NewConnectionWrapperForTest("data", connection))
+	s := domain.NewQuizService(r)
+
+	// Given a question worth 2 points answered right and one worth 3 points answered wrong
+	quiz := quizWithQuestion(sha1Quiz1, quizFilename1, choiceQuestion("light", 1, 2, "valid", "wrong"))
+	quiz.Questions["heavy"] = choiceQuestion("heavy", 2, 3, "valid", "wrong")
+	err := r.Create(context.Background(), quiz)
+	if err != nil {
+		assert.Failf(t, "Fail to create quiz", "%v", err)
+	}
+	sessionUuid := startSession(t, r, sha1Quiz1)
+	err = r.AddSessionAnswer(context.Background(), sessionUuid, "light", "light-valid", true)
+	if err != nil {
+		assert.Failf(t, "Fail to add answer", "%v", err)
+	}
+	err = r.AddSessionAnswer(context.Background(), sessionUuid, "heavy", "heavy-wrong", true)
+	if err != nil {
+		assert.Failf(t, "Fail to add answer", "%v", err)
+	}
+	endSession(t, connection, sessionUuid)
+
+	// When
+	detail, err := s.FindQuizSessionByUuid(context.Background(), sessionUuid)
+	if err != nil {
+		assert.FailNow(t, "Fail to get session", "%v", err)
+	}
+
+	// Then
+	assert.Equal(t, 2, detail.Questions["light"].Points)
+	assert.Equal(t, 3, detail.Questions["heavy"].Points)
+	assert.Equal(t, 2.0, detail.Questions["light"].Score)
+	assert.Equal(t, 0.0, detail.Questions["heavy"].Score)
+	assert.Equal(t, &domain.SessionResult{GoodAnswer: 1, TotalAnswer: 2, PointsEarned: 2, PointsPossible: 5}, detail.Result)
+}
+
+func TestQuizDBRepository_FindQuizSessionsByUuids_scoring(t *testing.T) {
+	connection := getDBConnection(t, true)
+	defer connection.Close()
+
+	r := NewQuizRepository(NewConnectionWrapperForTest("data", connection))
+	s := domain.NewQuizService(r)
+
+	// Given a quiz by strategy with a right Choice question and an Ordering question having a single
+	// item at the right position, both worth 3 points
+	expectedPoints := map[domain.ScoringStrategy]float64{
+		domain.AllOrNothing:    3,
+		domain.Proportional:    4,
+		domain.NegativeMarking: 2,
+	}
+	quizSha1s := map[string]domain.ScoringStrategy{}
+	for strategy := range expectedPoints {
+		sha1 := fmt.Sprintf("quiz-%d", strategy)
+		quizSha1s[sha1] = strategy
+
+		quiz := quizWithQuestion(sha1, sha1+".quiz.md", choiceQuestion("choice", 1, 3, "valid", "wrong"))
+		quiz.Metadata.Scoring = strategy
+		ordering := orderingQuiz(sha1, "", "ordering", "first", "second", "third").Questions["ordering"]
+		ordering.Position = 2
+		ordering.Points = 3
+		quiz.Questions["ordering"] = ordering
+		err := r.Create(context.Background(), quiz)
+		if err != nil {
+			assert.FailNow(t, "Fail to create quiz", "%v", err)
+		}
+
+		sessionUuid := startSession(t, r, sha1)
+		err = r.AddSessionAnswer(context.Background(), sessionUuid, "choice", "choice-valid", true)
+		if err != nil {
+			assert.Failf(t, "Fail to add answer", "%v", err)
+		}
+		err = r.AddSessionOrderAnswer(context.Background(), sessionUuid, "ordering", map[string]int{"first": 1, "second": 3, "third": 2})
+		if err != nil {
+			assert.Failf(t, "Fail to add answer", "%v", err)
+		}
+		endSession(t, connection, sessionUuid)
+	}
+
+	// When
+	sessions, _, err := s.FindAllSessions(context.Background(), true, "", 10, 0)
+	if err != nil {
+		assert.FailNow(t, "Fail to get sessions", "%v", err)
+	}
+
+	// Then
+	assert.Len(t, sessions, len(expectedPoints))
+	for _, session := range sessions {
+		strategy := quizSha1s[session.QuizSha1]
+		if assert.NotNil(t, session.Result, session.QuizSha1) {
+			assert.Equal(t, expectedPoints[strategy], session.Result.PointsEarned, session.QuizSha1)
+			assert.Equal(t, 6, session.Result.PointsPossible, session.QuizSha1)
+		}
+
+		detail, err := r.FindQuizSessionByUuid(context.Background(), session.Id)
+		if err != nil {
+			assert.Failf(t, "Fail to get session", "%v", err)
+			continue
+		}
+		assert.Equal(t, strategy, detail.Scoring)
+	}
+}
+
+func TestQuizDBRepository_FindQuizSessionByUuid_explanations(t *testing.T) {
+	connection := getDBConnection(t, true)
+	defer connection.Close()
+
+	r := NewQuizRepository(NewConnectionWrapperForTest("data", connection))
+
+	// Given
+	question := choiceQuestion("question", 1, 1, "valid", "wrong")
+	question.Explanation = "The first Avengers."
+	answer := question.Answers["question-wrong"]
+	answer.Explanation = "Thanos is a villain."
+	question.Answers["question-wrong"] = answer
+	err := r.Create(context.Background(), quizWithQuestion(sha1Quiz1, quizFilename1, question))
+	if err != nil {
+		assert.Failf(t, "Fail to create quiz", "%v", err)
+	}
+	sessionUuid := startSession(t, r, sha1Quiz1)
+
+	// When
+	full, err := r.FindFullBySha1(context.Background(), sha1Quiz1, "")
+	if err != nil {
+		assert.Failf(t, "Fail to get quiz", "%v", err)
+	}
+	running, err := r.FindQuizSessionByUuid(context.Background(), sessionUuid)
+	if err != nil {
+		assert.FailNow(t, "Fail to get session", "%v", err)
+	}
+	endSession(t, connection, sessionUuid)
+	over, err := r.FindQuizSessionByUuid(context.Background(), sessionUuid)
+	if err != nil {
+		assert.FailNow(t, "Fail to get session", "%v", err)
+	}
+
+	// Then the explanations are only revealed once the session is over
+	assert.Equal(t, "The first Avengers.", full.Questions["question"].Explanation)
+	assert.Equal(t, "Thanos is a villain.", full.Questions["question"].Answers["question-wrong"].Explanation)
+
+	assert.Equal(t, "", running.Questions["question"].Explanation)
+	assert.Equal(t, "", running.Questions["question"].Answers["question-wrong"].Explanation)
+
+	assert.Equal(t, "The first Avengers.", over.Questions["question"].Explanation)
+	assert.Equal(t, "Thanos is a villain.", over.Questions["question"].Answers["question-wrong"].Explanation)
+}
+
+func TestQuizDBRepository_FindAssetBySha1(t *testing.T) {
+	connection := getDBConnection(t, true)
+	defer connection.Close()
+
+	r := NewQuizRepository(NewConnectionWrapperForTest("data", connection))
+
+	// Given
+	asset := &domain.Asset{Sha1: "hulk", Filename: "images/hulk.png", ContentType: "image/png", Content: []byte("hulk")}
+	quiz := quizWithQuestion(sha1Quiz1, quizFilename1, choiceQuestion("question", 1, 1, "valid"))
+	quiz.Assets = map[string]*domain.Asset{asset.Sha1: asset}
+	err := r.Create(context.Background(), quiz)
+	if err != nil {
+		assert.Failf(t, "Fail to create quiz", "%v", err)
+	}
+
+	// When
+	found, err := r.FindAssetBySha1(context.Background(), "hulk")
+	if err != nil {
+		assert.Failf(t, "Fail to get asset", "%v", err)
+	}
+	notFound, err := r.FindAssetBySha1(context.Background(), "thor")
+	if err != nil {
+		assert.Failf(t, "Fail to get asset", "%v", err)
+	}
+
+	// Then
+	assert.Equal(t, asset, found)
+	assert.Nil(t, notFound)
+}
+
+func TestQuizDBRepository_StartSession_pool(t *testing.T) {
+	connection := getDBConnection(t, true)
+	defer connection.Close()
+
+	r := NewQuizRepository(NewConnectionWrapperForTest("data", connection))
+
+	// Given a quiz drawing one of its team questions
+	team := choiceQuestion("team", 1, 1, "valid")
+	team.Tags = []string{"team", "avengers"}
+	solo := choiceQuestion("solo", 2, 1, "valid")
+	quiz := quizWithQuestion(sha1Quiz1, quizFilename1, team)
+	quiz.Questions["solo"] = solo
+	quiz.Metadata.Pool = domain.QuizPool{Draw: 1, Tags: []string{"team"}}
+	err := r.Create(context.Background(), quiz)
+	if err != nil {
+		assert.Failf(t, "Fail to create quiz", "%v", err)
+	}
+	err = NewUserRepository(r.w).CreateOrReplaceUser(context.Background(), &domain.User{Id: userId1, Login: login, Name: name, Role: domain.Student})
+	if err != nil {
+		assert.FailNow(t, "Fail to create user", "%v", err)
+	}
+
+	// When
+	pool, err := r.FindPoolBySha1(context.Background(), sha1Quiz1)
+	if err != nil {
+		assert.FailNow(t, "Fail to get pool", "%v", err)
+	}
+	sessionUuid, err := r.StartSession(context.Background(), userId1, sha1Quiz1, 42, []string{"team"})
+	if err != nil {
+		assert.FailNow(t, "Fail to start session", "%v", err)
+	}
+	drawnErr := r.AddSessionAnswer(context.Background(), sessionUuid, "team", "team-valid", true)
+	notDrawnErr := r.AddSessionAnswer(context.Background(), sessionUuid, "solo", "solo-valid", true)
+	detail, err := r.FindQuizSessionByUuid(context.Background(), sessionUuid)
+	if err != nil {
+		assert.FailNow(t, "Fail to get session", "%v", err)
+	}
+
+	// Then
+	assert.Equal(t, domain.QuizPool{Draw: 1, Tags: []string{"team"}}, pool.Metadata.Pool)
+	assert.Len(t, pool.Questions, 2)
+	assert.Equal(t, []string{"team", "avengers"}, pool.Questions["team"].Tags)
+	assert.Empty(t, pool.Questions["solo"].Tags)
+
+	assert.NoError(t, drawnErr)
+	assert.Error(t, notDrawnErr)
+	assert.Len(t, detail.Questions, 1)
+	assert.True(t, detail.Questions["team"].Answers["team-valid"].Checked)
+}
+
+func TestQuizDBRepository_FindQuizSessionByUuid_shuffle(t *testing.T) {
+	connection := getDBConnection(t, true)
+	defer connection.Close()
+
+	r := NewQuizRepository(NewConnectionWrapperForTest("data", connection))
+
+	// Given
+	quiz := quizWithQuestion(sha1Quiz1, quizFilename1, choiceQuestion("question", 1, 1, "valid", "wrong", "other"))
+	quiz.Metadata.ShuffleQuestions = true
+	quiz.Metadata.ShuffleAnswers = true
+	err := r.Create(context.Background(), quiz)
+	if err != nil {
+		assert.Failf(t, "Fail to create quiz", "%v", err)
+	}
+
+	// When
+	detail, err := r.FindQuizSessionByUuid(context.Background(), startSession(t, r, sha1Quiz1))
+	if err != nil {
+		assert.FailNow(t, "Fail to get session", "%v", err)
+	}
+
+	// Then the session keeps its seed to shuffle the same way each time it is read
+	assert.Equal(t, int64(42), detail.Seed)
+	assert.True(t, detail.ShuffleQuestions)
+	assert.True(t, detail.ShuffleAnswers)
+	assert.Equal(t, 1, detail.Questions["question"].Answers["question-valid"].Ordinal)
+	assert.Equal(t, 3, detail.Questions["question"].Answers["question-other"].Ordinal)
+}
+
+func TestQuizDBRepository_Create_translations(t *testing.T) {
+	connection := getDBConnection(t, true)
+	defer connection.Close()
+
+	r := NewQuizRepository(NewConnectionWrapperForTest("data", connection))
+
+	// Given a short-answer question translated in french
+	quiz := quizWithQuestion(sha1Quiz1, quizFilename1, domain.QuizQuestion{
+		Sha1:        "question",
+		Kind:        domain.ShortAnswer,
+		Content:     "Who assembles ?",
+		Explanation: "They are a team.",
+		Points:      1,
+		Answers: map[string]domain.QuizQuestionAnswer{
+			"avengers": {Sha1: "avengers", Content: "Avengers", Valid: true, Match: domain.CaseInsensitiveMatch, Ordinal: 1},
+		},
+	})
+	quiz.Metadata.Lang = "en"
+	translations := map[string]domain.QuizTranslation{
+		"fr": {
+			Name:        "Univers Marvel",
+			Description: "Le quiz des Avengers",
+			Questions: map[string]domain.QuestionTranslation{
+				"question": {
+					Content:     "Qui se rassemble ?",
+					Explanation: "C'est une équipe.",
+					Answers:     map[string]domain.AnswerTranslation{"avengers": {Content: "Vengeurs"}},
+				},
+			},
+		},
+	}
+	quiz.Translations = translations
+	err := r.Create(context.Background(), quiz)
+	if err != nil {
+		assert.Failf(t, "Fail to create quiz", "%v", err)
+	}
+	sessionUuid := startSession(t, r, sha1Quiz1)
+
+	// When
+	found, err := r.FindTranslations(context.Background(), sha1Quiz1)
+	if err != nil {
+		assert.Failf(t, "Fail to get translations", "%v", err)
+	}
+	active, err := r.FindActiveQuizTranslations(context.Background())
+	if err != nil {
+		assert.Failf(t, "Fail to get translations", "%v", err)
+	}
+	answers, err := r.FindSessionAnswerTranslations(context.Background(), sessionUuid, "question")
+	if err != nil {
+		assert.Failf(t, "Fail to get translations", "%v", err)
+	}
+	detail, err := r.FindQuizSessionByUuid(context.Background(), sessionUuid)
+	if err != nil {
+		assert.FailNow(t, "Fail to get session", "%v", err)
+	}
+
+	// Then
+	assert.Equal(t, translations, found)
+	assert.Equal(t, map[string]map[string]domain.QuizTranslation{
+		sha1Quiz1: {"fr": {Name: "Univers Marvel", Description: "Le quiz des Avengers"}},
+	}, active)
+	assert.Equal(t, map[string][]string{"avengers": {"Vengeurs"}}, answers)
+	assert.Equal(t, "en", detail.Lang)
+}
+
+func TestQuizDBRepository_Create_questionIdAcrossVersions(t *testing.T) {
+	connection := getDBConnection(t, true)
+	defer connection.Close()
+
+	r := NewQuizRepository(NewConnectionWrapperForTest("data", connection))
+
+	// Given a question whose content changes between two versions of the quiz, and a question kept
+	// as is that gets an id in the second version
+	hulk1 := choiceQuestion("hulk-1", 1, 1, "valid")
+	hulk1.Id = "hulk"
+	hulk2 := choiceQuestion("hulk-2", 1, 1, "valid")
+	hulk2.Id = "hulk"
+	thor := choiceQuestion("thor", 2, 1, "valid")
+
+	version1 := quizWithQuestion(sha1Quiz1, quizFilename1, hulk1)
+	version1.Questions["thor"] = thor
+	err := r.Create(context.Background(), version1)
+	if err != nil {
+		assert.Failf(t, "Fail to create quiz", "%v", err)
+	}
+	session1 := startSession(t, r, sha1Quiz1)
+
+	thor.Id = "thor-id"
+	version2 := quizWithQuestion(sha1Quiz2, quizFilename1, hulk2)
+	version2.Version = 2
+	version2.Questions["thor"] = thor
+	err = r.Create(context.Background(), version2)
+	if err != nil {
+		assert.Failf(t, "Fail to create quiz", "%v", err)
+	}
+
+	// When
+	full1, err := r.FindFullBySha1(context.Background(), sha1Quiz1, "")
+	if err != nil {
+		assert.Failf(t, "Fail to get quiz", "%v", err)
+	}
+	full2, err := r.FindFullBySha1(context.Background(), sha1Quiz2, "")
+	if err != nil {
+		assert.Failf(t, "Fail to get quiz", "%v", err)
+	}
+	detail1, err := r.FindQuizSessionByUuid(context.Background(), session1)
+	if err != nil {
+		assert.FailNow(t, "Fail to get session", "%v", err)
+	}
+
+	// Then both versions of the changed question share its id, and the latest version gives its id
+	// to the kept question in every version
+	assert.Equal(t, "hulk", full1.Questions["hulk-1"].Id)
+	assert.Equal(t, "hulk", full2.Questions["hulk-2"].Id)
+	assert.Equal(t, "thor-id", full1.Questions["thor"].Id)
+	assert.Equal(t, "thor-id", full2.Questions["thor"].Id)
+	assert.Equal(t, "hulk", detail1.Questions["hulk-1"].Id)
+	assert.Equal(t, "thor-id", detail1.Questions["thor"].Id)
+}
+
+func TestQuizDBRepository_FindAllActive_category(t *testing.T) {
+	connection := getDBConnection(t, true)
+	defer connection.Close()
+
+	r := NewQuizRepository(NewConnectionWrapperForTest("data", connection))
+
+	// Given
+	for sha1, category := range map[string]string{
+		"heroes":   "marvel/heroes",
+		"marvel":   "marvel",
+		"villains": "marvelous",
+		"root":     "",
+	} {
+		quiz := quizWithQuestion(sha1, category+"/"+sha1+".quiz.md", choiceQuestion("question", 1, 1, "valid"))
+		quiz.Category = category
+		err := r.Create(context.Background(), quiz)
+		if err != nil {
+			assert.FailNow(t, "Fail to create quiz", "%v", err)
+		}
+	}
+
+	// When
+	quizzes, err := r.FindAllActive(context.Background(), "", "marvel", 10, 0)
+	if err != nil {
+		assert.FailNow(t, "Fail to get quizzes", "%v", err)
+	}
+	count, err := r.CountAllActive(context.Background(), "", "marvel")
+	if err != nil {
+		assert.FailNow(t, "Fail to count quizzes", "%v", err)
+	}
+	all, err := r.CountAllActive(context.Background(), "", "")
+	if err != nil {
+		assert.FailNow(t, "Fail to count quizzes", "%v", err)
+	}
+
+	// Then the category holds the quizzes of its sub-folders
+	categories := map[string]string{}
+	for _, quiz := range quizzes {
+		categories[quiz.Sha1] = quiz.Category
+	}
+	assert.Equal(t, map[string]string{"heroes": "marvel/heroes", "marvel": "marvel"}, categories)
+	assert.Equal(t, uint32(2), count)
+	assert.Equal(t, uint32(4), all)
+}
+
+func TestQuizDBRepository_ActivateOnlyVersion_source(t *testing.T) {
+	connection := getDBConnection(t, true)
+	defer connection.Close()
+
+	r := NewQuizRepository(NewConnectionWrapperForTest("data", connection))
+
+	// Given the same file in two sources, twice in the first one
+	version1 := quizWithQuestion("default-1", quizFilename1, choiceQuestion("question", 1, 1, "valid"))
+	version2 := quizWithQuestion("default-2", quizFilename1, choiceQuestion("question", 1, 1, "valid"))
+	version2.Version = 2
+	exams := quizWithQuestion("exams-1", quizFilename1, choiceQuestion("question", 1, 1, "valid"))
+	exams.Source = "exams"
+	for _, quiz := range []*domain.Quiz{version1, version2, exams} {
+		err := r.Create(context.Background(), quiz)
+		if err != nil {
+			assert.FailNow(t, "Fail to create quiz", "%v", err)
+		}
+	}
+
+	// When
+	err := r.ActivateOnlyVersion(context.Background(), "default", quizFilename1, 2)
+	if err != nil {
+		assert.FailNow(t, "Fail to activate version", "%v", err)
+	}
+	defaultLatest, err := r.FindLatestVersionByFilename(context.Background(), "default", quizFilename1)
+	if err != nil {
+		assert.FailNow(t, "Fail to get quiz", "%v", err)
+	}
+	examsLatest, err := r.FindLatestVersionByFilename(context.Background(), "exams", quizFilename1)
+	if err != nil {
+		assert.FailNow(t, "Fail to get quiz", "%v", err)
+	}
+	active, err := r.FindAllActive(context.Background(), "", "", 10, 0)
+	if err != nil {
+		assert.FailNow(t, "Fail to get quizzes", "%v", err)
+	}
+
+	// Then the versions of a file are only the ones of its source
+	assert.Equal(t, "default-2", defaultLatest.Sha1)
+	assert.Equal(t, 2, defaultLatest.Version)
+	assert.Equal(t, "exams-1", examsLatest.Sha1)
+	assert.Equal(t, "exams", examsLatest.Source)
+
+	sha1s := make([]string, 0, len(active))
+	for _, quiz := range active {
+		sha1s = append(sha1s, quiz.Sha1)
+	}
+	assert.ElementsMatch(t, []string{"default-2", "exams-1"}, sha1s)
+}
@@ -27,37 +27,75 @@ import (
 	"github.com/google/uuid"
 )
 
+type Asset struct {
+	Sha1        string `db:"sha1"`
+	Filename    string `db:"filename"`
+	ContentType string `db:"content_type"`
+	Content     []byte `db:"content"`
+}
+
 type Quiz struct {
-	Sha1      string `db:"sha1"`
-	Name      string `db:"name"`
-	Filename  string `db:"filename"`
-	Version   int    `db:"version"`
-	Active    bool   `db:"active"`
-	CreatedAt string `db:"created_at"`
-	Duration  int    `db:"duration"`
+	Sha1             string `db:"sha1"`
+	Name             string `db:"name"`
+	Filename         string `db:"filename"`
+	Version          int    `db:"version"`
+	Active           bool   `db:"active"`
+	CreatedAt        string `db:"created_at"`
+	Duration         int    `db:"duration"`
+	Description      string `db:"description"`
+	Tags             string `db:"tags"`
+	Author           string `db:"author"`
+	PassMark         int    `db:"pass_mark"`
+	ShuffleQuestions bool   `db:"shuffle_questions"`
+	ShuffleAnswers   bool   `db:"shuffle_answers"`
+	MaxAttempts      int    `db:"max_attempts"`
+	Scoring          int8   `db:"scoring"`
+	PoolDraw         int    `db:"pool_draw"`
+	PoolTags         string `db:"pool_tags"`
+	Lang             string `db:"lang"`
+	Category         string `db:"category"`
+	Source           string `db:"source"`
+	ID               string `db:"id"`
 }
 
 type QuizAnswer struct {
-	Sha1    string `db:"sha1"`
-	Valid   bool   `db:"valid"`
-	Content string `db:"content"`
+	Sha1 string `db:"sha1"`
 }
 
-type QuizAnswerCountView struct {
-	QuizSha1       string `db:"quiz_sha1"`
-	CheckedAnswers int    `db:"checked_answers"`
+type QuizAnswerTranslation struct {
+	QuizSha1     string `db:"quiz_sha1"`
+	Lang         string `db:"lang"`
+	QuestionSha1 string `db:"question_sha1"`
+	AnswerSha1   string `db:"answer_sha1"`
+	Content      string `db:"content"`
+	RightContent string `db:"right_content"`
+	Explanation  string `db:"explanation"`
 }
 
 type QuizClassView struct {
-	Sha1      string    `db:"sha1"`
-	Name      string    `db:"name"`
-	Filename  string    `db:"filename"`
-	Version   int       `db:"version"`
-	Active    bool      `db:"active"`
-	CreatedAt string    `db:"created_at"`
-	Duration  int       `db:"duration"`
-	ClassUuid uuid.UUID `db:"class_uuid"`
-	ClassName string    `db:"class_name"`
+	Sha1             string    `db:"sha1"`
+	Name             string    `db:"name"`
+	Filename         string    `db:"filename"`
+	Version          int       `db:"version"`
+	Active           bool      `db:"active"`
+	CreatedAt        string    `db:"created_at"`
+	Duration         int       `db:"duration"`
+	Description      string    `db:"description"`
+	Tags             string    `db:"tags"`
+	Author           string    `db:"author"`
+	PassMark         int       `db:"pass_mark"`
+	ShuffleQuestions bool      `db:"shuffle_questions"`
+	ShuffleAnswers   bool      `db:"shuffle_answers"`
+	MaxAttempts      int       `db:"max_attempts"`
+	Scoring          int8      `db:"scoring"`
+	PoolDraw         int       `db:"pool_draw"`
+	PoolTags         string    `db:"pool_tags"`
+	Lang             string    `db:"lang"`
+	Category         string    `db:"category"`
+	Source           string    `db:"source"`
+	ID               string    `db:"id"`
+	ClassUuid        uuid.UUID `db:"class_uuid"`
+	ClassName        string    `db:"class_name"`
 }
 
 type QuizClassVisibility struct {
@@ -66,59 +104,116 @@ type QuizClassVisibility struct {
 }
 
 type QuizQuestion struct {
-	Sha1         string         `db:"sha1"`
-	Position     int            `db:"position"`
-	Content      string         `db:"content"`
-	Code         sql.NullString `db:"code"`
-	CodeLanguage sql.NullString `db:"code_language"`
+	Sha1 string `db:"sha1"`
 }
 
 type QuizQuestionAnswer struct {
+	QuizSha1     string `db:"quiz_sha1"`
+	QuestionSha1 string `db:"question_sha1"`
+	AnswerSha1   string `db:"answer_sha1"`
+	Content      string `db:"content"`
+	Valid        bool   `db:"valid"`
+	MatchMode    int8   `db:"match_mode"`
+	Explanation  string `db:"explanation"`
+	Ordinal      int    `db:"ordinal"`
+	Position     int    `db:"position"`
+}
+
+type QuizQuestionIdentity struct {
+	QuizSource   string `db:"quiz_source"`
+	QuizFilename string `db:"quiz_filename"`
+	QuestionSha1 string `db:"question_sha1"`
+	QuestionID   string `db:"question_id"`
+}
+
+type QuizQuestionPair struct {
+	QuizSha1     string `db:"quiz_sha1"`
 	QuestionSha1 string `db:"question_sha1"`
 	AnswerSha1   string `db:"answer_sha1"`
+	RightSha1    string `db:"right_sha1"`
+	RightContent string `db:"right_content"`
 }
 
 type QuizQuestionQuiz struct {
+	QuizSha1      string         `db:"quiz_sha1"`
+	QuestionSha1  string         `db:"question_sha1"`
+	Position      int            `db:"position"`
+	Kind          int8           `db:"kind"`
+	Content       string         `db:"content"`
+	Code          sql.NullString `db:"code"`
+	CodeLanguage  sql.NullString `db:"code_language"`
+	PartialCredit bool           `db:"partial_credit"`
+	Points        int            `db:"points"`
+	Explanation   string         `db:"explanation"`
+	Tags          string         `db:"tags"`
+}
+
+type QuizQuestionTranslation struct {
 	QuizSha1     string `db:"quiz_sha1"`
+	Lang         string `db:"lang"`
 	QuestionSha1 string `db:"question_sha1"`
+	Content      string `db:"content"`
+	Code         string `db:"code"`
+	Explanation  string `db:"explanation"`
 }
 
 type QuizSessionDetailView struct {
-	SessionUuid          uuid.UUID      `db:"session_uuid"`
-	UserID               string         `db:"user_id"`
-	RemainingSec         int            `db:"remaining_sec"`
-	QuizSha1             string         `db:"quiz_sha1"`
-	QuizName             string         `db:"quiz_name"`
-	QuizDuration         int            `db:"quiz_duration"`
-	CheckedAnswers       int            `db:"checked_answers"`
-	Results              int            `db:"results"`
-	QuestionSha1         string         `db:"question_sha1"`
-	QuestionPosition     int            `db:"question_position"`
-	QuestionContent      string         `db:"question_content"`
-	QuestionCode         sql.NullString `db:"question_code"`
-	QuestionCodeLanguage sql.NullString `db:"question_code_language"`
-	AnswerSha1           string         `db:"answer_sha1"`
-	AnswerContent        string         `db:"answer_content"`
-	AnswerChecked        bool           `db:"answer_checked"`
-	AnswerValid          bool           `db:"answer_valid"`
+	SessionUuid            uuid.UUID      `db:"session_uuid"`
+	UserID                 string         `db:"user_id"`
+	RemainingSec           int            `db:"remaining_sec"`
+	SessionSeed            int64          `db:"session_seed"`
+	QuizSha1               string         `db:"quiz_sha1"`
+	QuizName               string         `db:"quiz_name"`
+	QuizDuration           int            `db:"quiz_duration"`
+	QuizScoring            int8           `db:"quiz_scoring"`
+	QuizShuffleQuestions   bool           `db:"quiz_shuffle_questions"`
+	QuizShuffleAnswers     bool           `db:"quiz_shuffle_answers"`
+	QuizLang               string         `db:"quiz_lang"`
+	QuestionSha1           string         `db:"question_sha1"`
+	QuestionID             string         `db:"question_id"`
+	QuestionKind           int8           `db:"question_kind"`
+	QuestionPosition       int            `db:"question_position"`
+	QuestionContent        string         `db:"question_content"`
+	QuestionCode           sql.NullString `db:"question_code"`
+	QuestionCodeLanguage   sql.NullString `db:"question_code_language"`
+	QuestionPartialCredit  bool           `db:"question_partial_credit"`
+	QuestionPoints         int            `db:"question_points"`
+	QuestionExplanation    string         `db:"question_explanation"`
+	AnswerSha1             string         `db:"answer_sha1"`
+	AnswerContent          string         `db:"answer_content"`
+	AnswerChecked          bool           `db:"answer_checked"`
+	AnswerValid            bool           `db:"answer_valid"`
+	AnswerMatchMode        int8           `db:"answer_match_mode"`
+	AnswerText             sql.NullString `db:"answer_text"`
+	AnswerPosition         int            `db:"answer_position"`
+	AnswerAnsweredPosition sql.NullInt64  `db:"answer_answered_position"`
+	AnswerRightSha1        sql.NullString `db:"answer_right_sha1"`
+	AnswerRightContent     sql.NullString `db:"answer_right_content"`
+	AnswerExplanation      string         `db:"answer_explanation"`
+	AnswerOrdinal          int            `db:"answer_ordinal"`
 }
 
 type QuizSessionView struct {
-	QuizSha1       string    `db:"quiz_sha1"`
-	QuizName       string    `db:"quiz_name"`
-	QuizFilename   string    `db:"quiz_filename"`
-	QuizVersion    int       `db:"quiz_version"`
-	QuizDuration   int       `db:"quiz_duration"`
-	QuizCreatedAt  string    `db:"quiz_created_at"`
-	SessionUuid    uuid.UUID `db:"session_uuid"`
-	UserID         string    `db:"user_id"`
-	UserName       string    `db:"user_name"`
-	UserPicture    string    `db:"user_picture"`
-	ClassUuid      uuid.UUID `db:"class_uuid"`
-	ClassName      string    `db:"class_name"`
-	RemainingSec   int       `db:"remaining_sec"`
-	CheckedAnswers int       `db:"checked_answers"`
-	Results        int       `db:"results"`
+	QuizSha1      string    `db:"quiz_sha1"`
+	QuizName      string    `db:"quiz_name"`
+	QuizFilename  string    `db:"quiz_filename"`
+	QuizVersion   int       `db:"quiz_version"`
+	QuizDuration  int       `db:"quiz_duration"`
+	QuizCreatedAt string    `db:"quiz_created_at"`
+	SessionUuid   uuid.UUID `db:"session_uuid"`
+	UserID        string    `db:"user_id"`
+	UserName      string    `db:"user_name"`
+	UserPicture   string    `db:"user_picture"`
+	ClassUuid     uuid.UUID `db:"class_uuid"`
+	ClassName     string    `db:"class_name"`
+	RemainingSec  int       `db:"remaining_sec"`
+}
+
+type QuizTranslation struct {
+	QuizSha1    string `db:"quiz_sha1"`
+	Lang        string `db:"lang"`
+	Name        string `db:"name"`
+	Description string `db:"description"`
 }
 
 type Role struct {
@@ -131,36 +226,43 @@ type Session struct {
 	QuizSha1  string    `db:"quiz_sha1"`
 	UserID    string    `db:"user_id"`
 	CreatedAt time.Time `db:"created_at"`
+	Seed      int64     `db:"seed"`
 }
 
 type SessionAnswer struct {
+	SessionUuid  uuid.UUID      `db:"session_uuid"`
+	QuestionSha1 string         `db:"question_sha1"`
+	AnswerSha1   string         `db:"answer_sha1"`
+	Checked      bool           `db:"checked"`
+	Content      sql.NullString `db:"content"`
+	Position     sql.NullInt64  `db:"position"`
+}
+
+type SessionQuestion struct {
 	SessionUuid  uuid.UUID `db:"session_uuid"`
 	QuestionSha1 string    `db:"question_sha1"`
-	AnswerSha1   string    `db:"answer_sha1"`
-	Checked      bool      `db:"checked"`
 }
 
 type SessionResponseView struct {
-	QuizSha1     string      `db:"quiz_sha1"`
-	QuestionSha1 string      `db:"question_sha1"`
-	AnswerSha1   string      `db:"answer_sha1"`
-	SessionUuid  uuid.UUID   `db:"session_uuid"`
-	UserID       string      `db:"user_id"`
-	Checked      bool        `db:"checked"`
-	Result       interface{} `db:"result"`
+	QuizSha1     string         `db:"quiz_sha1"`
+	QuestionSha1 string         `db:"question_sha1"`
+	AnswerSha1   string         `db:"answer_sha1"`
+	SessionUuid  uuid.UUID      `db:"session_uuid"`
+	UserID       string         `db:"user_id"`
+	Checked      bool           `db:"checked"`
+	Content      sql.NullString `db:"content"`
+	Position     sql.NullInt64  `db:"position"`
 }
 
 type SessionView struct {
-	Uuid           uuid.UUID `db:"uuid"`
-	QuizSha1       string    `db:"quiz_sha1"`
-	QuizName       string    `db:"quiz_name"`
-	QuizActive     bool      `db:"quiz_active"`
-	UserID         string    `db:"user_id"`
-	UserName       string    `db:"user_name"`
-	UserPicture    string    `db:"user_picture"`
-	RemainingSec   int       `db:"remaining_sec"`
-	CheckedAnswers int       `db:"checked_answers"`
-	Results        int       `db:"results"`
+	Uuid         uuid.UUID `db:"uuid"`
+	QuizSha1     string    `db:"quiz_sha1"`
+	QuizName     string    `db:"quiz_name"`
+	QuizActive   bool      `db:"quiz_active"`
+	UserID       string    `db:"user_id"`
+	UserName     string    `db:"user_name"`
+	UserPicture  string    `db:"user_picture"`
+	RemainingSec int       `db:"remaining_sec"`
 }
 
 type StudentClass struct {
@@ -176,6 +278,7 @@ type User struct {
 	Active    bool      `db:"active"`
 	RoleID    int8      `db:"role_id"`
 	ClassUuid uuid.UUID `db:"class_uuid"`
+	Lang      string    `db:"lang"`
 }
 
 type UserClassView struct {
@@ -187,4 +290,5 @@ type UserClassView struct {
 	RoleID    int8      `db:"role_id"`
 	ClassUuid uuid.UUID `db:"class_uuid"`
 	ClassName string    `db:"class_name"`
+	Lang      string    `db:"lang"`
 }
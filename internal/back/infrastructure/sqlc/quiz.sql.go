@@ -24,6 +24,7 @@ package sqlc
 import (
 	"context"
 	"database/sql"
+	"strings"
 
 	"github.com/google/uuid"
 )
@@ -31,17 +32,19 @@ import (
 const activateOnlyVersion = `-- name: ActivateOnlyVersion :exec
 UPDATE quiz
 SET active = 0
-WHERE filename = ?
+WHERE source = ?
+  AND filename = ?
   AND version <> ?
 `
 
 type ActivateOnlyVersionParams struct {
+	Source   string `db:"source"`
 	Filename string `db:"filename"`
 	Version  int    `db:"version"`
 }
 
 func (q *Queries) ActivateOnlyVersion(ctx context.Context, arg ActivateOnlyVersionParams) error {
-	_, err := q.db.ExecContext(ctx, activateOnlyVersion, arg.Filename, arg.Version)
+	_, err := q.db.ExecContext(ctx, activateOnlyVersion, arg.Source, arg.Filename, arg.Version)
 	return err
 }
 
@@ -49,10 +52,13 @@ const countAllActiveQuiz = `-- name: CountAllActiveQuiz :one
 SELECT COUNT(1)
 FROM quiz q
 WHERE q.active = 1
+  AND (?1 = ''
+    OR q.category = ?1
+    OR substr(q.category, 1, length(?1) + 1) = ?1 || '/')
 `
 
-func (q *Queries) CountAllActiveQuiz(ctx context.Context) (int64, error) {
-	row := q.db.QueryRowContext(ctx, countAllActiveQuiz)
+func (q *Queries) CountAllActiveQuiz(ctx context.Context, category string) (int64, error) {
+	row := q.db.QueryRowContext(ctx, countAllActiveQuiz, category)
 	var count int64
 	err := row.Scan(&count)
 	return count, err
@@ -65,68 +71,162 @@ FROM quiz q
          JOIN student_class sc ON sc.uuid = qcv.class_uuid
          JOIN user u ON sc.uuid = u.class_uuid
 WHERE q.active = 1
-  AND u.id = ?
+  AND u.id = ?1
+  AND (?2 = ''
+    OR q.category = ?2
+    OR substr(q.category, 1, length(?2) + 1) = ?2 || '/')
 `
 
-func (q *Queries) CountAllActiveQuizForUser(ctx context.Context, id string) (int64, error) {
-	row := q.db.QueryRowContext(ctx, countAllActiveQuizForUser, id)
+type CountAllActiveQuizForUserParams struct {
+	ID       string `db:"id"`
+	Category string `db:"category"`
+}
+
+func (q *Queries) CountAllActiveQuizForUser(ctx context.Context, arg CountAllActiveQuizForUserParams) (int64, error) {
+	row := q.db.QueryRowContext(ctx, countAllActiveQuizForUser, arg.ID, arg.Category)
 	var count int64
 	err := row.Scan(&count)
 	return count, err
 }
 
-const createOrReplaceAnswer = `-- name: CreateOrReplaceAnswer :exec
-REPLACE INTO quiz_answer (sha1, content, valid)
-VALUES (?, ?, ?)
+const createAnswer = `-- name: CreateAnswer :exec
+INSERT OR IGNORE INTO quiz_answer (sha1)
+VALUES (?)
+`
+
+func (q *Queries) CreateAnswer(ctx context.Context, sha1 string) error {
+	_, err := q.db.ExecContext(ctx, createAnswer, sha1)
+	return err
+}
+
+const createOrReplaceAnswerTranslation = `-- name: CreateOrReplaceAnswerTranslation :exec
+REPLACE INTO quiz_answer_translation (quiz_sha1, lang, question_sha1, answer_sha1, content, right_content, explanation)
+VALUES (?, ?, ?, ?, ?, ?, ?)
 `
 
-type CreateOrReplaceAnswerParams struct {
-	Sha1    string `db:"sha1"`
-	Content string `db:"content"`
-	Valid   bool   `db:"valid"`
+type CreateOrReplaceAnswerTranslationParams struct {
+	QuizSha1     string `db:"quiz_sha1"`
+	Lang         string `db:"lang"`
+	QuestionSha1 string `db:"question_sha1"`
+	AnswerSha1   string `db:"answer_sha1"`
+	Content      string `db:"content"`
+	RightContent string `db:"right_content"`
+	Explanation  string `db:"explanation"`
 }
 
-func (q *Queries) CreateOrReplaceAnswer(ctx context.Context, arg CreateOrReplaceAnswerParams) error {
-	_, err := q.db.ExecContext(ctx, createOrReplaceAnswer, arg.Sha1, arg.Content, arg.Valid)
+func (q *Queries) CreateOrReplaceAnswerTranslation(ctx context.Context, arg CreateOrReplaceAnswerTranslationParams) error {
+	_, err := q.db.ExecContext(ctx, createOrReplaceAnswerTranslation,
+		arg.QuizSha1,
+		arg.Lang,
+		arg.QuestionSha1,
+		arg.AnswerSha1,
+		arg.Content,
+		arg.RightContent,
+		arg.Explanation,
+	)
 	return err
 }
 
-const createOrReplaceQuestion = `-- name: CreateOrReplaceQuestion :exec
-REPLACE INTO quiz_question (sha1, position, content, code, code_language)
+const createOrReplacePair = `-- name: CreateOrReplacePair :exec
+REPLACE INTO quiz_question_pair (quiz_sha1, question_sha1, answer_sha1, right_sha1, right_content)
 VALUES (?, ?, ?, ?, ?)
 `
 
-type CreateOrReplaceQuestionParams struct {
-	Sha1         string         `db:"sha1"`
-	Position     int            `db:"position"`
-	Content      string         `db:"content"`
-	Code         sql.NullString `db:"code"`
-	CodeLanguage sql.NullString `db:"code_language"`
+type CreateOrReplacePairParams struct {
+	QuizSha1     string `db:"quiz_sha1"`
+	QuestionSha1 string `db:"question_sha1"`
+	AnswerSha1   string `db:"answer_sha1"`
+	RightSha1    string `db:"right_sha1"`
+	RightContent string `db:"right_content"`
 }
 
-func (q *Queries) CreateOrReplaceQuestion(ctx context.Context, arg CreateOrReplaceQuestionParams) error {
-	_, err := q.db.ExecContext(ctx, createOrReplaceQuestion,
-		arg.Sha1,
-		arg.Position,
+func (q *Queries) CreateOrReplacePair(ctx context.Context, arg CreateOrReplacePairParams) error {
+	_, err := q.db.ExecContext(ctx, createOrReplacePair,
+		arg.QuizSha1,
+		arg.QuestionSha1,
+		arg.AnswerSha1,
+		arg.RightSha1,
+		arg.RightContent,
+	)
+	return err
+}
+
+const createOrReplaceQuestionIdentity = `-- name: CreateOrReplaceQuestionIdentity :exec
+REPLACE INTO quiz_question_identity (quiz_source, quiz_filename, question_sha1, question_id)
+VALUES (?, ?, ?, ?)
+`
+
+type CreateOrReplaceQuestionIdentityParams struct {
+	QuizSource   string `db:"quiz_source"`
+	QuizFilename string `db:"quiz_filename"`
+	QuestionSha1 string `db:"question_sha1"`
+	QuestionID   string `db:"question_id"`
+}
+
+func (q *Queries) CreateOrReplaceQuestionIdentity(ctx context.Context, arg CreateOrReplaceQuestionIdentityParams) error {
+	_, err := q.db.ExecContext(ctx, createOrReplaceQuestionIdentity,
+		arg.QuizSource,
+		arg.QuizFilename,
+		arg.QuestionSha1,
+		arg.QuestionID,
+	)
+	return err
+}
+
+const createOrReplaceQuestionTranslation = `-- name: CreateOrReplaceQuestionTranslation :exec
+REPLACE INTO quiz_question_translation (quiz_sha1, lang, question_sha1, content, code, explanation)
+VALUES (?, ?, ?, ?, ?, ?)
+`
+
+type CreateOrReplaceQuestionTranslationParams struct {
+	QuizSha1     string `db:"quiz_sha1"`
+	Lang         string `db:"lang"`
+	QuestionSha1 string `db:"question_sha1"`
+	Content      string `db:"content"`
+	Code         string `db:"code"`
+	Explanation  string `db:"explanation"`
+}
+
+func (q *Queries) CreateOrReplaceQuestionTranslation(ctx context.Context, arg CreateOrReplaceQuestionTranslationParams) error {
+	_, err := q.db.ExecContext(ctx, createOrReplaceQuestionTranslation,
+		arg.QuizSha1,
+		arg.Lang,
+		arg.QuestionSha1,
 		arg.Content,
 		arg.Code,
-		arg.CodeLanguage,
+		arg.Explanation,
 	)
 	return err
 }
 
 const createOrReplaceQuiz = `-- name: CreateOrReplaceQuiz :exec
-REPLACE INTO quiz (sha1, name, filename, version, duration, created_at)
-VALUES (?, ?, ?, ?, ?, ?)
+REPLACE INTO quiz (sha1, name, filename, version, duration, created_at, description, tags, author, pass_mark,
+                  shuffle_questions, shuffle_answers, max_attempts, scoring, pool_draw, pool_tags, lang, category, source,
+                  id)
+VALUES (?, ?, ?, ?, ?, ?, ?, ?, ?, ?, ?, ?, ?, ?, ?, ?, ?, ?, ?, ?)
 `
 
 type CreateOrReplaceQuizParams struct {
-	Sha1      string `db:"sha1"`
-	Name      string `db:"name"`
-	Filename  string `db:"filename"`
-	Version   int    `db:"version"`
-	Duration  int    `db:"duration"`
-	CreatedAt string `db:"created_at"`
+	Sha1             string `db:"sha1"`
+	Name             string `db:"name"`
+	Filename         string `db:"filename"`
+	Version          int    `db:"version"`
+	Duration         int    `db:"duration"`
+	CreatedAt        string `db:"created_at"`
+	Description      string `db:"description"`
+	Tags             string `db:"tags"`
+	Author           string `db:"author"`
+	PassMark         int    `db:"pass_mark"`
+	ShuffleQuestions bool   `db:"shuffle_questions"`
+	ShuffleAnswers   bool   `db:"shuffle_answers"`
+	MaxAttempts      int    `db:"max_attempts"`
+	Scoring          int8   `db:"scoring"`
+	PoolDraw         int    `db:"pool_draw"`
+	PoolTags         string `db:"pool_tags"`
+	Lang             string `db:"lang"`
+	Category         string `db:"category"`
+	Source           string `db:"source"`
+	ID               string `db:"id"`
 }
 
 func (q *Queries) CreateOrReplaceQuiz(ctx context.Context, arg CreateOrReplaceQuizParams) error {
@@ -137,24 +237,109 @@ func (q *Queries) CreateOrReplaceQuiz(ctx context.Context, arg CreateOrReplaceQu
 		arg.Version,
 		arg.Duration,
 		arg.CreatedAt,
+		arg.Description,
+		arg.Tags,
+		arg.Author,
+		arg.PassMark,
+		arg.ShuffleQuestions,
+		arg.ShuffleAnswers,
+		arg.MaxAttempts,
+		arg.Scoring,
+		arg.PoolDraw,
+		arg.PoolTags,
+		arg.Lang,
+		arg.Category,
+		arg.Source,
+		arg.ID,
+	)
+	return err
+}
+
+const createOrReplaceQuizTranslation = `-- name: CreateOrReplaceQuizTranslation :exec
+REPLACE INTO quiz_translation (quiz_sha1, lang, name, description)
+VALUES (?, ?, ?, ?)
+`
+
+type CreateOrReplaceQuizTranslationParams struct {
+	QuizSha1    string `db:"quiz_sha1"`
+	Lang        string `db:"lang"`
+	Name        string `db:"name"`
+	Description string `db:"description"`
+}
+
+func (q *Queries) CreateOrReplaceQuizTranslation(ctx context.Context, arg CreateOrReplaceQuizTranslationParams) error {
+	_, err := q.db.ExecContext(ctx, createOrReplaceQuizTranslation,
+		arg.QuizSha1,
+		arg.Lang,
+		arg.Name,
+		arg.Description,
 	)
 	return err
 }
 
+const createQuestion = `-- name: CreateQuestion :exec
+INSERT OR IGNORE INTO quiz_question (sha1)
+VALUES (?)
+`
+
+func (q *Queries) CreateQuestion(ctx context.Context, sha1 string) error {
+	_, err := q.db.ExecContext(ctx, createQuestion, sha1)
+	return err
+}
+
+const findActiveQuizTranslations = `-- name: FindActiveQuizTranslations :many
+SELECT qt.quiz_sha1, qt.lang, qt.name, qt.description
+FROM quiz_translation qt
+         JOIN quiz q ON q.sha1 = qt.quiz_sha1
+WHERE q.active = 1
+`
+
+func (q *Queries) FindActiveQuizTranslations(ctx context.Context) ([]QuizTranslation, error) {
+	rows, err := q.db.QueryContext(ctx, findActiveQuizTranslations)
+	if err != nil {
+		return nil, err
+	}
+	defer rows.Close()
+	items := []QuizTranslation{}
+	for rows.Next() {
+		var i QuizTranslation
+		if err := rows.Scan(
+			&i.QuizSha1,
+			&i.Lang,
+			&i.Name,
+			&i.Description,
+		); err != nil {
+			return nil, err
+		}
+		items = append(items, i)
+	}
+	if err := rows.Close(); err != nil {
+		return nil, err
+	}
+	if err := rows.Err(); err != nil {
+		return nil, err
+	}
+	return items, nil
+}
+
 const findAllActiveQuiz = `-- name: FindAllActiveQuiz :many
-SELECT sha1, name, filename, version, active, created_at, duration, class_uuid, class_name
+SELECT sha1, name, filename, version, active, created_at, duration, description, tags, author, pass_mark, shuffle_questions, shuffle_answers, max_attempts, scoring, pool_draw, pool_tags, lang, category, source, id, class_uuid, class_name
 FROM quiz_class_view qcv
 WHERE qcv.active = 1
-LIMIT ? OFFSET ?
+  AND (?1 = ''
+    OR qcv.category = ?1
+    OR substr(qcv.category, 1, length(?1) + 1) = ?1 || '/')
+LIMIT ?2 OFFSET ?3
 `
 
 type FindAllActiveQuizParams struct {
-	Limit  int64 `db:"limit"`
-	Offset int64 `db:"offset"`
+	Category string `db:"category"`
+	Limit    int64  `db:"limit"`
+	Offset   int64  `db:"offset"`
 }
 
 func (q *Queries) FindAllActiveQuiz(ctx context.Context, arg FindAllActiveQuizParams) ([]QuizClassView, error) {
-	rows, err := q.db.QueryContext(ctx, findAllActiveQuiz, arg.Limit, arg.Offset)
+	rows, err := q.db.QueryContext(ctx, findAllActiveQuiz, arg.Category, arg.Limit, arg.Offset)
 	if err != nil {
 		return nil, err
 	}
@@ -170,6 +355,20 @@ func (q *Queries) FindAllActiveQuiz(ctx context.Context, arg FindAllActiveQuizPa
 			&i.Active,
 			&i.CreatedAt,
 			&i.Duration,
+			&i.Description,
+			&i.Tags,
+			&i.Author,
+			&i.PassMark,
+			&i.ShuffleQuestions,
+			&i.ShuffleAnswers,
+			&i.MaxAttempts,
+			&i.Scoring,
+			&i.PoolDraw,
+			&i.PoolTags,
+			&i.Lang,
+			&i.Category,
+			&i.Source,
+			&i.ID,
 			&i.ClassUuid,
 			&i.ClassName,
 		); err != nil {
@@ -186,16 +385,95 @@ func (q *Queries) FindAllActiveQuiz(ctx context.Context, arg FindAllActiveQuizPa
 	return items, nil
 }
 
+const findAnswerTranslations = `-- name: FindAnswerTranslations :many
+SELECT quiz_sha1, lang, question_sha1, answer_sha1, content, right_content, explanation
+FROM quiz_answer_translation
+WHERE quiz_sha1 = ?
+`
+
+func (q *Queries) FindAnswerTranslations(ctx context.Context, quizSha1 string) ([]QuizAnswerTranslation, error) {
+	rows, err := q.db.QueryContext(ctx, findAnswerTranslations, quizSha1)
+	if err != nil {
+		return nil, err
+	}
+	defer rows.Close()
+	items := []QuizAnswerTranslation{}
+	for rows.Next() {
+		var i QuizAnswerTranslation
+		if err := rows.Scan(
+			&i.QuizSha1,
+			&i.Lang,
+			&i.QuestionSha1,
+			&i.AnswerSha1,
+			&i.Content,
+			&i.RightContent,
+			&i.Explanation,
+		); err != nil {
+			return nil, err
+		}
+		items = append(items, i)
+	}
+	if err := rows.Close(); err != nil {
+		return nil, err
+	}
+	if err := rows.Err(); err != nil {
+		return nil, err
+	}
+	return items, nil
+}
+
+const findQuestionTranslations = `-- name: FindQuestionTranslations :many
+SELECT quiz_sha1, lang, question_sha1, content, code, explanation
+FROM quiz_question_translation
+WHERE quiz_sha1 = ?
+`
+
+func (q *Queries) FindQuestionTranslations(ctx context.Context, quizSha1 string) ([]QuizQuestionTranslation, error) {
+	rows, err := q.db.QueryContext(ctx, findQuestionTranslations, quizSha1)
+	if err != nil {
+		return nil, err
+	}
+	defer rows.Close()
+	items := []QuizQuestionTranslation{}
+	for rows.Next() {
+		var i QuizQuestionTranslation
+		if err := rows.Scan(
+			&i.QuizSha1,
+			&i.Lang,
+			&i.QuestionSha1,
+			&i.Content,
+			&i.Code,
+			&i.Explanation,
+		); err != nil {
+			return nil, err
+		}
+		items = append(items, i)
+	}
+	if err := rows.Close(); err != nil {
+		return nil, err
+	}
+	if err := rows.Err(); err != nil {
+		return nil, err
+	}
+	return items, nil
+}
+
 const findQuizByFilenameAndLatestVersion = `-- name: FindQuizByFilenameAndLatestVersion :one
-SELECT sha1, name, filename, version, active, created_at, duration
+SELECT sha1, name, filename, version, active, created_at, duration, description, tags, author, pass_mark, shuffle_questions, shuffle_answers, max_attempts, scoring, pool_draw, pool_tags, lang, category, source, id
 FROM quiz
-WHERE filename = ?
+WHERE source = ?
+  AND filename = ?
 ORDER BY version DESC
 LIMIT 1
 `
 
-func (q *Queries) FindQuizByFilenameAndLatestVersion(ctx context.Context, filename string) (Quiz, error) {
-	row := q.db.QueryRowContext(ctx, findQuizByFilenameAndLatestVersion, filename)
+type FindQuizByFilenameAndLatestVersionParams struct {
+	Source   string `db:"source"`
+	Filename string `db:"filename"`
+}
+
+func (q *Queries) FindQuizByFilenameAndLatestVersion(ctx context.Context, arg FindQuizByFilenameAndLatestVersionParams) (Quiz, error) {
+	row := q.db.QueryRowContext(ctx, findQuizByFilenameAndLatestVersion, arg.Source, arg.Filename)
 	var i Quiz
 	err := row.Scan(
 		&i.Sha1,
@@ -205,64 +483,134 @@ func (q *Queries) FindQuizByFilenameAndLatestVersion(ctx context.Context, filena
 		&i.Active,
 		&i.CreatedAt,
 		&i.Duration,
+		&i.Description,
+		&i.Tags,
+		&i.Author,
+		&i.PassMark,
+		&i.ShuffleQuestions,
+		&i.ShuffleAnswers,
+		&i.MaxAttempts,
+		&i.Scoring,
+		&i.PoolDraw,
+		&i.PoolTags,
+		&i.Lang,
+		&i.Category,
+		&i.Source,
+		&i.ID,
 	)
 	return i, err
 }
 
 const findQuizFullBySha1 = `-- name: FindQuizFullBySha1 :many
-SELECT q.sha1           AS quiz_sha1,
-       q.filename       AS quiz_filename,
-       q.name           AS quiz_name,
-       q.version        AS quiz_version,
-       q.created_at     AS quiz_created_at,
-       q.duration       AS quiz_duration,
-       q.active         AS quiz_active,
-       qq.sha1          AS question_sha1,
-       qq.content       AS question_content,
-       qq.position      AS question_position,
-       qq.code          AS question_code,
-       qq.code_language AS question_code_language,
-       qa.sha1          AS answer_sha1,
-       qa.content       AS answer_content,
-       qa.valid         AS answer_valid
+SELECT q.sha1              AS quiz_sha1,
+       q.filename          AS quiz_filename,
+       q.name              AS quiz_name,
+       q.version           AS quiz_version,
+       q.created_at        AS quiz_created_at,
+       q.duration          AS quiz_duration,
+       q.active            AS quiz_active,
+       q.description       AS quiz_description,
+       q.tags              AS quiz_tags,
+       q.author            AS quiz_author,
+       q.pass_mark         AS quiz_pass_mark,
+       q.shuffle_questions AS quiz_shuffle_questions,
+       q.shuffle_answers   AS quiz_shuffle_answers,
+       q.max_attempts      AS quiz_max_attempts,
+       q.scoring           AS quiz_scoring,
+       q.pool_draw         AS quiz_pool_draw,
+       q.pool_tags         AS quiz_pool_tags,
+       q.lang              AS quiz_lang,
+       q.category          AS quiz_category,
+       q.source            AS quiz_source,
+       q.id                AS quiz_id,
+       qqq.question_sha1   AS question_sha1,
+       qqi.question_id     AS question_id,
+       qqq.kind            AS question_kind,
+       qqq.content         AS question_content,
+       qqq.position        AS question_position,
+       qqq.code            AS question_code,
+       qqq.code_language   AS question_code_language,
+       qqq.partial_credit  AS question_partial_credit,
+       qqq.points          AS question_points,
+       qqq.explanation     AS question_explanation,
+       qqq.tags            AS question_tags,
+       qqa.answer_sha1     AS answer_sha1,
+       qqa.content         AS answer_content,
+       qqa.valid           AS answer_valid,
+       qqa.match_mode      AS answer_match_mode,
+       qqa.position        AS answer_position,
+       qqa.ordinal         AS answer_ordinal,
+       qqa.explanation     AS answer_explanation,
+       qqp.right_sha1      AS answer_right_sha1,
+       qqp.right_content   AS answer_right_content
 FROM quiz q
          JOIN quiz_question_quiz qqq ON q.sha1 = qqq.quiz_sha1
-         JOIN quiz_question qq ON qq.sha1 = qqq.question_sha1
-         JOIN quiz_question_answer qqa ON qq.sha1 = qqa.question_sha1
-         JOIN quiz_answer qa ON qa.sha1 = qqa.answer_sha1
-         JOIN quiz_class_visibility qcv ON q.sha1 = qcv.quiz_sha1
-         JOIN student_class sc ON sc.uuid = qcv.class_uuid
-         JOIN user u ON sc.uuid = u.class_uuid
-WHERE q.sha1 = ?
-    AND u.id = ''
-   OR u.id = ?
+         JOIN quiz_question_identity qqi
+              ON qqi.quiz_source = q.source AND qqi.quiz_filename = q.filename AND qqi.question_sha1 = qqq.question_sha1
+         JOIN quiz_question_answer qqa ON qqa.quiz_sha1 = q.sha1 AND qqa.question_sha1 = qqq.question_sha1
+         LEFT JOIN quiz_question_pair qqp
+                   ON qqp.quiz_sha1 = q.sha1 AND qqp.question_sha1 = qqq.question_sha1 AND
+                      qqp.answer_sha1 = qqa.answer_sha1
+WHERE q.sha1 = ?1
+  AND (?2 = ''
+    OR EXISTS (SELECT 1
+               FROM quiz_class_visibility qcv
+                        JOIN user u ON u.class_uuid = qcv.class_uuid
+               WHERE qcv.quiz_sha1 = q.sha1
+                 AND u.id = ?2))
 `
 
 type FindQuizFullBySha1Params struct {
-	Sha1 string `db:"sha1"`
-	ID   string `db:"id"`
+	Sha1   string `db:"sha1"`
+	UserId string `db:"user_id"`
 }
 
 type FindQuizFullBySha1Row struct {
-	QuizSha1             string         `db:"quiz_sha1"`
-	QuizFilename         string         `db:"quiz_filename"`
-	QuizName             string         `db:"quiz_name"`
-	QuizVersion          int            `db:"quiz_version"`
-	QuizCreatedAt        string         `db:"quiz_created_at"`
-	QuizDuration         int            `db:"quiz_duration"`
-	QuizActive           bool           `db:"quiz_active"`
-	QuestionSha1         string         `db:"question_sha1"`
-	QuestionContent      string         `db:"question_content"`
-	QuestionPosition     int            `db:"question_position"`
-	QuestionCode         sql.NullString `db:"question_code"`
-	QuestionCodeLanguage sql.NullString `db:"question_code_language"`
-	AnswerSha1           string         `db:"answer_sha1"`
-	AnswerContent        string         `db:"answer_content"`
-	AnswerValid          bool           `db:"answer_valid"`
+	QuizSha1              string         `db:"quiz_sha1"`
+	QuizFilename          string         `db:"quiz_filename"`
+	QuizName              string         `db:"quiz_name"`
+	QuizVersion           int            `db:"quiz_version"`
+	QuizCreatedAt         string         `db:"quiz_created_at"`
+	QuizDuration          int            `db:"quiz_duration"`
+	QuizActive            bool           `db:"quiz_active"`
+	QuizDescription       string         `db:"quiz_description"`
+	QuizTags              string         `db:"quiz_tags"`
+	QuizAuthor            string         `db:"quiz_author"`
+	QuizPassMark          int            `db:"quiz_pass_mark"`
+	QuizShuffleQuestions  bool           `db:"quiz_shuffle_questions"`
+	QuizShuffleAnswers    bool           `db:"quiz_shuffle_answers"`
+	QuizMaxAttempts       int            `db:"quiz_max_attempts"`
+	QuizScoring           int8           `db:"quiz_scoring"`
+	QuizPoolDraw          int            `db:"quiz_pool_draw"`
+	QuizPoolTags          string         `db:"quiz_pool_tags"`
+	QuizLang              string         `db:"quiz_lang"`
+	QuizCategory          string         `db:"quiz_category"`
+	QuizSource            string         `db:"quiz_source"`
+	QuizID                string         `db:"quiz_id"`
+	QuestionSha1          string         `db:"question_sha1"`
+	QuestionID            string         `db:"question_id"`
+	QuestionKind          int8           `db:"question_kind"`
+	QuestionContent       string         `db:"question_content"`
+	QuestionPosition      int            `db:"question_position"`
+	QuestionCode          sql.NullString `db:"question_code"`
+	QuestionCodeLanguage  sql.NullString `db:"question_code_language"`
+	QuestionPartialCredit bool           `db:"question_partial_credit"`
+	QuestionPoints        int            `db:"question_points"`
+	QuestionExplanation   string         `db:"question_explanation"`
+	QuestionTags          string         `db:"question_tags"`
+	AnswerSha1            string         `db:"answer_sha1"`
+	AnswerContent         string         `db:"answer_content"`
+	AnswerValid           bool           `db:"answer_valid"`
+	AnswerMatchMode       int8           `db:"answer_match_mode"`
+	AnswerPosition        int            `db:"answer_position"`
+	AnswerOrdinal         int            `db:"answer_ordinal"`
+	AnswerExplanation     string         `db:"answer_explanation"`
+	AnswerRightSha1       sql.NullString `db:"answer_right_sha1"`
+	AnswerRightContent    sql.NullString `db:"answer_right_content"`
 }
 
 func (q *Queries) FindQuizFullBySha1(ctx context.Context, arg FindQuizFullBySha1Params) ([]FindQuizFullBySha1Row, error) {
-	rows, err := q.db.QueryContext(ctx, findQuizFullBySha1, arg.Sha1, arg.ID)
+	rows, err := q.db.QueryContext(ctx, findQuizFullBySha1, arg.Sha1, arg.UserId)
 	if err != nil {
 		return nil, err
 	}
@@ -278,14 +626,88 @@ func (q *Queries) FindQuizFullBySha1(ctx context.Context, arg FindQuizFullBySha1
 			&i.QuizCreatedAt,
 			&i.QuizDuration,
 			&i.QuizActive,
+			&i.QuizDescription,
+			&i.QuizTags,
+			&i.QuizAuthor,
+			&i.QuizPassMark,
+			&i.QuizShuffleQuestions,
+			&i.QuizShuffleAnswers,
+			&i.QuizMaxAttempts,
+			&i.QuizScoring,
+			&i.QuizPoolDraw,
+			&i.QuizPoolTags,
+			&i.QuizLang,
+			&i.QuizCategory,
+			&i.QuizSource,
+			&i.QuizID,
 			&i.QuestionSha1,
+			&i.QuestionID,
+			&i.QuestionKind,
 			&i.QuestionContent,
 			&i.QuestionPosition,
 			&i.QuestionCode,
 			&i.QuestionCodeLanguage,
+			&i.QuestionPartialCredit,
+			&i.QuestionPoints,
+			&i.QuestionExplanation,
+			&i.QuestionTags,
 			&i.AnswerSha1,
 			&i.AnswerContent,
 			&i.AnswerValid,
+			&i.AnswerMatchMode,
+			&i.AnswerPosition,
+			&i.AnswerOrdinal,
+			&i.AnswerExplanation,
+			&i.AnswerRightSha1,
+			&i.AnswerRightContent,
+		); err != nil {
+			return nil, err
+		}
+		items = append(items, i)
+	}
+	if err := rows.Close(); err != nil {
+		return nil, err
+	}
+	if err := rows.Err(); err != nil {
+		return nil, err
+	}
+	return items, nil
+}
+
+const findQuizPoolBySha1 = `-- name: FindQuizPoolBySha1 :many
+SELECT q.pool_draw  AS quiz_pool_draw,
+       q.pool_tags  AS quiz_pool_tags,
+       qqq.question_sha1 AS question_sha1,
+       qqq.position      AS question_position,
+       qqq.tags          AS question_tags
+FROM quiz q
+         JOIN quiz_question_quiz qqq ON q.sha1 = qqq.quiz_sha1
+WHERE q.sha1 = ?
+`
+
+type FindQuizPoolBySha1Row struct {
+	QuizPoolDraw     int    `db:"quiz_pool_draw"`
+	QuizPoolTags     string `db:"quiz_pool_tags"`
+	QuestionSha1     string `db:"question_sha1"`
+	QuestionPosition int    `db:"question_position"`
+	QuestionTags     string `db:"question_tags"`
+}
+
+func (q *Queries) FindQuizPoolBySha1(ctx context.Context, sha1 string) ([]FindQuizPoolBySha1Row, error) {
+	rows, err := q.db.QueryContext(ctx, findQuizPoolBySha1, sha1)
+	if err != nil {
+		return nil, err
+	}
+	defer rows.Close()
+	items := []FindQuizPoolBySha1Row{}
+	for rows.Next() {
+		var i FindQuizPoolBySha1Row
+		if err := rows.Scan(
+			&i.QuizPoolDraw,
+			&i.QuizPoolTags,
+			&i.QuestionSha1,
+			&i.QuestionPosition,
+			&i.QuestionTags,
 		); err != nil {
 			return nil, err
 		}
@@ -301,7 +723,7 @@ func (q *Queries) FindQuizFullBySha1(ctx context.Context, arg FindQuizFullBySha1
 }
 
 const findQuizSessionByUuid = `-- name: FindQuizSessionByUuid :many
-SELECT session_uuid, user_id, remaining_sec, quiz_sha1, quiz_name, quiz_duration, checked_answers, results, question_sha1, question_position, question_content, question_code, question_code_language, answer_sha1, answer_content, answer_checked, answer_valid
+SELECT session_uuid, user_id, remaining_sec, session_seed, quiz_sha1, quiz_name, quiz_duration, quiz_scoring, quiz_shuffle_questions, quiz_shuffle_answers, quiz_lang, question_sha1, question_id, question_kind, question_position, question_content, question_code, question_code_language, question_partial_credit, question_points, question_explanation, answer_sha1, answer_content, answer_checked, answer_valid, answer_match_mode, answer_text, answer_position, answer_answered_position, answer_right_sha1, answer_right_content, answer_explanation, answer_ordinal
 FROM quiz_session_detail_view
 WHERE session_uuid = ?
 `
@@ -319,20 +741,272 @@ func (q *Queries) FindQuizSessionByUuid(ctx context.Context, sessionUuid uuid.UU
 			&i.SessionUuid,
 			&i.UserID,
 			&i.RemainingSec,
+			&i.SessionSeed,
 			&i.QuizSha1,
 			&i.QuizName,
 			&i.QuizDuration,
-			&i.CheckedAnswers,
-			&i.Results,
+			&i.QuizScoring,
+			&i.QuizShuffleQuestions,
+			&i.QuizShuffleAnswers,
+			&i.QuizLang,
 			&i.QuestionSha1,
+			&i.QuestionID,
+			&i.QuestionKind,
 			&i.QuestionPosition,
 			&i.QuestionContent,
 			&i.QuestionCode,
 			&i.QuestionCodeLanguage,
+			&i.QuestionPartialCredit,
+			&i.QuestionPoints,
+			&i.QuestionExplanation,
 			&i.AnswerSha1,
 			&i.AnswerContent,
 			&i.AnswerChecked,
 			&i.AnswerValid,
+			&i.AnswerMatchMode,
+			&i.AnswerText,
+			&i.AnswerPosition,
+			&i.AnswerAnsweredPosition,
+			&i.AnswerRightSha1,
+			&i.AnswerRightContent,
+			&i.AnswerExplanation,
+			&i.AnswerOrdinal,
+		); err != nil {
+			return nil, err
+		}
+		items = append(items, i)
+	}
+	if err := rows.Close(); err != nil {
+		return nil, err
+	}
+	if err := rows.Err(); err != nil {
+		return nil, err
+	}
+	return items, nil
+}
+
+const findQuizSessionsByUuids = `-- name: FindQuizSessionsByUuids :many
+SELECT session_uuid, user_id, remaining_sec, session_seed, quiz_sha1, quiz_name, quiz_duration, quiz_scoring, quiz_shuffle_questions, quiz_shuffle_answers, quiz_lang, question_sha1, question_id, question_kind, question_position, question_content, question_code, question_code_language, question_partial_credit, question_points, question_explanation, answer_sha1, answer_content, answer_checked, answer_valid, answer_match_mode, answer_text, answer_position, answer_answered_position, answer_right_sha1, answer_right_content, answer_explanation, answer_ordinal
+FROM quiz_session_detail_view
+WHERE session_uuid IN (/*SLICE:session_uuids*/?)
+`
+
+func (q *Queries) FindQuizSessionsByUuids(ctx context.Context, sessionUuids []uuid.UUID) ([]QuizSessionDetailView, error) {
+	query := findQuizSessionsByUuids
+	var queryParams []interface{}
+	if len(sessionUuids) > 0 {
+		for _, v := range sessionUuids {
+			queryParams = append(queryParams, v)
+		}
+		query = strings.Replace(query, "/*SLICE:session_uuids*/?", strings.Repeat(",?", len(sessionUuids))[1:], 1)
+	} else {
+		query = strings.Replace(query, "/*SLICE:session_uuids*/?", "NULL", 1)
+	}
+	rows, err := q.db.QueryContext(ctx, query, queryParams...)
+	if err != nil {
+		return nil, err
+	}
+	defer rows.Close()
+	items := []QuizSessionDetailView{}
+	for rows.Next() {
+		var i QuizSessionDetailView
+		if err := rows.Scan(
+			&i.SessionUuid,
+			&i.UserID,
+			&i.RemainingSec,
+			&i.SessionSeed,
+			&i.QuizSha1,
+			&i.QuizName,
+			&i.QuizDuration,
+			&i.QuizScoring,
+			&i.QuizShuffleQuestions,
+			&i.QuizShuffleAnswers,
+			&i.QuizLang,
+			&i.QuestionSha1,
+			&i.QuestionID,
+			&i.QuestionKind,
+			&i.QuestionPosition,
+			&i.QuestionContent,
+			&i.QuestionCode,
+			&i.QuestionCodeLanguage,
+			&i.QuestionPartialCredit,
+			&i.QuestionPoints,
+			&i.QuestionExplanation,
+			&i.AnswerSha1,
+			&i.AnswerContent,
+			&i.AnswerChecked,
+			&i.AnswerValid,
+			&i.AnswerMatchMode,
+			&i.AnswerText,
+			&i.AnswerPosition,
+			&i.AnswerAnsweredPosition,
+			&i.AnswerRightSha1,
+			&i.AnswerRightContent,
+			&i.AnswerExplanation,
+			&i.AnswerOrdinal,
+		); err != nil {
+			return nil, err
+		}
+		items = append(items, i)
+	}
+	if err := rows.Close(); err != nil {
+		return nil, err
+	}
+	if err := rows.Err(); err != nil {
+		return nil, err
+	}
+	return items, nil
+}
+
+const findQuizTranslations = `-- name: FindQuizTranslations :many
+SELECT quiz_sha1, lang, name, description
+FROM quiz_translation
+WHERE quiz_sha1 = ?
+`
+
+func (q *Queries) FindQuizTranslations(ctx context.Context, quizSha1 string) ([]QuizTranslation, error) {
+	rows, err := q.db.QueryContext(ctx, findQuizTranslations, quizSha1)
+	if err != nil {
+		return nil, err
+	}
+	defer rows.Close()
+	items := []QuizTranslation{}
+	for rows.Next() {
+		var i QuizTranslation
+		if err := rows.Scan(
+			&i.QuizSha1,
+			&i.Lang,
+			&i.Name,
+			&i.Description,
+		); err != nil {
+			return nil, err
+		}
+		items = append(items, i)
+	}
+	if err := rows.Close(); err != nil {
+		return nil, err
+	}
+	if err := rows.Err(); err != nil {
+		return nil, err
+	}
+	return items, nil
+}
+
+const findSessionAnswerTranslations = `-- name: FindSessionAnswerTranslations :many
+SELECT DISTINCT qat.answer_sha1, qat.content
+FROM session s
+         JOIN quiz_answer_translation qat ON qat.quiz_sha1 = s.quiz_sha1
+WHERE s.uuid = ?
+  AND qat.question_sha1 = ?
+`
+
+type FindSessionAnswerTranslationsParams struct {
+	SessionUuid  uuid.UUID `db:"session_uuid"`
+	QuestionSha1 string    `db:"question_sha1"`
+}
+
+type FindSessionAnswerTranslationsRow struct {
+	AnswerSha1 string `db:"answer_sha1"`
+	Content    string `db:"content"`
+}
+
+func (q *Queries) FindSessionAnswerTranslations(ctx context.Context, arg FindSessionAnswerTranslationsParams) ([]FindSessionAnswerTranslationsRow, error) {
+	rows, err := q.db.QueryContext(ctx, findSessionAnswerTranslations, arg.SessionUuid, arg.QuestionSha1)
+	if err != nil {
+		return nil, err
+	}
+	defer rows.Close()
+	items := []FindSessionAnswerTranslationsRow{}
+	for rows.Next() {
+		var i FindSessionAnswerTranslationsRow
+		if err := rows.Scan(
+			&i.AnswerSha1,
+			&i.Content,
+		); err != nil {
+			return nil, err
+		}
+		items = append(items, i)
+	}
+	if err := rows.Close(); err != nil {
+		return nil, err
+	}
+	if err := rows.Err(); err != nil {
+		return nil, err
+	}
+	return items, nil
+}
+
+const findSessionQuestion = `-- name: FindSessionQuestion :many
+SELECT qqq.question_sha1  AS question_sha1,
+       qqq.kind           AS question_kind,
+       qqq.content        AS question_content,
+       qqq.code           AS question_code,
+       qqq.code_language  AS question_code_language,
+       qqq.partial_credit AS question_partial_credit,
+       qqq.points         AS question_points,
+       qqa.answer_sha1    AS answer_sha1,
+       qqa.content        AS answer_content,
+       qqa.valid          AS answer_valid,
+       qqa.match_mode     AS answer_match_mode,
+       qqa.position       AS answer_position,
+       qqp.right_sha1     AS answer_right_sha1,
+       qqp.right_content  AS answer_right_content
+FROM session s
+         JOIN quiz_question_quiz qqq ON qqq.quiz_sha1 = s.quiz_sha1
+         JOIN quiz_question_answer qqa ON qqa.quiz_sha1 = qqq.quiz_sha1 AND qqa.question_sha1 = qqq.question_sha1
+         LEFT JOIN quiz_question_pair qqp
+                   ON qqp.quiz_sha1 = qqq.quiz_sha1 AND qqp.question_sha1 = qqq.question_sha1 AND
+                      qqp.answer_sha1 = qqa.answer_sha1
+WHERE s.uuid = ?
+  AND qqq.question_sha1 = ?
+`
+
+type FindSessionQuestionParams struct {
+	SessionUuid  uuid.UUID `db:"session_uuid"`
+	QuestionSha1 string    `db:"question_sha1"`
+}
+
+type FindSessionQuestionRow struct {
+	QuestionSha1          string         `db:"question_sha1"`
+	QuestionKind          int8           `db:"question_kind"`
+	QuestionContent       string         `db:"question_content"`
+	QuestionCode          sql.NullString `db:"question_code"`
+	QuestionCodeLanguage  sql.NullString `db:"question_code_language"`
+	QuestionPartialCredit bool           `db:"question_partial_credit"`
+	QuestionPoints        int            `db:"question_points"`
+	AnswerSha1            string         `db:"answer_sha1"`
+	AnswerContent         string         `db:"answer_content"`
+	AnswerValid           bool           `db:"answer_valid"`
+	AnswerMatchMode       int8           `db:"answer_match_mode"`
+	AnswerPosition        int            `db:"answer_position"`
+	AnswerRightSha1       sql.NullString `db:"answer_right_sha1"`
+	AnswerRightContent    sql.NullString `db:"answer_right_content"`
+}
+
+func (q *Queries) FindSessionQuestion(ctx context.Context, arg FindSessionQuestionParams) ([]FindSessionQuestionRow, error) {
+	rows, err := q.db.QueryContext(ctx, findSessionQuestion, arg.SessionUuid, arg.QuestionSha1)
+	if err != nil {
+		return nil, err
+	}
+	defer rows.Close()
+	items := []FindSessionQuestionRow{}
+	for rows.Next() {
+		var i FindSessionQuestionRow
+		if err := rows.Scan(
+			&i.QuestionSha1,
+			&i.QuestionKind,
+			&i.QuestionContent,
+			&i.QuestionCode,
+			&i.QuestionCodeLanguage,
+			&i.QuestionPartialCredit,
+			&i.QuestionPoints,
+			&i.AnswerSha1,
+			&i.AnswerContent,
+			&i.AnswerValid,
+			&i.AnswerMatchMode,
+			&i.AnswerPosition,
+			&i.AnswerRightSha1,
+			&i.AnswerRightContent,
 		); err != nil {
 			return nil, err
 		}
@@ -348,31 +1022,71 @@ func (q *Queries) FindQuizSessionByUuid(ctx context.Context, sessionUuid uuid.UU
 }
 
 const linkAnswer = `-- name: LinkAnswer :exec
-REPLACE INTO quiz_question_answer (question_sha1, answer_sha1)
-VALUES (?, ?)
+REPLACE INTO quiz_question_answer (quiz_sha1, question_sha1, answer_sha1, content, valid, match_mode, explanation, ordinal,
+                                  position)
+VALUES (?, ?, ?, ?, ?, ?, ?, ?, ?)
 `
 
 type LinkAnswerParams struct {
+	QuizSha1     string `db:"quiz_sha1"`
 	QuestionSha1 string `db:"question_sha1"`
 	AnswerSha1   string `db:"answer_sha1"`
+	Content      string `db:"content"`
+	Valid        bool   `db:"valid"`
+	MatchMode    int8   `db:"match_mode"`
+	Explanation  string `db:"explanation"`
+	Ordinal      int    `db:"ordinal"`
+	Position     int    `db:"position"`
 }
 
 func (q *Queries) LinkAnswer(ctx context.Context, arg LinkAnswerParams) error {
-	_, err := q.db.ExecContext(ctx, linkAnswer, arg.QuestionSha1, arg.AnswerSha1)
+	_, err := q.db.ExecContext(ctx, linkAnswer,
+		arg.QuizSha1,
+		arg.QuestionSha1,
+		arg.AnswerSha1,
+		arg.Content,
+		arg.Valid,
+		arg.MatchMode,
+		arg.Explanation,
+		arg.Ordinal,
+		arg.Position,
+	)
 	return err
 }
 
 const linkQuestion = `-- name: LinkQuestion :exec
-REPLACE INTO quiz_question_quiz (quiz_sha1, question_sha1)
-VALUES (?, ?)
+REPLACE INTO quiz_question_quiz (quiz_sha1, question_sha1, position, kind, content, code, code_language, partial_credit,
+                                points, explanation, tags)
+VALUES (?, ?, ?, ?, ?, ?, ?, ?, ?, ?, ?)
 `
 
 type LinkQuestionParams struct {
-	QuizSha1     string `db:"quiz_sha1"`
-	QuestionSha1 string `db:"question_sha1"`
+	QuizSha1      string         `db:"quiz_sha1"`
+	QuestionSha1  string         `db:"question_sha1"`
+	Position      int            `db:"position"`
+	Kind          int8           `db:"kind"`
+	Content       string         `db:"content"`
+	Code          sql.NullString `db:"code"`
+	CodeLanguage  sql.NullString `db:"code_language"`
+	PartialCredit bool           `db:"partial_credit"`
+	Points        int            `db:"points"`
+	Explanation   string         `db:"explanation"`
+	Tags          string         `db:"tags"`
 }
 
 func (q *Queries) LinkQuestion(ctx context.Context, arg LinkQuestionParams) error {
-	_, err := q.db.ExecContext(ctx, linkQuestion, arg.QuizSha1, arg.QuestionSha1)
+	_, err := q.db.ExecContext(ctx, linkQuestion,
+		arg.QuizSha1,
+		arg.QuestionSha1,
+		arg.Position,
+		arg.Kind,
+		arg.Content,
+		arg.Code,
+		arg.CodeLanguage,
+		arg.PartialCredit,
+		arg.Points,
+		arg.Explanation,
+		arg.Tags,
+	)
 	return err
 }
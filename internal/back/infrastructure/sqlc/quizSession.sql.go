@@ -22,7 +22,7 @@ import (
 )
 
 const findAllQuizSessions = `
-SELECT quiz_sha1, quiz_name, quiz_filename, quiz_version, quiz_duration, quiz_created_at, session_uuid, user_id, user_name, user_picture, class_uuid, class_name, remaining_sec, checked_answers, results
+SELECT quiz_sha1, quiz_name, quiz_filename, quiz_version, quiz_duration, quiz_created_at, session_uuid, user_id, user_name, user_picture, class_uuid, class_name, remaining_sec
 FROM quiz_session_view 
 %s
 LIMIT ? OFFSET ?
@@ -59,8 +59,6 @@ func (q *Queries) FindAllQuizSessions(ctx context.Context, arg FindAllQuizSessio
 			&i.ClassUuid,
 			&i.ClassName,
 			&i.RemainingSec,
-			&i.CheckedAnswers,
-			&i.Results,
 		); err != nil {
 			return nil, err
 		}
@@ -102,9 +100,7 @@ SELECT q.sha1                                                                  A
        CASE WHEN u.picture IS NULL THEN '' ELSE u.picture END                  AS user_picture,
        CASE WHEN sc.uuid IS NULL THEN '' ELSE sc.uuid END                      AS class_uuid,
        CASE WHEN sc.name IS NULL THEN '' ELSE sc.name END                      AS class_name,
-       CASE WHEN sv.remaining_sec IS NULL THEN 0 ELSE sv.remaining_sec END     AS remaining_sec,
-       CASE WHEN sv.checked_answers IS NULL THEN 0 ELSE sv.checked_answers END AS checked_answers,
-       CASE WHEN sv.results IS NULL THEN 0 ELSE sv.results END                 AS results
+       CASE WHEN sv.remaining_sec IS NULL THEN 0 ELSE sv.remaining_sec END     AS remaining_sec
 FROM quiz q
          JOIN quiz_class_visibility qcv ON q.sha1 = qcv.quiz_sha1
          JOIN student_class sc ON qcv.class_uuid = sc.uuid
@@ -139,8 +135,6 @@ func (q *Queries) FindAllQuizSessionsForUser(ctx context.Context, arg FindAllQui
 			&i.ClassUuid,
 			&i.ClassName,
 			&i.RemainingSec,
-			&i.CheckedAnswers,
-			&i.Results,
 		); err != nil {
 			return nil, err
 		}
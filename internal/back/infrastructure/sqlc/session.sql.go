@@ -23,6 +23,7 @@ package sqlc
 
 import (
 	"context"
+	"database/sql"
 
 	"github.com/google/uuid"
 )
@@ -60,31 +61,39 @@ func (q *Queries) CountAllSessionsForUser(ctx context.Context, arg CountAllSessi
 }
 
 const createOrReplaceSession = `-- name: CreateOrReplaceSession :exec
-INSERT INTO session (uuid, quiz_sha1, user_id)
-VALUES (?, ?, ?)
+INSERT INTO session (uuid, quiz_sha1, user_id, seed)
+VALUES (?, ?, ?, ?)
 `
 
 type CreateOrReplaceSessionParams struct {
 	Uuid     uuid.UUID `db:"uuid"`
 	QuizSha1 string    `db:"quiz_sha1"`
 	UserID   string    `db:"user_id"`
+	Seed     int64     `db:"seed"`
 }
 
 func (q *Queries) CreateOrReplaceSession(ctx context.Context, arg CreateOrReplaceSessionParams) error {
-	_, err := q.db.ExecContext(ctx, createOrReplaceSession, arg.Uuid, arg.QuizSha1, arg.UserID)
+	_, err := q.db.ExecContext(ctx, createOrReplaceSession,
+		arg.Uuid,
+		arg.QuizSha1,
+		arg.UserID,
+		arg.Seed,
+	)
 	return err
 }
 
 const createOrReplaceSessionAnswer = `-- name: CreateOrReplaceSessionAnswer :exec
-REPLACE INTO session_answer (session_uuid, question_sha1, answer_sha1, checked)
-VALUES (?, ?, ?, ?)
+REPLACE INTO session_answer (session_uuid, question_sha1, answer_sha1, checked, content, position)
+VALUES (?, ?, ?, ?, ?, ?)
 `
 
 type CreateOrReplaceSessionAnswerParams struct {
-	SessionUuid  uuid.UUID `db:"session_uuid"`
-	QuestionSha1 string    `db:"question_sha1"`
-	AnswerSha1   string    `db:"answer_sha1"`
-	Checked      bool      `db:"checked"`
+	SessionUuid  uuid.UUID      `db:"session_uuid"`
+	QuestionSha1 string         `db:"question_sha1"`
+	AnswerSha1   string         `db:"answer_sha1"`
+	Checked      bool           `db:"checked"`
+	Content      sql.NullString `db:"content"`
+	Position     sql.NullInt64  `db:"position"`
 }
 
 func (q *Queries) CreateOrReplaceSessionAnswer(ctx context.Context, arg CreateOrReplaceSessionAnswerParams) error {
@@ -93,12 +102,29 @@ func (q *Queries) CreateOrReplaceSessionAnswer(ctx context.Context, arg CreateOr
 		arg.QuestionSha1,
 		arg.AnswerSha1,
 		arg.Checked,
+		arg.Content,
+		arg.Position,
 	)
 	return err
 }
 
+const createSessionQuestion = `-- name: CreateSessionQuestion :exec
+INSERT INTO session_question (session_uuid, question_sha1)
+VALUES (?, ?)
+`
+
+type CreateSessionQuestionParams struct {
+	SessionUuid  uuid.UUID `db:"session_uuid"`
+	QuestionSha1 string    `db:"question_sha1"`
+}
+
+func (q *Queries) CreateSessionQuestion(ctx context.Context, arg CreateSessionQuestionParams) error {
+	_, err := q.db.ExecContext(ctx, createSessionQuestion, arg.SessionUuid, arg.QuestionSha1)
+	return err
+}
+
 const findAllSessions = `-- name: FindAllSessions :many
-SELECT uuid, quiz_sha1, quiz_name, quiz_active, user_id, user_name, user_picture, remaining_sec, checked_answers, results
+SELECT uuid, quiz_sha1, quiz_name, quiz_active, user_id, user_name, user_picture, remaining_sec
 FROM session_view
 WHERE quiz_active = ?
 LIMIT ? OFFSET ?
@@ -128,71 +154,6 @@ func (q *Queries) FindAllSessions(ctx context.Context, arg FindAllSessionsParams
 			&i.UserName,
 			&i.UserPicture,
 			&i.RemainingSec,
-			&i.CheckedAnswers,
-			&i.Results,
-		); err != nil {
-			return nil, err
-		}
-		items = append(items, i)
-	}
-	if err := rows.Close(); err != nil {
-		return nil, err
-	}
-	if err := rows.Err(); err != nil {
-		return nil, err
-	}
-	return items, nil
-}
-
-const findAllSessionsAnswerForSession = `-- name: FindAllSessionsAnswerForSession :many
-SELECT srv.quiz_sha1,
-       srv.question_sha1,
-       srv.answer_sha1,
-       srv.session_uuid,
-       srv.user_id,
-       srv.checked,
-       CASE
-           WHEN sv.remaining_sec = 0
-               THEN srv.result
-           END
-FROM session_response_view srv
-         JOIN session_view sv ON srv.session_uuid = sv.uuid
-WHERE session_uuid = ?
-  AND srv.user_id = ?
-`
-
-type FindAllSessionsAnswerForSessionParams struct {
-	SessionUuid uuid.UUID `db:"session_uuid"`
-	UserID      string    `db:"user_id"`
-}
-
-type FindAllSessionsAnswerForSessionRow struct {
-	QuizSha1     string      `db:"quiz_sha1"`
-	QuestionSha1 string      `db:"question_sha1"`
-	AnswerSha1   string      `db:"answer_sha1"`
-	SessionUuid  uuid.UUID   `db:"session_uuid"`
-	UserID       string      `db:"user_id"`
-	Checked      bool        `db:"checked"`
-	Column7      interface{} `db:"column_7"`
-}
-
-func (q *Queries) FindAllSessionsAnswerForSession(ctx context.Context, arg FindAllSessionsAnswerForSessionParams) ([]FindAllSessionsAnswerForSessionRow, error) {
-	rows, err := q.db.QueryContext(ctx, findAllSessionsAnswerForSession, arg.SessionUuid, arg.UserID)
-	if err != nil {
-		return nil, err
-	}
-	defer rows.Close()
-	items := []FindAllSessionsAnswerForSessionRow{}
-	for rows.Next() {
-		var i FindAllSessionsAnswerForSessionRow
-		if err := rows.Scan(
-			&i.QuizSha1,
-			&i.QuestionSha1,
-			&i.AnswerSha1,
-			&i.SessionUuid,
-			&i.UserID,
-			&i.Checked,
-			&i.Column7,
 		); err != nil {
 			return nil, err
 		}
@@ -208,7 +169,7 @@ func (q *Queries) FindAllSessionsAnswerForSession(ctx context.Context, arg FindA
 }
 
 const findAllSessionsForUser = `-- name: FindAllSessionsForUser :many
-SELECT uuid, quiz_sha1, quiz_name, quiz_active, user_id, user_name, user_picture, remaining_sec, checked_answers, results
+SELECT uuid, quiz_sha1, quiz_name, quiz_active, user_id, user_name, user_picture, remaining_sec
 FROM session_view
 WHERE quiz_active = ?
   AND user_id = ?
@@ -245,8 +206,6 @@ func (q *Queries) FindAllSessionsForUser(ctx context.Context, arg FindAllSession
 			&i.UserName,
 			&i.UserPicture,
 			&i.RemainingSec,
-			&i.CheckedAnswers,
-			&i.Results,
 		); err != nil {
 			return nil, err
 		}
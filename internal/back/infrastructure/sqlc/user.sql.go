@@ -63,7 +63,7 @@ func (q *Queries) CreateOrReplaceUser(ctx context.Context, arg CreateOrReplaceUs
 }
 
 const findActiveUserById = `-- name: FindActiveUserById :one
-SELECT id, login, name, picture, active, role_id, class_uuid, class_name
+SELECT id, login, name, picture, active, role_id, class_uuid, class_name, lang
 FROM user_class_view
 WHERE id = ?
   AND active = 1
@@ -81,12 +81,13 @@ func (q *Queries) FindActiveUserById(ctx context.Context, id string) (UserClassV
 		&i.RoleID,
 		&i.ClassUuid,
 		&i.ClassName,
+		&i.Lang,
 	)
 	return i, err
 }
 
 const findAllUser = `-- name: FindAllUser :many
-SELECT id, login, name, picture, active, role_id, class_uuid, class_name
+SELECT id, login, name, picture, active, role_id, class_uuid, class_name, lang
 FROM user_class_view
 `
 
@@ -108,6 +109,7 @@ func (q *Queries) FindAllUser(ctx context.Context) ([]UserClassView, error) {
 			&i.RoleID,
 			&i.ClassUuid,
 			&i.ClassName,
+			&i.Lang,
 		); err != nil {
 			return nil, err
 		}
@@ -123,7 +125,7 @@ func (q *Queries) FindAllUser(ctx context.Context) ([]UserClassView, error) {
 }
 
 const findUserById = `-- name: FindUserById :one
-SELECT id, login, name, picture, active, role_id, class_uuid, class_name
+SELECT id, login, name, picture, active, role_id, class_uuid, class_name, lang
 FROM user_class_view
 WHERE id = ?
 `
@@ -140,6 +142,7 @@ func (q *Queries) FindUserById(ctx context.Context, id string) (UserClassView, e
 		&i.RoleID,
 		&i.ClassUuid,
 		&i.ClassName,
+		&i.Lang,
 	)
 	return i, err
 }
@@ -201,6 +204,22 @@ func (q *Queries) UpdateUserInfo(ctx context.Context, arg UpdateUserInfoParams)
 	return err
 }
 
+const updateUserLang = `-- name: UpdateUserLang :exec
+UPDATE user
+SET lang = ?
+WHERE id = ?
+`
+
+type UpdateUserLangParams struct {
+	Lang string `db:"lang"`
+	ID   string `db:"id"`
+}
+
+func (q *Queries) UpdateUserLang(ctx context.Context, arg UpdateUserLangParams) error {
+	_, err := q.db.ExecContext(ctx, updateUserLang, arg.Lang, arg.ID)
+	return err
+}
+
 const updateUserRole = `-- name: UpdateUserRole :exec
 UPDATE user
 SET role_id = ?
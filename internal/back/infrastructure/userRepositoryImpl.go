@@ -137,6 +137,15 @@ func (r *UserDBRepository) UpdateUserInfo(ctx context.Context, user *domain.User
 	})
 }
 
+func (r *UserDBRepository) UpdateUserLang(ctx context.Context, userId string, lang string) error {
+	r.uc.Delete(userId)
+
+	return r.w.queries().UpdateUserLang(ctx, sqlc.UpdateUserLangParams{
+		Lang: lang,
+		ID:   userId,
+	})
+}
+
 func (r *UserDBRepository) AssignUserToClass(ctx context.Context, userId string, classId uuid.UUID) error {
 	return r.w.queries().AssignUserToClass(ctx, sqlc.AssignUserToClassParams{
 		ClassUuid: classId,
@@ -152,6 +161,7 @@ func (r *UserDBRepository) toUser(entity sqlc.UserClassView) *domain.User {
 		Picture: entity.Picture,
 		Active:  entity.Active,
 		Role:    r.toRole(entity.RoleID),
+		Lang:    entity.Lang,
 	}
 
 	if entity.ClassName != "" {
@@ -21,11 +21,14 @@ import (
 	"log"
 	"net/http"
 	"regexp"
+	"sort"
 	"strconv"
+	"strings"
 	"time"
 
 	"github.com/fatih/color"
 	"github.com/gin-gonic/gin"
+	"github.com/spf13/viper"
 
 	"github.com/michaelcoll/quiz-app/internal/back/domain"
 )
@@ -35,7 +38,7 @@ const apiPort = ":8080"
 var rangeRxp = regexp.MustCompile(`(?P<Unit>.*)=(?P<Start>[0-9]+)-(?P<End>[0-9]*)`)
 
 type ApiController struct {
-	lastSyncUpdate time.Time
+	syncThrottle *syncThrottle
 
 	authService        *domain.AuthService
 	classService       *domain.ClassService
@@ -43,6 +46,7 @@ type ApiController struct {
 	userService        *domain.UserService
 	healthService      *domain.HealthService
 	maintenanceService *domain.MaintenanceService
+	markdownRenderer   *markdownRenderer
 }
 
 func NewApiController(
@@ -52,9 +56,9 @@ func NewApiController(
 	userService *domain.UserService,
 	healthService *domain.HealthService,
 	maintenanceService *domain.MaintenanceService) ApiController {
-	return ApiController{lastSyncUpdate: time.Now(), authService: authService, classService: classService,
+	return ApiController{syncThrottle: newSyncThrottle(10 * time.Second), authService: authService, classService: classService,
 		quizService: quizService, userService: userService, healthService: healthService,
-		maintenanceService: maintenanceService}
+		maintenanceService: maintenanceService, markdownRenderer: newMarkdownRenderer()}
 }
 
 var pathRoleMapping = map[*endPointDef]domain.Role{}
@@ -84,7 +88,12 @@ func (c *ApiController) Serve() {
 	maintenance.Use(enforceApiKey)
 
 	addPostEndpoint(public, "/login", domain.NoRole, c.login)
-	addPostEndpoint(public, "/sync", domain.NoRole, c.sync)
+	if viper.GetBool("public-sync") {
+		addPostEndpoint(public, "/sync", domain.NoRole, c.sync)
+	}
+	if viper.GetString("webhook-secret") != "" {
+		addPostEndpoint(public, "/webhook", domain.NoRole, c.webhook)
+	}
 
 	addGetEndpoint(health, "/started", domain.NoRole, c.started)
 	addGetEndpoint(health, "/ready", domain.NoRole, c.ready)
@@ -92,13 +101,23 @@ func (c *ApiController) Serve() {
 
 	addGetEndpoint(maintenance, "/database/dump", domain.Machine, c.dbDump)
 
+	addPostEndpoint(private, "/sync/report", domain.Teacher, c.syncReport)
+
 	addGetEndpoint(private, "/quiz", domain.Student, c.quizList)
 	addGetEndpoint(private, "/quiz/:sha1", domain.Student, c.quizBySha1)
+	addPostEndpoint(private, "/quiz/import", domain.Admin, c.quizImport)
+	addGetEndpoint(private, "/quiz/:sha1/export", domain.Teacher, c.quizExport)
 	addPostEndpoint(private, "/quiz/:sha1/class/:uuid", domain.Teacher, c.createQuizClassVisibility)
 	addDeleteEndpoint(private, "/quiz/:sha1/class/:uuid", domain.Teacher, c.deleteQuizClassVisibility)
 
+	addGetEndpoint(private, "/asset/:sha1", domain.Student, c.assetBySha1)
+
+	addPutEndpoint(private, "/source/:name/archive", domain.Admin, c.sourceArchiveUpload)
+
 	addGetEndpoint(private, "/user", domain.Teacher, c.userList)
 	addGetEndpoint(private, "/user/me", domain.Student, c.me)
+	addPutEndpoint(private, "/user/me/lang/:lang", domain.Student, c.updateMyLang)
+	addDeleteEndpoint(private, "/user/me/lang", domain.Student, c.clearMyLang)
 	addDeleteEndpoint(private, "/user/:id", domain.Admin, c.deactivateUser)
 	addPutEndpoint(private, "/user/:id/activate", domain.Admin, c.activateUser)
 	addPutEndpoint(private, "/user/:id/role/:roleName", domain.Admin, c.updateUserRole)
@@ -193,3 +212,57 @@ func extractRangeHeader(rangeHeader string, unit string) (uint16, uint16, error)
 
 	return uint16(start), uint16(end), nil
 }
+
+// extractAcceptLanguageHeader gives the languages of an Accept-Language header, the preferred ones
+// first. The wildcard and the languages with a zero weight are left out
+func extractAcceptLanguageHeader(acceptLanguageHeader string) []string {
+	type weightedLang struct {
+		lang   string
+		weight float64
+	}
+
+	var weightedLangs []weightedLang
+	for _, part := range strings.Split(acceptLanguageHeader, ",") {
+		lang, params, _ := strings.Cut(part, ";")
+		lang = strings.TrimSpace(lang)
+		if lang == "" || lang == "*" {
+			continue
+		}
+
+		weight := 1.0
+		if q, found := strings.CutPrefix(strings.TrimSpace(params), "q="); found {
+			w, err := strconv.ParseFloat(q, 64)
+			if err != nil {
+				continue
+			}
+			weight = w
+		}
+		if weight <= 0 {
+			continue
+		}
+
+		weightedLangs = append(weightedLangs, weightedLang{lang: lang, weight: weight})
+	}
+
+	sort.SliceStable(weightedLangs, func(i, j int) bool {
+		return weightedLangs[i].weight > weightedLangs[j].weight
+	})
+
+	langs := make([]string, len(weightedLangs))
+	for i, l := range weightedLangs {
+		langs[i] = l.lang
+	}
+
+	return langs
+}
+
+// getPreferredLangs gives the languages the user wants the quizzes in, the language chosen by the
+// user coming before the ones of the Accept-Language header
+func getPreferredLangs(ctx *gin.Context) []string {
+	var langs []string
+	if user, found := ctx.Get(userCtxKey); found && user.(*domain.User).Lang != "" {
+		langs = append(langs, user.(*domain.User).Lang)
+	}
+
+	return append(langs, extractAcceptLanguageHeader(ctx.GetHeader("Accept-Language"))...)
+}
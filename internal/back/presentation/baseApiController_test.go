@@ -113,3 +113,9 @@ func Test_addDeleteEndpoint(t *testing.T) {
 
 func testHandlerFunc(_ *gin.Context) {
 }
+
+func Test_extractAcceptLanguageHeader(t *testing.T) {
+	assert.Equal(t, []string{"fr-CH", "fr", "en"}, extractAcceptLanguageHeader("fr-CH, fr;q=0.9, en;q=0.8, *;q=0.5"))
+	assert.Equal(t, []string{"en", "de"}, extractAcceptLanguageHeader("de;q=0.5, en, fr;q=0"))
+	assert.Empty(t, extractAcceptLanguageHeader(""))
+}
@@ -0,0 +1,344 @@
+/*
+ * Copyright (c) 2023 Michaël COLL.
+ *
+ * Licensed under the Apache License, Version 2.0 (the "License");
+ * you may not use this file except in compliance with the License.
+ * You may obtain a copy of the License at
+ *
+ *      http://www.apache.org/licenses/LICENSE-2.0
+ *
+ * Unless required by applicable law or agreed to in writing, software
+ * distributed under the License is distributed on an "AS IS" BASIS,
+ * WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+ * See the License for the specific language governing permissions and
+ * limitations under the License.
+ */
+
+package presentation
+
+import (
+	"html"
+	"net/http"
+	"regexp"
+	"strings"
+	"sync"
+	"time"
+	"unicode"
+	"unicode/utf8"
+
+	"github.com/patrickmn/go-cache"
+	xhtml "golang.org/x/net/html"
+
+	"github.com/michaelcoll/quiz-app/internal/back/domain"
+)
+
+type ContentFormat string
+
+const (
+	MarkdownFormat ContentFormat = "markdown"
+	HtmlFormat     ContentFormat = "html"
+)
+
+// parseContentFormat reads the format query parameter telling how the texts of the questions are
+// returned, markdown being the default
+func parseContentFormat(format string) (ContentFormat, error) {
+	switch ContentFormat(strings.ToLower(format)) {
+	case "", MarkdownFormat:
+		return MarkdownFormat, nil
+	case HtmlFormat:
+		return HtmlFormat, nil
+	}
+
+	return "", Errorf(http.StatusBadRequest, "format '%s' is not supported, supported formats : markdown, html", format)
+}
+
+// inlineRegexp matches the inline markdown elements, in order of precedence : escaped characters,
+// code spans, LaTeX formulas, images, links, strong and emphasized texts
+var inlineRegexp = regexp.MustCompile(
+	"\\\\([\\\\`*_{}\\[\\]()#+\\-.!$])" +
+		"|``([\\s\\S]+?)``" +
+		"|`([^`]+)`" +
+		"|\\$\\$([\\s\\S]+?)\\$\\$" +
+		"|\\$([^\\s$](?:[^$\\n]*[^\\s$])?)\\$" +
+		"|!\\[([^\\]]*)\\]\\(([^)\\s]+)(?:\\s+\"[^\"]*\")?\\)" +
+		"|\\[([^\\]]+)\\]\\(([^)\\s]+)(?:\\s+\"[^\"]*\")?\\)" +
+		"|\\*\\*(\\S(?:[\\s\\S]*?\\S)?)\\*\\*" +
+		"|__(\\S(?:[\\s\\S]*?\\S)?)__" +
+		"|\\*(\\S(?:[\\s\\S]*?\\S)?)\\*" +
+		"|_(\\S(?:[\\s\\S]*?\\S)?)_")
+
+var paragraphRegexp = regexp.MustCompile(`\n[ \t]*\n`)
+
+// renderMarkdown renders the inline elements of a markdown text to sanitized HTML. The paragraphs
+// are separated by blank lines and the lines of a paragraph by line breaks, the HTML written in the
+// text being escaped
+func renderMarkdown(text string) string {
+	text = strings.TrimSpace(strings.ReplaceAll(text, "\r\n", "\n"))
+	if text == "" {
+		return ""
+	}
+
+	var sb strings.Builder
+	for _, paragraph := range paragraphRegexp.Split(text, -1) {
+		sb.WriteString("<p>")
+		sb.WriteString(strings.ReplaceAll(renderInline(strings.TrimSpace(paragraph)), "\n", "<br>"))
+		sb.WriteString("</p>")
+	}
+
+	return sanitizeHtml(sb.String())
+}
+
+// renderInline renders the inline elements of a text, the text around them being escaped
+func renderInline(text string) string {
+	var sb strings.Builder
+
+	for text != "" {
+		m := inlineRegexp.FindStringSubmatchIndex(text)
+		if m == nil {
+			sb.WriteString(html.EscapeString(text))
+			break
+		}
+
+		sb.WriteString(html.EscapeString(text[:m[0]]))
+		group := func(i int) string {
+			if m[2*i] == -1 {
+				return ""
+			}
+			return text[m[2*i]:m[2*i+1]]
+		}
+		matched := func(i int) bool {
+			return m[2*i] != -1
+		}
+
+		element, ok := "", true
+		switch {
+		case matched(1):
+			element = html.EscapeString(group(1))
+		case matched(2):
+			element = "<code>" + html.EscapeString(strings.TrimSpace(group(2))) + "</code>"
+		case matched(3):
+			element = "<code>" + html.EscapeString(group(3)) + "</code>"
+		case matched(4):
+			element = `<span class="math display">` + html.EscapeString(strings.TrimSpace(group(4))) + "</span>"
+		case matched(5):
+			// a dollar followed by a digit is an amount, not the end of a formula
+			ok = !startsWithDigit(text[m[1]:])
+			element = `<span class="math inline">` + html.EscapeString(group(5)) + "</span>"
+		case matched(6):
+			element = renderImage(group(6), group(7))
+		case matched(8):
+			element = renderLink(group(8), group(9))
+		case matched(10):
+			element = "<strong>" + renderInline(group(10)) + "</strong>"
+		case matched(11):
+			ok = isWordBoundary(text, m[0], m[1])
+			element = "<strong>" + renderInline(group(11)) + "</strong>"
+		case matched(12):
+			element = "<em>" + renderInline(group(12)) + "</em>"
+		case matched(13):
+			ok = isWordBoundary(text, m[0], m[1])
+			element = "<em>" + renderInline(group(13)) + "</em>"
+		}
+
+		if !ok {
+			// the first character is kept as is and the text is read again from the next one
+			_, size := utf8.DecodeRuneInString(text[m[0]:])
+			sb.WriteString(html.EscapeString(text[m[0] : m[0]+size]))
+			text = text[m[0]+size:]
+			continue
+		}
+
+		sb.WriteString(element)
+		text = text[m[1]:]
+	}
+
+	return sb.String()
+}
+
+// renderImage renders the images served by the asset endpoint, the others are replaced by their
+// alternative text so that showing a question never calls another server
+func renderImage(alt string, src string) string {
+	if !strings.HasPrefix(src, domain.AssetPath) {
+		return html.EscapeString(alt)
+	}
+
+	return `<img src="` + html.EscapeString(src) + `" alt="` + html.EscapeString(alt) + `">`
+}
+
+// renderLink renders the links to web pages, mail addresses and assets, the others are replaced by
+// their text
+func renderLink(text string, href string) string {
+	if !isAllowedHref(href) {
+		return renderInline(text)
+	}
+
+	return `<a href="` + html.EscapeString(href) + `" rel="noopener noreferrer nofollow" target="_blank">` + renderInline(text) + "</a>"
+}
+
+func isAllowedHref(href string) bool {
+	lower := strings.ToLower(href)
+	for _, prefix := range []string{"https://", "http://", "mailto:", domain.AssetPath} {
+		if strings.HasPrefix(lower, prefix) {
+			return true
+		}
+	}
+
+	return false
+}
+
+func startsWithDigit(s string) bool {
+	return s != "" && s[0] >= '0' && s[0] <= '9'
+}
+
+// isWordBoundary tells if the underscores delimiting a text are not inside a word, like in a
+// snake_case name
+func isWordBoundary(text string, start int, end int) bool {
+	isWordChar := func(r rune) bool {
+		return unicode.IsLetter(r) || unicode.IsDigit(r)
+	}
+
+	if before, _ := utf8.DecodeLastRuneInString(text[:start]); start > 0 && isWordChar(before) {
+		return false
+	}
+	if after, _ := utf8.DecodeRuneInString(text[end:]); end < len(text) && isWordChar(after) {
+		return false
+	}
+
+	return true
+}
+
+// allowedAttributes are the only elements and attributes the sanitizer keeps
+var allowedAttributes = map[string]map[string]bool{
+	"p":      {},
+	"br":     {},
+	"code":   {},
+	"em":     {},
+	"strong": {},
+	"span":   {"class": true},
+	"a":      {"href": true, "rel": true, "target": true},
+	"img":    {"src": true, "alt": true},
+}
+
+var allowedClasses = map[string]bool{"math inline": true, "math display": true}
+
+// sanitizeHtml keeps only the allowed elements and attributes of an HTML fragment, the text of the
+// other elements being escaped and the content of the script and style elements dropped
+func sanitizeHtml(fragment string) string {
+	var sb strings.Builder
+	tokenizer := xhtml.NewTokenizer(strings.NewReader(fragment))
+	skipped := ""
+
+	for {
+		tokenType := tokenizer.Next()
+		if tokenType == xhtml.ErrorToken {
+			return sb.String()
+		}
+
+		token := tokenizer.Token()
+		if skipped != "" {
+			if tokenType == xhtml.EndTagToken && token.Data == skipped {
+				skipped = ""
+			}
+			continue
+		}
+
+		switch tokenType {
+		case xhtml.TextToken:
+			sb.WriteString(html.EscapeString(token.Data))
+		case xhtml.StartTagToken, xhtml.SelfClosingTagToken:
+			if token.Data == "script" || token.Data == "style" {
+				if tokenType == xhtml.StartTagToken {
+					skipped = token.Data
+				}
+				continue
+			}
+			attributes, allowed := allowedAttributes[token.Data]
+			if !allowed {
+				continue
+			}
+			sb.WriteString("<" + token.Data)
+			for _, attribute := range token.Attr {
+				if attributes[attribute.Key] && isAllowedAttribute(token.Data, attribute) {
+					sb.WriteString(" " + attribute.Key + `="` + html.EscapeString(attribute.Val) + `"`)
+				}
+			}
+			sb.WriteString(">")
+		case xhtml.EndTagToken:
+			if _, allowed := allowedAttributes[token.Data]; allowed && token.Data != "br" && token.Data != "img" {
+				sb.WriteString("</" + token.Data + ">")
+			}
+		}
+	}
+}
+
+func isAllowedAttribute(element string, attribute xhtml.Attribute) bool {
+	switch attribute.Key {
+	case "href":
+		return isAllowedHref(attribute.Val)
+	case "src":
+		return strings.HasPrefix(attribute.Val, domain.AssetPath)
+	case "class":
+		return element == "span" && allowedClasses[attribute.Val]
+	}
+
+	return true
+}
+
+// markdownRenderer renders the texts of the questions to HTML. As a question is identified by the
+// sha1 of its content, the HTML of its texts is cached by its sha1, in all the languages it is
+// returned in
+type markdownRenderer struct {
+	c  *cache.Cache
+	mu sync.Mutex
+}
+
+func newMarkdownRenderer() *markdownRenderer {
+	return &markdownRenderer{c: cache.New(30*time.Minute, 10*time.Minute)}
+}
+
+// render gives the HTML of a text of the given question
+func (r *markdownRenderer) render(questionSha1 string, text string) string {
+	if text == "" {
+		return ""
+	}
+
+	r.mu.Lock()
+	defer r.mu.Unlock()
+
+	rendered := map[string]string{}
+	if cached, found := r.c.Get(questionSha1); found {
+		rendered = cached.(map[string]string)
+	}
+
+	if h, found := rendered[text]; found {
+		return h
+	}
+
+	h := renderMarkdown(text)
+	rendered[text] = h
+	r.c.Set(questionSha1, rendered, cache.DefaultExpiration)
+
+	return h
+}
+
+// renderQuestions replaces the markdown texts of the questions by their HTML. The code of a
+// question is left as is, being shown as a code block
+func (r *markdownRenderer) renderQuestions(questions []QuizQuestion) {
+	for i := range questions {
+		question := &questions[i]
+		question.Content = r.render(question.Sha1, question.Content)
+		question.Explanation = r.render(question.Sha1, question.Explanation)
+
+		for j := range question.Answers {
+			answer := &question.Answers[j]
+			answer.Content = r.render(question.Sha1, answer.Content)
+			answer.Right = r.render(question.Sha1, answer.Right)
+			answer.AnsweredRight = r.render(question.Sha1, answer.AnsweredRight)
+			answer.Explanation = r.render(question.Sha1, answer.Explanation)
+		}
+
+		for j := range question.RightItems {
+			question.RightItems[j].Content = r.render(question.Sha1, question.RightItems[j].Content)
+		}
+	}
+}
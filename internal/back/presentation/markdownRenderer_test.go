@@ -0,0 +1,99 @@
+/*
+ * Copyright (c) 2023 Michaël COLL.
+ *
+ * Licensed under the Apache License, Version 2.0 (the "License");
+ * you may not use this file except in compliance with the License.
+ * You may obtain a copy of the License at
+ *
+ *      http://www.apache.org/licenses/LICENSE-2.0
+ *
+ * Unless required by applicable law or agreed to in writing, software
+ * distributed under the License is distributed on an "AS IS" BASIS,
+ * WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+ * See the License for the specific language governing permissions and
+ * limitations under the License.
+ */
+
+package presentation
+
+import (
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+)
+
+func Test_renderMarkdown(t *testing.T) {
+	tests := []struct {
+		name     string
+		markdown string
+		html     string
+	}{
+		{"text", "What is 1 < 2 ?", "<p>What is 1 &lt; 2 ?</p>"},
+		{"code", "What does `git add <file>` do ?", "<p>What does <code>git add &lt;file&gt;</code> do ?</p>"},
+		{"double backticks", "Type ``a `b` c``", "<p>Type <code>a `b` c</code></p>"},
+		{"emphasis", "A *very* **important** _point_ and __this__", "<p>A <em>very</em> <strong>important</strong> <em>point</em> and <strong>this</strong></p>"},
+		{"snake case", "Call my_function_name", "<p>Call my_function_name</p>"},
+		{"escaped", `Not \*emphasized\*`, "<p>Not *emphasized*</p>"},
+		{"link", "See [the *doc*](https://git-scm.com/doc)", `<p>See <a href="https://git-scm.com/doc" rel="noopener noreferrer nofollow" target="_blank">the <em>doc</em></a></p>`},
+		{"javascript link", "[click](javascript:alert(1))", "<p>click)</p>"},
+		{"asset image", "![branch](/api/v1/asset/42)", `<p><img src="/api/v1/asset/42" alt="branch"></p>`},
+		{"external image", "![tracker](https://evil.example/pixel.png)", "<p>tracker</p>"},
+		{"inline math", "Solve $x^2 < 4$", `<p>Solve <span class="math inline">x^2 &lt; 4</span></p>`},
+		{"display math", "$$\\frac{a}{b}$$", `<p><span class="math display">\frac{a}{b}</span></p>`},
+		{"amounts", "It costs $5 or $10", "<p>It costs $5 or $10</p>"},
+		{"paragraphs", "First line\nsecond line\n\nNew paragraph", "<p>First line<br>second line</p><p>New paragraph</p>"},
+		{"script", "<script>alert('x')</script><b onclick=\"x\">bold</b>", "<p>&lt;script&gt;alert(&#39;x&#39;)&lt;/script&gt;&lt;b onclick=&#34;x&#34;&gt;bold&lt;/b&gt;</p>"},
+		{"empty", "  ", ""},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			assert.Equal(t, tt.html, renderMarkdown(tt.markdown))
+		})
+	}
+}
+
+func Test_sanitizeHtml(t *testing.T) {
+	assert.Equal(t, "<p>hello</p>", sanitizeHtml(`<p onclick="alert(1)">hello<script>alert(1)</script></p>`))
+	assert.Equal(t, `<a>x</a>`, sanitizeHtml(`<a href="javascript:alert(1)">x</a>`))
+	assert.Equal(t, `<span>x</span><img alt="y">`, sanitizeHtml(`<span class="evil">x</span><img src="https://evil.example" alt="y">`))
+	assert.Equal(t, "text", sanitizeHtml(`<iframe src="https://evil.example"></iframe><div>text</div>`))
+}
+
+func Test_parseContentFormat(t *testing.T) {
+	format, err := parseContentFormat("")
+	assert.NoError(t, err)
+	assert.Equal(t, MarkdownFormat, format)
+
+	format, err = parseContentFormat("HTML")
+	assert.NoError(t, err)
+	assert.Equal(t, HtmlFormat, format)
+
+	_, err = parseContentFormat("pdf")
+	assert.Error(t, err)
+}
+
+func TestMarkdownRenderer_renderQuestions(t *testing.T) {
+	r := newMarkdownRenderer()
+	questions := []QuizQuestion{{
+		Sha1:        "q1",
+		Content:     "What does `git add` do ?",
+		Code:        "git add <file>",
+		Explanation: "It *stages* the file",
+		Answers:     []QuizQuestionAnswer{{Sha1: "a1", Content: "Stages <file>", Right: "**index**"}},
+		RightItems:  []QuizPairItem{{Sha1: "r1", Content: "**index**"}},
+	}}
+
+	r.renderQuestions(questions)
+
+	assert.Equal(t, "<p>What does <code>git add</code> do ?</p>", questions[0].Content)
+	assert.Equal(t, "git add <file>", questions[0].Code)
+	assert.Equal(t, "<p>It <em>stages</em> the file</p>", questions[0].Explanation)
+	assert.Equal(t, "<p>Stages &lt;file&gt;</p>", questions[0].Answers[0].Content)
+	assert.Equal(t, "<p><strong>index</strong></p>", questions[0].Answers[0].Right)
+	assert.Equal(t, "<p><strong>index</strong></p>", questions[0].RightItems[0].Content)
+	assert.Empty(t, questions[0].Answers[0].Explanation)
+
+	cached, found := r.c.Get("q1")
+	assert.True(t, found)
+	assert.Len(t, cached, 4)
+}
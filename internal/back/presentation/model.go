@@ -17,6 +17,7 @@
 package presentation
 
 import (
+	"math"
 	"net/http"
 	"regexp"
 	"sort"
@@ -33,16 +34,88 @@ type QuizInfos interface {
 
 type Quiz struct {
 	Sha1      string         `json:"sha1"`
+	Source    string         `json:"source,omitempty"`
 	Filename  string         `json:"filename"`
 	Name      string         `json:"name"`
+	Category  string         `json:"category,omitempty"`
 	Version   int            `json:"version"`
 	CreatedAt string         `json:"createdAt"`
 	Duration  int            `json:"duration"`
 	Active    bool           `json:"active"`
+	Lang      string         `json:"lang,omitempty"`
+	Langs     []string       `json:"langs,omitempty"`
+	Metadata  *QuizMetadata  `json:"metadata,omitempty"`
 	Questions []QuizQuestion `json:"questions,omitempty"`
 	Classes   []Class        `json:"classes,omitempty"`
 }
 
+type QuizMetadata struct {
+	Description      string          `json:"description,omitempty"`
+	Tags             []string        `json:"tags,omitempty"`
+	Author           string          `json:"author,omitempty"`
+	PassMark         int             `json:"passMark,omitempty"`
+	ShuffleQuestions bool            `json:"shuffleQuestions,omitempty"`
+	ShuffleAnswers   bool            `json:"shuffleAnswers,omitempty"`
+	MaxAttempts      int             `json:"maxAttempts,omitempty"`
+	Scoring          ScoringStrategy `json:"scoring,omitempty"`
+	Pool             *QuizPool       `json:"pool,omitempty"`
+}
+
+type QuizPool struct {
+	Draw int      `json:"draw"`
+	Tags []string `json:"tags,omitempty"`
+}
+
+func toQuizPoolDto(d domain.QuizPool) *QuizPool {
+	if d.Draw == 0 {
+		return nil
+	}
+
+	return &QuizPool{
+		Draw: d.Draw,
+		Tags: d.Tags,
+	}
+}
+
+type ScoringStrategy string
+
+const (
+	AllOrNothing    ScoringStrategy = "ALL_OR_NOTHING"
+	Proportional                    = "PARTIAL"
+	NegativeMarking                 = "NEGATIVE"
+)
+
+func toScoringStrategyDto(d domain.ScoringStrategy) ScoringStrategy {
+	dto := AllOrNothing
+	switch d {
+	case domain.Proportional:
+		dto = Proportional
+	case domain.NegativeMarking:
+		dto = NegativeMarking
+	}
+	return dto
+}
+
+func toQuizMetadataDto(d domain.QuizMetadata) *QuizMetadata {
+	if d.Description == "" && len(d.Tags) == 0 && d.Author == "" && d.PassMark == 0 &&
+		!d.ShuffleQuestions && !d.ShuffleAnswers && d.MaxAttempts == 0 && d.Scoring == domain.AllOrNothing &&
+		d.Pool.Draw == 0 {
+		return nil
+	}
+
+	return &QuizMetadata{
+		Description:      d.Description,
+		Tags:             d.Tags,
+		Author:           d.Author,
+		PassMark:         d.PassMark,
+		ShuffleQuestions: d.ShuffleQuestions,
+		ShuffleAnswers:   d.ShuffleAnswers,
+		MaxAttempts:      d.MaxAttempts,
+		Scoring:          toScoringStrategyDto(d.Scoring),
+		Pool:             toQuizPoolDto(d.Pool),
+	}
+}
+
 func (dto *Quiz) setSha1NameAndDuration(sha1 string, name string, duration int) {
 	dto.Sha1 = sha1
 	dto.Name = name
@@ -53,20 +126,103 @@ func (dto *Quiz) setQuestions(questions []QuizQuestion) {
 	dto.Questions = questions
 }
 
+type QuestionKind string
+
+const (
+	Choice      QuestionKind = "CHOICE"
+	ShortAnswer              = "SHORT_ANSWER"
+	Numeric                  = "NUMERIC"
+	Ordering                 = "ORDERING"
+	Matching                 = "MATCHING"
+)
+
+func toQuestionKindDto(d domain.QuestionKind) QuestionKind {
+	dto := Choice
+	switch d {
+	case domain.ShortAnswer:
+		dto = ShortAnswer
+	case domain.Numeric:
+		dto = Numeric
+	case domain.Ordering:
+		dto = Ordering
+	case domain.Matching:
+		dto = Matching
+	}
+	return dto
+}
+
+type AnswerMatch string
+
+const (
+	NoMatch              AnswerMatch = ""
+	ExactMatch                       = "EXACT"
+	CaseInsensitiveMatch             = "CASE_INSENSITIVE"
+	RegexMatch                       = "REGEX"
+	ToleranceMatch                   = "TOLERANCE"
+)
+
+func toAnswerMatchDto(d domain.AnswerMatch) AnswerMatch {
+	dto := NoMatch
+	switch d {
+	case domain.ExactMatch:
+		dto = ExactMatch
+	case domain.CaseInsensitiveMatch:
+		dto = CaseInsensitiveMatch
+	case domain.RegexMatch:
+		dto = RegexMatch
+	case domain.ToleranceMatch:
+		dto = ToleranceMatch
+	}
+	return dto
+}
+
 type QuizQuestion struct {
-	Sha1         string               `json:"sha1"`
-	Position     int                  `json:"position"`
-	Content      string               `json:"content"`
-	Code         string               `json:"code,omitempty"`
-	CodeLanguage string               `json:"codeLanguage,omitempty"`
-	Answers      []QuizQuestionAnswer `json:"answers,omitempty"`
+	Sha1          string               `json:"sha1"`
+	Id            string               `json:"id"`
+	Kind          QuestionKind         `json:"kind"`
+	Position      int                  `json:"position"`
+	Content       string               `json:"content"`
+	Code          string               `json:"code,omitempty"`
+	CodeLanguage  string               `json:"codeLanguage,omitempty"`
+	Text          string               `json:"text,omitempty"`
+	PartialCredit bool                 `json:"partialCredit,omitempty"`
+	Points        int                  `json:"points"`
+	Score         float64              `json:"score,omitempty"`
+	Explanation   string               `json:"explanation,omitempty"`
+	Answers       []QuizQuestionAnswer `json:"answers,omitempty"`
+	RightItems    []QuizPairItem       `json:"rightItems,omitempty"`
 }
 
 type QuizQuestionAnswer struct {
+	Sha1             string      `json:"sha1"`
+	Content          string      `json:"content"`
+	Checked          bool        `json:"checked"`
+	Valid            bool        `json:"valid,omitempty"`
+	Match            AnswerMatch `json:"match,omitempty"`
+	Position         int         `json:"position,omitempty"`
+	AnsweredPosition int         `json:"answeredPosition,omitempty"`
+	Right            string      `json:"right,omitempty"`
+	AnsweredRight    string      `json:"answeredRight,omitempty"`
+	Explanation      string      `json:"explanation,omitempty"`
+}
+
+type QuizPairItem struct {
 	Sha1    string `json:"sha1"`
 	Content string `json:"content"`
-	Checked bool   `json:"checked"`
-	Valid   bool   `json:"valid,omitempty"`
+}
+
+// toQuizPairItemDtos sorts the right-hand items by content so that their order does not give the pairs away
+func toQuizPairItemDtos(rightItems map[string]string) []QuizPairItem {
+	dtos := make([]QuizPairItem, 0, len(rightItems))
+	for sha1, content := range rightItems {
+		dtos = append(dtos, QuizPairItem{Sha1: sha1, Content: content})
+	}
+
+	sort.Slice(dtos, func(i, j int) bool {
+		return dtos[i].Content < dtos[j].Content
+	})
+
+	return dtos
 }
 
 func mapQuizInfos(d domain.QuizInfos, dto QuizInfos) {
@@ -77,25 +233,49 @@ func mapQuizInfos(d domain.QuizInfos, dto QuizInfos) {
 	i := 0
 	for _, question := range d.GetQuestions() {
 
-		j := 0
-		answers := make([]QuizQuestionAnswer, len(question.Answers))
+		// the answers are listed in the order they are shown to the user
+		sortedAnswers := make([]domain.QuizQuestionAnswer, 0, len(question.Answers))
 		for _, a := range question.Answers {
+			sortedAnswers = append(sortedAnswers, a)
+		}
+		sort.Slice(sortedAnswers, func(i, j int) bool {
+			if sortedAnswers[i].Ordinal != sortedAnswers[j].Ordinal {
+				return sortedAnswers[i].Ordinal < sortedAnswers[j].Ordinal
+			}
+			return sortedAnswers[i].Sha1 < sortedAnswers[j].Sha1
+		})
+
+		answers := make([]QuizQuestionAnswer, len(sortedAnswers))
+		for j, a := range sortedAnswers {
 			answers[j] = QuizQuestionAnswer{
-				Sha1:    a.Sha1,
-				Content: a.Content,
-				Checked: a.Checked,
-				Valid:   a.Valid,
+				Sha1:             a.Sha1,
+				Content:          a.Content,
+				Checked:          a.Checked,
+				Valid:            a.Valid,
+				Match:            toAnswerMatchDto(a.Match),
+				Position:         a.Position,
+				AnsweredPosition: a.AnsweredPosition,
+				Right:            a.Right,
+				AnsweredRight:    a.AnsweredRight,
+				Explanation:      a.Explanation,
 			}
-			j++
 		}
 
 		questions[i] = QuizQuestion{
-			Sha1:         question.Sha1,
-			Position:     question.Position,
-			Content:      question.Content,
-			Code:         question.Code,
-			CodeLanguage: question.CodeLanguage,
-			Answers:      answers,
+			Sha1:          question.Sha1,
+			Id:            question.Id,
+			Kind:          toQuestionKindDto(question.Kind),
+			Position:      question.Position,
+			Content:       question.Content,
+			Code:          question.Code,
+			CodeLanguage:  question.CodeLanguage,
+			Text:          question.Text,
+			PartialCredit: question.PartialCredit,
+			Points:        question.Points,
+			Score:         roundScore(question.Score),
+			Explanation:   question.Explanation,
+			Answers:       answers,
+			RightItems:    toQuizPairItemDtos(question.RightItems),
 		}
 		i++
 	}
@@ -108,11 +288,16 @@ func mapQuizInfos(d domain.QuizInfos, dto QuizInfos) {
 }
 
 func (dto *Quiz) fromDomain(d *domain.Quiz) *Quiz {
+	dto.Source = d.Source
 	dto.Filename = d.Filename
+	dto.Category = d.Category
 	dto.Version = d.Version
 	dto.Duration = d.Duration
 	dto.CreatedAt = d.CreatedAt
 	dto.Active = d.Active
+	dto.Lang = d.Metadata.Lang
+	dto.Langs = d.Langs
+	dto.Metadata = toQuizMetadataDto(d.Metadata)
 
 	for id, name := range d.Classes {
 		dto.Classes = append(dto.Classes, Class{
@@ -195,6 +380,7 @@ type User struct {
 	Active  bool   `json:"active"`
 	Role    Role   `json:"role"`
 	Class   *Class `json:"class"`
+	Lang    string `json:"lang,omitempty"`
 }
 
 func (dto *User) fromDomain(d *domain.User) *User {
@@ -204,6 +390,7 @@ func (dto *User) fromDomain(d *domain.User) *User {
 	dto.Picture = d.Picture
 	dto.Active = d.Active
 	dto.Role = toRoleDto(d.Role)
+	dto.Lang = d.Lang
 	if d.Class != nil {
 		dto.Class = toClassDto(d.Class)
 	}
@@ -212,8 +399,26 @@ func (dto *User) fromDomain(d *domain.User) *User {
 }
 
 type SessionResult struct {
-	GoodAnswer  int `json:"goodAnswer,omitempty"`
-	TotalAnswer int `json:"totalAnswer,omitempty"`
+	GoodAnswer     int     `json:"goodAnswer,omitempty"`
+	TotalAnswer    int     `json:"totalAnswer,omitempty"`
+	PointsEarned   float64 `json:"pointsEarned,omitempty"`
+	PointsPossible int     `json:"pointsPossible,omitempty"`
+	Percentage     float64 `json:"percentage,omitempty"`
+}
+
+func toSessionResult(d *domain.SessionResult) *SessionResult {
+	return &SessionResult{
+		GoodAnswer:     d.GoodAnswer,
+		TotalAnswer:    d.TotalAnswer,
+		PointsEarned:   roundScore(d.PointsEarned),
+		PointsPossible: d.PointsPossible,
+		Percentage:     d.Percentage(),
+	}
+}
+
+// roundScore rounds the points earned with partial credit to 2 decimals
+func roundScore(score float64) float64 {
+	return math.Round(score*100) / 100
 }
 
 type Session struct {
@@ -236,10 +441,7 @@ func (dto *Session) fromDomain(d *domain.Session) *Session {
 	dto.UserName = d.UserName
 	dto.RemainingSec = d.RemainingSec
 	if d.Result != nil {
-		dto.Result = &SessionResult{
-			GoodAnswer:  d.Result.GoodAnswer,
-			TotalAnswer: d.Result.TotalAnswer,
-		}
+		dto.Result = toSessionResult(d.Result)
 	}
 
 	return dto
@@ -257,9 +459,13 @@ func toSessionDtos(domains []*domain.Session) []*Session {
 }
 
 type SessionAnswerRequestBody struct {
-	QuestionSha1 string `json:"questionSha1" binding:"required"`
-	AnswerSha1   string `json:"answerSha1" binding:"required"`
-	Checked      bool   `json:"checked"`
+	QuestionSha1 string            `json:"questionSha1" binding:"required"`
+	AnswerSha1   string            `json:"answerSha1"`
+	Checked      bool              `json:"checked"`
+	Text         *string           `json:"text"`
+	Number       *float64          `json:"number"`
+	Order        []string          `json:"order"`
+	Pairs        map[string]string `json:"pairs"`
 }
 
 type Class struct {
@@ -288,6 +494,14 @@ type ClassRequestBody struct {
 	Name string `json:"name" binding:"required"`
 }
 
+type QuizImportRequestBody struct {
+	Filename string `json:"filename" binding:"required"`
+	Format   string `json:"format"`
+	Name     string `json:"name"`
+	Duration int    `json:"duration"`
+	Content  string `json:"content" binding:"required"`
+}
+
 type UserSession struct {
 	SessionId    *uuid.UUID     `json:"sessionId"`
 	UserId       string         `json:"userId"`
@@ -332,10 +546,7 @@ func toQuizSession(domain *domain.QuizSession, userId string) *QuizSession {
 				session.RemainingSec = userSession.RemainingSec
 
 				if userSession.Result != nil {
-					session.Result = &SessionResult{
-						GoodAnswer:  userSession.Result.GoodAnswer,
-						TotalAnswer: userSession.Result.TotalAnswer,
-					}
+					session.Result = toSessionResult(userSession.Result)
 				}
 			}
 
@@ -349,8 +560,7 @@ func toQuizSession(domain *domain.QuizSession, userId string) *QuizSession {
 func toUserSession(domain *domain.UserSession) *UserSession {
 	result := &SessionResult{}
 	if domain.Result != nil {
-		result.GoodAnswer = domain.Result.GoodAnswer
-		result.TotalAnswer = domain.Result.TotalAnswer
+		result = toSessionResult(domain.Result)
 	}
 
 	return &UserSession{
@@ -379,9 +589,11 @@ type QuizSessionDetail struct {
 	UserId       string         `json:"userId"`
 	RemainingSec int            `json:"remainingSec"`
 	Result       *SessionResult `json:"result,omitempty"`
+	Seed         int64          `json:"seed,string"`
 	QuizSha1     string         `json:"quizSha1"`
 	Name         string         `json:"name"`
 	QuizDuration int            `json:"quizDuration"`
+	Lang         string         `json:"lang,omitempty"`
 	Questions    []QuizQuestion `json:"questions"`
 }
 
@@ -400,16 +612,100 @@ func toQuizSessionDetail(d *domain.QuizSessionDetail) *QuizSessionDetail {
 		SessionId:    d.SessionId,
 		UserId:       d.UserId,
 		RemainingSec: d.RemainingSec,
+		Seed:         d.Seed,
+		Lang:         d.Lang,
 	}
 
 	if d.Result != nil {
-		dto.Result = &SessionResult{
-			GoodAnswer:  d.Result.GoodAnswer,
-			TotalAnswer: d.Result.TotalAnswer,
-		}
+		dto.Result = toSessionResult(d.Result)
 	}
 
 	mapQuizInfos(d, dto)
 
 	return dto
 }
+
+type Severity string
+
+const (
+	ErrorSeverity   Severity = "ERROR"
+	WarningSeverity          = "WARNING"
+)
+
+type Diagnostic struct {
+	Source   string   `json:"source,omitempty"`
+	Filename string   `json:"filename"`
+	Line     int      `json:"line"`
+	Column   int      `json:"column"`
+	Severity Severity `json:"severity"`
+	Message  string   `json:"message"`
+}
+
+func ToDiagnosticDtos(domains domain.Diagnostics) []Diagnostic {
+	dtos := make([]Diagnostic, len(domains))
+
+	for i, d := range domains {
+		severity := ErrorSeverity
+		if d.Severity == domain.WarningSeverity {
+			severity = WarningSeverity
+		}
+
+		dtos[i] = Diagnostic{
+			Source:   d.Source,
+			Filename: d.Filename,
+			Line:     d.Line,
+			Column:   d.Column,
+			Severity: severity,
+			Message:  d.Message,
+		}
+	}
+
+	return dtos
+}
+
+type SyncStats struct {
+	Created     int          `json:"created"`
+	Updated     int          `json:"updated"`
+	Diagnostics []Diagnostic `json:"diagnostics"`
+}
+
+func toSyncStatsDto(d *domain.SyncStats) *SyncStats {
+	return &SyncStats{
+		Created:     d.Created,
+		Updated:     d.Updated,
+		Diagnostics: ToDiagnosticDtos(d.Diagnostics),
+	}
+}
+
+// SyncCounts is the outcome of a sync given to anyone, without the content of the quiz files
+type SyncCounts struct {
+	Created  int `json:"created"`
+	Updated  int `json:"updated"`
+	Errors   int `json:"errors"`
+	Warnings int `json:"warnings"`
+}
+
+func toSyncCountsDto(d *domain.SyncStats) *SyncCounts {
+	return &SyncCounts{
+		Created:  d.Created,
+		Updated:  d.Updated,
+		Errors:   d.Diagnostics.Count(domain.ErrorSeverity),
+		Warnings: d.Diagnostics.Count(domain.WarningSeverity),
+	}
+}
+
+type LintReport struct {
+	Files       int          `json:"files"`
+	Errors      int          `json:"errors"`
+	Warnings    int          `json:"warnings"`
+	Diagnostics []Diagnostic `json:"diagnostics"`
+}
+
+func ToLintReportDto(d *domain.LintReport) *LintReport {
+	return &LintReport{
+		Files:       d.Files,
+		Errors:      d.Diagnostics.Count(domain.ErrorSeverity),
+		Warnings:    d.Diagnostics.Count(domain.WarningSeverity),
+		Diagnostics: ToDiagnosticDtos(d.Diagnostics),
+	}
+}
@@ -128,6 +128,37 @@ func TestQuiz_fromDomain(t *testing.T) {
 	assert.Equal(t, answer4Content, answer.Content)
 }
 
+func Test_toSessionResult(t *testing.T) {
+	dto := toSessionResult(&domain.SessionResult{
+		GoodAnswer:     2,
+		TotalAnswer:    3,
+		PointsEarned:   2.0 / 3,
+		PointsPossible: 3,
+	})
+
+	assert.Equal(t, 2, dto.GoodAnswer)
+	assert.Equal(t, 3, dto.TotalAnswer)
+	assert.Equal(t, 0.67, dto.PointsEarned)
+	assert.Equal(t, 3, dto.PointsPossible)
+	assert.Equal(t, 22.22, dto.Percentage)
+
+	assert.Equal(t, 0.0, toSessionResult(&domain.SessionResult{}).Percentage)
+}
+
+func Test_toSyncCountsDto(t *testing.T) {
+	dto := toSyncCountsDto(&domain.SyncStats{
+		Created: 2,
+		Updated: 1,
+		Diagnostics: domain.Diagnostics{
+			{Filename: "secret.quiz.md", Line: 3, Severity: domain.ErrorSeverity, Message: "the answer is 42"},
+			{Filename: "secret.quiz.md", Line: 5, Severity: domain.WarningSeverity, Message: "no explanation"},
+			{Filename: "other.quiz.md", Line: 1, Severity: domain.ErrorSeverity, Message: "no question"},
+		},
+	})
+
+	assert.Equal(t, &SyncCounts{Created: 2, Updated: 1, Errors: 2, Warnings: 1}, dto)
+}
+
 func getQuestion(questions []QuizQuestion, sha1 string) (QuizQuestion, bool) {
 	for _, question := range questions {
 		if question.Sha1 == sha1 {
@@ -18,10 +18,15 @@ package presentation
 
 import (
 	"fmt"
+	"mime"
 	"net/http"
+	"path"
+	"strings"
 
 	"github.com/gin-gonic/gin"
 	"github.com/google/uuid"
+
+	"github.com/michaelcoll/quiz-app/internal/back/domain"
 )
 
 func (c *ApiController) quizList(ctx *gin.Context) {
@@ -42,7 +47,15 @@ func (c *ApiController) quizList(ctx *gin.Context) {
 		}
 	}
 
-	quizzes, total, err := c.quizService.FindAllActive(ctx.Request.Context(), userId, end-start, start)
+	category := strings.Trim(ctx.Query("category"), "/")
+
+	quizzes, total, err := c.quizService.FindAllActive(ctx.Request.Context(), userId, category, end-start, start)
+	if err != nil {
+		handleError(ctx, err)
+		return
+	}
+
+	err = c.quizService.TranslateQuizzes(ctx.Request.Context(), quizzes, getPreferredLangs(ctx))
 	if err != nil {
 		handleError(ctx, err)
 		return
@@ -55,6 +68,12 @@ func (c *ApiController) quizList(ctx *gin.Context) {
 func (c *ApiController) quizBySha1(ctx *gin.Context) {
 	sha1 := ctx.Param("sha1")
 
+	format, err := parseContentFormat(ctx.Query("format"))
+	if err != nil {
+		handleError(ctx, err)
+		return
+	}
+
 	userId := ""
 	if isStudent(ctx) {
 		if id, found := getUserIdFromContext(ctx); found {
@@ -65,14 +84,106 @@ func (c *ApiController) quizBySha1(ctx *gin.Context) {
 		}
 	}
 
-	quiz, err := c.quizService.FindFullBySha1(ctx, sha1, userId)
+	quiz, err := c.quizService.FindFullBySha1(ctx.Request.Context(), sha1, userId, getPreferredLangs(ctx))
 	if err != nil {
 		handleError(ctx, err)
 		return
 	}
 
 	dto := Quiz{}
-	ctx.JSON(http.StatusOK, dto.fromDomain(quiz))
+	dto.fromDomain(quiz)
+	if format == HtmlFormat {
+		c.markdownRenderer.renderQuestions(dto.Questions)
+	}
+
+	ctx.JSON(http.StatusOK, dto)
+}
+
+func (c *ApiController) quizImport(ctx *gin.Context) {
+
+	var r QuizImportRequestBody
+	if err := ctx.BindJSON(&r); err != nil {
+		handleError(ctx, err)
+		return
+	}
+
+	format, err := domain.ParseImportFormat(r.Format, r.Filename)
+	if err != nil {
+		handleError(ctx, err)
+		return
+	}
+
+	stats, err := c.quizService.ImportQuiz(ctx.Request.Context(), r.Filename, r.Content, format, r.Name, r.Duration)
+	if err != nil {
+		handleError(ctx, err)
+		return
+	}
+
+	ctx.JSON(http.StatusOK, toSyncStatsDto(stats))
+}
+
+func (c *ApiController) quizExport(ctx *gin.Context) {
+	sha1 := ctx.Param("sha1")
+
+	format, err := domain.ParseExportFormat(ctx.Query("format"))
+	if err != nil {
+		handleError(ctx, err)
+		return
+	}
+
+	export, err := c.quizService.ExportQuiz(ctx.Request.Context(), sha1, format)
+	if err != nil {
+		handleError(ctx, err)
+		return
+	}
+
+	ctx.Header("Content-Disposition", fmt.Sprintf("attachment; filename=%q", export.Filename))
+	ctx.Data(http.StatusOK, export.ContentType, export.Content)
+}
+
+func (c *ApiController) assetBySha1(ctx *gin.Context) {
+	sha1 := ctx.Param("sha1")
+
+	asset, err := c.quizService.FindAssetBySha1(ctx, sha1)
+	if err != nil {
+		handleError(ctx, err)
+		return
+	}
+
+	// an asset is addressed by the sha1 of its content, so it never changes
+	ctx.Header("Cache-Control", "private, max-age=31536000, immutable")
+	ctx.Header("X-Content-Type-Options", "nosniff")
+
+	if !isInlineAsset(asset.ContentType) {
+		ctx.Header("Content-Disposition", fmt.Sprintf("attachment; filename=%q", path.Base(asset.Filename)))
+		ctx.Data(http.StatusOK, "application/octet-stream", asset.Content)
+		return
+	}
+
+	ctx.Header("Content-Security-Policy", "sandbox")
+	ctx.Data(http.StatusOK, asset.ContentType, asset.Content)
+}
+
+// inlineAssetTypes are the content types of the assets shown in the quizzes. The other assets, SVG
+// or HTML files being able to run scripts, are only downloaded
+var inlineAssetTypes = map[string]bool{
+	"image/avif": true,
+	"image/bmp":  true,
+	"image/gif":  true,
+	"image/jpeg": true,
+	"image/png":  true,
+	"image/webp": true,
+	"audio/mpeg": true,
+	"audio/ogg":  true,
+	"audio/wav":  true,
+	"video/mp4":  true,
+	"video/webm": true,
+}
+
+func isInlineAsset(contentType string) bool {
+	mediaType, _, err := mime.ParseMediaType(contentType)
+
+	return err == nil && inlineAssetTypes[mediaType]
 }
 
 func (c *ApiController) sessionList(ctx *gin.Context) {
@@ -154,7 +265,21 @@ func (c *ApiController) addSessionAnswer(ctx *gin.Context) {
 		return
 	}
 
-	err = c.quizService.AddSessionAnswer(ctx, sessionId, userId, r.QuestionSha1, r.AnswerSha1, r.Checked)
+	switch {
+	case r.Text != nil:
+		err = c.quizService.AddSessionTextAnswer(ctx, sessionId, userId, r.QuestionSha1, *r.Text)
+	case r.Number != nil:
+		err = c.quizService.AddSessionNumericAnswer(ctx, sessionId, userId, r.QuestionSha1, *r.Number)
+	case r.Order != nil:
+		err = c.quizService.AddSessionOrderAnswer(ctx, sessionId, userId, r.QuestionSha1, r.Order)
+	case r.Pairs != nil:
+		err = c.quizService.AddSessionPairAnswer(ctx, sessionId, userId, r.QuestionSha1, r.Pairs)
+	case r.AnswerSha1 != "":
+		err = c.quizService.AddSessionAnswer(ctx, sessionId, userId, r.QuestionSha1, r.AnswerSha1, r.Checked)
+	default:
+		handleHttpError(ctx, http.StatusBadRequest, "answerSha1, text, number, order or pairs is required")
+		return
+	}
 	if err != nil {
 		handleError(ctx, err)
 		return
@@ -205,6 +330,12 @@ func (c *ApiController) quizSessionByUuid(ctx *gin.Context) {
 		return
 	}
 
+	format, err := parseContentFormat(ctx.Query("format"))
+	if err != nil {
+		handleError(ctx, err)
+		return
+	}
+
 	userId := ""
 	if id, found := getUserIdFromContext(ctx); found {
 		userId = id
@@ -224,5 +355,16 @@ func (c *ApiController) quizSessionByUuid(ctx *gin.Context) {
 		return
 	}
 
-	ctx.JSON(http.StatusOK, toQuizSessionDetail(sessionDetail))
+	err = c.quizService.TranslateQuizSession(ctx.Request.Context(), sessionDetail, getPreferredLangs(ctx))
+	if err != nil {
+		handleError(ctx, err)
+		return
+	}
+
+	dto := toQuizSessionDetail(sessionDetail)
+	if format == HtmlFormat {
+		c.markdownRenderer.renderQuestions(dto.Questions)
+	}
+
+	ctx.JSON(http.StatusOK, dto)
 }
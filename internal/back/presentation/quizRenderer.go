@@ -0,0 +1,110 @@
+/*
+ * Copyright (c) 2023 Michaël COLL.
+ *
+ * Licensed under the Apache License, Version 2.0 (the "License");
+ * you may not use this file except in compliance with the License.
+ * You may obtain a copy of the License at
+ *
+ *      http://www.apache.org/licenses/LICENSE-2.0
+ *
+ * Unless required by applicable law or agreed to in writing, software
+ * distributed under the License is distributed on an "AS IS" BASIS,
+ * WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+ * See the License for the specific language governing permissions and
+ * limitations under the License.
+ */
+
+package presentation
+
+import (
+	"html/template"
+	"io"
+	"sort"
+	"strings"
+
+	"github.com/michaelcoll/quiz-app/internal/back/domain"
+)
+
+const quizPreviewHtml = `<!DOCTYPE html>
+<html lang="en">
+<head>
+<meta charset="utf-8">
+<title>{{.Name}}</title>
+<style>
+  body { font-family: sans-serif; max-width: 50rem; margin: 2rem auto; padding: 0 1rem; color: #222; }
+  section { border: 1px solid #ddd; border-radius: .5rem; padding: 0 1rem 1rem; margin: 1rem 0; }
+  pre { background: #f5f5f5; padding: .75rem; overflow: auto; }
+  ul { list-style: none; padding-left: 0; }
+  li { margin: .25rem 0; }
+  .text { white-space: pre-wrap; }
+  .info { color: #666; font-size: .9rem; font-weight: normal; }
+  .valid { color: #1a7f37; font-weight: bold; }
+  .explanation { border-left: 3px solid #0969da; padding-left: .75rem; color: #444; white-space: pre-wrap; }
+</style>
+</head>
+<body>
+<h1>{{.Name}}</h1>
+<p class="info">{{.Filename}} — {{minutes .Duration}} min{{with .Metadata}}{{if .Author}} — {{.Author}}{{end}}{{if .Scoring}} — {{.Scoring}}{{end}}{{with .Pool}} — {{.Draw}} question(s) drawn{{end}}{{end}}</p>
+{{- with .Metadata}}
+{{- if .Description}}
+<p class="text">{{.Description}}</p>
+{{- end}}
+{{- if .Tags}}
+<p class="info">{{join .Tags ", "}}</p>
+{{- end}}
+{{- end}}
+{{- range $question := .Questions}}
+<section>
+<h2>{{$question.Position}}. <span class="info">{{$question.Kind}} — {{$question.Points}} point(s){{if $question.PartialCredit}}, partial credit{{end}}</span></h2>
+<div class="text">{{$question.Content}}</div>
+{{- if $question.Code}}
+<pre><code class="language-{{$question.CodeLanguage}}">{{$question.Code}}</code></pre>
+{{- end}}
+<ul>
+{{- range $question.Answers}}
+<li>
+{{- if eq $question.Kind "ORDERING"}}{{.Position}}. {{.Content}}
+{{- else if eq $question.Kind "MATCHING"}}{{.Content}} → {{.Right}}
+{{- else if .Valid}}<span class="valid">✓ {{.Content}}</span>{{if .Match}} <span class="info">{{.Match}}</span>{{end}}
+{{- else}}✗ {{.Content}}
+{{- end}}
+{{- if .Explanation}}
+<div class="explanation">{{.Explanation}}</div>
+{{- end}}</li>
+{{- end}}
+</ul>
+{{- if $question.Explanation}}
+<div class="explanation">{{$question.Explanation}}</div>
+{{- end}}
+</section>
+{{- end}}
+</body>
+</html>
+`
+
+var quizPreviewTemplate = template.Must(template.New("quiz").Funcs(template.FuncMap{
+	"minutes": func(seconds int) int { return seconds / 60 },
+	"join":    strings.Join,
+}).Parse(quizPreviewHtml))
+
+// ToQuizDto maps a quiz the way the quiz endpoint returns it
+func ToQuizDto(d *domain.Quiz) *Quiz {
+	dto := &Quiz{}
+	return dto.fromDomain(d)
+}
+
+// RenderQuizHtml writes a self-contained HTML page showing the questions of a quiz with their
+// answers, the valid ones being marked, and their explanations
+func RenderQuizHtml(w io.Writer, d *domain.Quiz) error {
+	dto := ToQuizDto(d)
+
+	// the items of an ordering question and the pairs of a matching question are shown in their
+	// expected order, the other answers keep the order of the quiz file
+	for _, question := range dto.Questions {
+		sort.SliceStable(question.Answers, func(i, j int) bool {
+			return question.Answers[i].Position < question.Answers[j].Position
+		})
+	}
+
+	return quizPreviewTemplate.Execute(w, dto)
+}
@@ -0,0 +1,78 @@
+/*
+ * Copyright (c) 2023 Michaël COLL.
+ *
+ * Licensed under the Apache License, Version 2.0 (the "License");
+ * you may not use this file except in compliance with the License.
+ * You may obtain a copy of the License at
+ *
+ *      http://www.apache.org/licenses/LICENSE-2.0
+ *
+ * Unless required by applicable law or agreed to in writing, software
+ * distributed under the License is distributed on an "AS IS" BASIS,
+ * WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+ * See the License for the specific language governing permissions and
+ * limitations under the License.
+ */
+
+package presentation
+
+import (
+	"strings"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+
+	"github.com/michaelcoll/quiz-app/internal/back/domain"
+)
+
+func TestRenderQuizHtml(t *testing.T) {
+	quiz := &domain.Quiz{
+		Sha1:     "42",
+		Filename: "git.quiz.md",
+		Name:     "Git <basics>",
+		Duration: 600,
+		Questions: map[string]domain.QuizQuestion{
+			"q1": {
+				Sha1:         "q1",
+				Kind:         domain.Choice,
+				Position:     1,
+				Content:      "What does this command do ?",
+				Code:         "git add <file>",
+				CodeLanguage: "shell",
+				Points:       2,
+				Explanation:  "It stages the file",
+				Answers: map[string]domain.QuizQuestionAnswer{
+					"a1": {Sha1: "a1", Content: "Stages the file", Valid: true, Ordinal: 2},
+					"a2": {Sha1: "a2", Content: "Commits the file", Explanation: "This is git commit", Ordinal: 1},
+				},
+			},
+			"q2": {
+				Sha1:     "q2",
+				Kind:     domain.Ordering,
+				Position: 2,
+				Content:  "Order the commands",
+				Points:   1,
+				Answers: map[string]domain.QuizQuestionAnswer{
+					"a3": {Sha1: "a3", Content: "git commit", Valid: true, Position: 2, Ordinal: 1},
+					"a4": {Sha1: "a4", Content: "git add", Valid: true, Position: 1, Ordinal: 2},
+				},
+			},
+		},
+	}
+
+	var html strings.Builder
+	err := RenderQuizHtml(&html, quiz)
+	if err != nil {
+		assert.Failf(t, "Fail to render quiz", "%v", err)
+	}
+
+	assert.Contains(t, html.String(), "<h1>Git &lt;basics&gt;</h1>")
+	assert.Contains(t, html.String(), "git.quiz.md — 10 min")
+	assert.Contains(t, html.String(), `<pre><code class="language-shell">git add &lt;file&gt;</code></pre>`)
+	assert.Contains(t, html.String(), `<li>✗ Commits the file
+<div class="explanation">This is git commit</div></li>
+<li><span class="valid">✓ Stages the file</span></li>`)
+	assert.Contains(t, html.String(), `<div class="explanation">It stages the file</div>`)
+	assert.Contains(t, html.String(), "<li>1. git add</li>\n<li>2. git commit</li>")
+	assert.Less(t, strings.Index(html.String(), "1. <span"), strings.Index(html.String(), "2. <span"))
+}
@@ -0,0 +1,50 @@
+/*
+ * Copyright (c) 2023 Michaël COLL.
+ *
+ * Licensed under the Apache License, Version 2.0 (the "License");
+ * you may not use this file except in compliance with the License.
+ * You may obtain a copy of the License at
+ *
+ *      http://www.apache.org/licenses/LICENSE-2.0
+ *
+ * Unless required by applicable law or agreed to in writing, software
+ * distributed under the License is distributed on an "AS IS" BASIS,
+ * WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+ * See the License for the specific language governing permissions and
+ * limitations under the License.
+ */
+
+package presentation
+
+import (
+	"errors"
+	"io"
+	"net/http"
+
+	"github.com/gin-gonic/gin"
+)
+
+// maxArchiveUploadSize is the maximum size of an uploaded zip archive
+const maxArchiveUploadSize = 32 << 20
+
+func (c *ApiController) sourceArchiveUpload(ctx *gin.Context) {
+	name := ctx.Param("name")
+
+	content, err := io.ReadAll(http.MaxBytesReader(ctx.Writer, ctx.Request.Body, maxArchiveUploadSize))
+	var maxBytesError *http.MaxBytesError
+	if errors.As(err, &maxBytesError) {
+		handleHttpError(ctx, http.StatusRequestEntityTooLarge, "the archive is bigger than 32 MB")
+		return
+	} else if err != nil {
+		handleError(ctx, err)
+		return
+	}
+
+	stats, err := c.quizService.UploadArchive(ctx.Request.Context(), name, content)
+	if err != nil {
+		handleError(ctx, err)
+		return
+	}
+
+	ctx.JSON(http.StatusOK, toSyncStatsDto(stats))
+}
@@ -37,6 +37,30 @@ func (c *ApiController) me(ctx *gin.Context) {
 	}
 }
 
+func (c *ApiController) updateMyLang(ctx *gin.Context) {
+	c.setMyLang(ctx, ctx.Param("lang"))
+}
+
+func (c *ApiController) clearMyLang(ctx *gin.Context) {
+	c.setMyLang(ctx, "")
+}
+
+func (c *ApiController) setMyLang(ctx *gin.Context, lang string) {
+	userId, found := getUserIdFromContext(ctx)
+	if !found {
+		handleHttpError(ctx, http.StatusUnauthorized, "userId not present in context")
+		return
+	}
+
+	err := c.userService.UpdateUserLang(ctx, userId, lang)
+	if err != nil {
+		handleError(ctx, err)
+		return
+	}
+
+	ctx.JSON(http.StatusOK, gin.H{"message": "lang updated"})
+}
+
 func (c *ApiController) userList(ctx *gin.Context) {
 	users, err := c.userService.FindAllUser(ctx)
 	if err != nil {
@@ -18,22 +18,59 @@ package presentation
 
 import (
 	"net/http"
+	"sync"
 	"time"
 
 	"github.com/gin-gonic/gin"
 )
 
+// syncThrottle lets the public sync endpoint run a sync at most once per interval
+type syncThrottle struct {
+	mu       sync.Mutex
+	interval time.Duration
+	last     time.Time
+}
+
+func newSyncThrottle(interval time.Duration) *syncThrottle {
+	return &syncThrottle{interval: interval, last: time.Now()}
+}
+
+// allow tells if a sync can run now, and if so counts the interval from now
+func (t *syncThrottle) allow() bool {
+	t.mu.Lock()
+	defer t.mu.Unlock()
+
+	if time.Since(t.last) <= t.interval {
+		return false
+	}
+	t.last = time.Now()
+
+	return true
+}
+
 func (c *ApiController) sync(ctx *gin.Context) {
 
-	if time.Since(c.lastSyncUpdate) > 10*time.Second {
-		c.lastSyncUpdate = time.Now()
-		err := c.quizService.Sync(ctx.Request.Context())
-		if err != nil {
-			handleError(ctx, err)
-			return
-		}
-	} else {
+	if !c.syncThrottle.allow() {
 		handleHttpError(ctx, http.StatusTooManyRequests, "too many sync requests")
 		return
 	}
+
+	stats, err := c.quizService.Sync(ctx.Request.Context())
+	if err != nil {
+		handleError(ctx, err)
+		return
+	}
+
+	ctx.JSON(http.StatusOK, toSyncCountsDto(stats))
+}
+
+func (c *ApiController) syncReport(ctx *gin.Context) {
+
+	stats, err := c.quizService.Sync(ctx.Request.Context())
+	if err != nil {
+		handleError(ctx, err)
+		return
+	}
+
+	ctx.JSON(http.StatusOK, toSyncStatsDto(stats))
 }
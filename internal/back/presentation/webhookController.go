@@ -0,0 +1,145 @@
+/*
+ * Copyright (c) 2023 Michaël COLL.
+ *
+ * Licensed under the Apache License, Version 2.0 (the "License");
+ * you may not use this file except in compliance with the License.
+ * You may obtain a copy of the License at
+ *
+ *      http://www.apache.org/licenses/LICENSE-2.0
+ *
+ * Unless required by applicable law or agreed to in writing, software
+ * distributed under the License is distributed on an "AS IS" BASIS,
+ * WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+ * See the License for the specific language governing permissions and
+ * limitations under the License.
+ */
+
+package presentation
+
+import (
+	"crypto/hmac"
+	"crypto/sha256"
+	"encoding/hex"
+	"encoding/json"
+	"errors"
+	"io"
+	"net/http"
+	"strings"
+
+	"github.com/gin-gonic/gin"
+	"github.com/spf13/viper"
+)
+
+// maxWebhookPayloadSize is the maximum size of a webhook payload, the one GitHub sends at most
+const maxWebhookPayloadSize = 25 << 20
+
+// pushEvent holds the fields of a GitHub or Gitea push event telling which branch of which repository
+// moved
+type pushEvent struct {
+	Ref        string `json:"ref"`
+	Repository struct {
+		DefaultBranch string `json:"default_branch"`
+		CloneUrl      string `json:"clone_url"`
+		HtmlUrl       string `json:"html_url"`
+		SshUrl        string `json:"ssh_url"`
+	} `json:"repository"`
+}
+
+// webhook receives the push events of the repository and queues a sync when a synced branch moves.
+// The payload must be signed with the webhook secret
+func (c *ApiController) webhook(ctx *gin.Context) {
+	body, err := io.ReadAll(http.MaxBytesReader(ctx.Writer, ctx.Request.Body, maxWebhookPayloadSize))
+	var maxBytesError *http.MaxBytesError
+	if errors.As(err, &maxBytesError) {
+		handleHttpError(ctx, http.StatusRequestEntityTooLarge, "the payload is bigger than 25 MB")
+		return
+	} else if err != nil {
+		handleError(ctx, err)
+		return
+	}
+
+	if err := verifyWebhookSignature(ctx.Request.Header, body, viper.GetString("webhook-secret")); err != nil {
+		handleError(ctx, err)
+		return
+	}
+
+	event := webhookEvent(ctx.Request.Header)
+	switch event {
+	case "ping":
+		ctx.JSON(http.StatusOK, gin.H{"message": "pong"})
+		return
+	case "push":
+	default:
+		ctx.JSON(http.StatusOK, gin.H{"message": "event '" + event + "' ignored"})
+		return
+	}
+
+	var push pushEvent
+	if err := json.Unmarshal(body, &push); err != nil {
+		handleHttpError(ctx, http.StatusBadRequest, "the payload is not a valid push event")
+		return
+	}
+
+	branch, isBranch := strings.CutPrefix(push.Ref, "refs/heads/")
+	tracked := false
+	if isBranch {
+		repository := push.Repository
+		tracked, err = c.quizService.TracksBranch([]string{repository.CloneUrl, repository.HtmlUrl, repository.SshUrl},
+			branch, repository.DefaultBranch)
+		if err != nil {
+			handleError(ctx, err)
+			return
+		}
+	}
+	if !tracked {
+		ctx.JSON(http.StatusOK, gin.H{"message": "push to " + push.Ref + " ignored"})
+		return
+	}
+
+	c.quizService.QueueSync()
+
+	ctx.JSON(http.StatusAccepted, gin.H{"message": "sync queued"})
+}
+
+// webhookEvent gives the kind of event sent by GitHub or Gitea
+func webhookEvent(header http.Header) string {
+	if event := header.Get("X-GitHub-Event"); event != "" {
+		return event
+	}
+
+	return header.Get("X-Gitea-Event")
+}
+
+// verifyWebhookSignature checks the HMAC-SHA256 of the payload given by GitHub in the
+// X-Hub-Signature-256 header, prefixed by sha256=, or by Gitea in the X-Gitea-Signature header
+func verifyWebhookSignature(header http.Header, body []byte, secret string) error {
+	if secret == "" {
+		return Errorf(http.StatusUnauthorized, "no webhook secret is configured")
+	}
+
+	var signature string
+	if githubSignature := header.Get("X-Hub-Signature-256"); githubSignature != "" {
+		var found bool
+		signature, found = strings.CutPrefix(githubSignature, "sha256=")
+		if !found {
+			return Errorf(http.StatusUnauthorized, "X-Hub-Signature-256 header must start with sha256=")
+		}
+	} else if giteaSignature := header.Get("X-Gitea-Signature"); giteaSignature != "" {
+		signature = giteaSignature
+	} else {
+		return Errorf(http.StatusUnauthorized, "no X-Hub-Signature-256 or X-Gitea-Signature header")
+	}
+
+	expected, err := hex.DecodeString(signature)
+	if err != nil {
+		return Errorf(http.StatusUnauthorized, "signature is not an hexadecimal string")
+	}
+
+	mac := hmac.New(sha256.New, []byte(secret))
+	mac.Write(body)
+	if !hmac.Equal(expected, mac.Sum(nil)) {
+		return Errorf(http.StatusUnauthorized, "signature does not match the payload")
+	}
+
+	return nil
+}
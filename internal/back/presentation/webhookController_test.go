@@ -0,0 +1,78 @@
+/*
+ * Copyright (c) 2023 Michaël COLL.
+ *
+ * Licensed under the Apache License, Version 2.0 (the "License");
+ * you may not use this file except in compliance with the License.
+ * You may obtain a copy of the License at
+ *
+ *      http://www.apache.org/licenses/LICENSE-2.0
+ *
+ * Unless required by applicable law or agreed to in writing, software
+ * distributed under the License is distributed on an "AS IS" BASIS,
+ * WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+ * See the License for the specific language governing permissions and
+ * limitations under the License.
+ */
+
+package presentation
+
+import (
+	"crypto/hmac"
+	"crypto/sha256"
+	"encoding/hex"
+	"net/http"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+)
+
+func Test_verifyWebhookSignature(t *testing.T) {
+	body := []byte(`{"ref":"refs/heads/main"}`)
+	mac := hmac.New(sha256.New, []byte("secret"))
+	mac.Write(body)
+	signature := hex.EncodeToString(mac.Sum(nil))
+
+	tests := []struct {
+		name    string
+		header  http.Header
+		secret  string
+		message string
+	}{
+		{"github", http.Header{"X-Hub-Signature-256": []string{"sha256=" + signature}}, "secret", ""},
+		{"gitea", http.Header{"X-Gitea-Signature": []string{signature}}, "secret", ""},
+		{"wrong secret", http.Header{"X-Hub-Signature-256": []string{"sha256=" + signature}}, "other", "signature does not match the payload"},
+		{"no prefix", http.Header{"X-Hub-Signature-256": []string{signature}}, "secret", "X-Hub-Signature-256 header must start with sha256="},
+		{"not hexadecimal", http.Header{"X-Gitea-Signature": []string{"xyz"}}, "secret", "signature is not an hexadecimal string"},
+		{"no signature", http.Header{}, "secret", "no X-Hub-Signature-256 or X-Gitea-Signature header"},
+		{"no secret", http.Header{"X-Gitea-Signature": []string{signature}}, "", "no webhook secret is configured"},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			err := verifyWebhookSignature(tt.header, body, tt.secret)
+			if tt.message == "" {
+				assert.NoError(t, err)
+				return
+			}
+
+			assert.EqualError(t, err, tt.message)
+			status, _ := GetCodeFromError(err)
+			assert.Equal(t, http.StatusUnauthorized, status)
+		})
+	}
+}
+
+func Test_webhookEvent(t *testing.T) {
+	assert.Equal(t, "push", webhookEvent(http.Header{"X-Github-Event": []string{"push"}}))
+	assert.Equal(t, "ping", webhookEvent(http.Header{"X-Gitea-Event": []string{"ping"}}))
+	assert.Equal(t, "", webhookEvent(http.Header{}))
+}
+
+func TestSyncThrottle_allow(t *testing.T) {
+	throttle := newSyncThrottle(time.Hour)
+	assert.False(t, throttle.allow())
+
+	throttle = newSyncThrottle(0)
+	time.Sleep(time.Millisecond)
+	assert.True(t, throttle.allow())
+}